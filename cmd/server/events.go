@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"hub-user-service/internal/config"
+	"hub-user-service/internal/events"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// newEventStream creates the USER_EVENTS_BACKEND stream; a Redis stream is shared by all replicas
+// and keeps resume tokens valid across restarts
+func newEventStream(cfg *config.Config) events.Stream {
+	if cfg.UserEventsBackend != "redis" {
+		log.Println("✅ User event broker initialized (in memory, per replica)")
+		return events.NewBroker(cfg.UserEventsHistorySize, cfg.UserEventsBufferSize)
+	}
+
+	client := redis.NewClient(&redis.Options{Addr: cfg.GetRedisAddress()})
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	// Publishing failures are logged and never fail the call that produced the event
+	if err := client.Ping(ctx).Err(); err != nil {
+		log.Printf("⚠️  Redis at %s is unreachable, user events are lost until it is: %v", cfg.GetRedisAddress(), err)
+	}
+	log.Printf("✅ User event stream initialized (Redis at %s)", cfg.GetRedisAddress())
+	return events.NewRedisStream(client, cfg.UserEventsHistorySize, cfg.UserEventsBufferSize)
+}
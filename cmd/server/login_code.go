@@ -0,0 +1,38 @@
+package main
+
+import (
+	"log"
+
+	"hub-user-service/internal/config"
+	loginCodeUsecase "hub-user-service/internal/logincode/application/usecase"
+	"hub-user-service/internal/notification"
+	"hub-user-service/internal/ratelimit"
+)
+
+// newNotificationSender creates the sender for the configured NOTIFICATION_SENDER
+func newNotificationSender(cfg *config.Config) notification.Sender {
+	if cfg.NotificationSender == "smtp" {
+		return notification.NewSMTPSender(notification.SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.SMTPFrom,
+		})
+	}
+	log.Println("⚠️  Notifications are written to the log (NOTIFICATION_SENDER=log), do not use in production")
+	return notification.NewLogSender(log.Writer())
+}
+
+// newLoginCodeUsecase creates the passwordless email login use case
+func newLoginCodeUsecase(cfg *config.Config, repos *repositories, sender notification.Sender) loginCodeUsecase.ILoginCodeUsecase {
+	return loginCodeUsecase.NewLoginCodeUsecase(repos.login, repos.loginCodes, sender, loginCodeUsecase.LoginCodeConfig{
+		Delivery:      cfg.LoginCodeDelivery,
+		LinkURL:       cfg.LoginCodeLinkURL,
+		TTL:           cfg.LoginCodeTTL,
+		Limiter:       ratelimit.NewMemoryLimiter(),
+		MaxRequests:   cfg.LoginCodeMaxRequests,
+		RequestWindow: cfg.LoginCodeRequestWindow,
+		MaxAttempts:   cfg.LoginCodeMaxAttempts,
+	})
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"log"
+
+	"hub-user-service/internal/config"
+	"hub-user-service/internal/geoip"
+	loginHistoryUsecase "hub-user-service/internal/loginhistory/application/usecase"
+	"hub-user-service/internal/notification"
+)
+
+// newLocator loads GEOIP_DATABASE_FILE, or locates nothing when it is not set
+func newLocator(cfg *config.Config) (geoip.Locator, error) {
+	if cfg.GeoIPDatabaseFile == "" {
+		return geoip.NopLocator{}, nil
+	}
+
+	db, err := geoip.LoadDatabase(cfg.GeoIPDatabaseFile)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("🌍 GeoIP database loaded (%d networks)", db.Len())
+	return db, nil
+}
+
+// newLoginHistoryUsecase creates the login history use case; sender may be nil when new device
+// notifications are disabled
+func newLoginHistoryUsecase(cfg *config.Config, repos *repositories, locator geoip.Locator, sender notification.Sender) loginHistoryUsecase.ILoginHistoryUsecase {
+	return loginHistoryUsecase.NewLoginHistoryUsecase(repos.login, repos.loginHistory, locator, sender, loginHistoryUsecase.LoginHistoryConfig{
+		NotifyNewDevices: cfg.NewDeviceNotificationEnabled,
+	})
+}
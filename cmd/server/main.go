@@ -5,50 +5,114 @@ import (
 	"net"
 	"os"
 
+	"hub-user-service/internal/admin"
+	"hub-user-service/internal/audit"
 	"hub-user-service/internal/auth"
+	"hub-user-service/internal/auth/serviceauth"
 	"hub-user-service/internal/auth/token"
 	"hub-user-service/internal/config"
-	"hub-user-service/internal/database"
 	grpcServer "hub-user-service/internal/grpc"
+	"hub-user-service/internal/grpc/clientinfo"
+	"hub-user-service/internal/grpc/interceptor"
 	"hub-user-service/internal/grpc/proto"
 	"hub-user-service/internal/login/application/usecase"
-	"hub-user-service/internal/login/infra/persistence"
+	"hub-user-service/internal/notification"
+	riskUsecase "hub-user-service/internal/risk/application/usecase"
 
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/reflection"
 )
 
 func main() {
 	// Load configuration
 	cfg := config.Load()
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(cfg, os.Args[2:]); err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate-users" {
+		if err := runMigrateUsers(cfg, os.Args[2:]); err != nil {
+			log.Fatalf("User migration failed: %v", err)
+		}
+		return
+	}
+
 	log.Printf("Starting Hub User Service...")
 	log.Printf("gRPC Port: %s", cfg.GRPCPort)
 	log.Printf("HTTP Port: %s", cfg.HTTPPort)
-	log.Printf("Database URL: %s", maskDatabaseURL(cfg.DatabaseURL))
+	log.Printf("Database driver: %s", cfg.DBDriver)
 
-	// Initialize database connection
-	dbConfig := database.ConnectionConfig{
-		Driver:   "postgres",
-		Host:     getEnvWithDefault("DB_HOST", "localhost"),
-		Port:     getEnvWithDefault("DB_PORT", "5432"),
-		Database: getEnvWithDefault("DB_NAME", "hub_investments"),
-		Username: getEnvWithDefault("DB_USER", "postgres"),
-		Password: getEnvWithDefault("DB_PASSWORD", "postgres"),
-		SSLMode:  getEnvWithDefault("DB_SSLMODE", "disable"),
+	// Registered before the repositories so database spans are exported too
+	if cfg.TracingEnabled {
+		if err := setupTracing(cfg); err != nil {
+			log.Fatalf("Failed to initialize tracing: %v", err)
+		}
 	}
 
-	db, err := database.NewConnectionFactory(dbConfig).CreateConnection()
+	// Initialize repositories
+	repos, err := newRepositories(cfg)
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		log.Fatalf("Failed to initialize storage: %v", err)
 	}
-	log.Println("✅ Database connected successfully")
+	log.Println("✅ Repositories initialized")
 
-	// Initialize repositories
-	loginRepository := persistence.NewLoginRepository(db)
-	log.Println("✅ Login repository initialized")
+	// Audit trail for security relevant actions (MFA enrollment, recovery codes and passkeys)
+	auditRecorder := audit.NewLogRecorder(log.Writer())
+
+	totpUsecase, err := newTOTPUsecase(cfg, repos, auditRecorder)
+	if err != nil {
+		log.Fatalf("Failed to initialize MFA: %v", err)
+	}
+	log.Println("✅ TOTP use case initialized")
+
+	sessions := newSessionUsecase(cfg, repos)
+	log.Printf("✅ Session use case initialized (web idle %s, absolute %s)", cfg.SessionWebIdleTimeout, cfg.SessionWebAbsoluteTimeout)
+
+	authServerOptions := []grpcServer.AuthServerOption{grpcServer.WithTOTP(totpUsecase), grpcServer.WithSessions(sessions)}
+	if cfg.WebAuthnEnabled {
+		passkeyUsecase, err := newPasskeyUsecase(cfg, repos, auditRecorder)
+		if err != nil {
+			log.Fatalf("Failed to initialize passkeys: %v", err)
+		}
+		authServerOptions = append(authServerOptions, grpcServer.WithPasskeys(passkeyUsecase))
+		log.Printf("✅ Passkey use case initialized (rp id: %s)", cfg.WebAuthnRPID)
+	}
+	var sender notification.Sender
+	if cfg.LoginCodeEnabled || cfg.NewDeviceNotificationEnabled {
+		sender = newNotificationSender(cfg)
+	}
+	if cfg.LoginCodeEnabled {
+		loginCodes := newLoginCodeUsecase(cfg, repos, sender)
+		authServerOptions = append(authServerOptions, grpcServer.WithLoginCodes(loginCodes))
+		log.Printf("✅ Login code use case initialized (delivery: %s, sender: %s)", cfg.LoginCodeDelivery, cfg.NotificationSender)
+	}
+	locator, err := newLocator(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize GeoIP: %v", err)
+	}
+	loginHistory := newLoginHistoryUsecase(cfg, repos, locator, sender)
+	authServerOptions = append(authServerOptions, grpcServer.WithLoginHistory(loginHistory))
+	log.Printf("✅ Login history use case initialized (new device notifications: %t)", cfg.NewDeviceNotificationEnabled)
+	var risk riskUsecase.IRiskUsecase
+	if cfg.RiskEnabled {
+		risk, err = newRiskUsecase(cfg, repos, locator)
+		if err != nil {
+			log.Fatalf("Failed to initialize risk assessment: %v", err)
+		}
+		log.Println("✅ Risk use case initialized")
+	}
 
-	// Initialize use cases
-	loginUsecase := usecase.NewDoLoginUsecase(loginRepository)
+	// Password, passkey and login code logins share one gate for the second factor and risk decisions
+	loginGate := usecase.NewLoginGateUsecase(totpUsecase, risk)
+	loginUsecase := usecase.NewDoLoginUsecase(repos.login, usecase.WithLoginGate(loginGate))
+	authServerOptions = append(authServerOptions, grpcServer.WithLoginGate(loginGate))
 	log.Println("✅ Login use case initialized")
 
 	// Initialize authentication services
@@ -56,20 +120,77 @@ func main() {
 	authService := auth.NewAuthService(tokenService)
 	log.Println("✅ Auth service initialized")
 
+	// Initialize the user event stream
+	eventStream := newEventStream(cfg)
+
+	// Client metadata (x-forwarded-for, x-client-type) is only believed from gateways
+	trustedProxies, err := cfg.TrustedProxyPrefixes()
+	if err != nil {
+		log.Fatalf("Failed to parse trusted proxies: %v", err)
+	}
+	clientTrust := clientinfo.TrustGateways(trustedProxies)
+
 	// Initialize gRPC server
-	authGrpcServer := grpcServer.NewAuthServer(loginUsecase, authService)
+	authServerOptions = append(authServerOptions, grpcServer.WithEventPublisher(eventStream), grpcServer.WithClientTrust(clientTrust))
+	authGrpcServer := grpcServer.NewAuthServer(loginUsecase, authService, authServerOptions...)
+	userEventGrpcServer := grpcServer.NewUserEventServer(eventStream)
 	log.Println("✅ gRPC auth server initialized")
 
 	// Create gRPC server with options
-	grpcSrv := grpc.NewServer()
+	serverOptions := grpcServer.NewServerOptions(cfg)
+	serverOptions = append(serverOptions,
+		grpc.ChainUnaryInterceptor(interceptor.DefaultTimeout(cfg.GRPCRequestTimeout), interceptor.ReadYourWrites()),
+		grpc.ChainStreamInterceptor(interceptor.ReadYourWritesStream()),
+	)
+	if cfg.TracingEnabled {
+		// Continues the caller's trace and starts one server span per call
+		serverOptions = append(serverOptions, grpc.StatsHandler(otelgrpc.NewServerHandler()))
+	}
+	limiter := newRateLimiter(cfg)
+	if cfg.ServiceAuthEnabled {
+		registry, err := serviceauth.LoadRegistry(cfg.ServiceClientsFile)
+		if err != nil {
+			log.Fatalf("Failed to load service clients: %v", err)
+		}
+
+		serviceAuth := interceptor.NewServiceAuthInterceptor(serviceauth.NewAuthenticator(registry), limiter)
+		serverOptions = append(serverOptions,
+			grpc.ChainUnaryInterceptor(serviceAuth.Unary()),
+			grpc.ChainStreamInterceptor(serviceAuth.Stream()),
+		)
+		log.Printf("✅ Service-to-service authentication enabled (%d clients)", registry.Len())
+	}
+	// Chained after service auth so quotas can count by service client
+	if cfg.RateLimitEnabled {
+		rateLimit, err := newRateLimitInterceptor(cfg, limiter, clientTrust)
+		if err != nil {
+			log.Fatalf("Failed to initialize rate limiting: %v", err)
+		}
+		serverOptions = append(serverOptions,
+			grpc.ChainUnaryInterceptor(rateLimit.Unary()),
+			grpc.ChainStreamInterceptor(rateLimit.Stream()),
+		)
+	}
+
+	grpcSrv := grpc.NewServer(serverOptions...)
 
 	// Register services
 	proto.RegisterAuthServiceServer(grpcSrv, authGrpcServer)
 	log.Println("✅ AuthService registered")
-
-	// Register reflection service (useful for gRPC clients like grpcurl)
-	reflection.Register(grpcSrv)
-	log.Println("✅ gRPC reflection registered")
+	proto.RegisterUserEventServiceServer(grpcSrv, userEventGrpcServer)
+	log.Println("✅ UserEventService registered")
+
+	// Reflection, channelz, pprof and expvar are only exposed on the admin listener
+	if cfg.AdminListenerEnabled() {
+		adminSrv := admin.NewServer(cfg.AdminPort, grpcSrv, cfg.AdminToken)
+		go func() {
+			if err := adminSrv.ListenAndServe(); err != nil {
+				log.Printf("Admin listener stopped: %v", err)
+			}
+		}()
+	} else {
+		log.Println("Admin listener disabled")
+	}
 
 	// Start listening
 	listener, err := net.Listen("tcp", cfg.GRPCPort)
@@ -85,20 +206,3 @@ func main() {
 		log.Fatalf("Failed to serve gRPC: %v", err)
 	}
 }
-
-// getEnvWithDefault gets an environment variable or returns a default value
-func getEnvWithDefault(key, defaultValue string) string {
-	value := os.Getenv(key)
-	if value == "" {
-		return defaultValue
-	}
-	return value
-}
-
-// maskDatabaseURL masks sensitive information in database URL for logging
-func maskDatabaseURL(url string) string {
-	if url == "" {
-		return "not configured"
-	}
-	return "***configured***"
-}
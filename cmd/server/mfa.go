@@ -0,0 +1,36 @@
+package main
+
+import (
+	"hub-user-service/internal/audit"
+	"hub-user-service/internal/config"
+	mfaUsecase "hub-user-service/internal/mfa/application/usecase"
+	"hub-user-service/internal/mfa/infra/crypto"
+	"hub-user-service/internal/ratelimit"
+)
+
+// newTOTPUsecase creates the TOTP use case; without MFA_ENCRYPTION_KEY enrollment and
+// verification are unavailable, so enrolled users can not complete a login
+func newTOTPUsecase(cfg *config.Config, repos *repositories, auditRecorder audit.Recorder) (mfaUsecase.ITOTPUsecase, error) {
+	var box crypto.ISecretBox
+	if cfg.MFAEncryptionKey != "" {
+		key, err := crypto.ParseKey(cfg.MFAEncryptionKey)
+		if err != nil {
+			return nil, err
+		}
+		secretBox, err := crypto.NewSecretBox(key)
+		if err != nil {
+			return nil, err
+		}
+		box = secretBox
+	}
+
+	return mfaUsecase.NewTOTPUsecase(repos.totp, repos.recoveryCodes, box, mfaUsecase.TOTPConfig{
+		Issuer:        cfg.MFAIssuer,
+		Skew:          cfg.MFATOTPSkew,
+		Limiter:       ratelimit.NewMemoryLimiter(),
+		MaxAttempts:   cfg.MFAMaxAttempts,
+		AttemptWindow: cfg.MFAChallengeTTL,
+		RecoveryCodes: cfg.MFARecoveryCodes,
+		Audit:         auditRecorder,
+	}), nil
+}
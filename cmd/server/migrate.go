@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"hub-user-service/internal/config"
+	"hub-user-service/internal/database"
+	"hub-user-service/internal/database/migrate"
+	"hub-user-service/migrations"
+)
+
+const migrateUsage = "usage: hub-user-service migrate up|down|status|to <version>"
+
+// newMigrator creates a migrator for the migrations embedded in the binary
+func newMigrator(db database.Database) (*migrate.Migrator, error) {
+	embedded, err := migrate.Load(migrations.FS)
+	if err != nil {
+		return nil, err
+	}
+	return migrate.NewMigrator(db, embedded), nil
+}
+
+// runMigrate implements the "migrate" subcommand
+func runMigrate(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf(migrateUsage)
+	}
+	if cfg.DBDriver != "postgres" {
+		return fmt.Errorf("migrations require DB_DRIVER=postgres (got %s)", cfg.DBDriver)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	db, err := database.NewConnectionFactory(database.NewConnectionConfig(cfg)).CreateConnection()
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	migrator, err := newMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	var applied []migrate.Migration
+	switch {
+	case args[0] == "up" && len(args) == 1:
+		applied, err = migrator.Up(ctx)
+	case args[0] == "down" && len(args) == 1:
+		applied, err = migrator.Down(ctx)
+	case args[0] == "to" && len(args) == 2:
+		version, parseErr := strconv.ParseUint(args[1], 10, 64)
+		if parseErr != nil {
+			return fmt.Errorf("invalid version %q: %s", args[1], migrateUsage)
+		}
+		applied, err = migrator.To(ctx, uint(version))
+	case args[0] == "status" && len(args) == 1:
+		return printMigrationStatus(ctx, migrator)
+	default:
+		return fmt.Errorf(migrateUsage)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range applied {
+		log.Printf("✅ Applied migration %s (%s)", migration, args[0])
+	}
+	if len(applied) == 0 {
+		log.Println("Schema already up to date")
+	}
+	return printMigrationStatus(ctx, migrator)
+}
+
+// printMigrationStatus logs the current, expected and pending schema versions
+func printMigrationStatus(ctx context.Context, migrator *migrate.Migrator) error {
+	status, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("🗄️  Schema version: %d (dirty=%t), binary expects: %d", status.Current, status.Dirty, status.Latest)
+	for _, migration := range status.Pending {
+		log.Printf("  pending: %s", migration)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"hub-user-service/internal/config"
+	"hub-user-service/internal/database"
+	"hub-user-service/internal/events"
+	"hub-user-service/internal/login/infra/persistence"
+	"hub-user-service/internal/usermigration"
+)
+
+// runMigrateUsers implements the "migrate-users" subcommand: copy users from the monolith
+// database (source) into the configured database (target), then reconcile both
+func runMigrateUsers(cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("migrate-users", flag.ContinueOnError)
+	sourceDSN := flags.String("source-dsn", os.Getenv("SOURCE_DATABASE_URL"), "monolith database URL or DSN (default $SOURCE_DATABASE_URL)")
+	batchSize := flags.Int("batch-size", usermigration.DefaultBatchSize, "users read per batch")
+	checkpoint := flags.String("checkpoint", "", "file storing progress so an interrupted run resumes")
+	dryRun := flags.Bool("dry-run", false, "compare source and target without writing")
+	reportPath := flags.String("report", "", "write the reconciliation report as JSON to this file")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *sourceDSN == "" {
+		return fmt.Errorf("a source database is required (-source-dsn or SOURCE_DATABASE_URL)")
+	}
+	if cfg.DBDriver != "postgres" {
+		return fmt.Errorf("user migration requires DB_DRIVER=postgres (got %s)", cfg.DBDriver)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	sourceConfig := database.NewConnectionConfig(cfg)
+	sourceConfig.URL = *sourceDSN
+	sourceConfig.ReplicaURLs = nil
+	source, err := database.NewConnectionFactory(sourceConfig).CreateConnection()
+	if err != nil {
+		return fmt.Errorf("failed to connect to source database: %w", err)
+	}
+	defer source.Close()
+
+	// Reconciliation reads right after the writes, which a lagging replica would not have yet
+	targetConfig := database.NewConnectionConfig(cfg)
+	targetConfig.ReplicaURLs = nil
+	target, err := database.NewConnectionFactory(targetConfig).CreateConnection()
+	if err != nil {
+		return fmt.Errorf("failed to connect to target database: %w", err)
+	}
+	defer target.Close()
+
+	// Only a shared stream reaches the consumers of the running service
+	var publisher events.Publisher
+	if cfg.UserEventsBackend == "redis" {
+		publisher = newEventStream(cfg)
+	} else {
+		log.Println("⚠️  USER_EVENTS_BACKEND=memory, password and email changes are not published")
+	}
+
+	log.Printf("🔄 Migrating users to %s (batch size %d, dry run %t)", cfg.DatabaseDescription(), *batchSize, *dryRun)
+	migration := usermigration.NewUserMigration(source, persistence.NewUserRepository(target), usermigration.Options{
+		BatchSize:      *batchSize,
+		DryRun:         *dryRun,
+		CheckpointPath: *checkpoint,
+		Events:         publisher,
+	})
+
+	report, err := migration.Run(ctx)
+	if report != nil {
+		printUserMigrationReport(report)
+	}
+	if err != nil {
+		return err
+	}
+
+	if *reportPath != "" {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(*reportPath, data, 0o644); err != nil {
+			return fmt.Errorf("failed to write report: %w", err)
+		}
+	}
+
+	if !report.Consistent() && !report.DryRun {
+		return fmt.Errorf("reconciliation found %d missing and %d mismatched users, %d failed",
+			report.Reconciliation.Missing, report.Reconciliation.Mismatched, report.Copy.Failed)
+	}
+	return nil
+}
+
+// printUserMigrationReport logs the copy counts and the reconciliation
+func printUserMigrationReport(report *usermigration.Report) {
+	verb := "Copied"
+	if report.DryRun {
+		verb = "Would copy"
+	}
+	c, r := report.Copy, report.Reconciliation
+
+	log.Printf("📊 %s: read=%d inserted=%d updated=%d unchanged=%d failed=%d", verb, c.Read, c.Inserted, c.Updated, c.Unchanged, c.Failed)
+	if len(c.FailedIDs) > 0 {
+		log.Printf("   failed ids: %v", c.FailedIDs)
+	}
+	log.Printf("📊 Reconciliation: source=%d target=%d matched=%d missing=%d mismatched=%d",
+		r.SourceRows, r.TargetRows, r.Matched, r.Missing, r.Mismatched)
+	if len(r.MissingIDs) > 0 {
+		log.Printf("   missing ids: %v", r.MissingIDs)
+	}
+	if len(r.MismatchedIDs) > 0 {
+		log.Printf("   mismatched ids: %v", r.MismatchedIDs)
+	}
+	log.Printf("   source checksum: %s", r.SourceChecksum)
+	log.Printf("   target checksum: %s", r.TargetChecksum)
+
+	if report.Consistent() {
+		log.Println("✅ Source and target users match")
+	} else {
+		log.Println("⚠️  Source and target users differ")
+	}
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"hub-user-service/internal/audit"
+	"hub-user-service/internal/config"
+	passkeyUsecase "hub-user-service/internal/passkey/application/usecase"
+)
+
+// newPasskeyUsecase creates the WebAuthn use case for the configured relying party
+func newPasskeyUsecase(cfg *config.Config, repos *repositories, auditRecorder audit.Recorder) (passkeyUsecase.IPasskeyUsecase, error) {
+	return passkeyUsecase.NewPasskeyUsecase(repos.login, repos.passkeyCredentials, repos.passkeyCeremonies, passkeyUsecase.PasskeyConfig{
+		RPID:                      cfg.WebAuthnRPID,
+		RPDisplayName:             cfg.WebAuthnRPDisplayName,
+		RPOrigins:                 cfg.WebAuthnRPOrigins,
+		Attestation:               cfg.WebAuthnAttestation,
+		AllowedAttestationFormats: cfg.WebAuthnAttestationFormats,
+		RequireUserVerification:   cfg.WebAuthnRequireUserVerification,
+		CeremonyTimeout:           cfg.WebAuthnCeremonyTimeout,
+		Audit:                     auditRecorder,
+	})
+}
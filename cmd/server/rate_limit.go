@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"hub-user-service/internal/config"
+	"hub-user-service/internal/grpc/clientinfo"
+	"hub-user-service/internal/grpc/interceptor"
+	"hub-user-service/internal/ratelimit"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// newRateLimiter creates the RATE_LIMIT_BACKEND limiter; buckets in Redis are shared by all replicas
+func newRateLimiter(cfg *config.Config) ratelimit.Limiter {
+	if cfg.RateLimitBackend != "redis" {
+		return ratelimit.NewMemoryLimiter()
+	}
+
+	client := redis.NewClient(&redis.Options{Addr: cfg.GetRedisAddress()})
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	// The limiter fails open, so an unreachable Redis only disables rate limiting
+	if err := client.Ping(ctx).Err(); err != nil {
+		log.Printf("⚠️  Redis at %s is unreachable, rate limits are not enforced until it is: %v", cfg.GetRedisAddress(), err)
+	}
+	return ratelimit.NewRedisLimiter(client)
+}
+
+// newRateLimitInterceptor creates the interceptor enforcing RATE_LIMIT_QUOTAS with limiter, counting
+// the forwarded client IP of the calls trust holds for
+func newRateLimitInterceptor(cfg *config.Config, limiter ratelimit.Limiter, trust clientinfo.Trust) (*interceptor.RateLimitInterceptor, error) {
+	quotas, err := cfg.RateLimitQuotas()
+	if err != nil {
+		return nil, err
+	}
+
+	converted := make([]interceptor.RateLimitQuota, 0, len(quotas))
+	for _, quota := range quotas {
+		converted = append(converted, interceptor.NewRateLimitQuota(quota.Method, interceptor.RateLimitKey(quota.Key), quota.Count, quota.Period))
+	}
+	log.Printf("🚦 Rate limiting enabled (%s backend, %d quotas)", cfg.RateLimitBackend, len(converted))
+	return interceptor.NewRateLimitInterceptor(limiter, converted, trust), nil
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"log"
+	// Time zones of the risk policy, the runtime image has no tzdata
+	_ "time/tzdata"
+
+	"hub-user-service/internal/config"
+	"hub-user-service/internal/geoip"
+	riskUsecase "hub-user-service/internal/risk/application/usecase"
+	"hub-user-service/internal/risk/infra/filesource"
+)
+
+// newRiskUsecase creates the risk use case from RISK_RULES_FILE and RISK_BLOCKLIST_FILE, both
+// reloaded when they change
+func newRiskUsecase(cfg *config.Config, repos *repositories, locator geoip.Locator) (riskUsecase.IRiskUsecase, error) {
+	policies, err := filesource.LoadPolicyFile(cfg.RiskRulesFile)
+	if err != nil {
+		return nil, err
+	}
+	policies.Watch(cfg.RiskReloadInterval)
+	log.Printf("🛡️  Risk rules loaded from %s (%d rules)", cfg.RiskRulesFile, len(policies.Policy().Rules))
+
+	var blocklist riskUsecase.IPBlocklist
+	if cfg.RiskBlocklistFile != "" {
+		file, err := filesource.LoadBlocklistFile(cfg.RiskBlocklistFile)
+		if err != nil {
+			return nil, err
+		}
+		file.Watch(cfg.RiskReloadInterval)
+		log.Printf("🛡️  IP blocklist loaded from %s (%d entries)", cfg.RiskBlocklistFile, file.Len())
+		blocklist = file
+	}
+
+	return riskUsecase.NewRiskUsecase(repos.loginHistory, locator, policies, blocklist), nil
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"hub-user-service/internal/config"
+	sessionUsecase "hub-user-service/internal/session/application/usecase"
+	"hub-user-service/internal/session/domain/model"
+)
+
+// newSessionUsecase creates the login session use case
+func newSessionUsecase(cfg *config.Config, repos *repositories) sessionUsecase.ISessionUsecase {
+	return sessionUsecase.NewSessionUsecase(repos.sessions, sessionUsecase.SessionConfig{
+		LastSeenInterval: cfg.SessionLastSeenInterval,
+		Timeouts: map[model.ClientType]model.Timeouts{
+			model.ClientWeb:    {Idle: cfg.SessionWebIdleTimeout, Absolute: cfg.SessionWebAbsoluteTimeout},
+			model.ClientMobile: {Idle: cfg.SessionMobileIdleTimeout, Absolute: cfg.SessionMobileAbsoluteTimeout},
+			model.ClientAPI:    {Idle: cfg.SessionAPIIdleTimeout, Absolute: cfg.SessionAPIAbsoluteTimeout},
+		},
+	})
+}
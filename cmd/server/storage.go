@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"hub-user-service/internal/config"
+	"hub-user-service/internal/database"
+	"hub-user-service/internal/login/domain/repository"
+	"hub-user-service/internal/login/infra/persistence"
+	loginCodeRepository "hub-user-service/internal/logincode/domain/repository"
+	loginCodePersistence "hub-user-service/internal/logincode/infra/persistence"
+	loginHistoryRepository "hub-user-service/internal/loginhistory/domain/repository"
+	loginHistoryPersistence "hub-user-service/internal/loginhistory/infra/persistence"
+	mfaRepository "hub-user-service/internal/mfa/domain/repository"
+	mfaPersistence "hub-user-service/internal/mfa/infra/persistence"
+	passkeyRepository "hub-user-service/internal/passkey/domain/repository"
+	passkeyPersistence "hub-user-service/internal/passkey/infra/persistence"
+	sessionRepository "hub-user-service/internal/session/domain/repository"
+	sessionPersistence "hub-user-service/internal/session/infra/persistence"
+)
+
+// repositories are the storage implementations for the configured DB_DRIVER
+type repositories struct {
+	login              repository.ILoginRepository
+	totp               mfaRepository.ITOTPRepository
+	recoveryCodes      mfaRepository.IRecoveryCodeRepository
+	passkeyCredentials passkeyRepository.ICredentialRepository
+	passkeyCeremonies  passkeyRepository.ICeremonyRepository
+	loginCodes         loginCodeRepository.ILoginCodeRepository
+	sessions           sessionRepository.ISessionRepository
+	loginHistory       loginHistoryRepository.ILoginHistoryRepository
+}
+
+// newRepositories creates the repositories for the configured DB_DRIVER
+func newRepositories(cfg *config.Config) (*repositories, error) {
+	switch cfg.DBDriver {
+	case "memory":
+		login, err := newMemoryLoginRepository(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return newMemoryRepositories(login), nil
+	case "sqlite":
+		log.Printf("Database: %s", cfg.DatabaseDescription())
+		login, err := newSQLiteLoginRepository(cfg)
+		if err != nil {
+			return nil, err
+		}
+		// The other tables use PostgreSQL-only SQL
+		log.Println("⚠️  MFA, passkeys, login codes, sessions and login history are kept in memory with DB_DRIVER=sqlite")
+		return newMemoryRepositories(login), nil
+	}
+
+	log.Printf("Database: %s", cfg.DatabaseDescription())
+	db, err := connectPostgres(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &repositories{
+		login:              persistence.NewLoginRepository(db),
+		totp:               mfaPersistence.NewTOTPRepository(db),
+		recoveryCodes:      mfaPersistence.NewRecoveryCodeRepository(db),
+		passkeyCredentials: passkeyPersistence.NewCredentialRepository(db),
+		passkeyCeremonies:  passkeyPersistence.NewCeremonyRepository(db),
+		loginCodes:         loginCodePersistence.NewLoginCodeRepository(db),
+		sessions:           sessionPersistence.NewSessionRepository(db),
+		loginHistory:       loginHistoryPersistence.NewLoginHistoryRepository(db),
+	}, nil
+}
+
+// newMemoryRepositories keeps everything but the users in memory
+func newMemoryRepositories(login repository.ILoginRepository) *repositories {
+	return &repositories{
+		login:              login,
+		totp:               mfaPersistence.NewMemoryTOTPRepository(),
+		recoveryCodes:      mfaPersistence.NewMemoryRecoveryCodeRepository(),
+		passkeyCredentials: passkeyPersistence.NewMemoryCredentialRepository(),
+		passkeyCeremonies:  passkeyPersistence.NewMemoryCeremonyRepository(),
+		loginCodes:         loginCodePersistence.NewMemoryLoginCodeRepository(),
+		sessions:           sessionPersistence.NewMemorySessionRepository(),
+		loginHistory:       loginHistoryPersistence.NewMemoryLoginHistoryRepository(),
+	}
+}
+
+// connectPostgres opens the PostgreSQL pool, publishes its statistics and checks the schema version
+func connectPostgres(cfg *config.Config) (database.Database, error) {
+	db, err := database.NewConnectionFactory(database.NewConnectionConfig(cfg)).CreateConnection()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	log.Println("✅ Database connected successfully")
+	database.PublishPoolStats("primary", db)
+	if routing, ok := db.(*database.RoutingDatabase); ok {
+		for name, replica := range routing.Replicas() {
+			database.PublishPoolStats(name, replica)
+		}
+		log.Printf("🗄️  Read replicas: %d configured, %d healthy (health check every %s)",
+			len(cfg.DBReplicaURLs), routing.HealthyReplicas(), cfg.DBReplicaHealthCheckInterval)
+	}
+	log.Printf("🗄️  Database pool: max_open=%d max_idle=%d max_lifetime=%s max_idle_time=%s",
+		cfg.DBMaxOpenConns, cfg.DBMaxIdleConns, cfg.DBConnMaxLifetime, cfg.DBConnMaxIdleTime)
+
+	if cfg.DBInstrumentationEnabled {
+		db = database.NewInstrumentedDatabase(db, database.InstrumentationOptions{
+			SlowQueryThreshold: cfg.DBSlowQueryThreshold,
+		})
+		log.Printf("📈 Query instrumentation enabled (slow query threshold %s)", cfg.DBSlowQueryThreshold)
+	}
+
+	if cfg.DBReadRetryEnabled {
+		db = database.NewRetryingDatabase(db, database.RetryPolicy{
+			MaxAttempts:    cfg.DBReadRetryMaxAttempts,
+			InitialBackoff: cfg.DBReadRetryInitialBackoff,
+			MaxBackoff:     cfg.DBReadRetryMaxBackoff,
+		})
+		log.Printf("🔁 Database read retries enabled (max %d attempts)", cfg.DBReadRetryMaxAttempts)
+	}
+
+	if cfg.DBSchemaCheck {
+		migrator, err := newMigrator(db)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load migrations: %w", err)
+		}
+		if err := migrator.Verify(context.Background()); err != nil {
+			return nil, fmt.Errorf("refusing to start: %w (run \"hub-user-service migrate up\")", err)
+		}
+		log.Printf("✅ Database schema at expected version %d", migrator.Latest())
+	}
+
+	return db, nil
+}
+
+// newMemoryLoginRepository creates the in-memory repository, seeded from DB_MEMORY_SEED_FILE when set
+func newMemoryLoginRepository(cfg *config.Config) (repository.ILoginRepository, error) {
+	if cfg.DBMemorySeedFile == "" {
+		log.Println("⚠️  In-memory database is empty, set DB_MEMORY_SEED_FILE to seed users")
+		return persistence.NewMemoryLoginRepository(), nil
+	}
+
+	repo, err := persistence.LoadMemoryLoginRepository(cfg.DBMemorySeedFile)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("✅ In-memory database seeded with %d users from %s", repo.Len(), cfg.DBMemorySeedFile)
+	return repo, nil
+}
+
+// newSQLiteLoginRepository opens DB_SQLITE_PATH and upserts the users of DB_MEMORY_SEED_FILE when set
+func newSQLiteLoginRepository(cfg *config.Config) (repository.ILoginRepository, error) {
+	db, err := database.NewConnectionFactory(database.NewConnectionConfig(cfg)).CreateConnection()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	log.Println("✅ SQLite database opened")
+
+	if cfg.DBInstrumentationEnabled {
+		db = database.NewInstrumentedDatabase(db, database.InstrumentationOptions{
+			SlowQueryThreshold: cfg.DBSlowQueryThreshold,
+			System:             "sqlite",
+		})
+		log.Printf("📈 Query instrumentation enabled (slow query threshold %s)", cfg.DBSlowQueryThreshold)
+	}
+
+	if cfg.DBMemorySeedFile != "" {
+		users, err := persistence.LoadSeedUsers(cfg.DBMemorySeedFile)
+		if err != nil {
+			db.Close()
+			return nil, err
+		}
+		// Like migrate-users, the upsert keeps the status and public id already stored
+		userRepo := persistence.NewUserRepository(db)
+		for _, user := range users {
+			if err := userRepo.UpsertUser(context.Background(), user); err != nil {
+				db.Close()
+				return nil, err
+			}
+		}
+		log.Printf("✅ SQLite database seeded with %d users from %s", len(users), cfg.DBMemorySeedFile)
+	}
+	return persistence.NewLoginRepository(db), nil
+}
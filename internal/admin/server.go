@@ -0,0 +1,159 @@
+package admin
+
+import (
+	"context"
+	"crypto/subtle"
+	"expvar"
+	"log"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"strings"
+	"time"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/grpc"
+	channelzservice "google.golang.org/grpc/channelz/service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	reflectionv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionv1alpha "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+)
+
+// Server is the admin listener: gRPC reflection and channelz plus the pprof and expvar
+// HTTP endpoints, served together on a single port separate from the public API.
+//
+// gRPC and HTTP/1.1 share the port through h2c: requests with an application/grpc
+// content type go to the admin gRPC server, everything else to the debug HTTP mux.
+type Server struct {
+	grpcServer *grpc.Server
+	httpServer *http.Server
+	token      string
+}
+
+// NewServer creates the admin server
+// publicServices is the public gRPC server whose services are advertised through reflection.
+// When token is not empty every request must carry "authorization: Bearer <token>".
+func NewServer(addr string, publicServices reflection.ServiceInfoProvider, token string) *Server {
+	s := &Server{token: token}
+
+	s.grpcServer = grpc.NewServer(
+		grpc.ChainUnaryInterceptor(s.unaryAuth),
+		grpc.ChainStreamInterceptor(s.streamAuth),
+	)
+	reflectionOptions := reflection.ServerOptions{Services: publicServices}
+	reflectionv1.RegisterServerReflectionServer(s.grpcServer, reflection.NewServerV1(reflectionOptions))
+	reflectionv1alpha.RegisterServerReflectionServer(s.grpcServer, reflection.NewServer(reflectionOptions))
+	channelzservice.RegisterChannelzServiceToServer(s.grpcServer)
+
+	s.httpServer = &http.Server{
+		Addr:              addr,
+		Handler:           h2c.NewHandler(s.route(s.debugMux()), &http2.Server{}),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	return s
+}
+
+// debugMux returns the HTTP handlers for pprof and expvar
+func (s *Server) debugMux() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+	return mux
+}
+
+// route dispatches gRPC requests to the admin gRPC server and guards the debug handlers
+func (s *Server) route(debug http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+			s.grpcServer.ServeHTTP(w, r)
+			return
+		}
+
+		if !s.authorized(r.Header.Get("Authorization")) {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		debug.ServeHTTP(w, r)
+	})
+}
+
+// ListenAndServe starts serving on the configured address (blocking call)
+func (s *Server) ListenAndServe() error {
+	listener, err := net.Listen("tcp", s.httpServer.Addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(listener)
+}
+
+// Serve serves admin requests on listener (blocking call)
+func (s *Server) Serve(listener net.Listener) error {
+	log.Printf("🔧 Admin listener (reflection, channelz, pprof, expvar) on %s", listener.Addr())
+	err := s.httpServer.Serve(listener)
+	if err == http.ErrServerClosed {
+		return nil
+	}
+	return err
+}
+
+// Shutdown stops the admin listener
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.grpcServer.Stop()
+	return s.httpServer.Shutdown(ctx)
+}
+
+// authorized checks an "Authorization: Bearer <token>" value against the admin token
+func (s *Server) authorized(header string) bool {
+	if s.token == "" {
+		return true
+	}
+
+	const prefix = "bearer "
+	if len(header) < len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return false
+	}
+
+	presented := strings.TrimSpace(header[len(prefix):])
+	return subtle.ConstantTimeCompare([]byte(presented), []byte(s.token)) == 1
+}
+
+// authorizeContext checks the admin token carried in gRPC metadata
+func (s *Server) authorizeContext(ctx context.Context) error {
+	var header string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			header = values[0]
+		}
+	}
+
+	if !s.authorized(header) {
+		return status.Error(codes.Unauthenticated, "admin token required")
+	}
+	return nil
+}
+
+// unaryAuth guards unary admin RPCs (channelz)
+func (s *Server) unaryAuth(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := s.authorizeContext(ctx); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// streamAuth guards streaming admin RPCs (reflection)
+func (s *Server) streamAuth(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := s.authorizeContext(ss.Context()); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
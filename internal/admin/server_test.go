@@ -0,0 +1,133 @@
+package admin
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"hub-user-service/internal/grpc/proto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	channelzpb "google.golang.org/grpc/channelz/grpc_channelz_v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	reflectionv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+)
+
+// startAdminServer serves an admin server on a random local port
+func startAdminServer(t *testing.T, token string) string {
+	t.Helper()
+
+	public := grpc.NewServer()
+	proto.RegisterAuthServiceServer(public, proto.UnimplementedAuthServiceServer{})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := NewServer(listener.Addr().String(), public, token)
+	go server.Serve(listener)
+	t.Cleanup(func() { server.Shutdown(context.Background()) })
+
+	return listener.Addr().String()
+}
+
+func httpGet(t *testing.T, url, authorization string) int {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	return resp.StatusCode
+}
+
+func dialAdmin(t *testing.T, addr string) *grpc.ClientConn {
+	t.Helper()
+
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func listServices(ctx context.Context, conn *grpc.ClientConn) ([]string, error) {
+	stream, err := reflectionv1.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = stream.Send(&reflectionv1.ServerReflectionRequest{
+		MessageRequest: &reflectionv1.ServerReflectionRequest_ListServices{},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, service := range resp.GetListServicesResponse().GetService() {
+		names = append(names, service.Name)
+	}
+	return names, nil
+}
+
+func TestServer_DebugEndpointsWithoutToken(t *testing.T) {
+	addr := startAdminServer(t, "")
+
+	assert.Equal(t, http.StatusOK, httpGet(t, "http://"+addr+"/debug/vars", ""))
+	assert.Equal(t, http.StatusOK, httpGet(t, "http://"+addr+"/debug/pprof/", ""))
+}
+
+func TestServer_DebugEndpointsRequireToken(t *testing.T) {
+	addr := startAdminServer(t, "admin-secret")
+
+	assert.Equal(t, http.StatusUnauthorized, httpGet(t, "http://"+addr+"/debug/vars", ""))
+	assert.Equal(t, http.StatusUnauthorized, httpGet(t, "http://"+addr+"/debug/vars", "Bearer wrong"))
+	assert.Equal(t, http.StatusOK, httpGet(t, "http://"+addr+"/debug/vars", "Bearer admin-secret"))
+}
+
+func TestServer_ReflectionAdvertisesPublicServices(t *testing.T) {
+	addr := startAdminServer(t, "")
+	conn := dialAdmin(t, addr)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	services, err := listServices(ctx, conn)
+
+	require.NoError(t, err)
+	assert.Contains(t, services, "hub_investments.AuthService")
+}
+
+func TestServer_GRPCRequiresToken(t *testing.T) {
+	addr := startAdminServer(t, "admin-secret")
+	conn := dialAdmin(t, addr)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := listServices(ctx, conn)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	authorized := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer admin-secret")
+	_, err = listServices(authorized, conn)
+	assert.NoError(t, err)
+
+	_, err = channelzpb.NewChannelzClient(conn).GetServers(authorized, &channelzpb.GetServersRequest{})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,65 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"time"
+)
+
+// Action identifies a security relevant action kept in the audit trail
+type Action string
+
+const (
+	ActionMFAEnabled               Action = "mfa.enabled"
+	ActionRecoveryCodeUsed         Action = "mfa.recovery_code_used"
+	ActionRecoveryCodesRegenerated Action = "mfa.recovery_codes_regenerated"
+	ActionPasskeyRegistered        Action = "passkey.registered"
+	ActionPasskeyCloneSuspected    Action = "passkey.clone_suspected"
+)
+
+// Entry is a single audit record
+type Entry struct {
+	Action     Action            `json:"action"`
+	UserID     string            `json:"user_id"`
+	OccurredAt time.Time         `json:"occurred_at"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// Recorder appends entries to the audit trail
+type Recorder interface {
+	Record(ctx context.Context, entry Entry) error
+}
+
+// NopRecorder discards every entry
+type NopRecorder struct{}
+
+// Record implements Recorder
+func (NopRecorder) Record(ctx context.Context, entry Entry) error {
+	return nil
+}
+
+// LogRecorder writes one JSON line per entry, for the log pipeline to ship to the audit store
+type LogRecorder struct {
+	logger *log.Logger
+}
+
+// NewLogRecorder creates a recorder writing to w
+func NewLogRecorder(w io.Writer) *LogRecorder {
+	return &LogRecorder{logger: log.New(w, "📝 AUDIT ", log.LstdFlags)}
+}
+
+// Record implements Recorder
+func (r *LogRecorder) Record(ctx context.Context, entry Entry) error {
+	if entry.OccurredAt.IsZero() {
+		entry.OccurredAt = time.Now().UTC()
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+	r.logger.Println(string(line))
+	return nil
+}
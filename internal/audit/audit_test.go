@@ -0,0 +1,38 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogRecorder_Record(t *testing.T) {
+	var out bytes.Buffer
+	recorder := NewLogRecorder(&out)
+
+	err := recorder.Record(context.Background(), Entry{
+		Action:     ActionRecoveryCodeUsed,
+		UserID:     "42",
+		Attributes: map[string]string{"remaining": "9"},
+	})
+	require.NoError(t, err)
+
+	line := out.String()
+	assert.True(t, strings.HasPrefix(line, "📝 AUDIT "))
+	var entry Entry
+	require.NoError(t, json.Unmarshal([]byte(line[strings.Index(line, "{"):]), &entry))
+	assert.Equal(t, ActionRecoveryCodeUsed, entry.Action)
+	assert.Equal(t, "42", entry.UserID)
+	assert.Equal(t, "9", entry.Attributes["remaining"])
+	assert.WithinDuration(t, time.Now(), entry.OccurredAt, time.Minute)
+}
+
+func TestNopRecorder(t *testing.T) {
+	assert.NoError(t, NopRecorder{}.Record(context.Background(), Entry{Action: ActionMFAEnabled}))
+}
@@ -5,11 +5,27 @@ import (
 	"fmt"
 	"hub-user-service/internal/auth/token"
 	"net/http"
+	"time"
 )
 
 type IAuthService interface {
 	VerifyToken(tokenString string, w http.ResponseWriter) (string, error)
 	CreateToken(userName string, userId string) (string, error)
+	CreateAuthenticatedToken(userName string, userId string, authn token.Authentication) (string, error)
+	CreateStepUpToken(userName string, userId string, authn token.Authentication) (string, error)
+	VerifyAccessToken(tokenString string) (*Identity, error)
+	VerifyRefreshableToken(tokenString string) (*Identity, error)
+	CreateMFAChallenge(userName string, userId string, methods []string) (string, error)
+	VerifyMFAChallenge(challenge string) (*Identity, error)
+}
+
+// Identity is the user a verified token was issued to
+type Identity struct {
+	UserID   string
+	UserName string
+	// Authentication is how the user authenticated; a challenge only carries the first factor methods
+	Authentication token.Authentication
+	ExpiresAt      time.Time
 }
 
 type AuthService struct {
@@ -42,3 +58,70 @@ func (s *AuthService) VerifyToken(tokenString string, w http.ResponseWriter) (st
 func (s *AuthService) CreateToken(userName string, userId string) (string, error) {
 	return s.tokenService.CreateAndSignToken(userName, userId)
 }
+
+// CreateAuthenticatedToken issues the access token of a login completed with the given methods
+func (s *AuthService) CreateAuthenticatedToken(userName string, userId string, authn token.Authentication) (string, error) {
+	return s.tokenService.CreateAccessToken(userName, userId, authn)
+}
+
+// CreateStepUpToken issues the short-lived token of a fresh authentication for sensitive operations
+func (s *AuthService) CreateStepUpToken(userName string, userId string, authn token.Authentication) (string, error) {
+	return s.tokenService.CreateStepUpToken(userName, userId, authn)
+}
+
+// VerifyAccessToken validates a "Bearer " access token and returns its user
+func (s *AuthService) VerifyAccessToken(tokenString string) (*Identity, error) {
+	if tokenString == "" {
+		return nil, errors.New("missing access token")
+	}
+
+	claims, err := s.tokenService.ValidateToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	return identityFromClaims(claims)
+}
+
+// VerifyRefreshableToken validates a "Bearer " access token bound to a session, expired or not
+func (s *AuthService) VerifyRefreshableToken(tokenString string) (*Identity, error) {
+	if tokenString == "" {
+		return nil, errors.New("missing access token")
+	}
+
+	claims, err := s.tokenService.ValidateRefreshableToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	return identityFromClaims(claims)
+}
+
+// CreateMFAChallenge issues the token proving the first factor of a login
+func (s *AuthService) CreateMFAChallenge(userName string, userId string, methods []string) (string, error) {
+	return s.tokenService.CreateMFAChallengeToken(userName, userId, methods)
+}
+
+// VerifyMFAChallenge validates a challenge token and returns the user it was issued to
+func (s *AuthService) VerifyMFAChallenge(challenge string) (*Identity, error) {
+	if challenge == "" {
+		return nil, errors.New("missing MFA challenge token")
+	}
+
+	claims, err := s.tokenService.ValidateMFAChallengeToken(challenge)
+	if err != nil {
+		return nil, err
+	}
+	return identityFromClaims(claims)
+}
+
+func identityFromClaims(claims map[string]interface{}) (*Identity, error) {
+	userId, _ := claims["userId"].(string)
+	if userId == "" {
+		return nil, errors.New("token has no user")
+	}
+	userName, _ := claims["username"].(string)
+	identity := &Identity{UserID: userId, UserName: userName, Authentication: token.AuthenticationFromClaims(claims)}
+	if exp, ok := claims["exp"].(float64); ok {
+		identity.ExpiresAt = time.Unix(int64(exp), 0)
+	}
+	return identity, nil
+}
@@ -3,9 +3,11 @@ package auth_test
 import (
 	"errors"
 	"hub-user-service/internal/auth"
+	"hub-user-service/internal/auth/token"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -20,11 +22,38 @@ func (m *MockTokenService) ValidateToken(tokenString string) (map[string]interfa
 	return args.Get(0).(map[string]interface{}), args.Error(1)
 }
 
+func (m *MockTokenService) ValidateRefreshableToken(tokenString string) (map[string]interface{}, error) {
+	args := m.Called(tokenString)
+	claims, _ := args.Get(0).(map[string]interface{})
+	return claims, args.Error(1)
+}
+
 func (m *MockTokenService) CreateAndSignToken(userName string, userId string) (string, error) {
 	args := m.Called(userName, userId)
 	return args.String(0), args.Error(1)
 }
 
+func (m *MockTokenService) CreateAccessToken(userName string, userId string, authn token.Authentication) (string, error) {
+	args := m.Called(userName, userId, authn)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockTokenService) CreateStepUpToken(userName string, userId string, authn token.Authentication) (string, error) {
+	args := m.Called(userName, userId, authn)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockTokenService) CreateMFAChallengeToken(userName string, userId string, methods []string) (string, error) {
+	args := m.Called(userName, userId, methods)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockTokenService) ValidateMFAChallengeToken(tokenString string) (map[string]interface{}, error) {
+	args := m.Called(tokenString)
+	claims, _ := args.Get(0).(map[string]interface{})
+	return claims, args.Error(1)
+}
+
 func TestNewAuthService(t *testing.T) {
 	tokenService := &MockTokenService{}
 	authService := auth.NewAuthService(tokenService)
@@ -270,3 +299,99 @@ func TestAuth_IntegrationScenarios(t *testing.T) {
 		tokenService.AssertExpectations(t)
 	})
 }
+
+func TestVerifyAccessToken(t *testing.T) {
+	tokenService := &MockTokenService{}
+	tokenService.On("ValidateToken", "Bearer valid").Return(
+		map[string]interface{}{"userId": "user123", "username": "test@example.com"}, nil)
+	tokenService.On("ValidateToken", "Bearer anonymous").Return(map[string]interface{}{}, nil)
+	authService := auth.NewAuthService(tokenService)
+
+	identity, err := authService.VerifyAccessToken("Bearer valid")
+	assert.NoError(t, err)
+	assert.Equal(t, &auth.Identity{UserID: "user123", UserName: "test@example.com"}, identity)
+
+	_, err = authService.VerifyAccessToken("Bearer anonymous")
+	assert.Error(t, err)
+
+	_, err = authService.VerifyAccessToken("")
+	assert.Error(t, err)
+}
+
+func TestVerifyRefreshableToken(t *testing.T) {
+	tokenService := &MockTokenService{}
+	tokenService.On("ValidateRefreshableToken", "Bearer expired").Return(map[string]interface{}{
+		"userId":   "user123",
+		"username": "test@example.com",
+		"exp":      float64(1800000600),
+		"sid":      "s1",
+	}, nil)
+	tokenService.On("ValidateRefreshableToken", "Bearer monolith").Return(nil, errors.New("token is not bound to a session"))
+	authService := auth.NewAuthService(tokenService)
+
+	identity, err := authService.VerifyRefreshableToken("Bearer expired")
+	assert.NoError(t, err)
+	assert.Equal(t, "s1", identity.Authentication.SessionID)
+	assert.Equal(t, time.Unix(1800000600, 0), identity.ExpiresAt)
+
+	_, err = authService.VerifyRefreshableToken("Bearer monolith")
+	assert.Error(t, err)
+
+	_, err = authService.VerifyRefreshableToken("")
+	assert.Error(t, err)
+}
+
+func TestVerifyAccessToken_ReadsAuthentication(t *testing.T) {
+	tokenService := &MockTokenService{}
+	tokenService.On("ValidateToken", "Bearer strong").Return(map[string]interface{}{
+		"userId":    "user123",
+		"username":  "test@example.com",
+		"exp":       float64(1800000600),
+		"auth_time": float64(1800000000),
+		"amr":       []interface{}{"pwd", "otp", "mfa"},
+		"acr":       "aal2",
+	}, nil)
+	authService := auth.NewAuthService(tokenService)
+
+	identity, err := authService.VerifyAccessToken("Bearer strong")
+
+	assert.NoError(t, err)
+	assert.Equal(t, token.Authentication{
+		Methods: []string{"pwd", "otp", "mfa"},
+		Level:   token.LevelMultiFactor,
+		Time:    time.Unix(1800000000, 0),
+	}, identity.Authentication)
+	assert.Equal(t, time.Unix(1800000600, 0), identity.ExpiresAt)
+}
+
+func TestCreateAuthenticatedToken(t *testing.T) {
+	tokenService := &MockTokenService{}
+	authn := token.PasswordAuthentication(time.Unix(1800000000, 0))
+	tokenService.On("CreateAccessToken", "testuser", "user123", authn).Return("access", nil)
+	tokenService.On("CreateStepUpToken", "testuser", "user123", authn).Return("elevated", nil)
+	authService := auth.NewAuthService(tokenService)
+
+	access, err := authService.CreateAuthenticatedToken("testuser", "user123", authn)
+	assert.NoError(t, err)
+	assert.Equal(t, "access", access)
+
+	elevated, err := authService.CreateStepUpToken("testuser", "user123", authn)
+	assert.NoError(t, err)
+	assert.Equal(t, "elevated", elevated)
+	tokenService.AssertExpectations(t)
+}
+
+func TestVerifyMFAChallenge(t *testing.T) {
+	tokenService := &MockTokenService{}
+	tokenService.On("ValidateMFAChallengeToken", "challenge").Return(
+		map[string]interface{}{"userId": "user123", "username": "test@example.com"}, nil)
+	tokenService.On("ValidateMFAChallengeToken", "expired").Return(nil, errors.New("token is expired"))
+	authService := auth.NewAuthService(tokenService)
+
+	identity, err := authService.VerifyMFAChallenge("challenge")
+	assert.NoError(t, err)
+	assert.Equal(t, "user123", identity.UserID)
+
+	_, err = authService.VerifyMFAChallenge("expired")
+	assert.Error(t, err)
+}
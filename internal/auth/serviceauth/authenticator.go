@@ -0,0 +1,132 @@
+package serviceauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// Audience is the value service tokens must carry in the "aud" claim
+const Audience = "hub-user-service"
+
+// MaxTokenLifetime bounds exp-iat so a leaked service token is only briefly useful
+const MaxTokenLifetime = 5 * time.Minute
+
+var (
+	ErrMissingToken  = errors.New("missing service token")
+	ErrUnknownClient = errors.New("unknown service client")
+	ErrInvalidToken  = errors.New("invalid service token")
+)
+
+// Authenticator verifies signed service JWTs against the client registry
+//
+// Internal callers sign a short-lived HS256 JWT with their own secret:
+//
+//	iss = sub = <client id>, aud = "hub-user-service", iat, exp (at most 5 minutes after iat)
+type Authenticator struct {
+	registry *Registry
+	now      func() time.Time
+}
+
+// NewAuthenticator creates a new service token authenticator
+func NewAuthenticator(registry *Registry) *Authenticator {
+	return &Authenticator{registry: registry, now: time.Now}
+}
+
+// Authenticate validates tokenString and returns the calling client
+func (a *Authenticator) Authenticate(tokenString string) (*Client, error) {
+	if tokenString == "" {
+		return nil, ErrMissingToken
+	}
+
+	var client *Client
+	parser := jwt.Parser{SkipClaimsValidation: true}
+	token, err := parser.ParseWithClaims(tokenString, &jwt.StandardClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+
+		claims := token.Claims.(*jwt.StandardClaims)
+		found, ok := a.registry.Lookup(claims.Issuer)
+		if !ok {
+			return nil, ErrUnknownClient
+		}
+		client = found
+
+		return []byte(found.Secret), nil
+	})
+	if err != nil {
+		// jwt v3 wraps key lookup errors without implementing Unwrap
+		if validationErr, ok := err.(*jwt.ValidationError); ok && validationErr.Inner == ErrUnknownClient {
+			return nil, ErrUnknownClient
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	if !token.Valid {
+		return nil, ErrInvalidToken
+	}
+
+	if err := a.validateClaims(token.Claims.(*jwt.StandardClaims)); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	return client, nil
+}
+
+// validateClaims checks audience, subject and the token time window
+func (a *Authenticator) validateClaims(claims *jwt.StandardClaims) error {
+	now := a.now().Unix()
+
+	if !claims.VerifyAudience(Audience, true) {
+		return errors.New("invalid audience")
+	}
+	if claims.Subject != "" && claims.Subject != claims.Issuer {
+		return errors.New("subject does not match issuer")
+	}
+	if claims.IssuedAt == 0 || claims.ExpiresAt == 0 {
+		return errors.New("iat and exp are required")
+	}
+	if !claims.VerifyExpiresAt(now, true) {
+		return errors.New("token is expired")
+	}
+	if !claims.VerifyIssuedAt(now+int64(time.Minute.Seconds()), true) {
+		return errors.New("token used before issued")
+	}
+	if claims.ExpiresAt-claims.IssuedAt > int64(MaxTokenLifetime.Seconds()) {
+		return fmt.Errorf("token lifetime exceeds %s", MaxTokenLifetime)
+	}
+
+	return nil
+}
+
+// SignToken creates a service token for clientID; used by internal callers and tests
+func SignToken(clientID, secret string, ttl time.Duration) (string, error) {
+	now := time.Now()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.StandardClaims{
+		Issuer:    clientID,
+		Subject:   clientID,
+		Audience:  Audience,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(ttl).Unix(),
+	})
+
+	return token.SignedString([]byte(secret))
+}
+
+type clientContextKey struct{}
+
+// NewContext returns a context carrying the authenticated service client
+func NewContext(ctx context.Context, client *Client) context.Context {
+	return context.WithValue(ctx, clientContextKey{}, client)
+}
+
+// FromContext returns the authenticated service client stored in ctx, if any
+func FromContext(ctx context.Context) (*Client, bool) {
+	client, ok := ctx.Value(clientContextKey{}).(*Client)
+	return client, ok
+}
@@ -0,0 +1,105 @@
+package serviceauth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAuthenticator(t *testing.T) *Authenticator {
+	registry, err := NewRegistry([]Client{
+		{ID: "order-service", Secret: "order-secret", AllowedMethods: []string{"*"}},
+	})
+	require.NoError(t, err)
+	return NewAuthenticator(registry)
+}
+
+func signClaims(t *testing.T, claims jwt.StandardClaims, secret string) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	require.NoError(t, err)
+	return token
+}
+
+func TestAuthenticator_Authenticate_Success(t *testing.T) {
+	authenticator := newTestAuthenticator(t)
+	token, err := SignToken("order-service", "order-secret", time.Minute)
+	require.NoError(t, err)
+
+	client, err := authenticator.Authenticate(token)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "order-service", client.ID)
+}
+
+func TestAuthenticator_Authenticate_Failures(t *testing.T) {
+	authenticator := newTestAuthenticator(t)
+	now := time.Now()
+
+	tests := []struct {
+		name  string
+		token string
+		want  error
+	}{
+		{"missing token", "", ErrMissingToken},
+		{"garbage", "not-a-jwt", ErrInvalidToken},
+		{"unknown client", signClaims(t, jwt.StandardClaims{
+			Issuer: "unknown", Audience: Audience, IssuedAt: now.Unix(), ExpiresAt: now.Add(time.Minute).Unix(),
+		}, "order-secret"), ErrUnknownClient},
+		{"wrong secret", signClaims(t, jwt.StandardClaims{
+			Issuer: "order-service", Audience: Audience, IssuedAt: now.Unix(), ExpiresAt: now.Add(time.Minute).Unix(),
+		}, "other-secret"), ErrInvalidToken},
+		{"wrong audience", signClaims(t, jwt.StandardClaims{
+			Issuer: "order-service", Audience: "monolith", IssuedAt: now.Unix(), ExpiresAt: now.Add(time.Minute).Unix(),
+		}, "order-secret"), ErrInvalidToken},
+		{"expired", signClaims(t, jwt.StandardClaims{
+			Issuer: "order-service", Audience: Audience, IssuedAt: now.Add(-2 * time.Minute).Unix(), ExpiresAt: now.Add(-time.Minute).Unix(),
+		}, "order-secret"), ErrInvalidToken},
+		{"lifetime too long", signClaims(t, jwt.StandardClaims{
+			Issuer: "order-service", Audience: Audience, IssuedAt: now.Unix(), ExpiresAt: now.Add(time.Hour).Unix(),
+		}, "order-secret"), ErrInvalidToken},
+		{"subject mismatch", signClaims(t, jwt.StandardClaims{
+			Issuer: "order-service", Subject: "monolith", Audience: Audience, IssuedAt: now.Unix(), ExpiresAt: now.Add(time.Minute).Unix(),
+		}, "order-secret"), ErrInvalidToken},
+		{"missing iat", signClaims(t, jwt.StandardClaims{
+			Issuer: "order-service", Audience: Audience, ExpiresAt: now.Add(time.Minute).Unix(),
+		}, "order-secret"), ErrInvalidToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := authenticator.Authenticate(tt.token)
+			assert.Nil(t, client)
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
+
+func TestAuthenticator_RejectsNonHMACAlgorithm(t *testing.T) {
+	authenticator := newTestAuthenticator(t)
+	now := time.Now()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.StandardClaims{
+		Issuer: "order-service", Audience: Audience, IssuedAt: now.Unix(), ExpiresAt: now.Add(time.Minute).Unix(),
+	}).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+
+	_, err = authenticator.Authenticate(token)
+
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestContext_RoundTrip(t *testing.T) {
+	client := &Client{ID: "monolith"}
+
+	ctx := NewContext(context.Background(), client)
+	got, ok := FromContext(ctx)
+
+	assert.True(t, ok)
+	assert.Same(t, client, got)
+
+	_, ok = FromContext(context.Background())
+	assert.False(t, ok)
+}
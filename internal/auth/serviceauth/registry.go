@@ -0,0 +1,132 @@
+package serviceauth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"hub-user-service/internal/ratelimit"
+)
+
+// RateLimit is the per-client quota applied to every RPC the client invokes
+type RateLimit struct {
+	RequestsPerSecond float64 `json:"requests_per_second"`
+	Burst             int     `json:"burst"`
+}
+
+// Client describes an internal caller (monolith, order service, portfolio service, ...)
+type Client struct {
+	ID string `json:"id"`
+
+	// Secret is the HMAC key the client signs its service tokens with.
+	// SecretEnv names an environment variable holding the secret and takes precedence,
+	// so the registry file itself can be committed without credentials.
+	Secret    string `json:"secret,omitempty"`
+	SecretEnv string `json:"secret_env,omitempty"`
+
+	// AllowedMethods lists full gRPC method names the client may invoke.
+	// "/package.Service/*" allows every method of a service and "*" allows everything.
+	AllowedMethods []string  `json:"allowed_methods"`
+	RateLimit      RateLimit `json:"rate_limit"`
+
+	// Gateway marks a client relaying end user calls: the end client IP address and type it
+	// forwards in metadata (x-forwarded-for, x-client-type) are trusted
+	Gateway bool `json:"gateway,omitempty"`
+	// ClientType, when set, is the session client type (web, mobile or api) of every login made
+	// through this client, whatever it forwards
+	ClientType string `json:"client_type,omitempty"`
+}
+
+// clientTypes are the valid Client.ClientType values
+var clientTypes = map[string]bool{"web": true, "mobile": true, "api": true}
+
+// CanInvoke checks whether the client is allowed to call the given full gRPC method
+func (c *Client) CanInvoke(fullMethod string) bool {
+	for _, allowed := range c.AllowedMethods {
+		if allowed == "*" || allowed == fullMethod {
+			return true
+		}
+		if strings.HasSuffix(allowed, "/*") && strings.HasPrefix(fullMethod, strings.TrimSuffix(allowed, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+// Limit converts the client quota into a rate limiter limit
+func (c *Client) Limit() ratelimit.Limit {
+	return ratelimit.Limit{Rate: c.RateLimit.RequestsPerSecond, Burst: c.RateLimit.Burst}
+}
+
+// registryFile is the on-disk representation of the client registry
+type registryFile struct {
+	Clients []Client `json:"clients"`
+}
+
+// Registry holds the known internal callers indexed by client id
+type Registry struct {
+	clients map[string]*Client
+}
+
+// NewRegistry validates the given clients and builds a registry
+func NewRegistry(clients []Client) (*Registry, error) {
+	registry := &Registry{clients: make(map[string]*Client, len(clients))}
+
+	for i := range clients {
+		client := clients[i]
+
+		if client.ID == "" {
+			return nil, errors.New("service client id is required")
+		}
+		if _, exists := registry.clients[client.ID]; exists {
+			return nil, fmt.Errorf("duplicate service client id: %s", client.ID)
+		}
+
+		if client.SecretEnv != "" {
+			client.Secret = os.Getenv(client.SecretEnv)
+		}
+		if client.Secret == "" {
+			return nil, fmt.Errorf("service client %s has no secret configured", client.ID)
+		}
+
+		if len(client.AllowedMethods) == 0 {
+			return nil, fmt.Errorf("service client %s has no allowed methods", client.ID)
+		}
+
+		if client.ClientType != "" && !clientTypes[client.ClientType] {
+			return nil, fmt.Errorf("service client %s has an invalid client type %q, expected web, mobile or api", client.ID, client.ClientType)
+		}
+
+		registry.clients[client.ID] = &client
+	}
+
+	return registry, nil
+}
+
+// LoadRegistry reads a JSON client registry from path
+func LoadRegistry(path string) (*Registry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read service clients file: %w", err)
+	}
+
+	var file registryFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse service clients file: %w", err)
+	}
+
+	return NewRegistry(file.Clients)
+}
+
+// Lookup returns the client registered under id
+func (r *Registry) Lookup(id string) (*Client, bool) {
+	client, ok := r.clients[id]
+	return client, ok
+}
+
+// Len returns the number of registered clients
+func (r *Registry) Len() int {
+	return len(r.clients)
+}
@@ -0,0 +1,113 @@
+package serviceauth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRegistry_Valid(t *testing.T) {
+	registry, err := NewRegistry([]Client{
+		{ID: "monolith", Secret: "monolith-secret", AllowedMethods: []string{"*"}},
+		{ID: "order-service", Secret: "order-secret", AllowedMethods: []string{"/hub_investments.AuthService/ValidateToken"}},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, registry.Len())
+
+	client, ok := registry.Lookup("order-service")
+	assert.True(t, ok)
+	assert.Equal(t, "order-secret", client.Secret)
+
+	_, ok = registry.Lookup("unknown")
+	assert.False(t, ok)
+}
+
+func TestNewRegistry_SecretFromEnvironment(t *testing.T) {
+	os.Setenv("TEST_PORTFOLIO_SECRET", "env-secret")
+	defer os.Unsetenv("TEST_PORTFOLIO_SECRET")
+
+	registry, err := NewRegistry([]Client{
+		{ID: "portfolio-service", Secret: "file-secret", SecretEnv: "TEST_PORTFOLIO_SECRET", AllowedMethods: []string{"*"}},
+	})
+
+	require.NoError(t, err)
+	client, _ := registry.Lookup("portfolio-service")
+	assert.Equal(t, "env-secret", client.Secret)
+}
+
+func TestNewRegistry_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		clients []Client
+		errMsg  string
+	}{
+		{"missing id", []Client{{Secret: "s", AllowedMethods: []string{"*"}}}, "id is required"},
+		{"missing secret", []Client{{ID: "a", AllowedMethods: []string{"*"}}}, "no secret"},
+		{"empty env secret", []Client{{ID: "a", SecretEnv: "TEST_UNSET_SECRET", AllowedMethods: []string{"*"}}}, "no secret"},
+		{"no methods", []Client{{ID: "a", Secret: "s"}}, "no allowed methods"},
+		{"invalid client type", []Client{{ID: "a", Secret: "s", AllowedMethods: []string{"*"}, ClientType: "desktop"}}, "invalid client type"},
+		{"duplicate", []Client{
+			{ID: "a", Secret: "s", AllowedMethods: []string{"*"}},
+			{ID: "a", Secret: "s", AllowedMethods: []string{"*"}},
+		}, "duplicate"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRegistry(tt.clients)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestLoadRegistry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "service_clients.json")
+	content := `{
+		"clients": [
+			{
+				"id": "monolith",
+				"secret": "monolith-secret",
+				"allowed_methods": ["/hub_investments.AuthService/*"],
+				"rate_limit": {"requests_per_second": 100, "burst": 200},
+				"gateway": true,
+				"client_type": "web"
+			}
+		]
+	}`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	registry, err := LoadRegistry(path)
+
+	require.NoError(t, err)
+	client, ok := registry.Lookup("monolith")
+	require.True(t, ok)
+	assert.Equal(t, float64(100), client.Limit().Rate)
+	assert.Equal(t, 200, client.Limit().Burst)
+	assert.True(t, client.Gateway)
+	assert.Equal(t, "web", client.ClientType)
+}
+
+func TestLoadRegistry_MissingFile(t *testing.T) {
+	_, err := LoadRegistry(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestClient_CanInvoke(t *testing.T) {
+	client := &Client{AllowedMethods: []string{
+		"/hub_investments.AuthService/ValidateToken",
+		"/hub_investments.UserService/*",
+	}}
+
+	assert.True(t, client.CanInvoke("/hub_investments.AuthService/ValidateToken"))
+	assert.False(t, client.CanInvoke("/hub_investments.AuthService/Login"))
+	assert.True(t, client.CanInvoke("/hub_investments.UserService/GetUser"))
+	assert.False(t, client.CanInvoke("/hub_investments.UserServiceV2/GetUser"))
+
+	wildcard := &Client{AllowedMethods: []string{"*"}}
+	assert.True(t, wildcard.CanInvoke("/anything/Method"))
+}
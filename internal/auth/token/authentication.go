@@ -0,0 +1,108 @@
+package token
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Authentication methods carried in the amr claim (RFC 8176)
+const (
+	MethodPassword    = "pwd"
+	MethodOTP         = "otp"
+	MethodHardwareKey = "hwk"
+	MethodMultiFactor = "mfa"
+	// MethodEmailCode is a code or magic link sent by email (not registered in RFC 8176)
+	MethodEmailCode = "email"
+)
+
+// Assurance levels carried in the acr claim, after NIST SP 800-63B authenticator assurance levels
+const (
+	// LevelSingleFactor is a password, an emailed code or a passkey without user verification
+	LevelSingleFactor = "aal1"
+	// LevelMultiFactor is a password with a second factor or a user verified passkey
+	LevelMultiFactor = "aal2"
+)
+
+// levels orders the assurance levels; tokens without an acr claim (issued by the monolith) rank below all of them
+var levels = map[string]int{LevelSingleFactor: 1, LevelMultiFactor: 2}
+
+var (
+	// ErrUnknownLevel is returned when a caller requires an assurance level this service does not issue
+	ErrUnknownLevel = errors.New("unknown assurance level")
+	// ErrInsufficientLevel is returned when the token was issued for a weaker authentication
+	ErrInsufficientLevel = errors.New("authentication assurance level too low")
+	// ErrAuthenticationTooOld is returned when the user authenticated longer ago than allowed
+	ErrAuthenticationTooOld = errors.New("authentication too old")
+)
+
+// Authentication describes how and when the user proved their identity
+type Authentication struct {
+	// Methods is the amr claim
+	Methods []string
+	// Level is the acr claim
+	Level string
+	// Time is the auth_time claim
+	Time time.Time
+	// SessionID is the sid claim, the login session the token belongs to; empty for tokens
+	// issued without session tracking
+	SessionID string
+}
+
+// PasswordAuthentication is a login with the password alone
+func PasswordAuthentication(at time.Time) Authentication {
+	return Authentication{Methods: []string{MethodPassword}, Level: LevelSingleFactor, Time: at}
+}
+
+// WithSecondFactor adds a verified second factor to a first factor authentication
+func (a Authentication) WithSecondFactor(method string, at time.Time) Authentication {
+	methods := append(append([]string{}, a.Methods...), method, MethodMultiFactor)
+	return Authentication{Methods: methods, Level: LevelMultiFactor, Time: at, SessionID: a.SessionID}
+}
+
+// Require checks the authentication meets the assurance level and happened within maxAge;
+// an empty level or a zero maxAge is not checked
+func (a Authentication) Require(level string, maxAge time.Duration, now time.Time) error {
+	if level != "" {
+		required, ok := levels[level]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownLevel, level)
+		}
+		if levels[a.Level] < required {
+			return ErrInsufficientLevel
+		}
+	}
+	if maxAge > 0 && (a.Time.IsZero() || now.Sub(a.Time) > maxAge) {
+		return ErrAuthenticationTooOld
+	}
+	return nil
+}
+
+// claims adds the authentication claims to a token
+func (a Authentication) claims(claims map[string]interface{}) {
+	claims["auth_time"] = a.Time.Unix()
+	claims["amr"] = a.Methods
+	claims["acr"] = a.Level
+	if a.SessionID != "" {
+		claims["sid"] = a.SessionID
+	}
+}
+
+// AuthenticationFromClaims reads the authentication claims of a validated token; tokens issued by
+// the monolith carry none of them
+func AuthenticationFromClaims(claims map[string]interface{}) Authentication {
+	var authn Authentication
+	if authTime, ok := claims["auth_time"].(float64); ok {
+		authn.Time = time.Unix(int64(authTime), 0)
+	}
+	if methods, ok := claims["amr"].([]interface{}); ok {
+		for _, method := range methods {
+			if method, ok := method.(string); ok {
+				authn.Methods = append(authn.Methods, method)
+			}
+		}
+	}
+	authn.Level, _ = claims["acr"].(string)
+	authn.SessionID, _ = claims["sid"].(string)
+	return authn
+}
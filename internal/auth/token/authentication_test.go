@@ -0,0 +1,84 @@
+package token
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthentication_WithSecondFactor(t *testing.T) {
+	at := time.Unix(1800000000, 0)
+	first := Authentication{Methods: []string{MethodHardwareKey}, Level: LevelSingleFactor, Time: at.Add(-time.Minute)}
+
+	authn := first.WithSecondFactor(MethodOTP, at)
+
+	assert.Equal(t, []string{MethodHardwareKey, MethodOTP, MethodMultiFactor}, authn.Methods)
+	assert.Equal(t, LevelMultiFactor, authn.Level)
+	assert.Equal(t, at, authn.Time)
+	assert.Equal(t, []string{MethodHardwareKey}, first.Methods, "the first factor is not modified")
+}
+
+func TestAuthentication_Require(t *testing.T) {
+	now := time.Unix(1800000000, 0)
+	password := PasswordAuthentication(now.Add(-10 * time.Minute))
+	mfa := password.WithSecondFactor(MethodOTP, now.Add(-time.Minute))
+	monolith := Authentication{}
+
+	tests := []struct {
+		name   string
+		authn  Authentication
+		level  string
+		maxAge time.Duration
+		err    error
+	}{
+		{"no requirement", monolith, "", 0, nil},
+		{"level met", mfa, LevelSingleFactor, 0, nil},
+		{"level exactly met", mfa, LevelMultiFactor, 0, nil},
+		{"level too low", password, LevelMultiFactor, 0, ErrInsufficientLevel},
+		{"monolith tokens have no level", monolith, LevelSingleFactor, 0, ErrInsufficientLevel},
+		{"unknown level", mfa, "aal3", 0, ErrUnknownLevel},
+		{"recent enough", mfa, "", 5 * time.Minute, nil},
+		{"too old", password, "", 5 * time.Minute, ErrAuthenticationTooOld},
+		{"monolith tokens have no auth_time", monolith, "", time.Hour, ErrAuthenticationTooOld},
+		{"both met", mfa, LevelMultiFactor, 5 * time.Minute, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.authn.Require(tt.level, tt.maxAge, now)
+			if tt.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestAuthenticationFromClaims(t *testing.T) {
+	authn := AuthenticationFromClaims(map[string]interface{}{
+		"auth_time": float64(1800000000),
+		"amr":       []interface{}{"pwd", 7},
+		"acr":       "aal1",
+	})
+	assert.Equal(t, Authentication{Methods: []string{"pwd"}, Level: LevelSingleFactor, Time: time.Unix(1800000000, 0)}, authn)
+
+	assert.Equal(t, Authentication{}, AuthenticationFromClaims(map[string]interface{}{"userId": "42"}))
+}
+
+func TestAuthentication_SessionClaim(t *testing.T) {
+	at := time.Unix(1800000000, 0)
+	authn := PasswordAuthentication(at)
+	authn.SessionID = "s1"
+
+	claims := map[string]interface{}{}
+	authn.claims(claims)
+	assert.Equal(t, "s1", claims["sid"])
+	assert.Equal(t, "s1", authn.WithSecondFactor(MethodOTP, at).SessionID, "a second factor keeps the session")
+
+	withoutSession := map[string]interface{}{}
+	PasswordAuthentication(at).claims(withoutSession)
+	assert.NotContains(t, withoutSession, "sid")
+
+	assert.Equal(t, "s1", AuthenticationFromClaims(map[string]interface{}{"sid": "s1"}).SessionID)
+}
@@ -3,6 +3,7 @@ package token
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"hub-user-service/internal/config"
@@ -12,9 +13,21 @@ import (
 
 type ITokenService interface {
 	CreateAndSignToken(userName string, userId string) (string, error)
+	CreateAccessToken(userName string, userId string, authn Authentication) (string, error)
+	CreateStepUpToken(userName string, userId string, authn Authentication) (string, error)
 	ValidateToken(tokenString string) (map[string]interface{}, error)
+	ValidateRefreshableToken(tokenString string) (map[string]interface{}, error)
+	CreateMFAChallengeToken(userName string, userId string, methods []string) (string, error)
+	ValidateMFAChallengeToken(tokenString string) (map[string]interface{}, error)
 }
 
+// tokenUseClaim distinguishes special purpose tokens from access tokens, which do not carry it
+// (monolith tokens must keep validating)
+const tokenUseClaim = "token_use"
+
+// TokenUseMFAChallenge marks the short-lived token Login returns when a second factor is required
+const TokenUseMFAChallenge = "mfa_challenge"
+
 type TokenService struct{}
 
 type TokenClaims map[string]interface{}
@@ -23,15 +36,33 @@ func NewTokenService() ITokenService {
 	return &TokenService{}
 }
 
+// CreateAndSignToken signs an access token with the monolith's claims only (username, userId, exp)
 func (s *TokenService) CreateAndSignToken(userName string, userId string) (string, error) {
+	return signAccessToken(userName, userId, nil, time.Minute*10) //token expiration time = 10 min
+}
+
+// CreateAccessToken signs an access token carrying how the user authenticated (auth_time, amr, acr)
+func (s *TokenService) CreateAccessToken(userName string, userId string, authn Authentication) (string, error) {
+	return signAccessToken(userName, userId, &authn, time.Minute*10)
+}
+
+// CreateStepUpToken signs an access token for a fresh authentication, valid for STEP_UP_TOKEN_TTL
+func (s *TokenService) CreateStepUpToken(userName string, userId string, authn Authentication) (string, error) {
+	return signAccessToken(userName, userId, &authn, config.Get().StepUpTokenTTL)
+}
+
+func signAccessToken(userName string, userId string, authn *Authentication, ttl time.Duration) (string, error) {
 	cfg := config.Get()
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256,
-		jwt.MapClaims{
-			"username": userName,
-			"userId":   userId,
-			"exp":      time.Now().Add(time.Minute * 10).Unix(), //token expiration time = 10 min
-		})
+	claims := jwt.MapClaims{
+		"username": userName,
+		"userId":   userId,
+		"exp":      time.Now().Add(ttl).Unix(),
+	}
+	if authn != nil {
+		authn.claims(claims)
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
 	tokenString, err := token.SignedString([]byte(cfg.JWTSecret))
 
@@ -55,16 +86,91 @@ func (s *TokenService) ValidateToken(tokenString string) (map[string]interface{}
 		return nil, err
 	}
 
+	// A challenge token only proves the password, it must not be accepted as an access token
+	if _, ok := claims[tokenUseClaim]; ok {
+		return nil, errors.New("not an access token")
+	}
+
 	bla := TokenClaims(claims)
 
 	return bla, nil
 }
 
+// ValidateRefreshableToken validates an access token of a login session, accepting it after it
+// expired; the session's idle and absolute timeouts bound how long it can be refreshed
+func (s *TokenService) ValidateRefreshableToken(tokenString string) (map[string]interface{}, error) {
+	token, err := s.parseToken(tokenString)
+	var validationErr *jwt.ValidationError
+	if err != nil && !(errors.As(err, &validationErr) && validationErr.Errors == jwt.ValidationErrorExpired) {
+		return nil, err
+	}
+
+	// The expired token is not Valid, but its signature was verified
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, errors.New("invalid claims")
+	}
+	if _, ok := claims[tokenUseClaim]; ok {
+		return nil, errors.New("not an access token")
+	}
+	if sid, _ := claims["sid"].(string); sid == "" {
+		return nil, errors.New("token is not bound to a session")
+	}
+
+	return TokenClaims(claims), nil
+}
+
+// CreateMFAChallengeToken signs a token proving the first factor of a login (methods), valid for MFA_CHALLENGE_TTL
+func (s *TokenService) CreateMFAChallengeToken(userName string, userId string, methods []string) (string, error) {
+	cfg := config.Get()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256,
+		jwt.MapClaims{
+			"username":    userName,
+			"userId":      userId,
+			"amr":         methods,
+			tokenUseClaim: TokenUseMFAChallenge,
+			"exp":         time.Now().Add(cfg.MFAChallengeTTL).Unix(),
+		})
+
+	return token.SignedString([]byte(cfg.JWTSecret))
+}
+
+// ValidateMFAChallengeToken validates a challenge token (sent without the "Bearer " prefix)
+func (s *TokenService) ValidateMFAChallengeToken(tokenString string) (map[string]interface{}, error) {
+	token, err := parseSignedToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := validateToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims[tokenUseClaim] != TokenUseMFAChallenge {
+		return nil, errors.New("not an MFA challenge token")
+	}
+
+	return TokenClaims(claims), nil
+}
+
 func (s *TokenService) parseToken(token string) (*jwt.Token, error) {
-	token = token[len("Bearer "):]
+	// The scheme is case-insensitive, like the monolith's check
+	if len(token) < len("Bearer ") || !strings.EqualFold(token[:len("Bearer ")], "Bearer ") {
+		return nil, errors.New("authorization must use the Bearer scheme")
+	}
+	return parseSignedToken(token[len("Bearer "):])
+}
+
+// parseSignedToken parses a raw JWT signed with the shared HS256 secret
+func parseSignedToken(token string) (*jwt.Token, error) {
 	cfg := config.Get()
 
 	jwtToken, err := jwt.Parse(token, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+		}
 		return []byte(cfg.JWTSecret), nil
 	})
 
@@ -126,3 +126,105 @@ func TestTokenService_ValidateToken_WrongKey(t *testing.T) {
 	assert.Error(t, err)
 	assert.Nil(t, claims)
 }
+
+func TestTokenService_ValidateToken_RequiresBearerScheme(t *testing.T) {
+	service := NewTokenService()
+	token, err := service.CreateAndSignToken("testuser", "user123")
+	assert.NoError(t, err)
+
+	_, err = service.ValidateToken(token)
+	assert.Error(t, err)
+
+	_, err = service.ValidateToken("abc")
+	assert.Error(t, err)
+}
+
+func TestTokenService_AccessTokenCarriesAuthentication(t *testing.T) {
+	service := NewTokenService()
+	at := time.Now().Add(-time.Minute).Truncate(time.Second)
+
+	access, err := service.CreateAccessToken("testuser", "user123", PasswordAuthentication(at).WithSecondFactor(MethodOTP, at))
+	assert.NoError(t, err)
+
+	claims, err := service.ValidateToken("Bearer " + access)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(at.Unix()), claims["auth_time"])
+	assert.Equal(t, []interface{}{"pwd", "otp", "mfa"}, claims["amr"])
+	assert.Equal(t, LevelMultiFactor, claims["acr"])
+	assert.InDelta(t, time.Now().Add(10*time.Minute).Unix(), int64(claims["exp"].(float64)), 5)
+
+	// Tokens signed with the monolith's claims carry no authentication and meet no requirement
+	legacy, err := service.CreateAndSignToken("testuser", "user123")
+	assert.NoError(t, err)
+	claims, err = service.ValidateToken("Bearer " + legacy)
+	assert.NoError(t, err)
+	assert.Equal(t, Authentication{}, AuthenticationFromClaims(claims))
+}
+
+func TestTokenService_StepUpTokenIsShortLived(t *testing.T) {
+	service := NewTokenService()
+
+	elevated, err := service.CreateStepUpToken("testuser", "user123", PasswordAuthentication(time.Now()))
+	assert.NoError(t, err)
+
+	claims, err := service.ValidateToken("Bearer " + elevated)
+	assert.NoError(t, err)
+	assert.InDelta(t, time.Now().Add(config.Get().StepUpTokenTTL).Unix(), int64(claims["exp"].(float64)), 5)
+}
+
+func TestTokenService_MFAChallengeToken(t *testing.T) {
+	service := NewTokenService()
+
+	challenge, err := service.CreateMFAChallengeToken("testuser", "user123", []string{MethodPassword})
+	assert.NoError(t, err)
+
+	claims, err := service.ValidateMFAChallengeToken(challenge)
+	assert.NoError(t, err)
+	assert.Equal(t, "user123", claims["userId"])
+	assert.Equal(t, TokenUseMFAChallenge, claims["token_use"])
+	assert.Equal(t, []interface{}{"pwd"}, claims["amr"])
+
+	exp := int64(claims["exp"].(float64))
+	assert.InDelta(t, time.Now().Add(config.Get().MFAChallengeTTL).Unix(), exp, 5)
+}
+
+func TestTokenService_MFAChallengeTokenIsNotAnAccessToken(t *testing.T) {
+	service := NewTokenService()
+
+	challenge, err := service.CreateMFAChallengeToken("testuser", "user123", []string{MethodPassword})
+	assert.NoError(t, err)
+	_, err = service.ValidateToken("Bearer " + challenge)
+	assert.Error(t, err)
+
+	access, err := service.CreateAndSignToken("testuser", "user123")
+	assert.NoError(t, err)
+	_, err = service.ValidateMFAChallengeToken(access)
+	assert.Error(t, err)
+}
+
+func TestTokenService_ValidateRefreshableToken(t *testing.T) {
+	service := NewTokenService()
+	secret := []byte(config.Get().JWTSecret)
+	sign := func(claims jwt.MapClaims, key []byte) string {
+		signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(key)
+		assert.NoError(t, err)
+		return "Bearer " + signed
+	}
+	expired := time.Now().Add(-time.Hour).Unix()
+
+	claims, err := service.ValidateRefreshableToken(sign(jwt.MapClaims{"userId": "user123", "sid": "s1", "exp": expired}, secret))
+	assert.NoError(t, err)
+	assert.Equal(t, "s1", claims["sid"])
+
+	_, err = service.ValidateRefreshableToken(sign(jwt.MapClaims{"userId": "user123", "exp": expired}, secret))
+	assert.EqualError(t, err, "token is not bound to a session")
+
+	_, err = service.ValidateRefreshableToken(sign(jwt.MapClaims{"userId": "user123", "sid": "s1", "exp": expired}, []byte("another-secret")))
+	assert.Error(t, err, "the signature is checked on expired tokens")
+
+	_, err = service.ValidateRefreshableToken(sign(jwt.MapClaims{"userId": "user123", "sid": "s1", "token_use": TokenUseMFAChallenge, "exp": expired}, secret))
+	assert.Error(t, err)
+
+	_, err = service.ValidateRefreshableToken("not-a-bearer-token")
+	assert.Error(t, err)
+}
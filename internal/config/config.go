@@ -1,10 +1,15 @@
 package config
 
 import (
+	"encoding/base64"
 	"fmt"
 	"log"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -15,22 +20,159 @@ type Config struct {
 	HTTPPort string
 	GRPCPort string
 
+	// GRPCRequestTimeout bounds unary calls that arrive without a client deadline
+	GRPCRequestTimeout time.Duration
+
+	// gRPC Server Options
+	GRPCKeepaliveTime                time.Duration // ping an idle client after this long
+	GRPCKeepaliveTimeout             time.Duration // close the connection if the ping is not acknowledged
+	GRPCKeepaliveMinTime             time.Duration // minimum client ping interval (enforcement policy)
+	GRPCKeepalivePermitWithoutStream bool          // allow client pings with no active streams
+	GRPCMaxConnectionIdle            time.Duration // close connections idle for this long (0 = never)
+	GRPCMaxConnectionAge             time.Duration // recycle connections so load balancers can rebalance (0 = never)
+	GRPCMaxConnectionAgeGrace        time.Duration // time for in-flight RPCs after MaxConnectionAge
+	GRPCMaxRecvMsgSize               int
+	GRPCMaxSendMsgSize               int
+	GRPCMaxConcurrentStreams         int
+	GRPCConnectionTimeout            time.Duration // deadline for the connection handshake
+
 	// JWT Configuration (MUST match monolith for token compatibility)
-	JWTSecret string
+	JWTSecret      string
+	StepUpTokenTTL time.Duration // lifetime of the elevated token returned by StepUp
 
 	// Database Configuration
-	DatabaseURL string
-	DBHost      string
-	DBPort      string
-	DBName      string
-	DBUser      string
-	DBPassword  string
-	DBSSLMode   string
-
-	// Redis Configuration (optional for caching)
+	// DBDriver selects the storage: "postgres", or "sqlite" or "memory" (tests and local development only)
+	DBDriver         string
+	DBMemorySeedFile string
+	DBSQLitePath     string // database file for the sqlite driver, ":memory:" for a private in-memory database
+	// DatabaseURL (postgres:// URL or key=value DSN) takes precedence over the individual DB_* fields
+	DatabaseURL       string
+	DBHost            string
+	DBPort            string
+	DBName            string
+	DBUser            string
+	DBPassword        string
+	DBSSLMode         string
+	DBSSLRootCert     string
+	DBConnectTimeout  time.Duration
+	DBApplicationName string
+
+	// Startup connection backoff
+	DBConnectMaxAttempts    int
+	DBConnectInitialBackoff time.Duration
+	DBConnectMaxBackoff     time.Duration
+
+	// Opt-in retries of idempotent reads on transient errors
+	DBReadRetryEnabled        bool
+	DBReadRetryMaxAttempts    int
+	DBReadRetryInitialBackoff time.Duration
+	DBReadRetryMaxBackoff     time.Duration
+
+	// Query instrumentation: latency histograms, error counts, slow query log and tracing spans
+	DBInstrumentationEnabled bool
+	DBSlowQueryThreshold     time.Duration
+
+	// Read replicas: comma-separated URLs or DSNs
+	DBReplicaURLs                []string
+	DBReplicaHealthCheckInterval time.Duration
+
+	// Database Connection Pool
+	DBMaxOpenConns    int
+	DBMaxIdleConns    int
+	DBConnMaxLifetime time.Duration
+	DBConnMaxIdleTime time.Duration
+
+	// Refuse to start unless the schema is at the version embedded in the binary
+	DBSchemaCheck bool
+
+	// Redis Configuration (rate limiting backend)
 	RedisHost string
 	RedisPort string
 
+	// Rate Limiting (token buckets per client IP, email or service client)
+	RateLimitEnabled   bool
+	RateLimitBackend   string // memory (per replica) or redis (shared, at REDIS_HOST:REDIS_PORT)
+	RateLimitQuotaList string // RATE_LIMIT_QUOTAS, parsed by RateLimitQuotas
+
+	// Service-to-service Authentication (internal callers)
+	ServiceAuthEnabled bool
+	ServiceClientsFile string
+
+	// TrustedProxies lists the networks (CIDR or single IPs) of the gateways whose forwarded client
+	// metadata is trusted, besides the service clients registered as gateways
+	TrustedProxies []string
+
+	// Admin Listener (reflection, channelz, pprof, expvar)
+	AdminEnabled bool
+	AdminPort    string
+	AdminToken   string
+
+	// Multi-factor Authentication
+	MFAEncryptionKey string        // base64 AES-256 key encrypting TOTP secrets; MFA is unavailable when empty
+	MFAIssuer        string        // issuer shown by authenticator apps
+	MFAChallengeTTL  time.Duration // lifetime of the challenge token returned by Login
+	MFATOTPSkew      int           // accepted time steps before and after the current one
+	MFAMaxAttempts   int           // codes checked per user within MFAChallengeTTL (brute force protection)
+	MFARecoveryCodes int           // one-time recovery codes generated per user
+
+	// WebAuthn passkeys
+	WebAuthnEnabled                 bool
+	WebAuthnRPID                    string        // registrable domain of the web app
+	WebAuthnRPDisplayName           string        // name shown by the browser during ceremonies
+	WebAuthnRPOrigins               []string      // origins allowed to run ceremonies
+	WebAuthnAttestation             string        // conveyance preference: none, indirect, direct or enterprise
+	WebAuthnAttestationFormats      []string      // accepted attestation formats, empty accepts any
+	WebAuthnRequireUserVerification bool          // require PIN or biometrics on every ceremony
+	WebAuthnCeremonyTimeout         time.Duration // time allowed between the begin and finish calls
+
+	// Passwordless login with emailed codes
+	LoginCodeEnabled       bool
+	LoginCodeDelivery      string        // code (6 digits typed into the login form) or link (magic link)
+	LoginCodeLinkURL       string        // web app page receiving magic links
+	LoginCodeTTL           time.Duration // lifetime of a code
+	LoginCodeMaxRequests   int           // codes sent per email within LoginCodeRequestWindow
+	LoginCodeRequestWindow time.Duration
+	LoginCodeMaxAttempts   int // codes checked per email within LoginCodeTTL, and wrong guesses before a code is deleted
+
+	// Sessions
+	SessionLastSeenInterval time.Duration // how often a session's last seen time is written on token validation
+	// Idle (no activity) and absolute (since login) session timeouts per client type (x-client-type)
+	SessionWebIdleTimeout        time.Duration
+	SessionWebAbsoluteTimeout    time.Duration
+	SessionMobileIdleTimeout     time.Duration
+	SessionMobileAbsoluteTimeout time.Duration
+	SessionAPIIdleTimeout        time.Duration
+	SessionAPIAbsoluteTimeout    time.Duration
+
+	// Login history
+	GeoIPDatabaseFile            string // offline GeoIP CSV (network,country,region,city[,latitude,longitude]); empty records no location
+	NewDeviceNotificationEnabled bool   // email users when a login succeeds from a device not seen before
+
+	// Risk-based authentication
+	RiskEnabled        bool
+	RiskRulesFile      string        // JSON risk policy, see risk_rules.example.json
+	RiskBlocklistFile  string        // blocked IP addresses and networks, one per line (e.g. Tor exit nodes)
+	RiskReloadInterval time.Duration // how often the rules and blocklist files are checked for changes
+
+	// Notifications (emails to users)
+	NotificationSender string // log (development only) or smtp
+	SMTPHost           string
+	SMTPPort           string
+	SMTPUsername       string
+	SMTPPassword       string
+	SMTPFrom           string
+
+	// User Events (WatchUserEvents stream)
+	UserEventsBackend     string // memory (per replica) or redis (shared stream at REDIS_HOST:REDIS_PORT)
+	UserEventsHistorySize int
+	UserEventsBufferSize  int
+
+	// Opt-in OpenTelemetry tracing of gRPC calls and database queries, exported over OTLP/gRPC
+	TracingEnabled     bool
+	TracingEndpoint    string  // host:port of the OTLP collector
+	TracingInsecure    bool    // plaintext connection to the collector, e.g. a local agent
+	TracingSampleRatio float64 // share of new traces sampled; calls in a sampled trace are always sampled
+
 	// Environment
 	Environment string
 }
@@ -56,22 +198,146 @@ func Load() *Config {
 			HTTPPort: getEnvWithDefault("HTTP_PORT", "localhost:8080"),
 			GRPCPort: getEnvWithDefault("GRPC_PORT", "localhost:50051"),
 
+			GRPCRequestTimeout: getEnvDurationWithDefault("GRPC_REQUEST_TIMEOUT", 10*time.Second),
+
+			// gRPC Server Options
+			GRPCKeepaliveTime:                getEnvDurationWithDefault("GRPC_KEEPALIVE_TIME", 2*time.Hour),
+			GRPCKeepaliveTimeout:             getEnvDurationWithDefault("GRPC_KEEPALIVE_TIMEOUT", 20*time.Second),
+			GRPCKeepaliveMinTime:             getEnvDurationWithDefault("GRPC_KEEPALIVE_MIN_TIME", 30*time.Second),
+			GRPCKeepalivePermitWithoutStream: getEnvBoolWithDefault("GRPC_KEEPALIVE_PERMIT_WITHOUT_STREAM", true),
+			GRPCMaxConnectionIdle:            getEnvDurationWithDefault("GRPC_MAX_CONNECTION_IDLE", 0),
+			GRPCMaxConnectionAge:             getEnvDurationWithDefault("GRPC_MAX_CONNECTION_AGE", 30*time.Minute),
+			GRPCMaxConnectionAgeGrace:        getEnvDurationWithDefault("GRPC_MAX_CONNECTION_AGE_GRACE", 5*time.Minute),
+			GRPCMaxRecvMsgSize:               getEnvIntWithDefault("GRPC_MAX_RECV_MSG_SIZE", 4*1024*1024),
+			GRPCMaxSendMsgSize:               getEnvIntWithDefault("GRPC_MAX_SEND_MSG_SIZE", 4*1024*1024),
+			GRPCMaxConcurrentStreams:         getEnvIntWithDefault("GRPC_MAX_CONCURRENT_STREAMS", 1000),
+			GRPCConnectionTimeout:            getEnvDurationWithDefault("GRPC_CONNECTION_TIMEOUT", 20*time.Second),
+
 			// JWT Configuration (MUST match monolith)
-			JWTSecret: getEnvWithDefault("MY_JWT_SECRET", "default-secret-key-change-in-production"),
+			JWTSecret:      getEnvWithDefault("MY_JWT_SECRET", "default-secret-key-change-in-production"),
+			StepUpTokenTTL: getEnvDurationWithDefault("STEP_UP_TOKEN_TTL", 5*time.Minute),
 
 			// Database Configuration
-			DatabaseURL: getEnvWithDefault("DATABASE_URL", ""),
-			DBHost:      getEnvWithDefault("DB_HOST", "localhost"),
-			DBPort:      getEnvWithDefault("DB_PORT", "5432"),
-			DBName:      getEnvWithDefault("DB_NAME", "hub_investments"),
-			DBUser:      getEnvWithDefault("DB_USER", "postgres"),
-			DBPassword:  getEnvWithDefault("DB_PASSWORD", "postgres"),
-			DBSSLMode:   getEnvWithDefault("DB_SSLMODE", "disable"),
+			DBDriver:          getEnvWithDefault("DB_DRIVER", "postgres"),
+			DBMemorySeedFile:  getEnvWithDefault("DB_MEMORY_SEED_FILE", ""),
+			DBSQLitePath:      getEnvWithDefault("DB_SQLITE_PATH", "hub_users.db"),
+			DatabaseURL:       getEnvWithDefault("DATABASE_URL", ""),
+			DBHost:            getEnvWithDefault("DB_HOST", "localhost"),
+			DBPort:            getEnvWithDefault("DB_PORT", "5432"),
+			DBName:            getEnvWithDefault("DB_NAME", "hub_investments"),
+			DBUser:            getEnvWithDefault("DB_USER", "postgres"),
+			DBPassword:        getEnvWithDefault("DB_PASSWORD", "postgres"),
+			DBSSLMode:         getEnvWithDefault("DB_SSLMODE", "disable"),
+			DBSSLRootCert:     getEnvWithDefault("DB_SSLROOTCERT", ""),
+			DBConnectTimeout:  getEnvDurationWithDefault("DB_CONNECT_TIMEOUT", 10*time.Second),
+			DBApplicationName: getEnvWithDefault("DB_APPLICATION_NAME", "hub-user-service"),
+
+			DBConnectMaxAttempts:    getEnvIntWithDefault("DB_CONNECT_MAX_ATTEMPTS", 10),
+			DBConnectInitialBackoff: getEnvDurationWithDefault("DB_CONNECT_INITIAL_BACKOFF", 500*time.Millisecond),
+			DBConnectMaxBackoff:     getEnvDurationWithDefault("DB_CONNECT_MAX_BACKOFF", 10*time.Second),
+
+			DBReadRetryEnabled:        getEnvBoolWithDefault("DB_READ_RETRY_ENABLED", false),
+			DBReadRetryMaxAttempts:    getEnvIntWithDefault("DB_READ_RETRY_MAX_ATTEMPTS", 3),
+			DBReadRetryInitialBackoff: getEnvDurationWithDefault("DB_READ_RETRY_INITIAL_BACKOFF", 50*time.Millisecond),
+			DBReadRetryMaxBackoff:     getEnvDurationWithDefault("DB_READ_RETRY_MAX_BACKOFF", time.Second),
+
+			DBInstrumentationEnabled: getEnvBoolWithDefault("DB_INSTRUMENTATION_ENABLED", false),
+			DBSlowQueryThreshold:     getEnvDurationWithDefault("DB_SLOW_QUERY_THRESHOLD", 200*time.Millisecond),
+
+			DBReplicaURLs:                getEnvListWithDefault("DB_REPLICA_URLS", nil),
+			DBReplicaHealthCheckInterval: getEnvDurationWithDefault("DB_REPLICA_HEALTH_CHECK_INTERVAL", 5*time.Second),
+
+			// Database Connection Pool
+			DBMaxOpenConns:    getEnvIntWithDefault("DB_MAX_OPEN_CONNS", 25),
+			DBMaxIdleConns:    getEnvIntWithDefault("DB_MAX_IDLE_CONNS", 10),
+			DBConnMaxLifetime: getEnvDurationWithDefault("DB_CONN_MAX_LIFETIME", 30*time.Minute),
+			DBConnMaxIdleTime: getEnvDurationWithDefault("DB_CONN_MAX_IDLE_TIME", 5*time.Minute),
+			DBSchemaCheck:     getEnvBoolWithDefault("DB_SCHEMA_CHECK", false),
 
 			// Redis Configuration (optional)
 			RedisHost: getEnvWithDefault("REDIS_HOST", "localhost"),
 			RedisPort: getEnvWithDefault("REDIS_PORT", "6379"),
 
+			// Rate Limiting
+			RateLimitEnabled:   getEnvBoolWithDefault("RATE_LIMIT_ENABLED", true),
+			RateLimitBackend:   getEnvWithDefault("RATE_LIMIT_BACKEND", "memory"),
+			RateLimitQuotaList: getEnvWithDefault("RATE_LIMIT_QUOTAS", defaultRateLimitQuotas),
+
+			// Service-to-service Authentication
+			ServiceAuthEnabled: getEnvBoolWithDefault("SERVICE_AUTH_ENABLED", false),
+			ServiceClientsFile: getEnvWithDefault("SERVICE_CLIENTS_FILE", "service_clients.json"),
+			TrustedProxies:     getEnvListWithDefault("TRUSTED_PROXIES", nil),
+
+			// Admin Listener
+			AdminEnabled: getEnvBoolWithDefault("ADMIN_ENABLED", true),
+			AdminPort:    getEnvWithDefault("ADMIN_PORT", "localhost:6060"),
+			AdminToken:   getEnvWithDefault("ADMIN_TOKEN", ""),
+
+			// Multi-factor Authentication
+			MFAEncryptionKey: getEnvWithDefault("MFA_ENCRYPTION_KEY", ""),
+			MFAIssuer:        getEnvWithDefault("MFA_ISSUER", "Hub Investments"),
+			MFAChallengeTTL:  getEnvDurationWithDefault("MFA_CHALLENGE_TTL", 5*time.Minute),
+			MFATOTPSkew:      getEnvIntWithDefault("MFA_TOTP_SKEW", 1),
+			MFAMaxAttempts:   getEnvIntWithDefault("MFA_MAX_ATTEMPTS", 5),
+			MFARecoveryCodes: getEnvIntWithDefault("MFA_RECOVERY_CODES", 10),
+
+			// WebAuthn passkeys
+			WebAuthnEnabled:                 getEnvBoolWithDefault("WEBAUTHN_ENABLED", false),
+			WebAuthnRPID:                    getEnvWithDefault("WEBAUTHN_RP_ID", "localhost"),
+			WebAuthnRPDisplayName:           getEnvWithDefault("WEBAUTHN_RP_DISPLAY_NAME", "Hub Investments"),
+			WebAuthnRPOrigins:               getEnvListWithDefault("WEBAUTHN_RP_ORIGINS", []string{"http://localhost:3000"}),
+			WebAuthnAttestation:             getEnvWithDefault("WEBAUTHN_ATTESTATION", "none"),
+			WebAuthnAttestationFormats:      getEnvListWithDefault("WEBAUTHN_ATTESTATION_FORMATS", nil),
+			WebAuthnRequireUserVerification: getEnvBoolWithDefault("WEBAUTHN_REQUIRE_USER_VERIFICATION", true),
+			WebAuthnCeremonyTimeout:         getEnvDurationWithDefault("WEBAUTHN_CEREMONY_TIMEOUT", 5*time.Minute),
+
+			// Passwordless login
+			LoginCodeEnabled:       getEnvBoolWithDefault("LOGIN_CODE_ENABLED", false),
+			LoginCodeDelivery:      getEnvWithDefault("LOGIN_CODE_DELIVERY", "code"),
+			LoginCodeLinkURL:       getEnvWithDefault("LOGIN_CODE_LINK_URL", "http://localhost:3000/login/code"),
+			LoginCodeTTL:           getEnvDurationWithDefault("LOGIN_CODE_TTL", 10*time.Minute),
+			LoginCodeMaxRequests:   getEnvIntWithDefault("LOGIN_CODE_MAX_REQUESTS", 5),
+			LoginCodeRequestWindow: getEnvDurationWithDefault("LOGIN_CODE_REQUEST_WINDOW", time.Hour),
+			LoginCodeMaxAttempts:   getEnvIntWithDefault("LOGIN_CODE_MAX_ATTEMPTS", 5),
+
+			// Sessions
+			SessionLastSeenInterval:      getEnvDurationWithDefault("SESSION_LAST_SEEN_INTERVAL", time.Minute),
+			SessionWebIdleTimeout:        getEnvDurationWithDefault("SESSION_WEB_IDLE_TIMEOUT", 15*time.Minute),
+			SessionWebAbsoluteTimeout:    getEnvDurationWithDefault("SESSION_WEB_ABSOLUTE_TIMEOUT", 12*time.Hour),
+			SessionMobileIdleTimeout:     getEnvDurationWithDefault("SESSION_MOBILE_IDLE_TIMEOUT", 7*24*time.Hour),
+			SessionMobileAbsoluteTimeout: getEnvDurationWithDefault("SESSION_MOBILE_ABSOLUTE_TIMEOUT", 30*24*time.Hour),
+			SessionAPIIdleTimeout:        getEnvDurationWithDefault("SESSION_API_IDLE_TIMEOUT", time.Hour),
+			SessionAPIAbsoluteTimeout:    getEnvDurationWithDefault("SESSION_API_ABSOLUTE_TIMEOUT", 24*time.Hour),
+
+			// Login history
+			GeoIPDatabaseFile:            getEnvWithDefault("GEOIP_DATABASE_FILE", ""),
+			NewDeviceNotificationEnabled: getEnvBoolWithDefault("NEW_DEVICE_NOTIFICATION_ENABLED", true),
+
+			// Risk-based authentication
+			RiskEnabled:        getEnvBoolWithDefault("RISK_ENABLED", false),
+			RiskRulesFile:      getEnvWithDefault("RISK_RULES_FILE", "risk_rules.json"),
+			RiskBlocklistFile:  getEnvWithDefault("RISK_BLOCKLIST_FILE", ""),
+			RiskReloadInterval: getEnvDurationWithDefault("RISK_RELOAD_INTERVAL", 30*time.Second),
+
+			// Notifications
+			NotificationSender: getEnvWithDefault("NOTIFICATION_SENDER", "log"),
+			SMTPHost:           getEnvWithDefault("SMTP_HOST", ""),
+			SMTPPort:           getEnvWithDefault("SMTP_PORT", "587"),
+			SMTPUsername:       getEnvWithDefault("SMTP_USERNAME", ""),
+			SMTPPassword:       getEnvWithDefault("SMTP_PASSWORD", ""),
+			SMTPFrom:           getEnvWithDefault("SMTP_FROM", ""),
+
+			// User Events
+			UserEventsBackend:     getEnvWithDefault("USER_EVENTS_BACKEND", "memory"),
+			UserEventsHistorySize: getEnvIntWithDefault("USER_EVENTS_HISTORY_SIZE", 10000),
+			UserEventsBufferSize:  getEnvIntWithDefault("USER_EVENTS_BUFFER_SIZE", 256),
+
+			// Tracing
+			TracingEnabled:     getEnvBoolWithDefault("TRACING_ENABLED", false),
+			TracingEndpoint:    getEnvWithDefault("TRACING_OTLP_ENDPOINT", "localhost:4317"),
+			TracingInsecure:    getEnvBoolWithDefault("TRACING_OTLP_INSECURE", false),
+			TracingSampleRatio: getEnvFloatWithDefault("TRACING_SAMPLE_RATIO", 1),
+
 			// Environment
 			Environment: getEnvWithDefault("ENVIRONMENT", "development"),
 		}
@@ -82,8 +348,20 @@ func Load() *Config {
 			log.Println("⚠️  WARNING: JWT tokens will NOT be compatible with monolith unless secrets match!")
 		}
 
+		if !instance.ServiceAuthEnabled {
+			log.Println("⚠️  WARNING: Service-to-service authentication is disabled. Any caller can invoke the gRPC API.")
+		}
+
+		if instance.IsProduction() && instance.AdminEnabled && instance.AdminToken == "" {
+			log.Println("⚠️  WARNING: Admin listener disabled in production because ADMIN_TOKEN is not set.")
+		}
+
+		if instance.MFAEncryptionKey == "" {
+			log.Println("⚠️  WARNING: MFA_ENCRYPTION_KEY is not set. TOTP enrollment is unavailable and enrolled users can not log in.")
+		}
+
 		// Validate database configuration
-		if instance.DBHost == "" || instance.DBName == "" {
+		if instance.DatabaseURL == "" && (instance.DBHost == "" || instance.DBName == "") {
 			log.Println("⚠️  WARNING: Database configuration incomplete. Service may not start correctly.")
 		}
 
@@ -92,9 +370,18 @@ func Load() *Config {
 		log.Printf("  Environment: %s", instance.Environment)
 		log.Printf("  HTTP Port: %s", instance.HTTPPort)
 		log.Printf("  gRPC Port: %s", instance.GRPCPort)
-		log.Printf("  Database: %s:%s/%s", instance.DBHost, instance.DBPort, instance.DBName)
+		log.Printf("  Database: %s", instance.DatabaseDescription())
 		log.Printf("  JWT Secret: %s", maskSecret(instance.JWTSecret))
 		log.Printf("  Redis: %s:%s", instance.RedisHost, instance.RedisPort)
+		log.Printf("  Rate Limiting: %t (backend: %s)", instance.RateLimitEnabled, instance.RateLimitBackend)
+		log.Printf("  User Events: %s backend (history: %d)", instance.UserEventsBackend, instance.UserEventsHistorySize)
+		log.Printf("  Service Auth: %t (clients: %s)", instance.ServiceAuthEnabled, instance.ServiceClientsFile)
+		log.Printf("  Admin Listener: %t (%s, token: %s)", instance.AdminListenerEnabled(), instance.AdminPort, maskSecret(instance.AdminToken))
+		log.Printf("  MFA: %t (issuer: %s, key: %s)", instance.MFAEncryptionKey != "", instance.MFAIssuer, maskSecret(instance.MFAEncryptionKey))
+		log.Printf("  Passkeys: %t (rp id: %s, origins: %v)", instance.WebAuthnEnabled, instance.WebAuthnRPID, instance.WebAuthnRPOrigins)
+		log.Printf("  Login Codes: %t (delivery: %s, sender: %s)", instance.LoginCodeEnabled, instance.LoginCodeDelivery, instance.NotificationSender)
+		log.Printf("  New Device Notifications: %t (GeoIP database: %q)", instance.NewDeviceNotificationEnabled, instance.GeoIPDatabaseFile)
+		log.Printf("  Risk Assessment: %t (rules: %s, blocklist: %q)", instance.RiskEnabled, instance.RiskRulesFile, instance.RiskBlocklistFile)
 	})
 
 	return instance
@@ -117,9 +404,94 @@ func getEnvWithDefault(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getEnvListWithDefault gets a comma-separated environment variable, skipping empty items
+func getEnvListWithDefault(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// getEnvBoolWithDefault gets a boolean environment variable with a fallback default value
+func getEnvBoolWithDefault(key string, defaultValue bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("⚠️  WARNING: Invalid boolean for %s=%q, using default %t", key, value, defaultValue)
+		return defaultValue
+	}
+	return parsed
+}
+
+// getEnvIntWithDefault gets an integer environment variable with a fallback default value
+func getEnvIntWithDefault(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("⚠️  WARNING: Invalid integer for %s=%q, using default %d", key, value, defaultValue)
+		return defaultValue
+	}
+	return parsed
+}
+
+// getEnvFloatWithDefault gets a floating point environment variable with a fallback default value
+func getEnvFloatWithDefault(key string, defaultValue float64) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		log.Printf("⚠️  WARNING: Invalid number for %s=%q, using default %g", key, value, defaultValue)
+		return defaultValue
+	}
+	return parsed
+}
+
+// getEnvDurationWithDefault gets a duration environment variable (e.g. "5s", "2m") with a fallback default value
+func getEnvDurationWithDefault(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("⚠️  WARNING: Invalid duration for %s=%q, using default %s", key, value, defaultValue)
+		return defaultValue
+	}
+	return parsed
+}
+
 // IsProduction checks if the application is running in production mode
 func (c *Config) IsProduction() bool {
-	return os.Getenv("ENVIRONMENT") == "production"
+	return c.Environment == "production"
+}
+
+// AdminListenerEnabled reports whether the admin listener should be started
+// Outside production it follows ADMIN_ENABLED; in production it additionally requires ADMIN_TOKEN,
+// so reflection and profiling are never exposed unauthenticated.
+func (c *Config) AdminListenerEnabled() bool {
+	if !c.AdminEnabled {
+		return false
+	}
+	if c.IsProduction() {
+		return c.AdminToken != ""
+	}
+	return true
 }
 
 // GetRedisAddress returns the complete Redis address
@@ -127,17 +499,43 @@ func (c *Config) GetRedisAddress() string {
 	return c.RedisHost + ":" + c.RedisPort
 }
 
-// GetDatabaseConnectionString returns a PostgreSQL connection string
-func (c *Config) GetDatabaseConnectionString() string {
-	if c.DatabaseURL != "" {
-		return c.DatabaseURL
+// DatabaseDescription returns a loggable description of the database target without credentials
+func (c *Config) DatabaseDescription() string {
+	if c.DBDriver == "sqlite" {
+		return "SQLite " + c.DBSQLitePath
+	}
+	if c.DatabaseURL == "" {
+		return c.DBHost + ":" + c.DBPort + "/" + c.DBName
+	}
+
+	parsed, err := url.Parse(c.DatabaseURL)
+	if err != nil || parsed.Host == "" {
+		return "DATABASE_URL (DSN)"
 	}
-	return "host=" + c.DBHost +
-		" port=" + c.DBPort +
-		" user=" + c.DBUser +
-		" password=" + c.DBPassword +
-		" dbname=" + c.DBName +
-		" sslmode=" + c.DBSSLMode
+	return "DATABASE_URL " + parsed.Host + parsed.Path
+}
+
+// validateDatabaseURL checks that a postgres:// DATABASE_URL is well formed
+// key=value DSNs are passed to the driver as-is
+func validateDatabaseURL(databaseURL string) error {
+	if !strings.HasPrefix(databaseURL, "postgres://") && !strings.HasPrefix(databaseURL, "postgresql://") {
+		if !strings.Contains(databaseURL, "=") {
+			return fmt.Errorf("DATABASE_URL must be a postgres:// URL or a key=value DSN")
+		}
+		return nil
+	}
+
+	parsed, err := url.Parse(databaseURL)
+	if err != nil {
+		return fmt.Errorf("invalid DATABASE_URL: %w", err)
+	}
+	if parsed.Host == "" {
+		return fmt.Errorf("DATABASE_URL must include a host")
+	}
+	if strings.TrimPrefix(parsed.Path, "/") == "" {
+		return fmt.Errorf("DATABASE_URL must include a database name")
+	}
+	return nil
 }
 
 // Validate checks if all required configuration is present
@@ -146,22 +544,311 @@ func (c *Config) Validate() error {
 		log.Println("⚠️  WARNING: JWT secret not properly configured!")
 	}
 
-	if c.DBHost == "" {
-		return fmt.Errorf("database host is required (DB_HOST)")
+	// Elevated tokens must not outlive regular access tokens (10 minutes)
+	if c.StepUpTokenTTL <= 0 || c.StepUpTokenTTL > 10*time.Minute {
+		return fmt.Errorf("STEP_UP_TOKEN_TTL must be between 0 and 10m")
+	}
+
+	switch c.DBDriver {
+	case "postgres":
+	case "memory":
+		if c.IsProduction() {
+			return fmt.Errorf("the memory database driver is not allowed in production (DB_DRIVER)")
+		}
+		log.Println("⚠️  WARNING: Using the in-memory database driver, data is lost on restart (DB_DRIVER=memory)")
+	case "sqlite":
+		if c.IsProduction() {
+			return fmt.Errorf("the sqlite database driver is not allowed in production (DB_DRIVER)")
+		}
+		if c.DBSQLitePath == "" {
+			return fmt.Errorf("DB_SQLITE_PATH is required with DB_DRIVER=sqlite")
+		}
+		log.Printf("⚠️  WARNING: Using the SQLite database driver at %s, only users are stored in it (DB_DRIVER=sqlite)", c.DBSQLitePath)
+	default:
+		return fmt.Errorf("unsupported database driver %q, expected postgres, sqlite or memory (DB_DRIVER)", c.DBDriver)
+	}
+
+	if c.DatabaseURL != "" {
+		if err := validateDatabaseURL(c.DatabaseURL); err != nil {
+			return err
+		}
+	} else {
+		if c.DBHost == "" {
+			return fmt.Errorf("database host is required (DB_HOST)")
+		}
+
+		if c.DBName == "" {
+			return fmt.Errorf("database name is required (DB_NAME)")
+		}
+
+		if c.DBUser == "" {
+			return fmt.Errorf("database user is required (DB_USER)")
+		}
+
+		if c.DBPassword == "" {
+			log.Println("⚠️  WARNING: Database password not set (DB_PASSWORD)")
+		}
 	}
 
-	if c.DBName == "" {
-		return fmt.Errorf("database name is required (DB_NAME)")
+	if c.DBConnectMaxAttempts < 1 || c.DBConnectInitialBackoff < 0 || c.DBConnectMaxBackoff < c.DBConnectInitialBackoff {
+		return fmt.Errorf("invalid database connect backoff (DB_CONNECT_MAX_ATTEMPTS >= 1, DB_CONNECT_MAX_BACKOFF >= DB_CONNECT_INITIAL_BACKOFF)")
 	}
 
-	if c.DBUser == "" {
-		return fmt.Errorf("database user is required (DB_USER)")
+	if c.DBReadRetryEnabled && (c.DBReadRetryMaxAttempts < 1 || c.DBReadRetryInitialBackoff < 0 || c.DBReadRetryMaxBackoff < c.DBReadRetryInitialBackoff) {
+		return fmt.Errorf("invalid database read retry (DB_READ_RETRY_MAX_ATTEMPTS >= 1, DB_READ_RETRY_MAX_BACKOFF >= DB_READ_RETRY_INITIAL_BACKOFF)")
 	}
 
-	if c.DBPassword == "" {
-		log.Println("⚠️  WARNING: Database password not set (DB_PASSWORD)")
+	if c.DBSlowQueryThreshold < 0 {
+		return fmt.Errorf("DB_SLOW_QUERY_THRESHOLD must not be negative")
 	}
 
+	for i, replicaURL := range c.DBReplicaURLs {
+		if err := validateDatabaseURL(replicaURL); err != nil {
+			return fmt.Errorf("invalid replica %d (DB_REPLICA_URLS): %w", i, err)
+		}
+	}
+
+	if len(c.DBReplicaURLs) > 0 && c.DBReplicaHealthCheckInterval <= 0 {
+		return fmt.Errorf("replica health check interval must be positive (DB_REPLICA_HEALTH_CHECK_INTERVAL)")
+	}
+
+	if c.DBSSLRootCert != "" {
+		if _, err := os.Stat(c.DBSSLRootCert); err != nil {
+			return fmt.Errorf("database SSL root certificate not readable (DB_SSLROOTCERT): %w", err)
+		}
+	}
+
+	if c.DBMaxOpenConns <= 0 {
+		return fmt.Errorf("database max open connections must be positive (DB_MAX_OPEN_CONNS)")
+	}
+
+	if c.DBMaxIdleConns < 0 || c.DBMaxIdleConns > c.DBMaxOpenConns {
+		return fmt.Errorf("database max idle connections must be between 0 and DB_MAX_OPEN_CONNS (DB_MAX_IDLE_CONNS)")
+	}
+
+	if c.DBConnMaxLifetime < 0 || c.DBConnMaxIdleTime < 0 {
+		return fmt.Errorf("database connection lifetimes must not be negative (DB_CONN_MAX_LIFETIME, DB_CONN_MAX_IDLE_TIME)")
+	}
+
+	if c.GRPCMaxRecvMsgSize <= 0 || c.GRPCMaxSendMsgSize <= 0 {
+		return fmt.Errorf("gRPC message size limits must be positive (GRPC_MAX_RECV_MSG_SIZE, GRPC_MAX_SEND_MSG_SIZE)")
+	}
+
+	if c.GRPCMaxConcurrentStreams <= 0 {
+		return fmt.Errorf("gRPC max concurrent streams must be positive (GRPC_MAX_CONCURRENT_STREAMS)")
+	}
+
+	if c.ServiceAuthEnabled && c.ServiceClientsFile == "" {
+		return fmt.Errorf("service clients file is required when service auth is enabled (SERVICE_CLIENTS_FILE)")
+	}
+
+	if _, err := c.TrustedProxyPrefixes(); err != nil {
+		return err
+	}
+
+	if err := c.validateRateLimits(); err != nil {
+		return err
+	}
+
+	if c.UserEventsBackend != "memory" && c.UserEventsBackend != "redis" {
+		return fmt.Errorf("USER_EVENTS_BACKEND must be memory or redis")
+	}
+
+	if err := c.validateMFA(); err != nil {
+		return err
+	}
+
+	if err := c.validateWebAuthn(); err != nil {
+		return err
+	}
+
+	if err := c.validateSessions(); err != nil {
+		return err
+	}
+
+	if err := c.validateRisk(); err != nil {
+		return err
+	}
+
+	if err := c.validateNotifications(); err != nil {
+		return err
+	}
+
+	if err := c.validateLoginCodes(); err != nil {
+		return err
+	}
+
+	if err := c.validateTracing(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateSessions checks the session timeouts; the idle timeout is measured from the last seen
+// time, so it must be longer than the interval at which it is written
+func (c *Config) validateSessions() error {
+	if c.SessionLastSeenInterval <= 0 {
+		return fmt.Errorf("SESSION_LAST_SEEN_INTERVAL must be positive")
+	}
+
+	timeouts := []struct {
+		client         string
+		idle, absolute time.Duration
+	}{
+		{"WEB", c.SessionWebIdleTimeout, c.SessionWebAbsoluteTimeout},
+		{"MOBILE", c.SessionMobileIdleTimeout, c.SessionMobileAbsoluteTimeout},
+		{"API", c.SessionAPIIdleTimeout, c.SessionAPIAbsoluteTimeout},
+	}
+	for _, t := range timeouts {
+		if t.idle <= c.SessionLastSeenInterval {
+			return fmt.Errorf("SESSION_%s_IDLE_TIMEOUT must be longer than SESSION_LAST_SEEN_INTERVAL", t.client)
+		}
+		if t.absolute < t.idle {
+			return fmt.Errorf("SESSION_%s_ABSOLUTE_TIMEOUT must not be shorter than SESSION_%s_IDLE_TIMEOUT", t.client, t.client)
+		}
+	}
+	return nil
+}
+
+// validateRisk checks the risk assessment settings when it is enabled
+func (c *Config) validateRisk() error {
+	if !c.RiskEnabled {
+		return nil
+	}
+	if c.RiskRulesFile == "" {
+		return fmt.Errorf("RISK_RULES_FILE is required when RISK_ENABLED is true")
+	}
+	if c.RiskReloadInterval <= 0 {
+		return fmt.Errorf("RISK_RELOAD_INTERVAL must be positive")
+	}
+	return nil
+}
+
+// validateTracing checks the collector and sampling when tracing is enabled
+func (c *Config) validateTracing() error {
+	if !c.TracingEnabled {
+		return nil
+	}
+	if c.TracingEndpoint == "" {
+		return fmt.Errorf("TRACING_OTLP_ENDPOINT is required when TRACING_ENABLED is true")
+	}
+	if c.TracingSampleRatio < 0 || c.TracingSampleRatio > 1 {
+		return fmt.Errorf("TRACING_SAMPLE_RATIO must be between 0 and 1")
+	}
+	return nil
+}
+
+// validateRateLimits checks the rate limiting backend and quotas when it is enabled
+func (c *Config) validateRateLimits() error {
+	if !c.RateLimitEnabled {
+		return nil
+	}
+	if c.RateLimitBackend != "memory" && c.RateLimitBackend != "redis" {
+		return fmt.Errorf("RATE_LIMIT_BACKEND must be memory or redis")
+	}
+	_, err := c.RateLimitQuotas()
+	return err
+}
+
+// validateMFA checks the MFA settings; the encryption key is required in production
+func (c *Config) validateMFA() error {
+	if c.MFAEncryptionKey == "" {
+		if c.IsProduction() {
+			return fmt.Errorf("MFA_ENCRYPTION_KEY is required in production")
+		}
+	} else if key, err := base64.StdEncoding.DecodeString(c.MFAEncryptionKey); err != nil || len(key) != 32 {
+		return fmt.Errorf("MFA_ENCRYPTION_KEY must be 32 bytes encoded as base64 (openssl rand -base64 32)")
+	}
+
+	if c.MFAChallengeTTL <= 0 {
+		return fmt.Errorf("MFA_CHALLENGE_TTL must be positive")
+	}
+	if c.MFATOTPSkew < 0 || c.MFATOTPSkew > 10 {
+		return fmt.Errorf("MFA_TOTP_SKEW must be between 0 and 10")
+	}
+	if c.MFAMaxAttempts < 1 {
+		return fmt.Errorf("MFA_MAX_ATTEMPTS must be at least 1")
+	}
+	if c.MFARecoveryCodes < 1 || c.MFARecoveryCodes > 100 {
+		return fmt.Errorf("MFA_RECOVERY_CODES must be between 1 and 100")
+	}
+	return nil
+}
+
+// validateWebAuthn checks the passkey settings when passkeys are enabled
+func (c *Config) validateWebAuthn() error {
+	if !c.WebAuthnEnabled {
+		return nil
+	}
+	if c.WebAuthnRPID == "" || len(c.WebAuthnRPOrigins) == 0 {
+		return fmt.Errorf("WEBAUTHN_RP_ID and WEBAUTHN_RP_ORIGINS are required when passkeys are enabled")
+	}
+	if c.IsProduction() {
+		if c.WebAuthnRPID == "localhost" {
+			return fmt.Errorf("WEBAUTHN_RP_ID must be set to the web app domain in production")
+		}
+		for _, origin := range c.WebAuthnRPOrigins {
+			if !strings.HasPrefix(origin, "https://") {
+				return fmt.Errorf("WEBAUTHN_RP_ORIGINS must use https in production: %s", origin)
+			}
+		}
+	}
+	switch c.WebAuthnAttestation {
+	case "none", "indirect", "direct", "enterprise":
+	default:
+		return fmt.Errorf("WEBAUTHN_ATTESTATION must be none, indirect, direct or enterprise")
+	}
+	if c.WebAuthnCeremonyTimeout <= 0 {
+		return fmt.Errorf("WEBAUTHN_CEREMONY_TIMEOUT must be positive")
+	}
+	return nil
+}
+
+// validateNotifications checks the email sender settings
+func (c *Config) validateNotifications() error {
+	switch c.NotificationSender {
+	case "log":
+	case "smtp":
+		if c.SMTPHost == "" || c.SMTPPort == "" || c.SMTPFrom == "" {
+			return fmt.Errorf("SMTP_HOST, SMTP_PORT and SMTP_FROM are required when NOTIFICATION_SENDER is smtp")
+		}
+	default:
+		return fmt.Errorf("NOTIFICATION_SENDER must be log or smtp")
+	}
+	return nil
+}
+
+// validateLoginCodes checks the passwordless login settings when login codes are enabled
+func (c *Config) validateLoginCodes() error {
+	if !c.LoginCodeEnabled {
+		return nil
+	}
+	if c.IsProduction() && c.NotificationSender == "log" {
+		return fmt.Errorf("NOTIFICATION_SENDER must not be log in production when login codes are enabled, the log would contain the codes")
+	}
+	switch c.LoginCodeDelivery {
+	case "code":
+	case "link":
+		link, err := url.Parse(c.LoginCodeLinkURL)
+		if err != nil || !link.IsAbs() || link.Host == "" {
+			return fmt.Errorf("LOGIN_CODE_LINK_URL must be an absolute URL when LOGIN_CODE_DELIVERY is link")
+		}
+		if c.IsProduction() && link.Scheme != "https" {
+			return fmt.Errorf("LOGIN_CODE_LINK_URL must use https in production")
+		}
+	default:
+		return fmt.Errorf("LOGIN_CODE_DELIVERY must be code or link")
+	}
+	if c.LoginCodeTTL <= 0 || c.LoginCodeTTL > time.Hour {
+		return fmt.Errorf("LOGIN_CODE_TTL must be positive and at most 1h")
+	}
+	if c.LoginCodeMaxRequests < 1 || c.LoginCodeRequestWindow <= 0 {
+		return fmt.Errorf("LOGIN_CODE_MAX_REQUESTS must be at least 1 and LOGIN_CODE_REQUEST_WINDOW positive")
+	}
+	if c.LoginCodeMaxAttempts < 1 {
+		return fmt.Errorf("LOGIN_CODE_MAX_ATTEMPTS must be at least 1")
+	}
 	return nil
 }
 
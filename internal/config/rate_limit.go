@@ -0,0 +1,80 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Keys a rate limit quota counts calls by
+const (
+	RateLimitByIP     = "ip"     // client IP address (x-forwarded-for from trusted gateways, or the peer address)
+	RateLimitByEmail  = "email"  // email in the request (Login, RequestLoginCode, VerifyLoginCode)
+	RateLimitByClient = "client" // service client id (requires SERVICE_AUTH_ENABLED)
+)
+
+// RateLimitQuota allows Count calls to Method per Period and Key value, e.g. 5 Login calls per
+// minute and email; Method "*" counts the calls to every method together
+type RateLimitQuota struct {
+	Method string
+	Key    string
+	Count  int
+	Period time.Duration
+}
+
+// defaultRateLimitQuotas protects the login RPCs against brute force and token validation
+// against floods
+const defaultRateLimitQuotas = "Login:ip=20/m,Login:email=10/m,VerifyMFA:ip=20/m,VerifyLoginCode:ip=20/m," +
+	"RequestLoginCode:ip=10/m,FinishPasskeyLogin:ip=20/m,ValidateToken:ip=6000/m,ValidateToken:client=30000/m"
+
+// RateLimitQuotas parses RATE_LIMIT_QUOTAS, a comma-separated list of "Method:key=count/period"
+// where key is ip, email or client and period a duration ("1m", "30s") or a unit ("s", "m", "h")
+func (c *Config) RateLimitQuotas() ([]RateLimitQuota, error) {
+	var quotas []RateLimitQuota
+	for _, entry := range strings.Split(c.RateLimitQuotaList, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		quota, err := parseRateLimitQuota(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RATE_LIMIT_QUOTAS entry %q: %w", entry, err)
+		}
+		quotas = append(quotas, quota)
+	}
+	return quotas, nil
+}
+
+// parseRateLimitQuota parses a single "Method:key=count/period" entry
+func parseRateLimitQuota(entry string) (RateLimitQuota, error) {
+	method, rest, ok := strings.Cut(entry, ":")
+	if !ok || method == "" || strings.ContainsAny(method, "/ ") {
+		return RateLimitQuota{}, fmt.Errorf("expected Method:key=count/period")
+	}
+	key, rate, ok := strings.Cut(rest, "=")
+	if !ok {
+		return RateLimitQuota{}, fmt.Errorf("expected Method:key=count/period")
+	}
+	if key != RateLimitByIP && key != RateLimitByEmail && key != RateLimitByClient {
+		return RateLimitQuota{}, fmt.Errorf("key must be %s, %s or %s", RateLimitByIP, RateLimitByEmail, RateLimitByClient)
+	}
+
+	countText, periodText, ok := strings.Cut(rate, "/")
+	if !ok {
+		return RateLimitQuota{}, fmt.Errorf("expected count/period")
+	}
+	count, err := strconv.Atoi(countText)
+	if err != nil || count <= 0 {
+		return RateLimitQuota{}, fmt.Errorf("count must be a positive integer")
+	}
+	if periodText == "s" || periodText == "m" || periodText == "h" {
+		periodText = "1" + periodText
+	}
+	period, err := time.ParseDuration(periodText)
+	if err != nil || period <= 0 {
+		return RateLimitQuota{}, fmt.Errorf("period must be a positive duration")
+	}
+
+	return RateLimitQuota{Method: method, Key: key, Count: count, Period: period}, nil
+}
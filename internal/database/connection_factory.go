@@ -1,21 +1,88 @@
 package database
 
 import (
+	"context"
 	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"hub-user-service/internal/config"
 
 	"github.com/jmoiron/sqlx"
-	_ "github.com/lib/pq" // PostgreSQL driver
+	"github.com/lib/pq" // PostgreSQL driver
 )
 
 // ConnectionConfig holds database connection configuration
 type ConnectionConfig struct {
-	Driver   string
+	// Driver is "postgres" or "sqlite" (tests and local development only)
+	Driver string
+
+	// SQLitePath is the database file of the sqlite driver, or SQLiteInMemory
+	SQLitePath string
+
+	// URL is a complete connection string, either a postgres:// URL or a key=value DSN.
+	// When set it takes precedence over Host, Port, Database, Username, Password and SSLMode.
+	URL string
+
 	Host     string
 	Port     string
 	Database string
 	Username string
 	Password string
 	SSLMode  string
+
+	// Optional connection parameters, applied to URL connections too unless the URL already sets them
+	SSLRootCert     string
+	ConnectTimeout  time.Duration
+	ApplicationName string
+
+	// Connection pool limits; a zero MaxOpenConns or lifetime is unlimited, a zero MaxIdleConns
+	// keeps no idle connection
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+
+	// ConnectRetry retries the initial connection while the server is unreachable or starting up
+	ConnectRetry RetryPolicy
+
+	// Read replicas (URLs or DSNs); optional parameters and pool limits are shared with the primary
+	ReplicaURLs                []string
+	ReplicaHealthCheckInterval time.Duration
+}
+
+// NewConnectionConfig builds the connection configuration from the application configuration
+// This is the only place database settings are read from, so call cfg.Validate() first
+func NewConnectionConfig(cfg *config.Config) ConnectionConfig {
+	return ConnectionConfig{
+		Driver:          cfg.DBDriver,
+		SQLitePath:      cfg.DBSQLitePath,
+		URL:             cfg.DatabaseURL,
+		Host:            cfg.DBHost,
+		Port:            cfg.DBPort,
+		Database:        cfg.DBName,
+		Username:        cfg.DBUser,
+		Password:        cfg.DBPassword,
+		SSLMode:         cfg.DBSSLMode,
+		SSLRootCert:     cfg.DBSSLRootCert,
+		ConnectTimeout:  cfg.DBConnectTimeout,
+		ApplicationName: cfg.DBApplicationName,
+		MaxOpenConns:    cfg.DBMaxOpenConns,
+		MaxIdleConns:    cfg.DBMaxIdleConns,
+		ConnMaxLifetime: cfg.DBConnMaxLifetime,
+		ConnMaxIdleTime: cfg.DBConnMaxIdleTime,
+		ConnectRetry: RetryPolicy{
+			MaxAttempts:    cfg.DBConnectMaxAttempts,
+			InitialBackoff: cfg.DBConnectInitialBackoff,
+			MaxBackoff:     cfg.DBConnectMaxBackoff,
+		},
+
+		ReplicaURLs:                cfg.DBReplicaURLs,
+		ReplicaHealthCheckInterval: cfg.DBReplicaHealthCheckInterval,
+	}
 }
 
 // ConnectionFactory manages database connections and provides the abstracted Database interface
@@ -34,71 +101,172 @@ func (cf *ConnectionFactory) CreateConnection() (Database, error) {
 	switch cf.config.Driver {
 	case "postgres":
 		return cf.createPostgreSQLConnection()
+	case "sqlite":
+		return cf.createSQLiteConnection()
 	default:
 		return nil, fmt.Errorf("unsupported database driver: %s", cf.config.Driver)
 	}
 }
 
 // createPostgreSQLConnection creates a PostgreSQL connection using SQLX
+// With replicas configured the result is a RoutingDatabase over the primary and the replicas
 func (cf *ConnectionFactory) createPostgreSQLConnection() (Database, error) {
-	dsn := cf.buildPostgreSQLDSN()
-
-	db, err := sqlx.Connect("postgres", dsn)
+	db, err := cf.openPostgreSQL()
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to PostgreSQL: %w", err)
+		return nil, err
 	}
 
-	// Verify the connection
-	if err := db.Ping(); err != nil {
+	// Verify the connection, waiting for a server that is still starting
+	policy := cf.config.ConnectRetry
+	if policy.OnRetry == nil {
+		policy.OnRetry = func(attempt int, delay time.Duration, err error) {
+			log.Printf("⏳ PostgreSQL not ready (attempt %d/%d), retrying in %s: %v", attempt, policy.MaxAttempts, delay.Round(time.Millisecond), err)
+		}
+	}
+	if err := Retry(context.Background(), policy, db.Ping); err != nil {
 		db.Close()
 		return nil, fmt.Errorf("failed to ping PostgreSQL: %w", err)
 	}
 
-	return NewSQLXDatabase(db), nil
+	primary := NewSQLXDatabase(db)
+	if len(cf.config.ReplicaURLs) == 0 {
+		return primary, nil
+	}
+
+	replicas := make([]Database, 0, len(cf.config.ReplicaURLs))
+	for i, replicaURL := range cf.config.ReplicaURLs {
+		replicaConfig := cf.config
+		replicaConfig.URL = replicaURL
+		replicaConfig.ReplicaURLs = nil
+
+		replicaDB, err := NewConnectionFactory(replicaConfig).openPostgreSQL()
+		if err != nil {
+			primary.Close()
+			for _, opened := range replicas {
+				opened.Close()
+			}
+			return nil, fmt.Errorf("replica %d: %w", i, err)
+		}
+		replicas = append(replicas, NewSQLXDatabase(replicaDB))
+	}
+
+	// Unreachable replicas do not prevent startup, they are checked before serving reads
+	routing := NewRoutingDatabase(primary, replicas, cf.config.ReplicaHealthCheckInterval)
+	routing.CheckHealth()
+	return routing, nil
+}
+
+// openPostgreSQL opens a PostgreSQL pool without connecting
+func (cf *ConnectionFactory) openPostgreSQL() (*sqlx.DB, error) {
+	dsn, err := cf.buildPostgreSQLDSN()
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sqlx.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to PostgreSQL: %w", err)
+	}
+
+	// Pool limits must be in place before the first connection is opened
+	cf.configurePool(db)
+	return db, nil
+}
+
+// configurePool applies the connection pool limits
+func (cf *ConnectionFactory) configurePool(db *sqlx.DB) {
+	if cf.config.MaxOpenConns > 0 {
+		db.SetMaxOpenConns(cf.config.MaxOpenConns)
+	}
+	// Always set: database/sql keeps 2 idle connections by default, not 0
+	db.SetMaxIdleConns(cf.config.MaxIdleConns)
+	if cf.config.ConnMaxLifetime > 0 {
+		db.SetConnMaxLifetime(cf.config.ConnMaxLifetime)
+	}
+	if cf.config.ConnMaxIdleTime > 0 {
+		db.SetConnMaxIdleTime(cf.config.ConnMaxIdleTime)
+	}
 }
 
 // buildPostgreSQLDSN builds the PostgreSQL data source name
-func (cf *ConnectionFactory) buildPostgreSQLDSN() string {
-	dsn := fmt.Sprintf("user=%s dbname=%s sslmode=%s",
-		cf.config.Username,
-		cf.config.Database,
-		cf.config.SSLMode,
-	)
+// postgres:// URLs are converted to key=value form so optional parameters can be appended uniformly
+func (cf *ConnectionFactory) buildPostgreSQLDSN() (string, error) {
+	var dsn string
+
+	if cf.config.URL != "" {
+		dsn = cf.config.URL
+		if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+			converted, err := pq.ParseURL(dsn)
+			if err != nil {
+				return "", fmt.Errorf("invalid database URL: %w", err)
+			}
+			dsn = converted
+		}
+	} else {
+		dsn = fmt.Sprintf("user=%s dbname=%s sslmode=%s",
+			quoteDSNValue(cf.config.Username),
+			quoteDSNValue(cf.config.Database),
+			quoteDSNValue(cf.config.SSLMode),
+		)
 
-	if cf.config.Password != "" {
-		dsn += fmt.Sprintf(" password=%s", cf.config.Password)
+		if cf.config.Password != "" {
+			dsn += fmt.Sprintf(" password=%s", quoteDSNValue(cf.config.Password))
+		}
+
+		if cf.config.Host != "" {
+			dsn += fmt.Sprintf(" host=%s", quoteDSNValue(cf.config.Host))
+		}
+
+		if cf.config.Port != "" {
+			dsn += fmt.Sprintf(" port=%s", quoteDSNValue(cf.config.Port))
+		}
+	}
+
+	if cf.config.SSLRootCert != "" {
+		dsn = appendDSNParam(dsn, "sslrootcert", cf.config.SSLRootCert)
 	}
 
-	if cf.config.Host != "" {
-		dsn += fmt.Sprintf(" host=%s", cf.config.Host)
+	if cf.config.ConnectTimeout > 0 {
+		seconds := int(cf.config.ConnectTimeout.Seconds())
+		if seconds < 1 {
+			seconds = 1
+		}
+		dsn = appendDSNParam(dsn, "connect_timeout", strconv.Itoa(seconds))
 	}
 
-	if cf.config.Port != "" {
-		dsn += fmt.Sprintf(" port=%s", cf.config.Port)
+	if cf.config.ApplicationName != "" {
+		dsn = appendDSNParam(dsn, "application_name", cf.config.ApplicationName)
 	}
 
-	return dsn
+	return dsn, nil
 }
 
-// DefaultConfig returns a default configuration for local development
-// This matches the current configuration used in the codebase
-func DefaultConfig() ConnectionConfig {
-	return ConnectionConfig{
-		Driver:   "postgres",
-		Host:     "localhost",
-		Database: "yanrodrigues",
-		Username: "yanrodrigues",
-		Password: "",
-		SSLMode:  "disable",
+// dsnKeyPattern matches the start of a key=value pair in a DSN
+var dsnKeyPattern = regexp.MustCompile(`(?:^|\s)([a-z_]+)\s*=`)
+
+// appendDSNParam appends key=value unless the DSN already sets key
+func appendDSNParam(dsn, key, value string) string {
+	for _, match := range dsnKeyPattern.FindAllStringSubmatch(dsn, -1) {
+		if match[1] == key {
+			return dsn
+		}
 	}
+
+	if dsn == "" {
+		return key + "=" + quoteDSNValue(value)
+	}
+	return dsn + " " + key + "=" + quoteDSNValue(value)
 }
 
-// CreateDatabaseConnection is a convenience function that creates a database connection
-// with the default configuration. This can be used as a drop-in replacement for
-// the current sqlx.Connect calls throughout the codebase
-func CreateDatabaseConnection() (Database, error) {
-	factory := NewConnectionFactory(DefaultConfig())
-	return factory.CreateConnection()
+// quoteDSNValue quotes a value for a key=value DSN when it is empty or contains spaces, quotes or backslashes
+func quoteDSNValue(value string) string {
+	if value != "" && !strings.ContainsAny(value, " '\\") {
+		return value
+	}
+
+	escaped := strings.ReplaceAll(value, `\`, `\\`)
+	escaped = strings.ReplaceAll(escaped, `'`, `\'`)
+	return "'" + escaped + "'"
 }
 
 // CreateDatabaseConnectionWithConfig creates a database connection with custom configuration
@@ -5,10 +5,9 @@ import (
 	"database/sql"
 )
 
-// Database defines the interface for database operations
-// This abstraction allows switching between different SQL packages (sqlx, sql, gorm, etc.)
-// without changing repository implementations
-type Database interface {
+// Querier is the set of query methods shared by Database and Transaction
+// Repositories should depend on Querier so the same code runs inside or outside a transaction
+type Querier interface {
 	// Query execution methods
 	Query(query string, args ...interface{}) (Rows, error)
 	QueryContext(ctx context.Context, query string, args ...interface{}) (Rows, error)
@@ -19,34 +18,34 @@ type Database interface {
 	Exec(query string, args ...interface{}) (Result, error)
 	ExecContext(ctx context.Context, query string, args ...interface{}) (Result, error)
 
-	// Transaction support
-	Begin() (Transaction, error)
-	BeginTx(ctx context.Context, opts *sql.TxOptions) (Transaction, error)
-
 	// Convenience methods for common operations
 	Get(dest interface{}, query string, args ...interface{}) error
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
 	Select(dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+}
+
+// Database defines the interface for database operations
+// This abstraction allows switching between different SQL packages (sqlx, sql, gorm, etc.)
+// without changing repository implementations
+type Database interface {
+	Querier
+
+	// Transaction support
+	Begin() (Transaction, error)
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (Transaction, error)
 
 	// Connection management
 	Ping() error
 	Close() error
+
+	// Stats returns connection pool statistics
+	Stats() sql.DBStats
 }
 
 // Transaction represents a database transaction
 type Transaction interface {
-	// Query execution methods within transaction
-	Query(query string, args ...interface{}) (Rows, error)
-	QueryContext(ctx context.Context, query string, args ...interface{}) (Rows, error)
-	QueryRow(query string, args ...interface{}) Row
-	QueryRowContext(ctx context.Context, query string, args ...interface{}) Row
-
-	// Execute methods within transaction
-	Exec(query string, args ...interface{}) (Result, error)
-	ExecContext(ctx context.Context, query string, args ...interface{}) (Result, error)
-
-	// Convenience methods within transaction
-	Get(dest interface{}, query string, args ...interface{}) error
-	Select(dest interface{}, query string, args ...interface{}) error
+	Querier
 
 	// Transaction control
 	Commit() error
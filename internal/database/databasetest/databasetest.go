@@ -0,0 +1,107 @@
+// Package databasetest provides database doubles for repository tests: a testify mock of
+// database.Querier, and a real in-memory SQLite database to run conditional statements against
+package databasetest
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"hub-user-service/internal/database"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockQuerier is a testify mock of database.Querier
+// ExecContext calls are matched on (ctx, query, args) and return (Result, error); GetContext and
+// SelectContext calls are matched on (ctx, dest, query, args) and return an error, use Fill to
+// answer with rows
+type MockQuerier struct {
+	mock.Mock
+}
+
+func (m *MockQuerier) Query(query string, args ...interface{}) (database.Rows, error) {
+	return m.QueryContext(context.Background(), query, args...)
+}
+
+func (m *MockQuerier) QueryContext(ctx context.Context, query string, args ...interface{}) (database.Rows, error) {
+	mockArgs := m.Called(ctx, query, args)
+	rows, _ := mockArgs.Get(0).(database.Rows)
+	return rows, mockArgs.Error(1)
+}
+
+func (m *MockQuerier) QueryRow(query string, args ...interface{}) database.Row {
+	return m.QueryRowContext(context.Background(), query, args...)
+}
+
+func (m *MockQuerier) QueryRowContext(ctx context.Context, query string, args ...interface{}) database.Row {
+	row, _ := m.Called(ctx, query, args).Get(0).(database.Row)
+	return row
+}
+
+func (m *MockQuerier) Exec(query string, args ...interface{}) (database.Result, error) {
+	return m.ExecContext(context.Background(), query, args...)
+}
+
+func (m *MockQuerier) ExecContext(ctx context.Context, query string, args ...interface{}) (database.Result, error) {
+	mockArgs := m.Called(ctx, query, args)
+	result, _ := mockArgs.Get(0).(database.Result)
+	return result, mockArgs.Error(1)
+}
+
+func (m *MockQuerier) Get(dest interface{}, query string, args ...interface{}) error {
+	return m.GetContext(context.Background(), dest, query, args...)
+}
+
+func (m *MockQuerier) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return m.Called(ctx, dest, query, args).Error(0)
+}
+
+func (m *MockQuerier) Select(dest interface{}, query string, args ...interface{}) error {
+	return m.SelectContext(context.Background(), dest, query, args...)
+}
+
+func (m *MockQuerier) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return m.Called(ctx, dest, query, args).Error(0)
+}
+
+// Result is a database.Result reporting a fixed number of affected rows
+type Result int64
+
+func (r Result) LastInsertId() (int64, error) { return 0, nil }
+func (r Result) RowsAffected() (int64, error) { return int64(r), nil }
+
+// Fill answers a GetContext or SelectContext call by copying value into its destination
+func Fill[T any](value T) func(mock.Arguments) {
+	return func(args mock.Arguments) {
+		*args.Get(1).(*T) = value
+	}
+}
+
+// QueryContaining matches a query containing every fragment
+func QueryContaining(fragments ...string) interface{} {
+	return mock.MatchedBy(func(query string) bool {
+		for _, fragment := range fragments {
+			if !strings.Contains(query, fragment) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// OpenSQLite opens a private in-memory SQLite database with the users table, creates schema and
+// closes the database when the test ends
+func OpenSQLite(t testing.TB, schema string) database.Database {
+	t.Helper()
+	db, err := database.NewConnectionFactory(database.ConnectionConfig{Driver: "sqlite", SQLitePath: database.SQLiteInMemory}).CreateConnection()
+	if err != nil {
+		t.Fatalf("failed to open SQLite: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.ExecContext(context.Background(), schema); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	return db
+}
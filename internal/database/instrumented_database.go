@@ -0,0 +1,275 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName identifies the spans created by InstrumentedDatabase
+const tracerName = "hub-user-service/internal/database"
+
+// InstrumentationOptions configures InstrumentedDatabase
+type InstrumentationOptions struct {
+	// SlowQueryThreshold logs queries taking at least this long; zero disables the slow query log
+	SlowQueryThreshold time.Duration
+
+	// Tracer creates one span per query; nil uses the globally registered TracerProvider
+	Tracer trace.Tracer
+
+	// System is the db.system span attribute; empty means "postgresql"
+	System string
+}
+
+// instrumenter records metrics, slow queries and spans around a single database call
+type instrumenter struct {
+	slowQueryThreshold time.Duration
+	tracer             trace.Tracer
+	system             string
+}
+
+// observation is an in-flight database call
+type observation struct {
+	in        *instrumenter
+	span      trace.Span
+	operation string
+	query     string
+	args      []interface{}
+	started   time.Time
+}
+
+// start begins observing operation and returns the context carrying its span
+func (in *instrumenter) start(ctx context.Context, operation, query string, args []interface{}) (context.Context, *observation) {
+	query = normalizeQuery(query)
+	ctx, span := in.tracer.Start(ctx, "db."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", in.system),
+			attribute.String("db.operation", operation),
+			attribute.String("db.statement", query),
+		))
+	return ctx, &observation{in: in, span: span, operation: operation, query: query, args: args, started: time.Now()}
+}
+
+// finish records the outcome of the call
+func (o *observation) finish(err error) {
+	duration := time.Since(o.started)
+	failed := isQueryFailure(err)
+
+	histogramFor(o.query).observe(duration, failed)
+	if failed {
+		code := errorCode(err)
+		queryErrors.Add(code, 1)
+		o.span.SetAttributes(attribute.String("db.response.status_code", code))
+		o.span.RecordError(err)
+		o.span.SetStatus(codes.Error, err.Error())
+	}
+	o.span.End()
+
+	if o.in.slowQueryThreshold > 0 && duration >= o.in.slowQueryThreshold {
+		log.Printf("🐢 Slow query (%s, %s): %s args=%s", o.operation, duration.Round(time.Millisecond), o.query, redactArgs(o.args))
+	}
+}
+
+// redactArgs describes query parameters by position and type only, so values never reach the logs
+func redactArgs(args []interface{}) string {
+	parts := make([]string, len(args))
+	for i, arg := range args {
+		typeName := "nil"
+		if arg != nil {
+			typeName = fmt.Sprintf("%T", arg)
+		}
+		parts[i] = fmt.Sprintf("$%d=<%s>", i+1, typeName)
+	}
+	return "[" + strings.Join(parts, " ") + "]"
+}
+
+// instrumentedQuerier observes every call to querier
+type instrumentedQuerier struct {
+	querier Querier
+	in      *instrumenter
+}
+
+// Query executes a query; only the time to the first response is measured
+func (q instrumentedQuerier) Query(query string, args ...interface{}) (Rows, error) {
+	_, obs := q.in.start(context.Background(), "Query", query, args)
+	rows, err := q.querier.Query(query, args...)
+	obs.finish(err)
+	return rows, err
+}
+
+// QueryContext executes a query; only the time to the first response is measured
+func (q instrumentedQuerier) QueryContext(ctx context.Context, query string, args ...interface{}) (Rows, error) {
+	ctx, obs := q.in.start(ctx, "QueryContext", query, args)
+	rows, err := q.querier.QueryContext(ctx, query, args...)
+	obs.finish(err)
+	return rows, err
+}
+
+// QueryRow executes a single row query, observed until the row is scanned
+func (q instrumentedQuerier) QueryRow(query string, args ...interface{}) Row {
+	_, obs := q.in.start(context.Background(), "QueryRow", query, args)
+	return &instrumentedRow{row: q.querier.QueryRow(query, args...), obs: obs}
+}
+
+// QueryRowContext executes a single row query, observed until the row is scanned
+func (q instrumentedQuerier) QueryRowContext(ctx context.Context, query string, args ...interface{}) Row {
+	ctx, obs := q.in.start(ctx, "QueryRowContext", query, args)
+	return &instrumentedRow{row: q.querier.QueryRowContext(ctx, query, args...), obs: obs}
+}
+
+// Exec executes a statement
+func (q instrumentedQuerier) Exec(query string, args ...interface{}) (Result, error) {
+	_, obs := q.in.start(context.Background(), "Exec", query, args)
+	result, err := q.querier.Exec(query, args...)
+	obs.finish(err)
+	return result, err
+}
+
+// ExecContext executes a statement
+func (q instrumentedQuerier) ExecContext(ctx context.Context, query string, args ...interface{}) (Result, error) {
+	ctx, obs := q.in.start(ctx, "ExecContext", query, args)
+	result, err := q.querier.ExecContext(ctx, query, args...)
+	obs.finish(err)
+	return result, err
+}
+
+// Get reads a single row
+func (q instrumentedQuerier) Get(dest interface{}, query string, args ...interface{}) error {
+	_, obs := q.in.start(context.Background(), "Get", query, args)
+	err := q.querier.Get(dest, query, args...)
+	obs.finish(err)
+	return err
+}
+
+// GetContext reads a single row
+func (q instrumentedQuerier) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	ctx, obs := q.in.start(ctx, "GetContext", query, args)
+	err := q.querier.GetContext(ctx, dest, query, args...)
+	obs.finish(err)
+	return err
+}
+
+// Select reads rows
+func (q instrumentedQuerier) Select(dest interface{}, query string, args ...interface{}) error {
+	_, obs := q.in.start(context.Background(), "Select", query, args)
+	err := q.querier.Select(dest, query, args...)
+	obs.finish(err)
+	return err
+}
+
+// SelectContext reads rows
+func (q instrumentedQuerier) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	ctx, obs := q.in.start(ctx, "SelectContext", query, args)
+	err := q.querier.SelectContext(ctx, dest, query, args...)
+	obs.finish(err)
+	return err
+}
+
+// InstrumentedDatabase records per-query latency histograms, error counts by SQLSTATE,
+// slow queries and a tracing span for every call to the wrapped database
+//
+// Metrics are served on the admin listener's /debug/vars as "database_queries" (keyed by the
+// whitespace-normalized statement) and "database_errors". Slow query logs carry parameter types only.
+type InstrumentedDatabase struct {
+	instrumentedQuerier
+	db Database
+}
+
+// NewInstrumentedDatabase wraps db with query instrumentation
+func NewInstrumentedDatabase(db Database, opts InstrumentationOptions) *InstrumentedDatabase {
+	tracer := opts.Tracer
+	if tracer == nil {
+		tracer = otel.Tracer(tracerName)
+	}
+	system := opts.System
+	if system == "" {
+		system = "postgresql"
+	}
+	in := &instrumenter{slowQueryThreshold: opts.SlowQueryThreshold, tracer: tracer, system: system}
+	return &InstrumentedDatabase{instrumentedQuerier: instrumentedQuerier{querier: db, in: in}, db: db}
+}
+
+// Begin starts an instrumented transaction
+func (d *InstrumentedDatabase) Begin() (Transaction, error) {
+	return d.BeginTx(context.Background(), nil)
+}
+
+// BeginTx starts an instrumented transaction
+func (d *InstrumentedDatabase) BeginTx(ctx context.Context, opts *sql.TxOptions) (Transaction, error) {
+	ctx, obs := d.in.start(ctx, "Begin", "BEGIN", nil)
+	tx, err := d.db.BeginTx(ctx, opts)
+	obs.finish(err)
+	if err != nil {
+		return nil, err
+	}
+	return &instrumentedTransaction{instrumentedQuerier: instrumentedQuerier{querier: tx, in: d.in}, tx: tx, ctx: ctx}, nil
+}
+
+// Ping verifies the connection
+func (d *InstrumentedDatabase) Ping() error {
+	return d.db.Ping()
+}
+
+// Close closes the wrapped database
+func (d *InstrumentedDatabase) Close() error {
+	return d.db.Close()
+}
+
+// Stats returns the wrapped database pool statistics
+func (d *InstrumentedDatabase) Stats() sql.DBStats {
+	return d.db.Stats()
+}
+
+// instrumentedTransaction observes queries, commit and rollback of a transaction
+type instrumentedTransaction struct {
+	instrumentedQuerier
+	tx  Transaction
+	ctx context.Context // parents the commit and rollback spans
+}
+
+// Commit commits the transaction
+func (t *instrumentedTransaction) Commit() error {
+	_, obs := t.in.start(t.ctx, "Commit", "COMMIT", nil)
+	err := t.tx.Commit()
+	obs.finish(err)
+	return err
+}
+
+// Rollback aborts the transaction
+func (t *instrumentedTransaction) Rollback() error {
+	_, obs := t.in.start(t.ctx, "Rollback", "ROLLBACK", nil)
+	err := t.tx.Rollback()
+	obs.finish(err)
+	return err
+}
+
+// instrumentedRow finishes its observation on the first Scan or Err, when the query outcome is known
+type instrumentedRow struct {
+	row  Row
+	obs  *observation
+	once sync.Once
+}
+
+// Scan copies the row into dest
+func (r *instrumentedRow) Scan(dest ...interface{}) error {
+	err := r.row.Scan(dest...)
+	r.once.Do(func() { r.obs.finish(err) })
+	return err
+}
+
+// Err returns the query error
+func (r *instrumentedRow) Err() error {
+	err := r.row.Err()
+	r.once.Do(func() { r.obs.finish(err) })
+	return err
+}
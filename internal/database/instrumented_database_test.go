@@ -0,0 +1,224 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"expvar"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// recordedSpan captures what the instrumentation reports on a span
+type recordedSpan struct {
+	noop.Span
+	name   string
+	attrs  map[attribute.Key]string
+	status codes.Code
+	err    error
+	ended  bool
+}
+
+func (s *recordedSpan) SetAttributes(kv ...attribute.KeyValue) {
+	for _, attr := range kv {
+		s.attrs[attr.Key] = attr.Value.Emit()
+	}
+}
+
+func (s *recordedSpan) RecordError(err error, _ ...trace.EventOption) { s.err = err }
+
+func (s *recordedSpan) SetStatus(code codes.Code, _ string) { s.status = code }
+
+func (s *recordedSpan) End(...trace.SpanEndOption) { s.ended = true }
+
+// recordingTracer keeps every span it starts
+type recordingTracer struct {
+	noop.Tracer
+	spans []*recordedSpan
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	span := &recordedSpan{name: name, attrs: map[attribute.Key]string{}}
+	config := trace.NewSpanStartConfig(opts...)
+	span.SetAttributes(config.Attributes()...)
+	t.spans = append(t.spans, span)
+	return ctx, span
+}
+
+// committingDatabase hands out transactions that record their outcome
+type committingDatabase struct {
+	routedDatabase
+	tx *recordingTransaction
+}
+
+type recordingTransaction struct {
+	Querier
+	committed bool
+}
+
+func (t *recordingTransaction) Commit() error   { t.committed = true; return nil }
+func (t *recordingTransaction) Rollback() error { return sql.ErrTxDone }
+
+func (d *committingDatabase) BeginTx(ctx context.Context, opts *sql.TxOptions) (Transaction, error) {
+	d.tx = &recordingTransaction{Querier: &d.routedDatabase}
+	return d.tx, nil
+}
+
+func histogramCount(t *testing.T, query string) int64 {
+	t.Helper()
+	h, ok := queryMetrics.Get(query).(*latencyHistogram)
+	if !ok {
+		return 0
+	}
+	var snapshot struct{ Count int64 }
+	require.NoError(t, json.Unmarshal([]byte(h.String()), &snapshot))
+	return snapshot.Count
+}
+
+func errorCount(code string) int64 {
+	if v, ok := queryErrors.Get(code).(*expvar.Int); ok {
+		return v.Value()
+	}
+	return 0
+}
+
+func TestInstrumentedDatabase_RecordsLatencyAndSpan(t *testing.T) {
+	tracer := &recordingTracer{}
+	db := NewInstrumentedDatabase(&routedDatabase{name: "primary"}, InstrumentationOptions{Tracer: tracer})
+
+	var name string
+	err := db.GetContext(context.Background(), &name, "SELECT name\n\t FROM users  WHERE id = $1 -- latency", 7)
+
+	require.NoError(t, err)
+	query := "SELECT name FROM users WHERE id = $1 -- latency"
+	assert.Equal(t, int64(1), histogramCount(t, query))
+	require.Len(t, tracer.spans, 1)
+	span := tracer.spans[0]
+	assert.Equal(t, "db.GetContext", span.name)
+	assert.Equal(t, query, span.attrs["db.statement"])
+	assert.Equal(t, "postgresql", span.attrs["db.system"])
+	assert.True(t, span.ended)
+	assert.Equal(t, codes.Unset, span.status)
+}
+
+func TestInstrumentedDatabase_System(t *testing.T) {
+	tracer := &recordingTracer{}
+	db := NewInstrumentedDatabase(&routedDatabase{name: "primary"}, InstrumentationOptions{Tracer: tracer, System: "sqlite"})
+
+	var name string
+	require.NoError(t, db.GetContext(context.Background(), &name, "SELECT name FROM users WHERE id = $1 -- system", 7))
+
+	require.Len(t, tracer.spans, 1)
+	assert.Equal(t, "sqlite", tracer.spans[0].attrs["db.system"])
+}
+
+func TestInstrumentedDatabase_CountsErrorsBySQLState(t *testing.T) {
+	tracer := &recordingTracer{}
+	failure := &pq.Error{Code: "42P01"}
+	db := NewInstrumentedDatabase(&routedDatabase{readErr: failure}, InstrumentationOptions{Tracer: tracer})
+	before := errorCount("42P01")
+
+	var name string
+	err := db.GetContext(context.Background(), &name, "SELECT name FROM missing_table")
+
+	assert.Equal(t, failure, err)
+	assert.Equal(t, before+1, errorCount("42P01"))
+	require.Len(t, tracer.spans, 1)
+	assert.Equal(t, codes.Error, tracer.spans[0].status)
+	assert.Equal(t, "42P01", tracer.spans[0].attrs["db.response.status_code"])
+	assert.Equal(t, failure, tracer.spans[0].err)
+}
+
+func TestInstrumentedDatabase_NoRowsIsNotAFailure(t *testing.T) {
+	tracer := &recordingTracer{}
+	db := NewInstrumentedDatabase(&routedDatabase{readErr: sql.ErrNoRows}, InstrumentationOptions{Tracer: tracer})
+	before := errorCount("other")
+
+	var name string
+	err := db.GetContext(context.Background(), &name, "SELECT name FROM users WHERE id = $1 -- no rows", 0)
+
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.Equal(t, before, errorCount("other"))
+	assert.Equal(t, codes.Unset, tracer.spans[0].status)
+}
+
+func TestInstrumentedDatabase_SlowQueryLogRedactsParameters(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	db := NewInstrumentedDatabase(&routedDatabase{}, InstrumentationOptions{
+		SlowQueryThreshold: time.Nanosecond,
+		Tracer:             &recordingTracer{},
+	})
+
+	_, err := db.ExecContext(context.Background(), "UPDATE users SET email = $1 WHERE id = $2", "secret@example.com", 42)
+
+	require.NoError(t, err)
+	assert.Contains(t, logs.String(), "Slow query (ExecContext")
+	assert.Contains(t, logs.String(), "args=[$1=<string> $2=<int>]")
+	assert.NotContains(t, logs.String(), "secret@example.com")
+}
+
+func TestInstrumentedDatabase_InstrumentsTransactions(t *testing.T) {
+	tracer := &recordingTracer{}
+	inner := &committingDatabase{}
+	db := NewInstrumentedDatabase(inner, InstrumentationOptions{Tracer: tracer})
+
+	err := WithinTx(context.Background(), db, func(tx Transaction) error {
+		_, err := tx.ExecContext(context.Background(), "DELETE FROM users WHERE id = $1", 1)
+		return err
+	})
+
+	require.NoError(t, err)
+	assert.True(t, inner.tx.committed)
+	assert.Equal(t, int32(1), inner.writes.Load())
+	names := make([]string, len(tracer.spans))
+	for i, span := range tracer.spans {
+		names[i] = span.name
+	}
+	assert.Equal(t, []string{"db.Begin", "db.ExecContext", "db.Commit"}, names)
+}
+
+func TestLatencyHistogram_CumulativeBuckets(t *testing.T) {
+	h := newLatencyHistogram()
+	h.observe(3*time.Millisecond, false)
+	h.observe(40*time.Millisecond, true)
+	h.observe(time.Minute, false)
+
+	var snapshot struct {
+		Count     int64            `json:"count"`
+		Errors    int64            `json:"errors"`
+		SumMs     float64          `json:"sum_ms"`
+		BucketsMs map[string]int64 `json:"buckets_ms"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(h.String()), &snapshot))
+
+	assert.Equal(t, int64(3), snapshot.Count)
+	assert.Equal(t, int64(1), snapshot.Errors)
+	assert.Equal(t, 60043.0, snapshot.SumMs)
+	assert.Equal(t, int64(0), snapshot.BucketsMs["1"])
+	assert.Equal(t, int64(1), snapshot.BucketsMs["5"])
+	assert.Equal(t, int64(2), snapshot.BucketsMs["50"])
+	assert.Equal(t, int64(2), snapshot.BucketsMs["5000"])
+	assert.Equal(t, int64(3), snapshot.BucketsMs["+Inf"])
+}
+
+func TestErrorCode(t *testing.T) {
+	assert.Equal(t, "23505", errorCode(&pq.Error{Code: "23505"}))
+	assert.Equal(t, "canceled", errorCode(context.Canceled))
+	assert.Equal(t, "deadline_exceeded", errorCode(context.DeadlineExceeded))
+	assert.Equal(t, "connection", errorCode(sql.ErrConnDone))
+	assert.Equal(t, "other", errorCode(assert.AnError))
+}
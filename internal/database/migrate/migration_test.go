@@ -0,0 +1,64 @@
+package migrate
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"hub-user-service/migrations"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"000002_add_status.up.sql":           {Data: []byte("ALTER TABLE users ADD status text;")},
+		"000002_add_status.down.sql":         {Data: []byte("ALTER TABLE users DROP status;")},
+		"000001_create_users_table.up.sql":   {Data: []byte("CREATE TABLE users ();")},
+		"000001_create_users_table.down.sql": {Data: []byte("DROP TABLE users;")},
+		"000003_seed.up.sql":                 {Data: []byte("INSERT INTO users DEFAULT VALUES;")},
+		"embed.go":                           {Data: []byte("package migrations")},
+	}
+
+	loaded, err := Load(fsys)
+
+	require.NoError(t, err)
+	require.Len(t, loaded, 3)
+	assert.Equal(t, Migration{Version: 1, Name: "create_users_table", Up: "CREATE TABLE users ();", Down: "DROP TABLE users;"}, loaded[0])
+	assert.Equal(t, uint(2), loaded[1].Version)
+	assert.Equal(t, "3_seed", loaded[2].String())
+	assert.Empty(t, loaded[2].Down)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := map[string]fstest.MapFS{
+		"missing up file": {
+			"000001_create_users_table.down.sql": {Data: []byte("DROP TABLE users;")},
+		},
+		"duplicate version": {
+			"000001_create_users_table.up.sql": {Data: []byte("CREATE TABLE users ();")},
+			"000001_create_roles_table.up.sql": {Data: []byte("CREATE TABLE roles ();")},
+		},
+		"zero version": {
+			"000000_init.up.sql": {Data: []byte("SELECT 1;")},
+		},
+	}
+
+	for name, fsys := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Load(fsys)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoad_EmbeddedMigrations(t *testing.T) {
+	loaded, err := Load(migrations.FS)
+
+	require.NoError(t, err)
+	require.NotEmpty(t, loaded)
+	assert.Equal(t, uint(1), loaded[0].Version)
+	for _, migration := range loaded {
+		assert.NotEmpty(t, migration.Down, "migration %s should be reversible", migration)
+	}
+}
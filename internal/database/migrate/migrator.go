@@ -0,0 +1,256 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"hub-user-service/internal/database"
+)
+
+// The schema_migrations table layout matches golang-migrate, so databases migrated
+// with the migrate CLI keep working and can be inspected with either tool
+const (
+	createVersionTableQuery = `CREATE TABLE IF NOT EXISTS schema_migrations (version bigint NOT NULL PRIMARY KEY, dirty boolean NOT NULL)`
+	versionTableExistsQuery = `SELECT to_regclass('schema_migrations') IS NOT NULL`
+	selectVersionQuery      = `SELECT version, dirty FROM schema_migrations LIMIT 1`
+	deleteVersionQuery      = `DELETE FROM schema_migrations`
+	insertVersionQuery      = `INSERT INTO schema_migrations (version, dirty) VALUES ($1, false)`
+	advisoryLockQuery       = `SELECT pg_advisory_xact_lock($1)`
+)
+
+// advisoryLockKey identifies the migration lock ("hub-user" in ASCII)
+const advisoryLockKey int64 = 0x6875622d75736572
+
+var (
+	// ErrDirty is returned when a previous migration run failed half way (golang-migrate dirty flag)
+	ErrDirty = errors.New("database schema is dirty, fix it manually and force the version")
+
+	// ErrUnknownVersion is returned when the database is at a version this binary does not know
+	ErrUnknownVersion = errors.New("database schema version is unknown to this binary")
+
+	// ErrIrreversible is returned when rolling back a migration without a down file
+	ErrIrreversible = errors.New("migration has no down file")
+
+	// ErrSchemaMismatch is returned by Verify when the schema is not at the expected version
+	ErrSchemaMismatch = errors.New("database schema version does not match the binary")
+)
+
+// schemaVersion is a row of schema_migrations
+type schemaVersion struct {
+	Version int64 `db:"version"`
+	Dirty   bool  `db:"dirty"`
+}
+
+// Status describes the schema state of a database
+type Status struct {
+	Current uint
+	Dirty   bool
+	Latest  uint
+	Pending []Migration
+}
+
+// Migrator applies migrations to a database
+//
+// A run executes in a single transaction holding a PostgreSQL advisory lock, so concurrent
+// replicas serialize and a failing migration leaves the schema untouched. Migrations must
+// therefore not use statements that cannot run in a transaction (CREATE INDEX CONCURRENTLY).
+type Migrator struct {
+	db         database.Database
+	migrations []Migration
+}
+
+// NewMigrator creates a migrator for migrations sorted by version (see Load)
+func NewMigrator(db database.Database, migrations []Migration) *Migrator {
+	return &Migrator{db: db, migrations: migrations}
+}
+
+// Latest returns the version of the newest migration, the version this binary expects
+func (m *Migrator) Latest() uint {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Up applies all pending migrations
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	return m.run(ctx, func(uint) (uint, error) {
+		return m.Latest(), nil
+	})
+}
+
+// Down rolls back the most recently applied migration
+func (m *Migrator) Down(ctx context.Context) ([]Migration, error) {
+	return m.run(ctx, func(current uint) (uint, error) {
+		index := m.indexOf(current)
+		if index <= 0 {
+			return 0, nil
+		}
+		return m.migrations[index-1].Version, nil
+	})
+}
+
+// To migrates up or down to version; 0 rolls back every migration
+func (m *Migrator) To(ctx context.Context, version uint) ([]Migration, error) {
+	if version != 0 && m.indexOf(version) < 0 {
+		return nil, fmt.Errorf("unknown migration version %d", version)
+	}
+	return m.run(ctx, func(uint) (uint, error) {
+		return version, nil
+	})
+}
+
+// Status reports the current and pending versions without taking the migration lock
+func (m *Migrator) Status(ctx context.Context) (Status, error) {
+	// A lagging replica would report the version before the last migrate run
+	ctx = database.WithPrimary(ctx)
+
+	var exists bool
+	if err := m.db.GetContext(ctx, &exists, versionTableExistsQuery); err != nil {
+		return Status{}, fmt.Errorf("failed to check schema_migrations: %w", err)
+	}
+
+	status := Status{Latest: m.Latest()}
+	if exists {
+		version, err := readVersion(ctx, m.db)
+		if err != nil {
+			return Status{}, err
+		}
+		status.Current = uint(version.Version)
+		status.Dirty = version.Dirty
+	}
+
+	for _, migration := range m.migrations {
+		if migration.Version > status.Current {
+			status.Pending = append(status.Pending, migration)
+		}
+	}
+	return status, nil
+}
+
+// Verify returns ErrSchemaMismatch unless the database is clean and at the latest version
+func (m *Migrator) Verify(ctx context.Context) error {
+	status, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	if status.Dirty || status.Current != status.Latest {
+		return fmt.Errorf("%w: database at version %d (dirty=%t), binary expects %d",
+			ErrSchemaMismatch, status.Current, status.Dirty, status.Latest)
+	}
+	return nil
+}
+
+// run locks the schema, resolves the target version and applies the migrations in between
+func (m *Migrator) run(ctx context.Context, target func(current uint) (uint, error)) ([]Migration, error) {
+	var applied []Migration
+
+	err := database.WithinTx(ctx, m.db, func(tx database.Transaction) error {
+		if _, err := tx.ExecContext(ctx, advisoryLockQuery, advisoryLockKey); err != nil {
+			return fmt.Errorf("failed to acquire migration lock: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, createVersionTableQuery); err != nil {
+			return fmt.Errorf("failed to create schema_migrations: %w", err)
+		}
+
+		version, err := readVersion(ctx, tx)
+		if err != nil {
+			return err
+		}
+		if version.Dirty {
+			return fmt.Errorf("%w (version %d)", ErrDirty, version.Version)
+		}
+
+		current := uint(version.Version)
+		if current != 0 && m.indexOf(current) < 0 {
+			return fmt.Errorf("%w: %d", ErrUnknownVersion, current)
+		}
+
+		to, err := target(current)
+		if err != nil {
+			return err
+		}
+
+		applied, err = m.apply(ctx, tx, current, to)
+		if err != nil {
+			return err
+		}
+
+		if to == current {
+			return nil
+		}
+		if _, err := tx.ExecContext(ctx, deleteVersionQuery); err != nil {
+			return fmt.Errorf("failed to update schema version: %w", err)
+		}
+		if to != 0 {
+			if _, err := tx.ExecContext(ctx, insertVersionQuery, int64(to)); err != nil {
+				return fmt.Errorf("failed to update schema version: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return applied, nil
+}
+
+// apply executes the up files in (from, to] or the down files in (to, from] in reverse order
+func (m *Migrator) apply(ctx context.Context, tx database.Transaction, from, to uint) ([]Migration, error) {
+	var applied []Migration
+
+	if to > from {
+		for _, migration := range m.migrations {
+			if migration.Version <= from || migration.Version > to {
+				continue
+			}
+			if _, err := tx.ExecContext(ctx, migration.Up); err != nil {
+				return nil, fmt.Errorf("migration %s up failed: %w", migration, err)
+			}
+			applied = append(applied, migration)
+		}
+		return applied, nil
+	}
+
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		migration := m.migrations[i]
+		if migration.Version > from || migration.Version <= to {
+			continue
+		}
+		if migration.Down == "" {
+			return nil, fmt.Errorf("%w: %s", ErrIrreversible, migration)
+		}
+		if _, err := tx.ExecContext(ctx, migration.Down); err != nil {
+			return nil, fmt.Errorf("migration %s down failed: %w", migration, err)
+		}
+		applied = append(applied, migration)
+	}
+	return applied, nil
+}
+
+// indexOf returns the index of version in the migration list or -1
+func (m *Migrator) indexOf(version uint) int {
+	for i, migration := range m.migrations {
+		if migration.Version == version {
+			return i
+		}
+	}
+	return -1
+}
+
+// readVersion reads the schema version; an empty table means nothing is applied
+func readVersion(ctx context.Context, q database.Querier) (schemaVersion, error) {
+	var version schemaVersion
+	err := q.GetContext(ctx, &version, selectVersionQuery)
+	if errors.Is(err, sql.ErrNoRows) {
+		return schemaVersion{}, nil
+	}
+	if err != nil {
+		return schemaVersion{}, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return version, nil
+}
@@ -0,0 +1,254 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"hub-user-service/internal/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// schemaState is the part of the database the migrator can observe
+type schemaState struct {
+	tableExists bool
+	version     *schemaVersion
+	executed    []string
+}
+
+func (s schemaState) clone() schemaState {
+	clone := s
+	clone.executed = append([]string(nil), s.executed...)
+	if s.version != nil {
+		version := *s.version
+		clone.version = &version
+	}
+	return clone
+}
+
+// fakeDatabase interprets the migrator's bookkeeping queries and records migration SQL
+// Transactions work on a copy of the state that is only written back on commit
+type fakeDatabase struct {
+	database.Database
+	state  schemaState
+	failOn string
+	locks  int
+}
+
+type fakeTransaction struct {
+	database.Transaction
+	db    *fakeDatabase
+	state schemaState
+}
+
+type fakeResult struct {
+	database.Result
+}
+
+func (f *fakeDatabase) BeginTx(ctx context.Context, opts *sql.TxOptions) (database.Transaction, error) {
+	return &fakeTransaction{db: f, state: f.state.clone()}, nil
+}
+
+func (f *fakeDatabase) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return getState(&f.state, dest, query)
+}
+
+func (t *fakeTransaction) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return getState(&t.state, dest, query)
+}
+
+func (t *fakeTransaction) ExecContext(ctx context.Context, query string, args ...interface{}) (database.Result, error) {
+	switch query {
+	case advisoryLockQuery:
+		t.db.locks++
+	case createVersionTableQuery:
+		t.state.tableExists = true
+	case deleteVersionQuery:
+		t.state.version = nil
+	case insertVersionQuery:
+		t.state.version = &schemaVersion{Version: args[0].(int64)}
+	default:
+		if query == t.db.failOn {
+			return nil, errors.New("syntax error")
+		}
+		t.state.executed = append(t.state.executed, query)
+	}
+	return fakeResult{}, nil
+}
+
+func (t *fakeTransaction) Commit() error {
+	t.db.state = t.state
+	return nil
+}
+
+func (t *fakeTransaction) Rollback() error {
+	return nil
+}
+
+func getState(state *schemaState, dest interface{}, query string) error {
+	switch query {
+	case versionTableExistsQuery:
+		*dest.(*bool) = state.tableExists
+		return nil
+	case selectVersionQuery:
+		if !state.tableExists {
+			return errors.New(`relation "schema_migrations" does not exist`)
+		}
+		if state.version == nil {
+			return sql.ErrNoRows
+		}
+		*dest.(*schemaVersion) = *state.version
+		return nil
+	}
+	return errors.New("unexpected query: " + query)
+}
+
+func testMigrations() []Migration {
+	return []Migration{
+		{Version: 1, Name: "create_users", Up: "up 1", Down: "down 1"},
+		{Version: 2, Name: "add_status", Up: "up 2", Down: "down 2"},
+		{Version: 5, Name: "add_sessions", Up: "up 5", Down: "down 5"},
+	}
+}
+
+func versionOf(db *fakeDatabase) int64 {
+	if db.state.version == nil {
+		return 0
+	}
+	return db.state.version.Version
+}
+
+func TestMigrator_Up(t *testing.T) {
+	db := &fakeDatabase{}
+	migrator := NewMigrator(db, testMigrations())
+
+	applied, err := migrator.Up(context.Background())
+
+	require.NoError(t, err)
+	assert.Len(t, applied, 3)
+	assert.Equal(t, []string{"up 1", "up 2", "up 5"}, db.state.executed)
+	assert.Equal(t, int64(5), versionOf(db))
+	assert.Equal(t, 1, db.locks)
+
+	// Running again is a no-op
+	applied, err = migrator.Up(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+	assert.Len(t, db.state.executed, 3)
+}
+
+func TestMigrator_Down(t *testing.T) {
+	db := &fakeDatabase{state: schemaState{tableExists: true, version: &schemaVersion{Version: 5}}}
+	migrator := NewMigrator(db, testMigrations())
+
+	applied, err := migrator.Down(context.Background())
+	require.NoError(t, err)
+	require.Len(t, applied, 1)
+	assert.Equal(t, uint(5), applied[0].Version)
+	assert.Equal(t, int64(2), versionOf(db))
+
+	_, err = migrator.Down(context.Background())
+	require.NoError(t, err)
+	_, err = migrator.Down(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"down 5", "down 2", "down 1"}, db.state.executed)
+	assert.Nil(t, db.state.version)
+
+	// Nothing left to roll back
+	applied, err = migrator.Down(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+}
+
+func TestMigrator_To(t *testing.T) {
+	db := &fakeDatabase{}
+	migrator := NewMigrator(db, testMigrations())
+
+	_, err := migrator.To(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), versionOf(db))
+
+	_, err = migrator.To(context.Background(), 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"up 1", "up 2", "down 2", "down 1"}, db.state.executed)
+
+	_, err = migrator.To(context.Background(), 3)
+	assert.Error(t, err)
+}
+
+func TestMigrator_FailedMigrationRollsBack(t *testing.T) {
+	db := &fakeDatabase{failOn: "up 2"}
+	migrator := NewMigrator(db, testMigrations())
+
+	_, err := migrator.Up(context.Background())
+
+	assert.ErrorContains(t, err, "migration 2_add_status up failed")
+	assert.Empty(t, db.state.executed)
+	assert.Nil(t, db.state.version)
+}
+
+func TestMigrator_RefusesDirtyAndUnknownVersions(t *testing.T) {
+	dirty := &fakeDatabase{state: schemaState{tableExists: true, version: &schemaVersion{Version: 2, Dirty: true}}}
+	_, err := NewMigrator(dirty, testMigrations()).Up(context.Background())
+	assert.ErrorIs(t, err, ErrDirty)
+
+	ahead := &fakeDatabase{state: schemaState{tableExists: true, version: &schemaVersion{Version: 9}}}
+	_, err = NewMigrator(ahead, testMigrations()).Up(context.Background())
+	assert.ErrorIs(t, err, ErrUnknownVersion)
+}
+
+func TestMigrator_DownWithoutDownFile(t *testing.T) {
+	db := &fakeDatabase{state: schemaState{tableExists: true, version: &schemaVersion{Version: 1}}}
+	migrator := NewMigrator(db, []Migration{{Version: 1, Name: "seed", Up: "up 1"}})
+
+	_, err := migrator.Down(context.Background())
+
+	assert.ErrorIs(t, err, ErrIrreversible)
+	assert.Equal(t, int64(1), versionOf(db))
+}
+
+func TestMigrator_StatusAndVerify(t *testing.T) {
+	db := &fakeDatabase{}
+	migrator := NewMigrator(db, testMigrations())
+
+	status, err := migrator.Status(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint(0), status.Current)
+	assert.Equal(t, uint(5), status.Latest)
+	assert.Len(t, status.Pending, 3)
+	assert.ErrorIs(t, migrator.Verify(context.Background()), ErrSchemaMismatch)
+
+	_, err = migrator.To(context.Background(), 2)
+	require.NoError(t, err)
+	status, err = migrator.Status(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), status.Current)
+	require.Len(t, status.Pending, 1)
+	assert.Equal(t, uint(5), status.Pending[0].Version)
+
+	_, err = migrator.Up(context.Background())
+	require.NoError(t, err)
+	assert.NoError(t, migrator.Verify(context.Background()))
+
+	db.state.version.Dirty = true
+	assert.ErrorIs(t, migrator.Verify(context.Background()), ErrSchemaMismatch)
+}
+
+func TestMigrator_StatusReadsPrimary(t *testing.T) {
+	primary := &fakeDatabase{}
+	migrator := NewMigrator(primary, testMigrations())
+	_, err := migrator.Up(context.Background())
+	require.NoError(t, err)
+
+	// The replica has not replayed the migrations yet
+	lagging := &fakeDatabase{}
+	routed := NewMigrator(database.NewRoutingDatabase(primary, []database.Database{lagging}, 0), testMigrations())
+
+	status, err := routed.Status(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint(5), status.Current)
+	assert.NoError(t, routed.Verify(context.Background()))
+}
@@ -0,0 +1,32 @@
+package database
+
+import (
+	"expvar"
+)
+
+// poolStats holds one entry per published pool, served on /debug/vars as "database_pool"
+var poolStats = expvar.NewMap("database_pool")
+
+// PublishPoolStats publishes the connection pool statistics of db under name
+// Values are read from db on every scrape; publishing the same name again replaces the previous pool
+func PublishPoolStats(name string, db Database) {
+	poolStats.Set(name, expvar.Func(func() interface{} {
+		return poolStatsSnapshot(db)
+	}))
+}
+
+// poolStatsSnapshot converts sql.DBStats into the published metric names
+func poolStatsSnapshot(db Database) map[string]interface{} {
+	stats := db.Stats()
+	return map[string]interface{}{
+		"max_open_connections": stats.MaxOpenConnections,
+		"open_connections":     stats.OpenConnections,
+		"in_use":               stats.InUse,
+		"idle":                 stats.Idle,
+		"wait_count":           stats.WaitCount,
+		"wait_duration_ms":     stats.WaitDuration.Milliseconds(),
+		"max_idle_closed":      stats.MaxIdleClosed,
+		"max_idle_time_closed": stats.MaxIdleTimeClosed,
+		"max_lifetime_closed":  stats.MaxLifetimeClosed,
+	}
+}
@@ -0,0 +1,49 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"expvar"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// statsDatabase is a Database that only reports fixed pool statistics
+type statsDatabase struct {
+	Database
+	stats sql.DBStats
+}
+
+func (s *statsDatabase) Stats() sql.DBStats {
+	return s.stats
+}
+
+func TestPublishPoolStats(t *testing.T) {
+	db := &statsDatabase{stats: sql.DBStats{
+		MaxOpenConnections: 25,
+		OpenConnections:    7,
+		InUse:              5,
+		Idle:               2,
+		WaitCount:          3,
+		WaitDuration:       1500 * time.Millisecond,
+	}}
+
+	PublishPoolStats("test", db)
+
+	var published map[string]map[string]int64
+	require.NoError(t, json.Unmarshal([]byte(expvar.Get("database_pool").String()), &published))
+	assert.Equal(t, int64(25), published["test"]["max_open_connections"])
+	assert.Equal(t, int64(7), published["test"]["open_connections"])
+	assert.Equal(t, int64(5), published["test"]["in_use"])
+	assert.Equal(t, int64(3), published["test"]["wait_count"])
+	assert.Equal(t, int64(1500), published["test"]["wait_duration_ms"])
+
+	// Values are read live and republishing replaces the pool
+	db.stats.InUse = 1
+	PublishPoolStats("test", db)
+	require.NoError(t, json.Unmarshal([]byte(expvar.Get("database_pool").String()), &published))
+	assert.Equal(t, int64(1), published["test"]["in_use"])
+}
@@ -0,0 +1,150 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+var (
+	// queryMetrics holds one latency histogram per query, served on /debug/vars as "database_queries"
+	queryMetrics = expvar.NewMap("database_queries")
+
+	// queryErrors counts failed queries by SQLSTATE, served on /debug/vars as "database_errors"
+	queryErrors = expvar.NewMap("database_errors")
+
+	// histogramsMu guards creation of histograms in queryMetrics
+	histogramsMu sync.Mutex
+)
+
+// latencyBuckets are the upper bounds of the query duration histogram buckets
+var latencyBuckets = []time.Duration{
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+}
+
+// maxQueryKeyLength bounds the metric key derived from a query
+const maxQueryKeyLength = 200
+
+// latencyHistogram is a cumulative duration histogram published as an expvar.Var
+type latencyHistogram struct {
+	mu      sync.Mutex
+	buckets []int64 // one per latencyBuckets entry plus +Inf
+	count   int64
+	errors  int64
+	sum     time.Duration
+}
+
+func newLatencyHistogram() *latencyHistogram {
+	return &latencyHistogram{buckets: make([]int64, len(latencyBuckets)+1)}
+}
+
+// observe records one query duration and whether it failed
+func (h *latencyHistogram) observe(duration time.Duration, failed bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	i := 0
+	for i < len(latencyBuckets) && duration > latencyBuckets[i] {
+		i++
+	}
+	h.buckets[i]++
+	h.count++
+	h.sum += duration
+	if failed {
+		h.errors++
+	}
+}
+
+// String renders the histogram as JSON with cumulative bucket counts keyed by upper bound in milliseconds
+func (h *latencyHistogram) String() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	buckets := make(map[string]int64, len(h.buckets))
+	var cumulative int64
+	for i, n := range h.buckets {
+		cumulative += n
+		key := "+Inf"
+		if i < len(latencyBuckets) {
+			key = formatMillis(latencyBuckets[i])
+		}
+		buckets[key] = cumulative
+	}
+
+	out, _ := json.Marshal(map[string]interface{}{
+		"count":      h.count,
+		"errors":     h.errors,
+		"sum_ms":     float64(h.sum) / float64(time.Millisecond),
+		"buckets_ms": buckets,
+	})
+	return string(out)
+}
+
+func formatMillis(d time.Duration) string {
+	out, _ := json.Marshal(float64(d) / float64(time.Millisecond))
+	return string(out)
+}
+
+// histogramFor returns the histogram of key, creating it on first use
+func histogramFor(key string) *latencyHistogram {
+	if h, ok := queryMetrics.Get(key).(*latencyHistogram); ok {
+		return h
+	}
+
+	histogramsMu.Lock()
+	defer histogramsMu.Unlock()
+	if h, ok := queryMetrics.Get(key).(*latencyHistogram); ok {
+		return h
+	}
+	h := newLatencyHistogram()
+	queryMetrics.Set(key, h)
+	return h
+}
+
+// normalizeQuery collapses whitespace so the same statement formatted differently shares a metric
+func normalizeQuery(query string) string {
+	normalized := strings.Join(strings.Fields(query), " ")
+	if len(normalized) > maxQueryKeyLength {
+		normalized = normalized[:maxQueryKeyLength] + "..."
+	}
+	return normalized
+}
+
+// isQueryFailure reports whether err counts as a failed query; sql.ErrNoRows is an empty result
+func isQueryFailure(err error) bool {
+	return err != nil && !errors.Is(err, sql.ErrNoRows)
+}
+
+// errorCode classifies err for the error counters: the SQLSTATE for server errors, otherwise a short label
+func errorCode(err error) string {
+	var pqErr *pq.Error
+	switch {
+	case errors.As(err, &pqErr):
+		return string(pqErr.Code)
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "deadline_exceeded"
+	case isConnectionError(err):
+		return "connection"
+	default:
+		return "other"
+	}
+}
@@ -0,0 +1,102 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// RetryPolicy is a bounded exponential backoff
+// The delay before retry n is InitialBackoff * 2^(n-1), capped at MaxBackoff, with jitter
+// between half and the full delay so replicas restarting together do not retry in lockstep.
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+
+	// OnRetry, when set, is called before waiting for the next attempt
+	OnRetry func(attempt int, delay time.Duration, err error)
+}
+
+// Enabled reports whether the policy allows more than one attempt
+func (p RetryPolicy) Enabled() bool {
+	return p.MaxAttempts > 1
+}
+
+// backoff returns the capped delay before the attempt following attempt (1-based), without jitter
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.InitialBackoff
+	for i := 1; i < attempt && delay < p.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	return delay
+}
+
+// jittered returns a random delay in [delay/2, delay]
+func jittered(delay time.Duration) time.Duration {
+	if delay <= 1 {
+		return delay
+	}
+	half := delay / 2
+	return half + rand.N(delay-half+1)
+}
+
+// Retry calls fn until it succeeds, returns an error that is not transient (see IsTransientError),
+// the attempts are exhausted or ctx is done
+func Retry(ctx context.Context, policy RetryPolicy, fn func() error) error {
+	attempts := policy.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || !IsTransientError(err) {
+			return err
+		}
+		if attempt >= attempts {
+			if attempts > 1 {
+				return fmt.Errorf("giving up after %d attempts: %w", attempts, err)
+			}
+			return err
+		}
+
+		delay := jittered(policy.backoff(attempt))
+		if policy.OnRetry != nil {
+			policy.OnRetry(attempt, delay, err)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Join(err, ctx.Err())
+		}
+	}
+}
+
+// IsTransientError reports whether retrying the operation may succeed: connection failures
+// (SQLSTATE class 08, server starting or shutting down), serialization failures (40001, which
+// includes hot standby recovery conflicts) and deadlocks (40P01)
+func IsTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if isConnectionError(err) {
+		return true
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == "40001" || pqErr.Code == "40P01"
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var fastPolicy = RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+
+func TestRetryPolicy_BackoffDoublesUpToMax(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 10, InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	assert.Equal(t, 100*time.Millisecond, policy.backoff(1))
+	assert.Equal(t, 200*time.Millisecond, policy.backoff(2))
+	assert.Equal(t, 800*time.Millisecond, policy.backoff(4))
+	assert.Equal(t, time.Second, policy.backoff(5))
+	assert.Equal(t, time.Second, policy.backoff(50))
+}
+
+func TestJittered_StaysWithinHalfAndFullDelay(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		delay := jittered(time.Second)
+		assert.GreaterOrEqual(t, delay, 500*time.Millisecond)
+		assert.LessOrEqual(t, delay, time.Second)
+	}
+}
+
+func TestRetry_RetriesTransientErrors(t *testing.T) {
+	calls := 0
+	var retries []int
+	policy := fastPolicy
+	policy.OnRetry = func(attempt int, delay time.Duration, err error) { retries = append(retries, attempt) }
+
+	err := Retry(context.Background(), policy, func() error {
+		calls++
+		if calls < 3 {
+			return &pq.Error{Code: "57P03"}
+		}
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, []int{1, 2}, retries)
+}
+
+func TestRetry_DoesNotRetryPermanentErrors(t *testing.T) {
+	calls := 0
+	permanent := &pq.Error{Code: "23505"}
+
+	err := Retry(context.Background(), fastPolicy, func() error {
+		calls++
+		return permanent
+	})
+
+	assert.Equal(t, permanent, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetry_GivesUpAfterMaxAttempts(t *testing.T) {
+	calls := 0
+
+	err := Retry(context.Background(), fastPolicy, func() error {
+		calls++
+		return driver.ErrBadConn
+	})
+
+	assert.ErrorIs(t, err, driver.ErrBadConn)
+	assert.Contains(t, err.Error(), "giving up after 3 attempts")
+	assert.Equal(t, 3, calls)
+}
+
+func TestRetry_StopsWhenContextIsDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	policy := RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Hour, MaxBackoff: time.Hour}
+	policy.OnRetry = func(int, time.Duration, error) { cancel() }
+
+	err := Retry(ctx, policy, func() error { return driver.ErrBadConn })
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, err, driver.ErrBadConn)
+}
+
+func TestIsTransientError(t *testing.T) {
+	assert.True(t, IsTransientError(&pq.Error{Code: "08006"}))
+	assert.True(t, IsTransientError(&pq.Error{Code: "40001"}))
+	assert.True(t, IsTransientError(&pq.Error{Code: "40P01"}))
+	assert.True(t, IsTransientError(driver.ErrBadConn))
+	assert.False(t, IsTransientError(&pq.Error{Code: "42P01"}))
+	assert.False(t, IsTransientError(errors.New("sql: no rows in result set")))
+	assert.False(t, IsTransientError(context.DeadlineExceeded))
+	assert.False(t, IsTransientError(nil))
+}
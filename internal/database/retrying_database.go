@@ -0,0 +1,115 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+)
+
+// RetryingDatabase retries idempotent reads failing with transient errors (see IsTransientError)
+//
+// Only the query step is retried: an error while iterating Rows is returned as-is. Writes and
+// transactions, including writes run through read methods (see WithWrite), are passed through
+// unchanged, since retrying them is only safe for the caller.
+type RetryingDatabase struct {
+	Database
+	policy RetryPolicy
+}
+
+// NewRetryingDatabase wraps db so reads are retried according to policy
+func NewRetryingDatabase(db Database, policy RetryPolicy) *RetryingDatabase {
+	return &RetryingDatabase{Database: db, policy: policy}
+}
+
+// policyFor returns the retry policy for ctx, which makes a single attempt for writes
+func (r *RetryingDatabase) policyFor(ctx context.Context) RetryPolicy {
+	if isWrite(ctx) {
+		return RetryPolicy{}
+	}
+	return r.policy
+}
+
+// Query executes a query, retrying transient failures
+func (r *RetryingDatabase) Query(query string, args ...interface{}) (Rows, error) {
+	return r.QueryContext(context.Background(), query, args...)
+}
+
+// QueryContext executes a query, retrying transient failures
+func (r *RetryingDatabase) QueryContext(ctx context.Context, query string, args ...interface{}) (Rows, error) {
+	var rows Rows
+	err := Retry(ctx, r.policyFor(ctx), func() error {
+		var err error
+		rows, err = r.Database.QueryContext(ctx, query, args...)
+		return err
+	})
+	return rows, err
+}
+
+// QueryRow executes a single row query, retrying transient failures on Scan
+func (r *RetryingDatabase) QueryRow(query string, args ...interface{}) Row {
+	return r.QueryRowContext(context.Background(), query, args...)
+}
+
+// QueryRowContext executes a single row query, retrying transient failures on Scan
+func (r *RetryingDatabase) QueryRowContext(ctx context.Context, query string, args ...interface{}) Row {
+	return &retryingRow{ctx: ctx, db: r, query: query, args: args, row: r.Database.QueryRowContext(ctx, query, args...)}
+}
+
+// Get reads a single row, retrying transient failures
+func (r *RetryingDatabase) Get(dest interface{}, query string, args ...interface{}) error {
+	return r.GetContext(context.Background(), dest, query, args...)
+}
+
+// GetContext reads a single row, retrying transient failures
+func (r *RetryingDatabase) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return Retry(ctx, r.policyFor(ctx), func() error {
+		return r.Database.GetContext(ctx, dest, query, args...)
+	})
+}
+
+// Select reads rows, retrying transient failures
+func (r *RetryingDatabase) Select(dest interface{}, query string, args ...interface{}) error {
+	return r.SelectContext(context.Background(), dest, query, args...)
+}
+
+// SelectContext reads rows, retrying transient failures
+func (r *RetryingDatabase) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return Retry(ctx, r.policyFor(ctx), func() error {
+		return r.Database.SelectContext(ctx, dest, query, args...)
+	})
+}
+
+// Stats returns the wrapped database pool statistics
+func (r *RetryingDatabase) Stats() sql.DBStats {
+	return r.Database.Stats()
+}
+
+// retryingRow re-runs the query when Scan fails with a transient error
+type retryingRow struct {
+	ctx   context.Context
+	db    *RetryingDatabase
+	query string
+	args  []interface{}
+	row   Row
+	err   error
+}
+
+// Scan copies the row into dest, re-running the query on transient failures
+func (r *retryingRow) Scan(dest ...interface{}) error {
+	first := true
+	r.err = Retry(r.ctx, r.db.policyFor(r.ctx), func() error {
+		if !first {
+			r.row = r.db.Database.QueryRowContext(r.ctx, r.query, r.args...)
+		}
+		first = false
+		return r.row.Scan(dest...)
+	})
+	return r.err
+}
+
+// Err returns the error of the last Scan or of the query
+func (r *retryingRow) Err() error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.row.Err()
+}
@@ -0,0 +1,98 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+// flakyRow fails Scan with err, then succeeds
+type flakyRow struct {
+	err error
+}
+
+func (r *flakyRow) Scan(dest ...interface{}) error {
+	if r.err != nil {
+		return r.err
+	}
+	*dest[0].(*string) = "ok"
+	return nil
+}
+
+func (r *flakyRow) Err() error { return r.err }
+
+// flakyDatabase fails its first reads with transient errors and every write
+type flakyDatabase struct {
+	routedDatabase
+	failures int
+	rows     int
+}
+
+func (d *flakyDatabase) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	if int(d.reads.Add(1)) <= d.failures {
+		return &pq.Error{Code: "40001"}
+	}
+	*dest.(*string) = "ok"
+	return nil
+}
+
+func (d *flakyDatabase) QueryRowContext(ctx context.Context, query string, args ...interface{}) Row {
+	d.rows++
+	if d.rows <= d.failures {
+		return &flakyRow{err: &pq.Error{Code: "08006"}}
+	}
+	return &flakyRow{}
+}
+
+func (d *flakyDatabase) ExecContext(ctx context.Context, query string, args ...interface{}) (Result, error) {
+	d.writes.Add(1)
+	return nil, &pq.Error{Code: "40001"}
+}
+
+func TestRetryingDatabase_RetriesReads(t *testing.T) {
+	inner := &flakyDatabase{failures: 2}
+	db := NewRetryingDatabase(inner, fastPolicy)
+
+	var name string
+	err := db.GetContext(context.Background(), &name, "SELECT name FROM users")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", name)
+	assert.Equal(t, int32(3), inner.reads.Load())
+}
+
+func TestRetryingDatabase_RequeriesRowOnTransientScanError(t *testing.T) {
+	inner := &flakyDatabase{failures: 1}
+	db := NewRetryingDatabase(inner, fastPolicy)
+
+	var name string
+	row := db.QueryRowContext(context.Background(), "SELECT name FROM users WHERE id = $1", 1)
+
+	assert.NoError(t, row.Scan(&name))
+	assert.NoError(t, row.Err())
+	assert.Equal(t, "ok", name)
+	assert.Equal(t, 2, inner.rows)
+}
+
+func TestRetryingDatabase_DoesNotRetryWrites(t *testing.T) {
+	inner := &flakyDatabase{}
+	db := NewRetryingDatabase(inner, fastPolicy)
+
+	_, err := db.ExecContext(context.Background(), "UPDATE users SET name = $1", "x")
+
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), inner.writes.Load())
+}
+
+func TestRetryingDatabase_DoesNotRetryWritesThroughReads(t *testing.T) {
+	inner := &flakyDatabase{failures: 2}
+	db := NewRetryingDatabase(inner, fastPolicy)
+
+	var name string
+	err := db.GetContext(WithWrite(context.Background()), &name, "DELETE FROM users WHERE id = $1 RETURNING name", 1)
+
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), inner.reads.Load())
+}
@@ -0,0 +1,329 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"log"
+	"net"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// readYourWritesKey is the context key for the per-request stickiness marker
+type readYourWritesKey struct{}
+
+// primaryOnlyKey is the context key forcing reads to the primary
+type primaryOnlyKey struct{}
+
+// writeKey is the context key marking a write run through a read method
+type writeKey struct{}
+
+// stickyMarker records that a request has written to the primary
+type stickyMarker struct {
+	written atomic.Bool
+}
+
+// WithReadYourWrites returns a context in which reads go to the primary once a write
+// (Exec or a transaction) happened through it, so a request always sees its own writes.
+// The gRPC ReadYourWrites interceptor installs it for every request.
+func WithReadYourWrites(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(readYourWritesKey{}).(*stickyMarker); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, readYourWritesKey{}, &stickyMarker{})
+}
+
+// WithPrimary returns a context whose reads always go to the primary
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryOnlyKey{}, true)
+}
+
+// WithWrite returns a context for a statement that writes and returns rows (DELETE or UPDATE
+// ... RETURNING) run through GetContext or SelectContext: it goes to the primary, makes later reads
+// in ctx sticky to it and is never retried as a read
+func WithWrite(ctx context.Context) context.Context {
+	markWritten(ctx)
+	return context.WithValue(WithPrimary(ctx), writeKey{}, true)
+}
+
+// isWrite reports whether ctx was returned by WithWrite
+func isWrite(ctx context.Context) bool {
+	write, _ := ctx.Value(writeKey{}).(bool)
+	return write
+}
+
+// markWritten makes later reads in ctx sticky to the primary
+func markWritten(ctx context.Context) {
+	if marker, ok := ctx.Value(readYourWritesKey{}).(*stickyMarker); ok {
+		marker.written.Store(true)
+	}
+}
+
+// readsFromPrimary reports whether reads in ctx must go to the primary
+func readsFromPrimary(ctx context.Context) bool {
+	if primary, _ := ctx.Value(primaryOnlyKey{}).(bool); primary {
+		return true
+	}
+	marker, ok := ctx.Value(readYourWritesKey{}).(*stickyMarker)
+	return ok && marker.written.Load()
+}
+
+// replica is a read replica and its health state
+type replica struct {
+	name    string
+	db      Database
+	healthy atomic.Bool
+}
+
+// RoutingDatabase sends writes and transactions to the primary and context reads to healthy replicas
+//
+// Reads without a context (Query, QueryRow, Get, Select) cannot honor read-your-writes and go to
+// the primary. Replicas failing a health check or a read with a connection error are taken out of
+// rotation until the next successful health check; with no healthy replica reads use the primary.
+type RoutingDatabase struct {
+	primary  Database
+	replicas []*replica
+	next     atomic.Uint64
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// NewRoutingDatabase creates a routing database and starts health checking the replicas every
+// healthCheckInterval (disabled when zero)
+func NewRoutingDatabase(primary Database, replicas []Database, healthCheckInterval time.Duration) *RoutingDatabase {
+	r := &RoutingDatabase{primary: primary, stop: make(chan struct{})}
+	for i, db := range replicas {
+		rep := &replica{name: replicaName(i), db: db}
+		rep.healthy.Store(true)
+		r.replicas = append(r.replicas, rep)
+	}
+
+	if healthCheckInterval > 0 && len(r.replicas) > 0 {
+		r.wg.Add(1)
+		go r.healthCheckLoop(healthCheckInterval)
+	}
+	return r
+}
+
+// replicaName returns the name replicas are logged and published under
+func replicaName(index int) string {
+	return "replica_" + strconv.Itoa(index)
+}
+
+// Primary returns the primary database
+func (r *RoutingDatabase) Primary() Database {
+	return r.primary
+}
+
+// Replicas returns the replica databases by name
+func (r *RoutingDatabase) Replicas() map[string]Database {
+	replicas := make(map[string]Database, len(r.replicas))
+	for _, rep := range r.replicas {
+		replicas[rep.name] = rep.db
+	}
+	return replicas
+}
+
+// HealthyReplicas returns the number of replicas currently serving reads
+func (r *RoutingDatabase) HealthyReplicas() int {
+	healthy := 0
+	for _, rep := range r.replicas {
+		if rep.healthy.Load() {
+			healthy++
+		}
+	}
+	return healthy
+}
+
+// CheckHealth pings every replica and updates its health state
+func (r *RoutingDatabase) CheckHealth() {
+	for _, rep := range r.replicas {
+		err := rep.db.Ping()
+		wasHealthy := rep.healthy.Swap(err == nil)
+		if err != nil && wasHealthy {
+			log.Printf("⚠️  Database %s unhealthy, reads fail over: %v", rep.name, err)
+		} else if err == nil && !wasHealthy {
+			log.Printf("✅ Database %s healthy again", rep.name)
+		}
+	}
+}
+
+func (r *RoutingDatabase) healthCheckLoop(interval time.Duration) {
+	defer r.wg.Done()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			r.CheckHealth()
+		case <-r.stop:
+			return
+		}
+	}
+}
+
+// reader picks the database for a read in ctx; the replica is nil when the primary is used
+func (r *RoutingDatabase) reader(ctx context.Context) (Database, *replica) {
+	if len(r.replicas) == 0 || readsFromPrimary(ctx) {
+		return r.primary, nil
+	}
+
+	start := r.next.Add(1)
+	for i := range r.replicas {
+		rep := r.replicas[(start+uint64(i))%uint64(len(r.replicas))]
+		if rep.healthy.Load() {
+			return rep.db, rep
+		}
+	}
+	return r.primary, nil
+}
+
+// failover takes rep out of rotation when err shows its connection is broken
+// It reports whether the read should be retried on the primary
+func (r *RoutingDatabase) failover(rep *replica, err error) bool {
+	if rep == nil || err == nil || !isConnectionError(err) {
+		return false
+	}
+	if rep.healthy.Swap(false) {
+		log.Printf("⚠️  Database %s failed a read, failing over to primary: %v", rep.name, err)
+	}
+	return true
+}
+
+// isConnectionError reports whether err means the server could not be reached or the connection broke
+func isConnectionError(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) {
+		return true
+	}
+	// The caller gave up: context.DeadlineExceeded also implements net.Error, but the server is fine
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	// SQLSTATE class 08: connection exception; 57P01-57P03: server shutting down or starting
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		code := string(pqErr.Code)
+		return pqErr.Code.Class() == "08" || code == "57P01" || code == "57P02" || code == "57P03"
+	}
+	return false
+}
+
+// Query executes a query on the primary
+func (r *RoutingDatabase) Query(query string, args ...interface{}) (Rows, error) {
+	return r.primary.Query(query, args...)
+}
+
+// QueryContext executes a query on a replica unless ctx reads from the primary
+func (r *RoutingDatabase) QueryContext(ctx context.Context, query string, args ...interface{}) (Rows, error) {
+	db, rep := r.reader(ctx)
+	rows, err := db.QueryContext(ctx, query, args...)
+	if r.failover(rep, err) {
+		return r.primary.QueryContext(ctx, query, args...)
+	}
+	return rows, err
+}
+
+// QueryRow executes a single row query on the primary
+func (r *RoutingDatabase) QueryRow(query string, args ...interface{}) Row {
+	return r.primary.QueryRow(query, args...)
+}
+
+// QueryRowContext executes a single row query on a replica unless ctx reads from the primary
+// Errors surface on Scan, so a broken replica is only failed over by the health check
+func (r *RoutingDatabase) QueryRowContext(ctx context.Context, query string, args ...interface{}) Row {
+	db, _ := r.reader(ctx)
+	return db.QueryRowContext(ctx, query, args...)
+}
+
+// Exec executes a statement on the primary
+func (r *RoutingDatabase) Exec(query string, args ...interface{}) (Result, error) {
+	return r.primary.Exec(query, args...)
+}
+
+// ExecContext executes a statement on the primary and makes later reads in ctx sticky to it
+func (r *RoutingDatabase) ExecContext(ctx context.Context, query string, args ...interface{}) (Result, error) {
+	markWritten(ctx)
+	return r.primary.ExecContext(ctx, query, args...)
+}
+
+// Begin starts a transaction on the primary
+func (r *RoutingDatabase) Begin() (Transaction, error) {
+	return r.primary.Begin()
+}
+
+// BeginTx starts a transaction on the primary and makes later reads in ctx sticky to it
+func (r *RoutingDatabase) BeginTx(ctx context.Context, opts *sql.TxOptions) (Transaction, error) {
+	markWritten(ctx)
+	return r.primary.BeginTx(ctx, opts)
+}
+
+// Get reads a single row from the primary
+func (r *RoutingDatabase) Get(dest interface{}, query string, args ...interface{}) error {
+	return r.primary.Get(dest, query, args...)
+}
+
+// GetContext reads a single row from a replica unless ctx reads from the primary
+func (r *RoutingDatabase) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	db, rep := r.reader(ctx)
+	err := db.GetContext(ctx, dest, query, args...)
+	if r.failover(rep, err) {
+		return r.primary.GetContext(ctx, dest, query, args...)
+	}
+	return err
+}
+
+// Select reads rows from the primary
+func (r *RoutingDatabase) Select(dest interface{}, query string, args ...interface{}) error {
+	return r.primary.Select(dest, query, args...)
+}
+
+// SelectContext reads rows from a replica unless ctx reads from the primary
+func (r *RoutingDatabase) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	db, rep := r.reader(ctx)
+	err := db.SelectContext(ctx, dest, query, args...)
+	if r.failover(rep, err) {
+		return r.primary.SelectContext(ctx, dest, query, args...)
+	}
+	return err
+}
+
+// Ping verifies the primary connection
+func (r *RoutingDatabase) Ping() error {
+	return r.primary.Ping()
+}
+
+// Stats returns the primary connection pool statistics
+func (r *RoutingDatabase) Stats() sql.DBStats {
+	return r.primary.Stats()
+}
+
+// Close stops health checking and closes the primary and all replicas
+func (r *RoutingDatabase) Close() error {
+	r.stopOnce.Do(func() { close(r.stop) })
+	r.wg.Wait()
+
+	var errs []error
+	for _, rep := range r.replicas {
+		if err := rep.db.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := r.primary.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,216 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// routedDatabase answers reads with its own name and can be made to fail
+type routedDatabase struct {
+	Database
+	name    string
+	readErr error
+	pingErr error
+	reads   atomic.Int32
+	writes  atomic.Int32
+	closed  bool
+}
+
+func (d *routedDatabase) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	d.reads.Add(1)
+	if d.readErr != nil {
+		return d.readErr
+	}
+	*dest.(*string) = d.name
+	return nil
+}
+
+func (d *routedDatabase) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return d.GetContext(ctx, dest, query, args...)
+}
+
+func (d *routedDatabase) Get(dest interface{}, query string, args ...interface{}) error {
+	return d.GetContext(context.Background(), dest, query, args...)
+}
+
+func (d *routedDatabase) ExecContext(ctx context.Context, query string, args ...interface{}) (Result, error) {
+	d.writes.Add(1)
+	return nil, nil
+}
+
+func (d *routedDatabase) BeginTx(ctx context.Context, opts *sql.TxOptions) (Transaction, error) {
+	d.writes.Add(1)
+	return nil, nil
+}
+
+func (d *routedDatabase) Ping() error {
+	return d.pingErr
+}
+
+func (d *routedDatabase) Close() error {
+	d.closed = true
+	return nil
+}
+
+func servedBy(t *testing.T, ctx context.Context, db Database) string {
+	t.Helper()
+	var name string
+	require.NoError(t, db.GetContext(ctx, &name, "SELECT name FROM users"))
+	return name
+}
+
+func newTestRouting(replicas ...*routedDatabase) (*RoutingDatabase, *routedDatabase) {
+	primary := &routedDatabase{name: "primary"}
+	databases := make([]Database, len(replicas))
+	for i, replica := range replicas {
+		databases[i] = replica
+	}
+	return NewRoutingDatabase(primary, databases, 0), primary
+}
+
+func TestRoutingDatabase_ReadsGoToReplicasRoundRobin(t *testing.T) {
+	db, primary := newTestRouting(&routedDatabase{name: "a"}, &routedDatabase{name: "b"})
+
+	served := map[string]int{}
+	for i := 0; i < 4; i++ {
+		served[servedBy(t, context.Background(), db)]++
+	}
+
+	assert.Equal(t, map[string]int{"a": 2, "b": 2}, served)
+	assert.Zero(t, primary.reads.Load())
+}
+
+func TestRoutingDatabase_WritesAndTransactionsGoToPrimary(t *testing.T) {
+	replica := &routedDatabase{name: "replica"}
+	db, primary := newTestRouting(replica)
+
+	_, err := db.ExecContext(context.Background(), "UPDATE users SET name = $1", "Ada")
+	require.NoError(t, err)
+	_, err = db.BeginTx(context.Background(), nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(2), primary.writes.Load())
+	assert.Zero(t, replica.writes.Load())
+}
+
+func TestRoutingDatabase_ReadYourWrites(t *testing.T) {
+	db, _ := newTestRouting(&routedDatabase{name: "replica"})
+
+	ctx := WithReadYourWrites(context.Background())
+	assert.Equal(t, "replica", servedBy(t, ctx, db))
+
+	_, err := db.ExecContext(ctx, "UPDATE users SET name = $1", "Ada")
+	require.NoError(t, err)
+	assert.Equal(t, "primary", servedBy(t, ctx, db))
+
+	// Installing the marker again keeps the existing stickiness
+	assert.Equal(t, "primary", servedBy(t, WithReadYourWrites(ctx), db))
+
+	// Without a marker a write cannot be remembered
+	_, err = db.ExecContext(context.Background(), "UPDATE users SET name = $1", "Ada")
+	require.NoError(t, err)
+	assert.Equal(t, "replica", servedBy(t, context.Background(), db))
+}
+
+func TestRoutingDatabase_WithPrimaryAndContextlessReads(t *testing.T) {
+	db, _ := newTestRouting(&routedDatabase{name: "replica"})
+
+	assert.Equal(t, "primary", servedBy(t, WithPrimary(context.Background()), db))
+
+	var name string
+	require.NoError(t, db.Get(&name, "SELECT name FROM users"))
+	assert.Equal(t, "primary", name)
+}
+
+func TestRoutingDatabase_WithWrite(t *testing.T) {
+	db, _ := newTestRouting(&routedDatabase{name: "replica"})
+
+	ctx := WithReadYourWrites(context.Background())
+	assert.Equal(t, "primary", servedBy(t, WithWrite(ctx), db))
+	assert.Equal(t, "primary", servedBy(t, ctx, db), "later reads see the write")
+}
+
+func TestRoutingDatabase_NoReplicas(t *testing.T) {
+	db, _ := newTestRouting()
+	assert.Equal(t, "primary", servedBy(t, context.Background(), db))
+}
+
+func TestRoutingDatabase_HealthCheckFailover(t *testing.T) {
+	replica := &routedDatabase{name: "replica", pingErr: errors.New("connection refused")}
+	db, _ := newTestRouting(replica)
+
+	db.CheckHealth()
+	assert.Equal(t, 0, db.HealthyReplicas())
+	assert.Equal(t, "primary", servedBy(t, context.Background(), db))
+
+	replica.pingErr = nil
+	db.CheckHealth()
+	assert.Equal(t, 1, db.HealthyReplicas())
+	assert.Equal(t, "replica", servedBy(t, context.Background(), db))
+}
+
+func TestRoutingDatabase_ConnectionErrorFailsOverToPrimary(t *testing.T) {
+	broken := &routedDatabase{name: "broken", readErr: driver.ErrBadConn}
+	db, _ := newTestRouting(broken)
+
+	assert.Equal(t, "primary", servedBy(t, context.Background(), db))
+	assert.Equal(t, 0, db.HealthyReplicas())
+
+	// The unhealthy replica is skipped until a health check succeeds
+	assert.Equal(t, "primary", servedBy(t, context.Background(), db))
+	assert.Equal(t, int32(1), broken.reads.Load())
+}
+
+func TestRoutingDatabase_QueryErrorsAreNotFailedOver(t *testing.T) {
+	replica := &routedDatabase{name: "replica", readErr: sql.ErrNoRows}
+	db, primary := newTestRouting(replica)
+
+	var name string
+	err := db.GetContext(context.Background(), &name, "SELECT name FROM users WHERE id = $1", 1)
+
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.Equal(t, 1, db.HealthyReplicas())
+	assert.Zero(t, primary.reads.Load())
+}
+
+func TestIsConnectionError(t *testing.T) {
+	assert.True(t, isConnectionError(driver.ErrBadConn))
+	assert.True(t, isConnectionError(&pq.Error{Code: "08006"}))
+	assert.True(t, isConnectionError(&pq.Error{Code: "57P01"}))
+	assert.False(t, isConnectionError(&pq.Error{Code: "23505"}))
+	assert.False(t, isConnectionError(sql.ErrNoRows))
+	assert.False(t, isConnectionError(context.DeadlineExceeded))
+	assert.False(t, isConnectionError(fmt.Errorf("query: %w", context.Canceled)))
+}
+
+func TestRoutingDatabase_DeadlineExceededIsNotFailedOver(t *testing.T) {
+	replica := &routedDatabase{name: "replica", readErr: context.DeadlineExceeded}
+	db, primary := newTestRouting(replica)
+
+	var name string
+	err := db.GetContext(context.Background(), &name, "SELECT name FROM users")
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, db.HealthyReplicas())
+	assert.Zero(t, primary.reads.Load())
+}
+
+func TestRoutingDatabase_CloseClosesAll(t *testing.T) {
+	replica := &routedDatabase{name: "replica"}
+	primary := &routedDatabase{name: "primary"}
+	db := NewRoutingDatabase(primary, []Database{replica}, 10)
+
+	require.NoError(t, db.Close())
+	assert.True(t, primary.closed)
+	assert.True(t, replica.closed)
+	assert.Contains(t, db.Replicas(), "replica_0")
+}
@@ -0,0 +1,72 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	_ "github.com/glebarez/go-sqlite" // Pure Go SQLite driver, no cgo
+	"github.com/jmoiron/sqlx"
+)
+
+// SQLiteInMemory is the SQLitePath of a private in-memory database, dropped when it is closed
+const SQLiteInMemory = ":memory:"
+
+// sqliteUUIDv4 formats a random version 4 UUID like PostgreSQL's gen_random_uuid(), so PublicID
+// looks the same on every driver
+const sqliteUUIDv4 = `lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-' ||
+        substr('89ab', 1 + (abs(random()) % 4), 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6)))`
+
+// sqliteSchema mirrors the users table of the PostgreSQL migrations (000001, 000002)
+// The other tables rely on PostgreSQL-only SQL (arrays, data-modifying CTEs) and are not created
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS users (
+    id INTEGER PRIMARY KEY,
+    uuid TEXT NOT NULL UNIQUE DEFAULT (` + sqliteUUIDv4 + `),
+    email TEXT NOT NULL UNIQUE,
+    name TEXT NOT NULL,
+    password TEXT NOT NULL CHECK (LENGTH(password) > 0),
+    status TEXT NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'locked', 'disabled', 'pending_verification')),
+    last_login_at TIMESTAMP,
+    last_failed_login_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_users_status ON users(status);
+
+CREATE TRIGGER IF NOT EXISTS update_users_updated_at AFTER UPDATE ON users FOR EACH ROW
+BEGIN
+    UPDATE users SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+`
+
+// createSQLiteConnection opens the SQLite database at SQLitePath and creates the users table
+// It is meant for tests and local development: the pool is a single connection, since SQLite has
+// a single writer and every connection to ":memory:" opens a different database
+func (cf *ConnectionFactory) createSQLiteConnection() (Database, error) {
+	if cf.config.SQLitePath == "" {
+		return nil, fmt.Errorf("SQLite database path is required")
+	}
+
+	db, err := sqlx.Open("sqlite", cf.buildSQLiteDSN())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SQLite database: %w", err)
+	}
+	db.SetMaxOpenConns(1)
+	db.SetMaxIdleConns(1)
+
+	if _, err := db.ExecContext(context.Background(), sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create SQLite schema: %w", err)
+	}
+	return NewSQLXDatabase(db), nil
+}
+
+// buildSQLiteDSN adds the connection pragmas to SQLitePath
+func (cf *ConnectionFactory) buildSQLiteDSN() string {
+	pragmas := url.Values{}
+	pragmas.Add("_pragma", "foreign_keys(1)")
+	pragmas.Add("_pragma", "busy_timeout(5000)")
+	return cf.config.SQLitePath + "?" + pragmas.Encode()
+}
@@ -0,0 +1,54 @@
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateConnection_SQLiteInMemory(t *testing.T) {
+	db, err := NewConnectionFactory(ConnectionConfig{Driver: "sqlite", SQLitePath: SQLiteInMemory}).CreateConnection()
+	require.NoError(t, err)
+	defer db.Close()
+	ctx := context.Background()
+
+	_, err = db.ExecContext(ctx, "INSERT INTO users (id, email, name, password) VALUES ($1, $2, $3, $4)", 42, "dev@example.com", "Dev", "DevPass123!")
+	require.NoError(t, err)
+
+	var user struct {
+		UUID   string `db:"uuid"`
+		Status string `db:"status"`
+	}
+	require.NoError(t, db.GetContext(ctx, &user, "SELECT uuid, status FROM users WHERE email = $1", "dev@example.com"))
+	assert.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, user.UUID, "formatted like gen_random_uuid()")
+	assert.Equal(t, "active", user.Status)
+
+	_, err = db.ExecContext(ctx, "UPDATE users SET status = $2 WHERE id = $1", 42, "suspended")
+	assert.Error(t, err, "the status constraint is enforced")
+}
+
+func TestCreateConnection_SQLiteFileKeepsData(t *testing.T) {
+	config := ConnectionConfig{Driver: "sqlite", SQLitePath: filepath.Join(t.TempDir(), "users.db")}
+
+	db, err := NewConnectionFactory(config).CreateConnection()
+	require.NoError(t, err)
+	_, err = db.ExecContext(context.Background(), "INSERT INTO users (id, email, name, password) VALUES ($1, $2, $3, $4)", 1, "dev@example.com", "Dev", "DevPass123!")
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	db, err = NewConnectionFactory(config).CreateConnection()
+	require.NoError(t, err)
+	defer db.Close()
+
+	var count int
+	require.NoError(t, db.GetContext(context.Background(), &count, "SELECT COUNT(*) FROM users"))
+	assert.Equal(t, 1, count)
+}
+
+func TestCreateConnection_SQLiteRequiresPath(t *testing.T) {
+	_, err := NewConnectionFactory(ConnectionConfig{Driver: "sqlite"}).CreateConnection()
+	assert.Error(t, err)
+}
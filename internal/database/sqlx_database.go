@@ -88,16 +88,31 @@ func (s *SQLXDatabase) Get(dest interface{}, query string, args ...interface{})
 	return s.db.Get(dest, query, args...)
 }
 
+// GetContext executes a query with context and scans the result into dest
+func (s *SQLXDatabase) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return s.db.GetContext(ctx, dest, query, args...)
+}
+
 // Select executes a query and scans the results into dest
 func (s *SQLXDatabase) Select(dest interface{}, query string, args ...interface{}) error {
 	return s.db.Select(dest, query, args...)
 }
 
+// SelectContext executes a query with context and scans the results into dest
+func (s *SQLXDatabase) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return s.db.SelectContext(ctx, dest, query, args...)
+}
+
 // Ping verifies the database connection
 func (s *SQLXDatabase) Ping() error {
 	return s.db.Ping()
 }
 
+// Stats returns connection pool statistics
+func (s *SQLXDatabase) Stats() sql.DBStats {
+	return s.db.Stats()
+}
+
 // Close closes the database connection
 func (s *SQLXDatabase) Close() error {
 	return s.db.Close()
@@ -161,11 +176,21 @@ func (t *SQLXTransaction) Get(dest interface{}, query string, args ...interface{
 	return t.tx.Get(dest, query, args...)
 }
 
+// GetContext executes a query with context and scans the result into dest within the transaction
+func (t *SQLXTransaction) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return t.tx.GetContext(ctx, dest, query, args...)
+}
+
 // Select executes a query and scans the results into dest within the transaction
 func (t *SQLXTransaction) Select(dest interface{}, query string, args ...interface{}) error {
 	return t.tx.Select(dest, query, args...)
 }
 
+// SelectContext executes a query with context and scans the results into dest within the transaction
+func (t *SQLXTransaction) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return t.tx.SelectContext(ctx, dest, query, args...)
+}
+
 // Commit commits the transaction
 func (t *SQLXTransaction) Commit() error {
 	return t.tx.Commit()
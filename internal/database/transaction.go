@@ -0,0 +1,48 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// WithinTx runs fn in a transaction, committing when fn returns nil and rolling back otherwise
+// A panic in fn rolls the transaction back and is re-raised. Nested calls open independent
+// transactions, so pass tx down instead of calling WithinTx again inside fn.
+//
+//	err := database.WithinTx(ctx, db, func(tx database.Transaction) error {
+//		if err := persistence.NewTOTPRepository(tx).ConfirmTOTPFactor(ctx, userID, step); err != nil {
+//			return err
+//		}
+//		return persistence.NewRecoveryCodeRepository(tx).ReplaceRecoveryCodes(ctx, userID, hashes)
+//	})
+func WithinTx(ctx context.Context, db Database, fn func(tx Transaction) error) (err error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	committed := false
+	defer func() {
+		if committed {
+			return
+		}
+		if recovered := recover(); recovered != nil {
+			tx.Rollback()
+			panic(recovered)
+		}
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to roll back transaction: %w", rollbackErr))
+		}
+	}()
+
+	if err = fn(tx); err != nil {
+		return err
+	}
+
+	committed = true
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// txDatabase is a Database whose transactions record how they ended
+type txDatabase struct {
+	Database
+	beginErr error
+	tx       *recordingTx
+}
+
+type recordingTx struct {
+	Transaction
+	commitErr   error
+	rollbackErr error
+	committed   bool
+	rolledBack  bool
+}
+
+func (d *txDatabase) BeginTx(ctx context.Context, opts *sql.TxOptions) (Transaction, error) {
+	if d.beginErr != nil {
+		return nil, d.beginErr
+	}
+	return d.tx, nil
+}
+
+func (t *recordingTx) Commit() error {
+	t.committed = true
+	return t.commitErr
+}
+
+func (t *recordingTx) Rollback() error {
+	t.rolledBack = true
+	return t.rollbackErr
+}
+
+func TestWithinTx_CommitsOnSuccess(t *testing.T) {
+	db := &txDatabase{tx: &recordingTx{}}
+
+	var received Transaction
+	err := WithinTx(context.Background(), db, func(tx Transaction) error {
+		received = tx
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Same(t, db.tx, received)
+	assert.True(t, db.tx.committed)
+	assert.False(t, db.tx.rolledBack)
+}
+
+func TestWithinTx_RollsBackOnError(t *testing.T) {
+	db := &txDatabase{tx: &recordingTx{}}
+	failure := errors.New("insert failed")
+
+	err := WithinTx(context.Background(), db, func(tx Transaction) error {
+		return failure
+	})
+
+	assert.ErrorIs(t, err, failure)
+	assert.False(t, db.tx.committed)
+	assert.True(t, db.tx.rolledBack)
+}
+
+func TestWithinTx_ReportsRollbackFailure(t *testing.T) {
+	rollbackErr := errors.New("connection lost")
+	db := &txDatabase{tx: &recordingTx{rollbackErr: rollbackErr}}
+	failure := errors.New("insert failed")
+
+	err := WithinTx(context.Background(), db, func(tx Transaction) error {
+		return failure
+	})
+
+	assert.ErrorIs(t, err, failure)
+	assert.ErrorIs(t, err, rollbackErr)
+}
+
+func TestWithinTx_RollsBackAndRepanics(t *testing.T) {
+	db := &txDatabase{tx: &recordingTx{}}
+
+	assert.PanicsWithValue(t, "boom", func() {
+		WithinTx(context.Background(), db, func(tx Transaction) error {
+			panic("boom")
+		})
+	})
+	assert.False(t, db.tx.committed)
+	assert.True(t, db.tx.rolledBack)
+}
+
+func TestWithinTx_BeginAndCommitErrors(t *testing.T) {
+	beginErr := errors.New("too many connections")
+	called := false
+	err := WithinTx(context.Background(), &txDatabase{beginErr: beginErr}, func(tx Transaction) error {
+		called = true
+		return nil
+	})
+	assert.ErrorIs(t, err, beginErr)
+	assert.False(t, called)
+
+	commitErr := errors.New("serialization failure")
+	db := &txDatabase{tx: &recordingTx{commitErr: commitErr}}
+	err = WithinTx(context.Background(), db, func(tx Transaction) error {
+		return nil
+	})
+	assert.ErrorIs(t, err, commitErr)
+	assert.False(t, db.tx.rolledBack)
+}
@@ -0,0 +1,190 @@
+package events
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrInvalidResumeToken is returned when a resume token cannot be decoded
+	ErrInvalidResumeToken = errors.New("invalid resume token")
+	// ErrResumeTokenExpired is returned when the events after a resume token are no longer retained,
+	// either because they fell out of the history window or the token was issued by another Broker instance.
+	// Consumers must resynchronise their caches and watch again without a token.
+	ErrResumeTokenExpired = errors.New("resume token expired")
+	// ErrSubscriberTooSlow is set on a subscription that was dropped because its buffer filled up
+	ErrSubscriberTooSlow = errors.New("subscriber too slow")
+)
+
+// Broker is an in-memory Stream that fans events out to subscribers and keeps
+// a bounded history so consumers can resume after a disconnect
+type Broker struct {
+	mu          sync.Mutex
+	epoch       int64
+	sequence    uint64
+	history     []Event
+	head        int
+	size        int
+	subscribers map[*Subscription]struct{}
+	bufferSize  int
+	now         func() time.Time
+}
+
+// NewBroker creates a broker retaining historySize events, with bufferSize events buffered per subscriber
+func NewBroker(historySize, bufferSize int) *Broker {
+	if historySize < 1 {
+		historySize = 1
+	}
+	if bufferSize < 1 {
+		bufferSize = 1
+	}
+
+	return &Broker{
+		epoch:       time.Now().UnixNano(),
+		history:     make([]Event, historySize),
+		subscribers: make(map[*Subscription]struct{}),
+		bufferSize:  bufferSize,
+		now:         time.Now,
+	}
+}
+
+// Publish records the event and delivers it to every matching subscriber
+// Subscribers that cannot keep up are dropped instead of blocking the publisher
+func (b *Broker) Publish(ctx context.Context, event Event) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.sequence++
+	event.Sequence = b.sequence
+	event.ID = fmt.Sprintf("%d-%d", b.epoch, event.Sequence)
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = b.now()
+	}
+
+	b.history[(b.head+b.size)%len(b.history)] = event
+	if b.size < len(b.history) {
+		b.size++
+	} else {
+		b.head = (b.head + 1) % len(b.history)
+	}
+
+	for sub := range b.subscribers {
+		if !sub.filter.Matches(event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			b.drop(sub, ErrSubscriberTooSlow)
+		}
+	}
+
+	return nil
+}
+
+// Subscribe registers a subscriber for events matching filter
+// With a resume token, retained events published after the token are replayed first
+func (b *Broker) Subscribe(ctx context.Context, resumeToken string, filter Filter) (*Subscription, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var replay []Event
+	if resumeToken != "" {
+		after, err := b.decodeResumeToken(resumeToken)
+		if err != nil {
+			return nil, err
+		}
+		replay, err = b.eventsAfter(after, filter)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sub := newSubscription(filter, len(replay)+b.bufferSize)
+	sub.cancel = func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.drop(sub, nil)
+	}
+	for _, event := range replay {
+		sub.events <- event
+	}
+	b.subscribers[sub] = struct{}{}
+
+	return sub, nil
+}
+
+// eventsAfter returns retained events with a sequence greater than after
+// Caller must hold b.mu
+func (b *Broker) eventsAfter(after uint64, filter Filter) ([]Event, error) {
+	if after > b.sequence {
+		return nil, ErrInvalidResumeToken
+	}
+	if after == b.sequence {
+		return nil, nil
+	}
+
+	if b.size == 0 || after+1 < b.history[b.head].Sequence {
+		return nil, ErrResumeTokenExpired
+	}
+
+	var replay []Event
+	for i := 0; i < b.size; i++ {
+		event := b.history[(b.head+i)%len(b.history)]
+		if event.Sequence > after && filter.Matches(event) {
+			replay = append(replay, event)
+		}
+	}
+	return replay, nil
+}
+
+// drop removes the subscriber and closes its channel
+// Caller must hold b.mu
+func (b *Broker) drop(sub *Subscription, err error) {
+	if _, ok := b.subscribers[sub]; !ok {
+		return
+	}
+	delete(b.subscribers, sub)
+	sub.end(err)
+}
+
+// ResumeToken returns the opaque token that resumes a stream after event
+func (b *Broker) ResumeToken(event Event) string {
+	raw := strconv.FormatInt(b.epoch, 10) + ":" + strconv.FormatUint(event.Sequence, 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeResumeToken returns the sequence encoded in token
+func (b *Broker) decodeResumeToken(token string) (uint64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, ErrInvalidResumeToken
+	}
+
+	parts := strings.SplitN(string(raw), ":", 2)
+	if len(parts) != 2 {
+		return 0, ErrInvalidResumeToken
+	}
+
+	epoch, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, ErrInvalidResumeToken
+	}
+	sequence, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return 0, ErrInvalidResumeToken
+	}
+
+	// Tokens from a previous process or another replica refer to a different sequence space
+	if epoch != b.epoch {
+		return 0, ErrResumeTokenExpired
+	}
+
+	return sequence, nil
+}
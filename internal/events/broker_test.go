@@ -0,0 +1,167 @@
+package events
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func receive(t *testing.T, sub *Subscription) Event {
+	t.Helper()
+	select {
+	case event, ok := <-sub.Events():
+		require.True(t, ok, "subscription closed unexpectedly")
+		return event
+	default:
+		t.Fatal("expected an event")
+		return Event{}
+	}
+}
+
+func assertNoEvent(t *testing.T, sub *Subscription) {
+	t.Helper()
+	select {
+	case event := <-sub.Events():
+		t.Fatalf("unexpected event: %+v", event)
+	default:
+	}
+}
+
+func TestBroker_PublishDeliversToSubscribers(t *testing.T) {
+	broker := NewBroker(10, 10)
+	sub, err := broker.Subscribe(context.Background(), "", Filter{})
+	require.NoError(t, err)
+	defer sub.Close()
+
+	err = broker.Publish(context.Background(), Event{Type: EventLogin, UserID: "user-1"})
+	require.NoError(t, err)
+
+	event := receive(t, sub)
+	assert.Equal(t, EventLogin, event.Type)
+	assert.Equal(t, "user-1", event.UserID)
+	assert.Equal(t, uint64(1), event.Sequence)
+	assert.NotEmpty(t, event.ID)
+	assert.False(t, event.OccurredAt.IsZero())
+}
+
+func TestBroker_FilterByTypeAndUser(t *testing.T) {
+	broker := NewBroker(10, 10)
+	sub, err := broker.Subscribe(context.Background(), "", NewFilter([]EventType{EventLogout}, []string{"user-1"}))
+	require.NoError(t, err)
+	defer sub.Close()
+
+	broker.Publish(context.Background(), Event{Type: EventLogin, UserID: "user-1"})
+	broker.Publish(context.Background(), Event{Type: EventLogout, UserID: "user-2"})
+	broker.Publish(context.Background(), Event{Type: EventLogout, UserID: "user-1"})
+
+	event := receive(t, sub)
+	assert.Equal(t, EventLogout, event.Type)
+	assert.Equal(t, "user-1", event.UserID)
+	assertNoEvent(t, sub)
+}
+
+func TestBroker_ResumeReplaysMissedEvents(t *testing.T) {
+	broker := NewBroker(10, 10)
+	sub, _ := broker.Subscribe(context.Background(), "", Filter{})
+
+	broker.Publish(context.Background(), Event{Type: EventLogin, UserID: "user-1"})
+	first := receive(t, sub)
+	sub.Close()
+
+	broker.Publish(context.Background(), Event{Type: EventLogout, UserID: "user-1"})
+	broker.Publish(context.Background(), Event{Type: EventPasswordChanged, UserID: "user-1"})
+
+	resumed, err := broker.Subscribe(context.Background(), broker.ResumeToken(first), Filter{})
+	require.NoError(t, err)
+	defer resumed.Close()
+
+	assert.Equal(t, EventLogout, receive(t, resumed).Type)
+	assert.Equal(t, EventPasswordChanged, receive(t, resumed).Type)
+	assertNoEvent(t, resumed)
+
+	// Live events continue after the replay
+	broker.Publish(context.Background(), Event{Type: EventEmailChanged, UserID: "user-1"})
+	assert.Equal(t, EventEmailChanged, receive(t, resumed).Type)
+}
+
+func TestBroker_ResumeAtLatestEvent(t *testing.T) {
+	broker := NewBroker(10, 10)
+	sub, _ := broker.Subscribe(context.Background(), "", Filter{})
+	broker.Publish(context.Background(), Event{Type: EventLogin, UserID: "user-1"})
+	last := receive(t, sub)
+	sub.Close()
+
+	resumed, err := broker.Subscribe(context.Background(), broker.ResumeToken(last), Filter{})
+	require.NoError(t, err)
+	defer resumed.Close()
+	assertNoEvent(t, resumed)
+}
+
+func TestBroker_ResumeTokenExpired(t *testing.T) {
+	broker := NewBroker(2, 10)
+	sub, _ := broker.Subscribe(context.Background(), "", Filter{})
+	broker.Publish(context.Background(), Event{Type: EventLogin, UserID: "user-1"})
+	first := receive(t, sub)
+	sub.Close()
+
+	// Push the first three events after the token out of the history window
+	for i := 0; i < 3; i++ {
+		broker.Publish(context.Background(), Event{Type: EventLogin, UserID: "user-1"})
+	}
+
+	_, err := broker.Subscribe(context.Background(), broker.ResumeToken(first), Filter{})
+	assert.ErrorIs(t, err, ErrResumeTokenExpired)
+}
+
+func TestBroker_ResumeTokenFromOtherInstance(t *testing.T) {
+	other := NewBroker(10, 10)
+	sub, _ := other.Subscribe(context.Background(), "", Filter{})
+	other.Publish(context.Background(), Event{Type: EventLogin, UserID: "user-1"})
+	event := receive(t, sub)
+
+	broker := NewBroker(10, 10)
+	broker.epoch = other.epoch + 1
+
+	_, err := broker.Subscribe(context.Background(), other.ResumeToken(event), Filter{})
+	assert.ErrorIs(t, err, ErrResumeTokenExpired)
+}
+
+func TestBroker_InvalidResumeToken(t *testing.T) {
+	broker := NewBroker(10, 10)
+
+	tokens := []string{
+		"!!!",
+		base64.RawURLEncoding.EncodeToString([]byte("no-separator")),
+		base64.RawURLEncoding.EncodeToString([]byte("abc:1")),
+		base64.RawURLEncoding.EncodeToString([]byte("1:abc")),
+		broker.ResumeToken(Event{Sequence: 42}), // ahead of the broker
+	}
+
+	for _, token := range tokens {
+		_, err := broker.Subscribe(context.Background(), token, Filter{})
+		assert.ErrorIs(t, err, ErrInvalidResumeToken, "token %q", token)
+	}
+}
+
+func TestBroker_DropsSlowSubscriber(t *testing.T) {
+	broker := NewBroker(10, 1)
+	sub, _ := broker.Subscribe(context.Background(), "", Filter{})
+
+	broker.Publish(context.Background(), Event{Type: EventLogin, UserID: "user-1"})
+	broker.Publish(context.Background(), Event{Type: EventLogin, UserID: "user-1"})
+
+	receive(t, sub)
+	_, ok := <-sub.Events()
+	assert.False(t, ok)
+	assert.ErrorIs(t, sub.Err(), ErrSubscriberTooSlow)
+
+	// Closing an already dropped subscription is a no-op
+	sub.Close()
+}
+
+func TestNopPublisher(t *testing.T) {
+	assert.NoError(t, NopPublisher{}.Publish(context.Background(), Event{Type: EventLogin}))
+}
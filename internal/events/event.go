@@ -0,0 +1,86 @@
+package events
+
+import (
+	"context"
+	"time"
+)
+
+// EventType identifies a user or session lifecycle event
+type EventType string
+
+const (
+	EventLogin           EventType = "login"
+	EventLogout          EventType = "logout"
+	EventPasswordChanged EventType = "password_changed"
+	EventEmailChanged    EventType = "email_changed"
+)
+
+// Event is a single user lifecycle event
+type Event struct {
+	ID         string
+	Sequence   uint64
+	Type       EventType
+	UserID     string
+	OccurredAt time.Time
+	Attributes map[string]string
+}
+
+// Publisher publishes user lifecycle events to interested consumers
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// Stream is a Publisher whose events can be watched, and resumed after a disconnect
+type Stream interface {
+	Publisher
+	// Subscribe delivers events matching filter, starting after resumeToken when it is set
+	Subscribe(ctx context.Context, resumeToken string, filter Filter) (*Subscription, error)
+	// ResumeToken returns the opaque token that resumes a subscription after event
+	ResumeToken(event Event) string
+}
+
+// NopPublisher discards every event
+type NopPublisher struct{}
+
+// Publish implements Publisher
+func (NopPublisher) Publish(ctx context.Context, event Event) error {
+	return nil
+}
+
+// Filter selects the events a subscriber is interested in; empty sets match everything
+type Filter struct {
+	Types   map[EventType]bool
+	UserIDs map[string]bool
+}
+
+// NewFilter builds a filter from the given event types and user ids
+func NewFilter(types []EventType, userIDs []string) Filter {
+	filter := Filter{}
+
+	if len(types) > 0 {
+		filter.Types = make(map[EventType]bool, len(types))
+		for _, t := range types {
+			filter.Types[t] = true
+		}
+	}
+
+	if len(userIDs) > 0 {
+		filter.UserIDs = make(map[string]bool, len(userIDs))
+		for _, id := range userIDs {
+			filter.UserIDs[id] = true
+		}
+	}
+
+	return filter
+}
+
+// Matches reports whether the event passes the filter
+func (f Filter) Matches(event Event) bool {
+	if len(f.Types) > 0 && !f.Types[event.Type] {
+		return false
+	}
+	if len(f.UserIDs) > 0 && !f.UserIDs[event.UserID] {
+		return false
+	}
+	return true
+}
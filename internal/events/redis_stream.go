@@ -0,0 +1,201 @@
+package events
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisBlock is how long a subscriber waits for new entries before polling again
+const redisBlock = 5 * time.Second
+
+// RedisStream implements Stream on a Redis stream shared by every replica
+// Entry IDs are the event IDs and resume tokens, so consumers can resume on any replica and
+// after restarts for as long as Redis retains the entry
+type RedisStream struct {
+	client      redis.Cmdable
+	key         string
+	historySize int64
+	bufferSize  int
+	block       time.Duration
+	now         func() time.Time
+}
+
+// NewRedisStream creates a stream on client retaining about historySize events under "events:user",
+// with bufferSize events buffered per subscriber
+func NewRedisStream(client redis.Cmdable, historySize, bufferSize int) *RedisStream {
+	if historySize < 1 {
+		historySize = 1
+	}
+	if bufferSize < 1 {
+		bufferSize = 1
+	}
+
+	return &RedisStream{
+		client:      client,
+		key:         "events:user",
+		historySize: int64(historySize),
+		bufferSize:  bufferSize,
+		block:       redisBlock,
+		now:         time.Now,
+	}
+}
+
+// Publish appends the event to the stream, trimming it to the history size
+func (r *RedisStream) Publish(ctx context.Context, event Event) error {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = r.now()
+	}
+
+	values := map[string]interface{}{
+		"type":        string(event.Type),
+		"user_id":     event.UserID,
+		"occurred_at": event.OccurredAt.UnixNano(),
+	}
+	if len(event.Attributes) > 0 {
+		attributes, err := json.Marshal(event.Attributes)
+		if err != nil {
+			return fmt.Errorf("user events: %w", err)
+		}
+		values["attributes"] = attributes
+	}
+
+	err := r.client.XAdd(ctx, &redis.XAddArgs{Stream: r.key, MaxLen: r.historySize, Approx: true, Values: values}).Err()
+	if err != nil {
+		return fmt.Errorf("user events: %w", err)
+	}
+	return nil
+}
+
+// Subscribe follows the stream for events matching filter
+// With a resume token, retained events after the token are delivered first. The token is expired once
+// its own entry has been trimmed, since later entries may have been trimmed with it
+func (r *RedisStream) Subscribe(ctx context.Context, resumeToken string, filter Filter) (*Subscription, error) {
+	var after string
+	if resumeToken != "" {
+		id, err := decodeStreamID(resumeToken)
+		if err != nil {
+			return nil, err
+		}
+		retained, err := r.client.XRange(ctx, r.key, id, id).Result()
+		if err != nil {
+			return nil, fmt.Errorf("user events: %w", err)
+		}
+		if len(retained) == 0 {
+			return nil, ErrResumeTokenExpired
+		}
+		after = id
+	} else {
+		// Start after the last entry, so events published from here on are not missed
+		last, err := r.client.XRevRangeN(ctx, r.key, "+", "-", 1).Result()
+		if err != nil {
+			return nil, fmt.Errorf("user events: %w", err)
+		}
+		after = "0-0"
+		if len(last) > 0 {
+			after = last[0].ID
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	sub := newSubscription(filter, r.bufferSize)
+	sub.cancel = cancel
+	go r.follow(ctx, sub, after)
+	return sub, nil
+}
+
+// follow delivers the entries after the given ID until ctx is canceled
+// Subscribers that cannot keep up are dropped, like with the in-memory Broker
+func (r *RedisStream) follow(ctx context.Context, sub *Subscription, after string) {
+	for {
+		streams, err := r.client.XRead(ctx, &redis.XReadArgs{
+			Streams: []string{r.key, after},
+			Count:   int64(r.bufferSize),
+			Block:   r.block,
+		}).Result()
+		if ctx.Err() != nil {
+			sub.end(nil)
+			return
+		}
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			sub.end(fmt.Errorf("user events: %w", err))
+			return
+		}
+
+		for _, stream := range streams {
+			for _, message := range stream.Messages {
+				after = message.ID
+				event, err := decodeEvent(message)
+				if err != nil {
+					log.Printf("⚠️  Skipping malformed user event %s: %v", message.ID, err)
+					continue
+				}
+				if !sub.filter.Matches(event) {
+					continue
+				}
+				select {
+				case sub.events <- event:
+				default:
+					sub.end(ErrSubscriberTooSlow)
+					return
+				}
+			}
+		}
+	}
+}
+
+// ResumeToken returns the opaque token that resumes a subscription after event
+func (r *RedisStream) ResumeToken(event Event) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(event.ID))
+}
+
+// decodeEvent converts a stream entry to an event
+func decodeEvent(message redis.XMessage) (Event, error) {
+	eventType, _ := message.Values["type"].(string)
+	userID, _ := message.Values["user_id"].(string)
+	occurredAt, _ := message.Values["occurred_at"].(string)
+	nanos, err := strconv.ParseInt(occurredAt, 10, 64)
+	if eventType == "" || err != nil {
+		return Event{}, fmt.Errorf("missing type or occurred_at")
+	}
+
+	event := Event{ID: message.ID, Type: EventType(eventType), UserID: userID, OccurredAt: time.Unix(0, nanos)}
+	if attributes, ok := message.Values["attributes"].(string); ok {
+		if err := json.Unmarshal([]byte(attributes), &event.Attributes); err != nil {
+			return Event{}, fmt.Errorf("attributes: %w", err)
+		}
+	}
+	return event, nil
+}
+
+// decodeStreamID returns the stream entry ID encoded in token
+func decodeStreamID(token string) (string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return "", ErrInvalidResumeToken
+	}
+
+	id := string(raw)
+	millis, sequence, ok := strings.Cut(id, "-")
+	if !ok {
+		return "", ErrInvalidResumeToken
+	}
+	if _, err := strconv.ParseUint(millis, 10, 64); err != nil {
+		return "", ErrInvalidResumeToken
+	}
+	if _, err := strconv.ParseUint(sequence, 10, 64); err != nil {
+		return "", ErrInvalidResumeToken
+	}
+	return id, nil
+}
@@ -0,0 +1,112 @@
+package events
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRedisStreams creates two streams on one Redis server, like two replicas of the service
+func newTestRedisStreams(t *testing.T, historySize, bufferSize int) (*RedisStream, *RedisStream) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	streams := make([]*RedisStream, 2)
+	for i := range streams {
+		client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+		t.Cleanup(func() { client.Close() })
+		streams[i] = NewRedisStream(client, historySize, bufferSize)
+		streams[i].block = 20 * time.Millisecond
+	}
+	return streams[0], streams[1]
+}
+
+func receiveWithin(t *testing.T, sub *Subscription) Event {
+	t.Helper()
+	select {
+	case event, ok := <-sub.Events():
+		require.True(t, ok, "subscription closed unexpectedly: %v", sub.Err())
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("expected an event")
+		return Event{}
+	}
+}
+
+func TestRedisStream_ReplicasSeeEveryEvent(t *testing.T) {
+	first, second := newTestRedisStreams(t, 10, 10)
+	sub, err := second.Subscribe(context.Background(), "", Filter{})
+	require.NoError(t, err)
+	defer sub.Close()
+
+	occurredAt := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, first.Publish(context.Background(), Event{
+		Type: EventLogin, UserID: "user-1", OccurredAt: occurredAt, Attributes: map[string]string{"session_id": "s1"},
+	}))
+
+	event := receiveWithin(t, sub)
+	assert.Equal(t, EventLogin, event.Type)
+	assert.Equal(t, "user-1", event.UserID)
+	assert.True(t, occurredAt.Equal(event.OccurredAt))
+	assert.Equal(t, map[string]string{"session_id": "s1"}, event.Attributes)
+	assert.NotEmpty(t, event.ID)
+}
+
+func TestRedisStream_ResumeOnAnotherReplica(t *testing.T) {
+	first, second := newTestRedisStreams(t, 10, 10)
+	ctx := context.Background()
+	sub, _ := first.Subscribe(ctx, "", Filter{})
+	require.NoError(t, first.Publish(ctx, Event{Type: EventLogin, UserID: "user-1"}))
+	seen := receiveWithin(t, sub)
+	sub.Close()
+
+	require.NoError(t, first.Publish(ctx, Event{Type: EventLogin, UserID: "user-2"}))
+	require.NoError(t, first.Publish(ctx, Event{Type: EventLogout, UserID: "user-1"}))
+
+	resumed, err := second.Subscribe(ctx, first.ResumeToken(seen), NewFilter(nil, []string{"user-1"}))
+	require.NoError(t, err)
+	defer resumed.Close()
+
+	event := receiveWithin(t, resumed)
+	assert.Equal(t, EventLogout, event.Type, "missed events are replayed through the filter")
+	assert.Equal(t, "user-1", event.UserID)
+}
+
+func TestRedisStream_ExpiredAndInvalidTokens(t *testing.T) {
+	stream, _ := newTestRedisStreams(t, 1, 10)
+	ctx := context.Background()
+	sub, _ := stream.Subscribe(ctx, "", Filter{})
+	defer sub.Close()
+	require.NoError(t, stream.Publish(ctx, Event{Type: EventLogin, UserID: "user-1"}))
+	first := receiveWithin(t, sub)
+	require.NoError(t, stream.Publish(ctx, Event{Type: EventLogout, UserID: "user-1"}))
+	require.NoError(t, stream.Publish(ctx, Event{Type: EventPasswordChanged, UserID: "user-1"}))
+
+	_, err := stream.Subscribe(ctx, stream.ResumeToken(first), Filter{})
+	assert.ErrorIs(t, err, ErrResumeTokenExpired, "the token's entry was trimmed")
+
+	for _, token := range []string{"not base64!", base64.RawURLEncoding.EncodeToString([]byte("123:4"))} {
+		_, err := stream.Subscribe(ctx, token, Filter{})
+		assert.ErrorIs(t, err, ErrInvalidResumeToken, token)
+	}
+}
+
+func TestRedisStream_DropsSlowSubscribers(t *testing.T) {
+	stream, _ := newTestRedisStreams(t, 10, 1)
+	ctx := context.Background()
+	sub, err := stream.Subscribe(ctx, "", Filter{})
+	require.NoError(t, err)
+	defer sub.Close()
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, stream.Publish(ctx, Event{Type: EventLogin, UserID: "user-1"}))
+	}
+
+	require.Eventually(t, func() bool { return sub.Err() != nil }, time.Second, 10*time.Millisecond)
+	assert.ErrorIs(t, sub.Err(), ErrSubscriberTooSlow)
+}
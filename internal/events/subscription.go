@@ -0,0 +1,43 @@
+package events
+
+import "sync"
+
+// Subscription is a live feed of events from a Stream
+type Subscription struct {
+	filter Filter
+	events chan Event
+	cancel func()
+
+	mu  sync.Mutex
+	err error
+}
+
+// newSubscription creates a subscription buffering bufferSize events; the stream sets cancel
+func newSubscription(filter Filter, bufferSize int) *Subscription {
+	return &Subscription{filter: filter, events: make(chan Event, bufferSize)}
+}
+
+// Events returns the channel events are delivered on; it is closed when the subscription ends
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Err returns why the stream ended the subscription, if it did
+func (s *Subscription) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Close unregisters the subscription
+func (s *Subscription) Close() {
+	s.cancel()
+}
+
+// end records err and closes the events channel; the stream calls it once, after its last send
+func (s *Subscription) end(err error) {
+	s.mu.Lock()
+	s.err = err
+	s.mu.Unlock()
+	close(s.events)
+}
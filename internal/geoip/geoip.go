@@ -0,0 +1,204 @@
+package geoip
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/netip"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Location is the coarse location of an IP address; fields are empty when unknown
+type Location struct {
+	// Country is the ISO 3166-1 alpha-2 code, e.g. "PT"
+	Country string
+	Region  string
+	City    string
+	// Latitude and Longitude are in degrees, set when HasCoordinates
+	Latitude       float64
+	Longitude      float64
+	HasCoordinates bool
+}
+
+// earthRadiusKm is the mean radius of the Earth
+const earthRadiusKm = 6371.0
+
+// Distance returns the great-circle distance in kilometers between two locations, false when
+// either has no coordinates
+func Distance(a Location, b Location) (float64, bool) {
+	if !a.HasCoordinates || !b.HasCoordinates {
+		return 0, false
+	}
+
+	lat1, lat2 := a.Latitude*math.Pi/180, b.Latitude*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (b.Longitude - a.Longitude) * math.Pi / 180
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h))), true
+}
+
+// String formats the location as "City, Region, Country", skipping unknown parts
+func (l Location) String() string {
+	var parts []string
+	for _, part := range []string{l.City, l.Region, l.Country} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Locator resolves IP addresses to locations
+// Implementations must be safe for concurrent use
+type Locator interface {
+	// Locate returns the location of ip, or the zero Location when ip is invalid or unknown
+	Locate(ip string) Location
+}
+
+// NopLocator knows no location, used when no GeoIP database is configured
+type NopLocator struct{}
+
+// Locate implements Locator
+func (NopLocator) Locate(ip string) Location {
+	return Location{}
+}
+
+// network is a row of the database
+type network struct {
+	prefix   netip.Prefix
+	location Location
+}
+
+// Database is an offline GeoIP database held in memory
+type Database struct {
+	// networks are sorted by first address and do not overlap
+	networks []network
+}
+
+// header is the first line of a database file; the coordinate columns are optional
+var header = []string{"network", "country", "region", "city", "latitude", "longitude"}
+
+// requiredColumns is the number of columns of a database without coordinates
+const requiredColumns = 4
+
+// LoadDatabase reads a GeoIP CSV file, see ParseDatabase
+func LoadDatabase(path string) (*Database, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open GeoIP database: %w", err)
+	}
+	defer f.Close()
+
+	db, err := ParseDatabase(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read GeoIP database %s: %w", path, err)
+	}
+	return db, nil
+}
+
+// ParseDatabase reads a GeoIP CSV with the header "network,country,region,city", optionally
+// followed by "latitude,longitude", and one IPv4 or IPv6 network in CIDR notation per line;
+// networks must not overlap
+func ParseDatabase(r io.Reader) (*Database, error) {
+	reader := csv.NewReader(r)
+	reader.ReuseRecord = true
+
+	first, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("missing header: %w", err)
+	}
+	if len(first) != requiredColumns && len(first) != len(header) {
+		return nil, fmt.Errorf("header must be %q or %q", strings.Join(header[:requiredColumns], ","), strings.Join(header, ","))
+	}
+	for i := range first {
+		if strings.TrimSpace(first[i]) != header[i] {
+			return nil, fmt.Errorf("header must be %q or %q", strings.Join(header[:requiredColumns], ","), strings.Join(header, ","))
+		}
+	}
+	withCoordinates := len(first) == len(header)
+
+	var networks []network
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+		prefix, err := netip.ParsePrefix(strings.TrimSpace(record[0]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		location := Location{
+			Country: strings.TrimSpace(record[1]),
+			Region:  strings.TrimSpace(record[2]),
+			City:    strings.TrimSpace(record[3]),
+		}
+		if withCoordinates {
+			if location, err = withCoordinatesOf(location, record[4], record[5]); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+		}
+		networks = append(networks, network{prefix: prefix.Masked(), location: location})
+	}
+
+	sort.Slice(networks, func(i, j int) bool {
+		return networks[i].prefix.Addr().Less(networks[j].prefix.Addr())
+	})
+	for i := 1; i < len(networks); i++ {
+		if networks[i-1].prefix.Overlaps(networks[i].prefix) {
+			return nil, fmt.Errorf("networks %s and %s overlap", networks[i-1].prefix, networks[i].prefix)
+		}
+	}
+	return &Database{networks: networks}, nil
+}
+
+// withCoordinatesOf sets the coordinates of location, both empty meaning unknown
+func withCoordinatesOf(location Location, latitude string, longitude string) (Location, error) {
+	latitude, longitude = strings.TrimSpace(latitude), strings.TrimSpace(longitude)
+	if latitude == "" && longitude == "" {
+		return location, nil
+	}
+
+	lat, err := strconv.ParseFloat(latitude, 64)
+	if err != nil || lat < -90 || lat > 90 {
+		return location, fmt.Errorf("invalid latitude %q", latitude)
+	}
+	lon, err := strconv.ParseFloat(longitude, 64)
+	if err != nil || lon < -180 || lon > 180 {
+		return location, fmt.Errorf("invalid longitude %q", longitude)
+	}
+	location.Latitude, location.Longitude, location.HasCoordinates = lat, lon, true
+	return location, nil
+}
+
+// Len returns the number of networks in the database
+func (d *Database) Len() int {
+	return len(d.networks)
+}
+
+// Locate implements Locator
+func (d *Database) Locate(ip string) Location {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return Location{}
+	}
+	addr = addr.Unmap()
+
+	// The only candidate is the last network starting at or before addr
+	i := sort.Search(len(d.networks), func(i int) bool {
+		return addr.Less(d.networks[i].prefix.Addr())
+	})
+	if i == 0 || !d.networks[i-1].prefix.Contains(addr) {
+		return Location{}
+	}
+	return d.networks[i-1].location
+}
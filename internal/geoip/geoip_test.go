@@ -0,0 +1,99 @@
+package geoip
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testDatabase = `network,country,region,city
+203.0.113.0/24,PT,Lisbon,Lisbon
+198.51.100.0/25,BR,São Paulo,São Paulo
+198.51.100.128/25,US,,
+2001:db8:1::/48,DE,Berlin,Berlin
+`
+
+func TestDatabase_Locate(t *testing.T) {
+	db, err := ParseDatabase(strings.NewReader(testDatabase))
+	require.NoError(t, err)
+	assert.Equal(t, 4, db.Len())
+
+	tests := map[string]Location{
+		"203.0.113.7":        {Country: "PT", Region: "Lisbon", City: "Lisbon"},
+		"203.0.113.255":      {Country: "PT", Region: "Lisbon", City: "Lisbon"},
+		"198.51.100.1":       {Country: "BR", Region: "São Paulo", City: "São Paulo"},
+		"198.51.100.200":     {Country: "US"},
+		"::ffff:203.0.113.9": {Country: "PT", Region: "Lisbon", City: "Lisbon"},
+		"2001:db8:1:2::1":    {Country: "DE", Region: "Berlin", City: "Berlin"},
+		"203.0.114.1":        {},
+		"192.0.2.1":          {},
+		"2001:db8:2::1":      {},
+		"not-an-ip":          {},
+		"":                   {},
+	}
+	for ip, location := range tests {
+		assert.Equal(t, location, db.Locate(ip), ip)
+	}
+}
+
+func TestParseDatabase_Errors(t *testing.T) {
+	tests := map[string]string{
+		"missing header":      "",
+		"wrong header":        "cidr,country,region,city\n",
+		"invalid network":     "network,country,region,city\n203.0.113.0/33,PT,,\n",
+		"missing columns":     "network,country,region,city\n203.0.113.0/24,PT\n",
+		"partial coordinates": "network,country,region,city,latitude\n203.0.113.0/24,PT,,,38.7\n",
+		"invalid latitude":    "network,country,region,city,latitude,longitude\n203.0.113.0/24,PT,,,97,-9.1\n",
+		"invalid longitude":   "network,country,region,city,latitude,longitude\n203.0.113.0/24,PT,,,38.7,west\n",
+		"overlapping network": "network,country,region,city\n203.0.0.0/16,PT,,\n203.0.113.0/24,ES,,\n",
+	}
+	for name, input := range tests {
+		_, err := ParseDatabase(strings.NewReader(input))
+		assert.Error(t, err, name)
+	}
+}
+
+func TestDatabase_Coordinates(t *testing.T) {
+	db, err := ParseDatabase(strings.NewReader(`network,country,region,city,latitude,longitude
+203.0.113.0/24,PT,Lisbon,Lisbon,38.7223,-9.1393
+198.51.100.0/24,BR,São Paulo,São Paulo,-23.5505,-46.6333
+192.0.2.0/24,US,,,,
+`))
+	require.NoError(t, err)
+
+	lisbon, saoPaulo := db.Locate("203.0.113.7"), db.Locate("198.51.100.7")
+	assert.Equal(t, Location{Country: "PT", Region: "Lisbon", City: "Lisbon", Latitude: 38.7223, Longitude: -9.1393, HasCoordinates: true}, lisbon)
+	assert.False(t, db.Locate("192.0.2.1").HasCoordinates)
+
+	distance, ok := Distance(lisbon, saoPaulo)
+	require.True(t, ok)
+	assert.InDelta(t, 7930, distance, 20)
+	distance, ok = Distance(lisbon, lisbon)
+	require.True(t, ok)
+	assert.Zero(t, distance)
+	_, ok = Distance(lisbon, db.Locate("192.0.2.1"))
+	assert.False(t, ok)
+}
+
+func TestLoadDatabase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "geoip.csv")
+	require.NoError(t, os.WriteFile(path, []byte(testDatabase), 0o600))
+
+	db, err := LoadDatabase(path)
+	require.NoError(t, err)
+	assert.Equal(t, "PT", db.Locate("203.0.113.7").Country)
+
+	_, err = LoadDatabase(filepath.Join(t.TempDir(), "missing.csv"))
+	assert.Error(t, err)
+}
+
+func TestLocation_String(t *testing.T) {
+	assert.Equal(t, "Lisbon, Lisbon, PT", Location{Country: "PT", Region: "Lisbon", City: "Lisbon"}.String())
+	assert.Equal(t, "US", Location{Country: "US"}.String())
+	assert.Equal(t, "", Location{}.String())
+	assert.Equal(t, Location{}, NopLocator{}.Locate("203.0.113.7"))
+}
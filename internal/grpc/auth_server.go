@@ -2,28 +2,120 @@ package grpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
 	"time"
 
 	"hub-user-service/internal/auth"
+	"hub-user-service/internal/auth/token"
+	"hub-user-service/internal/events"
+	"hub-user-service/internal/grpc/clientinfo"
 	"hub-user-service/internal/grpc/proto"
 	"hub-user-service/internal/login/application/usecase"
+	loginCodeUsecase "hub-user-service/internal/logincode/application/usecase"
+	loginHistoryUsecase "hub-user-service/internal/loginhistory/application/usecase"
+	loginHistoryModel "hub-user-service/internal/loginhistory/domain/model"
+	mfaUsecase "hub-user-service/internal/mfa/application/usecase"
+	passkeyUsecase "hub-user-service/internal/passkey/application/usecase"
+	sessionUsecase "hub-user-service/internal/session/application/usecase"
 )
 
 // AuthServer implements the gRPC AuthService interface
 type AuthServer struct {
 	proto.UnimplementedAuthServiceServer
-	loginUsecase usecase.IDoLoginUsecase
-	authService  auth.IAuthService
+	loginUsecase   usecase.IDoLoginUsecase
+	authService    auth.IAuthService
+	eventPublisher events.Publisher
+	totp           mfaUsecase.ITOTPUsecase
+	passkeys       passkeyUsecase.IPasskeyUsecase
+	loginCodes     loginCodeUsecase.ILoginCodeUsecase
+	sessions       sessionUsecase.ISessionUsecase
+	loginHistory   loginHistoryUsecase.ILoginHistoryUsecase
+	loginGate      usecase.ILoginGateUsecase
+	clientTrust    clientinfo.Trust
+}
+
+// AuthServerOption configures optional AuthServer collaborators
+type AuthServerOption func(*AuthServer)
+
+// WithEventPublisher publishes user lifecycle events (login, ...) to the given publisher
+func WithEventPublisher(publisher events.Publisher) AuthServerOption {
+	return func(s *AuthServer) {
+		s.eventPublisher = publisher
+	}
+}
+
+// WithTOTP enables TOTP enrollment and requires the second factor at login for enrolled users
+func WithTOTP(totp mfaUsecase.ITOTPUsecase) AuthServerOption {
+	return func(s *AuthServer) {
+		s.totp = totp
+	}
+}
+
+// WithPasskeys enables WebAuthn passkey registration and passwordless login
+func WithPasskeys(passkeys passkeyUsecase.IPasskeyUsecase) AuthServerOption {
+	return func(s *AuthServer) {
+		s.passkeys = passkeys
+	}
+}
+
+// WithLoginCodes enables passwordless login with codes or magic links sent by email
+func WithLoginCodes(loginCodes loginCodeUsecase.ILoginCodeUsecase) AuthServerOption {
+	return func(s *AuthServer) {
+		s.loginCodes = loginCodes
+	}
+}
+
+// WithSessions records a session per login, embeds its ID in tokens and rejects tokens of revoked
+// or timed out sessions
+func WithSessions(sessions sessionUsecase.ISessionUsecase) AuthServerOption {
+	return func(s *AuthServer) {
+		s.sessions = sessions
+	}
+}
+
+// WithLoginHistory records every login attempt and serves GetLoginHistory
+func WithLoginHistory(history loginHistoryUsecase.ILoginHistoryUsecase) AuthServerOption {
+	return func(s *AuthServer) {
+		s.loginHistory = history
+	}
+}
+
+// WithLoginGate decides whether passkey and login code logins need a second factor or are denied
+// before tokens are issued; pass the gate of the login use case so every login is checked alike.
+// Without it only the TOTP enrollment of the user is checked
+func WithLoginGate(gate usecase.ILoginGateUsecase) AuthServerOption {
+	return func(s *AuthServer) {
+		s.loginGate = gate
+	}
+}
+
+// WithClientTrust believes the client IP address and type forwarded in the metadata of the calls
+// it trusts; without it sessions, login history and risk assessment use the peer address
+func WithClientTrust(trust clientinfo.Trust) AuthServerOption {
+	return func(s *AuthServer) {
+		s.clientTrust = trust
+	}
 }
 
 // NewAuthServer creates a new AuthServer instance
-func NewAuthServer(loginUsecase usecase.IDoLoginUsecase, authService auth.IAuthService) *AuthServer {
-	return &AuthServer{
-		loginUsecase: loginUsecase,
-		authService:  authService,
+func NewAuthServer(loginUsecase usecase.IDoLoginUsecase, authService auth.IAuthService, opts ...AuthServerOption) *AuthServer {
+	server := &AuthServer{
+		loginUsecase:   loginUsecase,
+		authService:    authService,
+		eventPublisher: events.NopPublisher{},
 	}
+
+	for _, opt := range opts {
+		opt(server)
+	}
+	if server.loginGate == nil {
+		server.loginGate = usecase.NewLoginGateUsecase(server.totp, nil)
+	}
+
+	return server
 }
 
 // Login handles user authentication and returns a JWT token
@@ -52,8 +144,13 @@ func (s *AuthServer) Login(ctx context.Context, req *proto.LoginRequest) (*proto
 	}
 
 	// Execute login use case (existing business logic)
-	user, err := s.loginUsecase.Execute(req.Email, req.Password)
+	user, decision, err := s.loginUsecase.Login(ctx, req.Email, req.Password, s.loginClient(ctx))
+	if errors.Is(err, usecase.ErrLoginCheck) {
+		log.Printf("Failed to check login of %s: %v", req.Email, err)
+		return &proto.LoginResponse{ApiResponse: newAPIResponse(false, "failed to check login", http.StatusInternalServerError)}, nil
+	}
 	if err != nil {
+		s.recordLogin(ctx, loginHistoryModel.LoginAttempt{Email: req.Email, Methods: []string{token.MethodPassword}, Outcome: loginHistoryModel.OutcomeFailure})
 		return &proto.LoginResponse{
 			ApiResponse: &proto.APIResponse{
 				Success:   false,
@@ -64,8 +161,22 @@ func (s *AuthServer) Login(ctx context.Context, req *proto.LoginRequest) (*proto
 		}, nil
 	}
 
+	return s.finishLogin(ctx, user.GetEmailString(), user.ID, token.PasswordAuthentication(time.Now()), decision), nil
+}
+
+// completeLogin issues the access token of an authenticated user and publishes the login event
+func (s *AuthServer) completeLogin(ctx context.Context, email string, userID string, authn token.Authentication) *proto.LoginResponse {
+	if s.sessions != nil {
+		session, err := s.sessions.Start(ctx, userID, s.clientInfo(ctx))
+		if err != nil {
+			log.Printf("Failed to start session for user %s: %v", userID, err)
+			return &proto.LoginResponse{ApiResponse: newAPIResponse(false, "failed to start session", http.StatusInternalServerError)}
+		}
+		authn.SessionID = session.ID
+	}
+
 	// Create JWT token using existing auth service
-	token, err := s.authService.CreateToken(user.GetEmailString(), user.ID)
+	accessToken, err := s.authService.CreateAuthenticatedToken(email, userID, authn)
 	if err != nil {
 		return &proto.LoginResponse{
 			ApiResponse: &proto.APIResponse{
@@ -74,7 +185,12 @@ func (s *AuthServer) Login(ctx context.Context, req *proto.LoginRequest) (*proto
 				Code:      http.StatusInternalServerError,
 				Timestamp: time.Now().Unix(),
 			},
-		}, nil
+		}
+	}
+
+	s.recordLogin(ctx, loginHistoryModel.LoginAttempt{UserID: userID, Email: email, Methods: authn.Methods, Outcome: loginHistoryModel.OutcomeSuccess})
+	if err := s.eventPublisher.Publish(ctx, events.Event{Type: events.EventLogin, UserID: userID}); err != nil {
+		log.Printf("Failed to publish login event for user %s: %v", userID, err)
 	}
 
 	// Return successful response
@@ -85,12 +201,12 @@ func (s *AuthServer) Login(ctx context.Context, req *proto.LoginRequest) (*proto
 			Code:      http.StatusOK,
 			Timestamp: time.Now().Unix(),
 		},
-		Token: token,
+		Token: accessToken,
 		UserInfo: &proto.UserInfo{
-			UserId: user.ID,
-			Email:  user.GetEmailString(),
+			UserId: userID,
+			Email:  email,
 		},
-	}, nil
+	}
 }
 
 // ValidateToken validates a JWT token and returns user information
@@ -108,10 +224,11 @@ func (s *AuthServer) ValidateToken(ctx context.Context, req *proto.ValidateToken
 		}, nil
 	}
 
-	// Verify token using existing auth service
-	// Note: VerifyToken expects http.ResponseWriter, but we don't need it for gRPC
-	// We'll pass nil and handle the response differently
-	userId, err := s.authService.VerifyToken(req.Token, nil)
+	if req.MaxAgeSeconds < 0 {
+		return &proto.ValidateTokenResponse{ApiResponse: newAPIResponse(false, "max_age_seconds must not be negative", http.StatusBadRequest)}, nil
+	}
+
+	identity, err := s.authService.VerifyAccessToken(req.Token)
 	if err != nil {
 		return &proto.ValidateTokenResponse{
 			ApiResponse: &proto.APIResponse{
@@ -123,19 +240,40 @@ func (s *AuthServer) ValidateToken(ctx context.Context, req *proto.ValidateToken
 			IsValid: false,
 		}, nil
 	}
+	if err := s.checkSession(ctx, identity); err != nil {
+		if errors.Is(err, sessionUsecase.ErrSessionRevoked) || errors.Is(err, sessionUsecase.ErrSessionExpired) {
+			return &proto.ValidateTokenResponse{ApiResponse: newAPIResponse(false, err.Error(), http.StatusUnauthorized)}, nil
+		}
+		return &proto.ValidateTokenResponse{ApiResponse: newAPIResponse(false, "failed to check session", http.StatusInternalServerError)}, nil
+	}
 
-	// Token is valid
-	return &proto.ValidateTokenResponse{
-		ApiResponse: &proto.APIResponse{
-			Success:   true,
-			Message:   "token is valid",
-			Code:      http.StatusOK,
-			Timestamp: time.Now().Unix(),
-		},
-		IsValid: true,
+	authn := identity.Authentication
+	resp := &proto.ValidateTokenResponse{
+		ApiResponse: newAPIResponse(true, "token is valid", http.StatusOK),
+		IsValid:     true,
 		UserInfo: &proto.UserInfo{
-			UserId: userId,
+			UserId: identity.UserID,
 		},
-		ExpiresAt: 0, // TODO: Extract expiration from token if needed
-	}, nil
+		Acr: authn.Level,
+		Amr: authn.Methods,
+	}
+	if !identity.ExpiresAt.IsZero() {
+		resp.ExpiresAt = identity.ExpiresAt.Unix()
+	}
+	if !authn.Time.IsZero() {
+		resp.AuthTime = authn.Time.Unix()
+	}
+
+	// Sensitive operations ask for a recent or stronger authentication
+	err = authn.Require(req.RequiredAcr, time.Duration(req.MaxAgeSeconds)*time.Second, time.Now())
+	switch {
+	case errors.Is(err, token.ErrUnknownLevel):
+		resp.IsValid = false
+		resp.ApiResponse = newAPIResponse(false, err.Error(), http.StatusBadRequest)
+	case err != nil:
+		resp.IsValid = false
+		resp.StepUpRequired = true
+		resp.ApiResponse = newAPIResponse(false, fmt.Sprintf("step-up required: %v", err), http.StatusForbidden)
+	}
+	return resp, nil
 }
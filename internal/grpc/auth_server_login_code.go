@@ -0,0 +1,76 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"hub-user-service/internal/auth/token"
+	"hub-user-service/internal/grpc/proto"
+	loginCodeUsecase "hub-user-service/internal/logincode/application/usecase"
+	loginHistoryModel "hub-user-service/internal/loginhistory/domain/model"
+)
+
+// errLoginCodesDisabled is answered when the server runs without LOGIN_CODE_ENABLED
+var errLoginCodesDisabled = errors.New("login codes are not enabled")
+
+// loginCodeErrorResponse maps login code use case errors to the response envelope without leaking internals
+func loginCodeErrorResponse(action string, err error) *proto.APIResponse {
+	switch {
+	case errors.Is(err, loginCodeUsecase.ErrInvalidCode):
+		return newAPIResponse(false, err.Error(), http.StatusUnauthorized)
+	case errors.Is(err, loginCodeUsecase.ErrTooManyRequests), errors.Is(err, loginCodeUsecase.ErrTooManyAttempts):
+		return newAPIResponse(false, err.Error(), http.StatusTooManyRequests)
+	case errors.Is(err, errLoginCodesDisabled):
+		return newAPIResponse(false, err.Error(), http.StatusServiceUnavailable)
+	default:
+		log.Printf("Failed to %s: %v", action, err)
+		return newAPIResponse(false, "failed to "+action, http.StatusInternalServerError)
+	}
+}
+
+// RequestLoginCode emails a one-time code or magic link; the answer is the same whether the account exists or not
+func (s *AuthServer) RequestLoginCode(ctx context.Context, req *proto.RequestLoginCodeRequest) (*proto.RequestLoginCodeResponse, error) {
+	if s.loginCodes == nil {
+		return &proto.RequestLoginCodeResponse{ApiResponse: loginCodeErrorResponse("", errLoginCodesDisabled)}, nil
+	}
+	if req.Email == "" {
+		return &proto.RequestLoginCodeResponse{ApiResponse: newAPIResponse(false, "email is required", http.StatusBadRequest)}, nil
+	}
+
+	if err := s.loginCodes.RequestCode(ctx, req.Email); err != nil {
+		return &proto.RequestLoginCodeResponse{ApiResponse: loginCodeErrorResponse("send login code", err)}, nil
+	}
+
+	return &proto.RequestLoginCodeResponse{
+		ApiResponse: newAPIResponse(true, "if an account exists for this email, a login code was sent", http.StatusOK),
+	}, nil
+}
+
+// VerifyLoginCode checks the emailed code and issues the same token as Login
+func (s *AuthServer) VerifyLoginCode(ctx context.Context, req *proto.VerifyLoginCodeRequest) (*proto.LoginResponse, error) {
+	if s.loginCodes == nil {
+		return &proto.LoginResponse{ApiResponse: loginCodeErrorResponse("", errLoginCodesDisabled)}, nil
+	}
+	if req.Email == "" || req.Code == "" {
+		return &proto.LoginResponse{ApiResponse: newAPIResponse(false, "email and code are required", http.StatusBadRequest)}, nil
+	}
+
+	user, err := s.loginCodes.VerifyCode(ctx, req.Email, req.Code)
+	if err != nil {
+		if errors.Is(err, loginCodeUsecase.ErrInvalidCode) || errors.Is(err, loginCodeUsecase.ErrTooManyAttempts) {
+			s.recordLogin(ctx, loginHistoryModel.LoginAttempt{Email: req.Email, Methods: []string{token.MethodEmailCode}, Outcome: loginHistoryModel.OutcomeFailure})
+		}
+		return &proto.LoginResponse{ApiResponse: loginCodeErrorResponse("verify login code", err)}, nil
+	}
+
+	// An emailed code replaces the password, enrolled users still need their second factor
+	return s.authenticate(ctx, user.GetEmailString(), user.ID, loginCodeAuthentication(time.Now())), nil
+}
+
+// loginCodeAuthentication describes a login with an emailed code, a single factor
+func loginCodeAuthentication(at time.Time) token.Authentication {
+	return token.Authentication{Methods: []string{token.MethodEmailCode}, Level: token.LevelSingleFactor, Time: at}
+}
@@ -0,0 +1,157 @@
+package grpc
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"hub-user-service/internal/auth/token"
+	"hub-user-service/internal/grpc/proto"
+	loginModel "hub-user-service/internal/login/domain/model"
+	loginCodeUsecase "hub-user-service/internal/logincode/application/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockLoginCodeUsecase mocks the login code use case
+type MockLoginCodeUsecase struct {
+	mock.Mock
+}
+
+func (m *MockLoginCodeUsecase) RequestCode(ctx context.Context, email string) error {
+	return m.Called(ctx, email).Error(0)
+}
+
+func (m *MockLoginCodeUsecase) VerifyCode(ctx context.Context, email string, code string) (*loginModel.User, error) {
+	args := m.Called(ctx, email, code)
+	user, _ := args.Get(0).(*loginModel.User)
+	return user, args.Error(1)
+}
+
+func TestAuthServer_RequestLoginCode(t *testing.T) {
+	t.Run("sends the code", func(t *testing.T) {
+		mockLoginCodes := new(MockLoginCodeUsecase)
+		mockLoginCodes.On("RequestCode", mock.Anything, "test@example.com").Return(nil)
+
+		server := NewAuthServer(new(MockLoginUsecase), new(MockAuthService), WithLoginCodes(mockLoginCodes))
+		resp, err := server.RequestLoginCode(context.Background(), &proto.RequestLoginCodeRequest{Email: "test@example.com"})
+
+		require.NoError(t, err)
+		assert.True(t, resp.ApiResponse.Success)
+		mockLoginCodes.AssertExpectations(t)
+	})
+
+	t.Run("too many requests", func(t *testing.T) {
+		mockLoginCodes := new(MockLoginCodeUsecase)
+		mockLoginCodes.On("RequestCode", mock.Anything, "test@example.com").Return(loginCodeUsecase.ErrTooManyRequests)
+
+		server := NewAuthServer(new(MockLoginUsecase), new(MockAuthService), WithLoginCodes(mockLoginCodes))
+		resp, err := server.RequestLoginCode(context.Background(), &proto.RequestLoginCodeRequest{Email: "test@example.com"})
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(http.StatusTooManyRequests), resp.ApiResponse.Code)
+	})
+
+	t.Run("delivery failure is not leaked", func(t *testing.T) {
+		mockLoginCodes := new(MockLoginCodeUsecase)
+		mockLoginCodes.On("RequestCode", mock.Anything, "test@example.com").Return(assert.AnError)
+
+		server := NewAuthServer(new(MockLoginUsecase), new(MockAuthService), WithLoginCodes(mockLoginCodes))
+		resp, err := server.RequestLoginCode(context.Background(), &proto.RequestLoginCodeRequest{Email: "test@example.com"})
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(http.StatusInternalServerError), resp.ApiResponse.Code)
+		assert.Equal(t, "failed to send login code", resp.ApiResponse.Message)
+	})
+
+	t.Run("missing email", func(t *testing.T) {
+		server := NewAuthServer(new(MockLoginUsecase), new(MockAuthService), WithLoginCodes(new(MockLoginCodeUsecase)))
+		resp, err := server.RequestLoginCode(context.Background(), &proto.RequestLoginCodeRequest{})
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(http.StatusBadRequest), resp.ApiResponse.Code)
+	})
+}
+
+func TestAuthServer_VerifyLoginCode(t *testing.T) {
+	t.Run("issues the access token", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		mockLoginCodes := new(MockLoginCodeUsecase)
+		mockTOTP := new(MockTOTPUsecase)
+		mockLoginCodes.On("VerifyCode", mock.Anything, "test@example.com", "123456").Return(createTestUserForGRPC(), nil)
+		mockTOTP.On("IsEnabled", mock.Anything, "user123").Return(false, nil)
+		mockAuthService.On("CreateAuthenticatedToken", "test@example.com", "user123", withLevel(token.LevelSingleFactor, token.MethodEmailCode)).
+			Return("mock-jwt-token-123", nil)
+
+		server := NewAuthServer(new(MockLoginUsecase), mockAuthService, WithLoginCodes(mockLoginCodes), WithTOTP(mockTOTP))
+		resp, err := server.VerifyLoginCode(context.Background(), &proto.VerifyLoginCodeRequest{Email: "test@example.com", Code: "123456"})
+
+		require.NoError(t, err)
+		assert.True(t, resp.ApiResponse.Success)
+		assert.Equal(t, "mock-jwt-token-123", resp.Token)
+		assert.Equal(t, "user123", resp.UserInfo.UserId)
+		mockAuthService.AssertExpectations(t)
+	})
+
+	t.Run("enrolled users need the second factor", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		mockLoginCodes := new(MockLoginCodeUsecase)
+		mockTOTP := new(MockTOTPUsecase)
+		mockLoginCodes.On("VerifyCode", mock.Anything, "test@example.com", "123456").Return(createTestUserForGRPC(), nil)
+		mockTOTP.On("IsEnabled", mock.Anything, "user123").Return(true, nil)
+		mockAuthService.On("CreateMFAChallenge", "test@example.com", "user123", []string{token.MethodEmailCode}).Return("challenge-token", nil)
+
+		server := NewAuthServer(new(MockLoginUsecase), mockAuthService, WithLoginCodes(mockLoginCodes), WithTOTP(mockTOTP))
+		resp, err := server.VerifyLoginCode(context.Background(), &proto.VerifyLoginCodeRequest{Email: "test@example.com", Code: "123456"})
+
+		require.NoError(t, err)
+		assert.True(t, resp.MfaRequired)
+		assert.Equal(t, "challenge-token", resp.MfaChallengeToken)
+		assert.Empty(t, resp.Token)
+	})
+
+	t.Run("invalid code", func(t *testing.T) {
+		mockLoginCodes := new(MockLoginCodeUsecase)
+		mockLoginCodes.On("VerifyCode", mock.Anything, "test@example.com", "000000").Return(nil, loginCodeUsecase.ErrInvalidCode)
+
+		server := NewAuthServer(new(MockLoginUsecase), new(MockAuthService), WithLoginCodes(mockLoginCodes))
+		resp, err := server.VerifyLoginCode(context.Background(), &proto.VerifyLoginCodeRequest{Email: "test@example.com", Code: "000000"})
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(http.StatusUnauthorized), resp.ApiResponse.Code)
+		assert.Empty(t, resp.Token)
+	})
+
+	t.Run("too many attempts", func(t *testing.T) {
+		mockLoginCodes := new(MockLoginCodeUsecase)
+		mockLoginCodes.On("VerifyCode", mock.Anything, "test@example.com", "000000").Return(nil, loginCodeUsecase.ErrTooManyAttempts)
+
+		server := NewAuthServer(new(MockLoginUsecase), new(MockAuthService), WithLoginCodes(mockLoginCodes))
+		resp, err := server.VerifyLoginCode(context.Background(), &proto.VerifyLoginCodeRequest{Email: "test@example.com", Code: "000000"})
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(http.StatusTooManyRequests), resp.ApiResponse.Code)
+	})
+
+	t.Run("missing fields", func(t *testing.T) {
+		server := NewAuthServer(new(MockLoginUsecase), new(MockAuthService), WithLoginCodes(new(MockLoginCodeUsecase)))
+		resp, err := server.VerifyLoginCode(context.Background(), &proto.VerifyLoginCodeRequest{Email: "test@example.com"})
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(http.StatusBadRequest), resp.ApiResponse.Code)
+	})
+}
+
+func TestAuthServer_LoginCodesDisabled(t *testing.T) {
+	server := NewAuthServer(new(MockLoginUsecase), new(MockAuthService))
+
+	request, err := server.RequestLoginCode(context.Background(), &proto.RequestLoginCodeRequest{Email: "test@example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(http.StatusServiceUnavailable), request.ApiResponse.Code)
+
+	verify, err := server.VerifyLoginCode(context.Background(), &proto.VerifyLoginCodeRequest{Email: "test@example.com", Code: "123456"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(http.StatusServiceUnavailable), verify.ApiResponse.Code)
+}
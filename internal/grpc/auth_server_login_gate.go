@@ -0,0 +1,47 @@
+package grpc
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"hub-user-service/internal/auth/token"
+	"hub-user-service/internal/grpc/proto"
+	"hub-user-service/internal/login/application/usecase"
+	loginHistoryModel "hub-user-service/internal/loginhistory/domain/model"
+)
+
+// authenticate finishes a passwordless login whose first factor (authn) was accepted; password
+// logins get their decision from the login use case instead
+func (s *AuthServer) authenticate(ctx context.Context, email string, userID string, authn token.Authentication) *proto.LoginResponse {
+	decision, err := s.loginGate.Check(ctx, usecase.FirstFactor{
+		UserID:      userID,
+		MultiFactor: authn.Level == token.LevelMultiFactor,
+		Client:      s.loginClient(ctx),
+		Time:        authn.Time,
+	})
+	if err != nil {
+		log.Printf("Failed to check login of user %s: %v", userID, err)
+		return &proto.LoginResponse{ApiResponse: newAPIResponse(false, "failed to check login", http.StatusInternalServerError)}
+	}
+	return s.finishLogin(ctx, email, userID, authn, decision)
+}
+
+// finishLogin carries out the login gate decision: a denied login is recorded and gets no token,
+// a login needing a second factor gets an MFA challenge
+func (s *AuthServer) finishLogin(ctx context.Context, email string, userID string, authn token.Authentication, decision usecase.LoginDecision) *proto.LoginResponse {
+	switch decision {
+	case usecase.LoginDenied:
+		s.recordLogin(ctx, loginHistoryModel.LoginAttempt{UserID: userID, Email: email, Methods: authn.Methods, Outcome: loginHistoryModel.OutcomeDenied})
+		return &proto.LoginResponse{ApiResponse: newAPIResponse(false, "login denied", http.StatusForbidden)}
+	case usecase.LoginNeedsMFA:
+		return s.mfaChallenge(ctx, email, userID, authn.Methods)
+	}
+	return s.completeLogin(ctx, email, userID, authn)
+}
+
+// loginClient is the client of the call as the login gate sees it
+func (s *AuthServer) loginClient(ctx context.Context) usecase.LoginClient {
+	client := s.clientInfo(ctx)
+	return usecase.LoginClient{IPAddress: client.IPAddress, UserAgent: client.UserAgent, Device: client.Device}
+}
@@ -0,0 +1,118 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+
+	"hub-user-service/internal/grpc/clientinfo"
+	"hub-user-service/internal/grpc/proto"
+	"hub-user-service/internal/login/application/usecase"
+	loginHistoryModel "hub-user-service/internal/loginhistory/domain/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// MockLoginGateUsecase mocks the login gate
+type MockLoginGateUsecase struct {
+	mock.Mock
+}
+
+func (m *MockLoginGateUsecase) Check(ctx context.Context, login usecase.FirstFactor) (usecase.LoginDecision, error) {
+	args := m.Called(ctx, login)
+	return args.Get(0).(usecase.LoginDecision), args.Error(1)
+}
+
+func TestAuthServer_LoginFollowsTheLoginDecision(t *testing.T) {
+	t.Run("allowed", func(t *testing.T) {
+		mockLoginUsecase := new(MockLoginUsecase)
+		mockAuthService := new(MockAuthService)
+		client := usecase.LoginClient{IPAddress: "203.0.113.7", UserAgent: "Mozilla/5.0"}
+		mockLoginUsecase.On("Login", mock.Anything, "test@example.com", "password123", client).Return(createTestUserForGRPC(), usecase.LoginAllowed, nil)
+		mockAuthService.On("CreateAuthenticatedToken", "test@example.com", "user123", mock.Anything).Return("access", nil)
+
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(clientinfo.ForwardedForMetadataKey, "203.0.113.7", clientinfo.UserAgentMetadataKey, "Mozilla/5.0"))
+		server := NewAuthServer(mockLoginUsecase, mockAuthService, WithClientTrust(trustGateway))
+		resp, err := server.Login(ctx, &proto.LoginRequest{Email: "test@example.com", Password: "password123"})
+
+		require.NoError(t, err)
+		assert.True(t, resp.ApiResponse.Success)
+		assert.Equal(t, "access", resp.Token)
+		mockLoginUsecase.AssertExpectations(t)
+	})
+
+	t.Run("spoofed client address", func(t *testing.T) {
+		mockLoginUsecase := new(MockLoginUsecase)
+		// The peer address is checked, not the address it claims
+		mockLoginUsecase.On("Login", mock.Anything, "test@example.com", "password123", usecase.LoginClient{IPAddress: "198.51.100.4"}).
+			Return(createTestUserForGRPC(), usecase.LoginDenied, nil)
+
+		ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("198.51.100.4"), Port: 52100}})
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(clientinfo.ForwardedForMetadataKey, "203.0.113.7"))
+		server := NewAuthServer(mockLoginUsecase, new(MockAuthService), WithClientTrust(func(context.Context) bool { return false }))
+		resp, err := server.Login(ctx, &proto.LoginRequest{Email: "test@example.com", Password: "password123"})
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(http.StatusForbidden), resp.ApiResponse.Code)
+		mockLoginUsecase.AssertExpectations(t)
+	})
+
+	t.Run("denied", func(t *testing.T) {
+		mockLoginUsecase := new(MockLoginUsecase)
+		mockAuthService := new(MockAuthService)
+		mockHistory := new(MockLoginHistoryUsecase)
+		mockLoginUsecase.On("Login", mock.Anything, "test@example.com", "password123", mock.Anything).Return(createTestUserForGRPC(), usecase.LoginDenied, nil)
+		mockHistory.On("Record", mock.Anything, withOutcome(loginHistoryModel.OutcomeDenied, "pwd")).Return(nil)
+
+		server := NewAuthServer(mockLoginUsecase, mockAuthService, WithLoginHistory(mockHistory))
+		resp, err := server.Login(context.Background(), &proto.LoginRequest{Email: "test@example.com", Password: "password123"})
+
+		require.NoError(t, err)
+		assert.False(t, resp.ApiResponse.Success)
+		assert.Equal(t, int32(http.StatusForbidden), resp.ApiResponse.Code)
+		assert.Empty(t, resp.Token)
+		mockAuthService.AssertNotCalled(t, "CreateAuthenticatedToken", mock.Anything, mock.Anything, mock.Anything)
+		mockHistory.AssertExpectations(t)
+	})
+}
+
+func TestAuthServer_PasswordlessLoginAsksTheLoginGate(t *testing.T) {
+	t.Run("denied", func(t *testing.T) {
+		mockLoginCodes := new(MockLoginCodeUsecase)
+		mockGate := new(MockLoginGateUsecase)
+		mockAuthService := new(MockAuthService)
+		mockLoginCodes.On("VerifyCode", mock.Anything, "test@example.com", "123456").Return(createTestUserForGRPC(), nil)
+		mockGate.On("Check", mock.Anything, mock.MatchedBy(func(login usecase.FirstFactor) bool {
+			return login.UserID == "user123" && !login.MultiFactor && login.Client.IPAddress == "203.0.113.7" && !login.Time.IsZero()
+		})).Return(usecase.LoginDenied, nil)
+
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(clientinfo.ForwardedForMetadataKey, "203.0.113.7"))
+		server := NewAuthServer(new(MockLoginUsecase), mockAuthService, WithLoginCodes(mockLoginCodes), WithLoginGate(mockGate), WithClientTrust(trustGateway))
+		resp, err := server.VerifyLoginCode(ctx, &proto.VerifyLoginCodeRequest{Email: "test@example.com", Code: "123456"})
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(http.StatusForbidden), resp.ApiResponse.Code)
+		assert.Empty(t, resp.Token)
+		mockGate.AssertExpectations(t)
+		mockAuthService.AssertNotCalled(t, "CreateAuthenticatedToken", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("check fails", func(t *testing.T) {
+		mockLoginCodes := new(MockLoginCodeUsecase)
+		mockGate := new(MockLoginGateUsecase)
+		mockLoginCodes.On("VerifyCode", mock.Anything, "test@example.com", "123456").Return(createTestUserForGRPC(), nil)
+		mockGate.On("Check", mock.Anything, mock.Anything).Return(usecase.LoginDecision(""), assert.AnError)
+
+		server := NewAuthServer(new(MockLoginUsecase), new(MockAuthService), WithLoginCodes(mockLoginCodes), WithLoginGate(mockGate))
+		resp, err := server.VerifyLoginCode(context.Background(), &proto.VerifyLoginCodeRequest{Email: "test@example.com", Code: "123456"})
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(http.StatusInternalServerError), resp.ApiResponse.Code)
+		assert.Empty(t, resp.Token)
+	})
+}
@@ -0,0 +1,80 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"hub-user-service/internal/grpc/proto"
+	"hub-user-service/internal/loginhistory/domain/model"
+)
+
+// Page size of GetLoginHistory
+const (
+	defaultLoginHistoryLimit = 20
+	maxLoginHistoryLimit     = 100
+)
+
+// errLoginHistoryDisabled is answered when the server runs without a login history
+var errLoginHistoryDisabled = errors.New("login history is not enabled")
+
+// recordLogin adds a login attempt from the current client to the history; a failure is only
+// logged, the history must never block a login
+func (s *AuthServer) recordLogin(ctx context.Context, attempt model.LoginAttempt) {
+	if s.loginHistory == nil {
+		return
+	}
+
+	client := s.clientInfo(ctx)
+	attempt.IPAddress, attempt.UserAgent, attempt.Device = client.IPAddress, client.UserAgent, client.Device
+	if err := s.loginHistory.Record(ctx, &attempt); err != nil {
+		log.Printf("Failed to record login attempt for %q: %v", attempt.Email, err)
+	}
+}
+
+// GetLoginHistory returns the caller's login attempts, most recent first
+func (s *AuthServer) GetLoginHistory(ctx context.Context, req *proto.GetLoginHistoryRequest) (*proto.GetLoginHistoryResponse, error) {
+	if s.loginHistory == nil {
+		return &proto.GetLoginHistoryResponse{ApiResponse: newAPIResponse(false, errLoginHistoryDisabled.Error(), http.StatusServiceUnavailable)}, nil
+	}
+	if req.Limit < 0 || req.BeforeId < 0 {
+		return &proto.GetLoginHistoryResponse{ApiResponse: newAPIResponse(false, "limit and before_id must not be negative", http.StatusBadRequest)}, nil
+	}
+
+	identity, err := s.verifyAccessToken(ctx, req.AccessToken)
+	if err != nil {
+		return &proto.GetLoginHistoryResponse{ApiResponse: newAPIResponse(false, "invalid access token", http.StatusUnauthorized)}, nil
+	}
+
+	limit := int(req.Limit)
+	if limit == 0 {
+		limit = defaultLoginHistoryLimit
+	}
+	if limit > maxLoginHistoryLimit {
+		limit = maxLoginHistoryLimit
+	}
+
+	attempts, err := s.loginHistory.List(ctx, identity.UserID, req.BeforeId, limit)
+	if err != nil {
+		log.Printf("Failed to get login history of user %s: %v", identity.UserID, err)
+		return &proto.GetLoginHistoryResponse{ApiResponse: newAPIResponse(false, "failed to get login history", http.StatusInternalServerError)}, nil
+	}
+
+	resp := &proto.GetLoginHistoryResponse{ApiResponse: newAPIResponse(true, "login history retrieved", http.StatusOK)}
+	for _, attempt := range attempts {
+		resp.Attempts = append(resp.Attempts, &proto.LoginAttempt{
+			Id:        attempt.ID,
+			CreatedAt: attempt.CreatedAt.Unix(),
+			Outcome:   string(attempt.Outcome),
+			Methods:   attempt.Methods,
+			Device:    attempt.Device,
+			IpAddress: attempt.IPAddress,
+			UserAgent: attempt.UserAgent,
+			Country:   attempt.Country,
+			Region:    attempt.Region,
+			City:      attempt.City,
+		})
+	}
+	return resp, nil
+}
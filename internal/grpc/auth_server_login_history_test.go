@@ -0,0 +1,190 @@
+package grpc
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"hub-user-service/internal/auth"
+	"hub-user-service/internal/auth/token"
+	"hub-user-service/internal/grpc/clientinfo"
+	"hub-user-service/internal/grpc/proto"
+	"hub-user-service/internal/login/application/usecase"
+	"hub-user-service/internal/loginhistory/domain/model"
+	mfaUsecase "hub-user-service/internal/mfa/application/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+// MockLoginHistoryUsecase mocks the login history use case
+type MockLoginHistoryUsecase struct {
+	mock.Mock
+}
+
+func (m *MockLoginHistoryUsecase) Record(ctx context.Context, attempt *model.LoginAttempt) error {
+	return m.Called(ctx, attempt).Error(0)
+}
+
+func (m *MockLoginHistoryUsecase) List(ctx context.Context, userID string, beforeID int64, limit int) ([]*model.LoginAttempt, error) {
+	args := m.Called(ctx, userID, beforeID, limit)
+	attempts, _ := args.Get(0).([]*model.LoginAttempt)
+	return attempts, args.Error(1)
+}
+
+// withOutcome matches an attempt of the given outcome and methods
+func withOutcome(outcome model.Outcome, methods ...string) interface{} {
+	return mock.MatchedBy(func(attempt *model.LoginAttempt) bool {
+		return attempt.Outcome == outcome && assert.ObjectsAreEqual(methods, attempt.Methods)
+	})
+}
+
+func TestAuthServer_LoginRecordsAttempt(t *testing.T) {
+	mockLoginUsecase := new(MockLoginUsecase)
+	mockAuthService := new(MockAuthService)
+	mockHistory := new(MockLoginHistoryUsecase)
+	mockLoginUsecase.On("Login", mock.Anything, "test@example.com", "password123", mock.Anything).Return(createTestUserForGRPC(), usecase.LoginAllowed, nil)
+	mockAuthService.On("CreateAuthenticatedToken", "test@example.com", "user123", mock.Anything).Return("access", nil)
+	mockHistory.On("Record", mock.Anything, mock.MatchedBy(func(attempt *model.LoginAttempt) bool {
+		return attempt.UserID == "user123" && attempt.Email == "test@example.com" && attempt.Outcome == model.OutcomeSuccess &&
+			attempt.IPAddress == "203.0.113.7" && attempt.UserAgent == "Mozilla/5.0" && attempt.Device == "Ada's laptop"
+	})).Return(nil)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		clientinfo.ForwardedForMetadataKey, "203.0.113.7",
+		clientinfo.UserAgentMetadataKey, "Mozilla/5.0",
+		clientinfo.DeviceNameMetadataKey, "Ada's laptop",
+	))
+	server := NewAuthServer(mockLoginUsecase, mockAuthService, WithLoginHistory(mockHistory), WithClientTrust(trustGateway))
+	resp, err := server.Login(ctx, &proto.LoginRequest{Email: "test@example.com", Password: "password123"})
+
+	require.NoError(t, err)
+	assert.True(t, resp.ApiResponse.Success)
+	mockHistory.AssertExpectations(t)
+}
+
+func TestAuthServer_FailedLoginRecordsAttempt(t *testing.T) {
+	mockLoginUsecase := new(MockLoginUsecase)
+	mockHistory := new(MockLoginHistoryUsecase)
+	mockLoginUsecase.On("Login", mock.Anything, "test@example.com", "wrong", mock.Anything).Return(nil, usecase.LoginDecision(""), assert.AnError)
+	mockHistory.On("Record", mock.Anything, mock.MatchedBy(func(attempt *model.LoginAttempt) bool {
+		return attempt.Email == "test@example.com" && attempt.UserID == "" && attempt.Outcome == model.OutcomeFailure
+	})).Return(assert.AnError)
+
+	server := NewAuthServer(mockLoginUsecase, new(MockAuthService), WithLoginHistory(mockHistory))
+	resp, err := server.Login(context.Background(), &proto.LoginRequest{Email: "test@example.com", Password: "wrong"})
+
+	// A history failure does not change the answer
+	require.NoError(t, err)
+	assert.Equal(t, int32(http.StatusUnauthorized), resp.ApiResponse.Code)
+	mockHistory.AssertExpectations(t)
+}
+
+func TestAuthServer_VerifyMFARecordsAttempts(t *testing.T) {
+	identity := &auth.Identity{UserID: "user123", UserName: "test@example.com", Authentication: token.Authentication{Methods: []string{token.MethodPassword}}}
+
+	t.Run("invalid code", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		mockTOTP := new(MockTOTPUsecase)
+		mockHistory := new(MockLoginHistoryUsecase)
+		mockAuthService.On("VerifyMFAChallenge", "challenge-token").Return(identity, nil)
+		mockTOTP.On("Verify", mock.Anything, "user123", "000000").Return(nil, mfaUsecase.ErrInvalidCode)
+		mockHistory.On("Record", mock.Anything, withOutcome(model.OutcomeFailure, token.MethodPassword, token.MethodOTP)).Return(nil)
+
+		server := NewAuthServer(new(MockLoginUsecase), mockAuthService, WithTOTP(mockTOTP), WithLoginHistory(mockHistory))
+		resp, err := server.VerifyMFA(context.Background(), &proto.VerifyMFARequest{MfaChallengeToken: "challenge-token", Code: "000000"})
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(http.StatusUnauthorized), resp.ApiResponse.Code)
+		mockHistory.AssertExpectations(t)
+	})
+
+	t.Run("storage error is not a failed attempt", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		mockTOTP := new(MockTOTPUsecase)
+		mockHistory := new(MockLoginHistoryUsecase)
+		mockAuthService.On("VerifyMFAChallenge", "challenge-token").Return(identity, nil)
+		mockTOTP.On("Verify", mock.Anything, "user123", "000000").Return(nil, assert.AnError)
+
+		server := NewAuthServer(new(MockLoginUsecase), mockAuthService, WithTOTP(mockTOTP), WithLoginHistory(mockHistory))
+		_, err := server.VerifyMFA(context.Background(), &proto.VerifyMFARequest{MfaChallengeToken: "challenge-token", Code: "000000"})
+
+		require.NoError(t, err)
+		mockHistory.AssertNotCalled(t, "Record", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAuthServer_GetLoginHistory(t *testing.T) {
+	identity := &auth.Identity{UserID: "user123", UserName: "test@example.com"}
+	at := time.Unix(1800000000, 0)
+
+	t.Run("success", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		mockHistory := new(MockLoginHistoryUsecase)
+		mockAuthService.On("VerifyAccessToken", "Bearer access").Return(identity, nil)
+		mockHistory.On("List", mock.Anything, "user123", int64(0), defaultLoginHistoryLimit).Return([]*model.LoginAttempt{
+			{ID: 2, Outcome: model.OutcomeSuccess, Methods: []string{"pwd"}, Device: "Chrome on macOS", IPAddress: "203.0.113.7", Country: "PT", City: "Lisbon", CreatedAt: at},
+			{ID: 1, Outcome: model.OutcomeFailure, Methods: []string{"pwd"}, CreatedAt: at.Add(-time.Minute)},
+		}, nil)
+
+		server := NewAuthServer(new(MockLoginUsecase), mockAuthService, WithLoginHistory(mockHistory))
+		resp, err := server.GetLoginHistory(context.Background(), &proto.GetLoginHistoryRequest{AccessToken: "Bearer access"})
+
+		require.NoError(t, err)
+		require.True(t, resp.ApiResponse.Success)
+		require.Len(t, resp.Attempts, 2)
+		assert.Equal(t, int64(2), resp.Attempts[0].Id)
+		assert.Equal(t, "success", resp.Attempts[0].Outcome)
+		assert.Equal(t, []string{"pwd"}, resp.Attempts[0].Methods)
+		assert.Equal(t, "Chrome on macOS", resp.Attempts[0].Device)
+		assert.Equal(t, "203.0.113.7", resp.Attempts[0].IpAddress)
+		assert.Equal(t, "PT", resp.Attempts[0].Country)
+		assert.Equal(t, "Lisbon", resp.Attempts[0].City)
+		assert.Equal(t, at.Unix(), resp.Attempts[0].CreatedAt)
+		assert.Equal(t, "failure", resp.Attempts[1].Outcome)
+	})
+
+	t.Run("limit is capped", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		mockHistory := new(MockLoginHistoryUsecase)
+		mockAuthService.On("VerifyAccessToken", "Bearer access").Return(identity, nil)
+		mockHistory.On("List", mock.Anything, "user123", int64(40), maxLoginHistoryLimit).Return([]*model.LoginAttempt{}, nil)
+
+		server := NewAuthServer(new(MockLoginUsecase), mockAuthService, WithLoginHistory(mockHistory))
+		resp, err := server.GetLoginHistory(context.Background(), &proto.GetLoginHistoryRequest{AccessToken: "Bearer access", Limit: 1000, BeforeId: 40})
+
+		require.NoError(t, err)
+		assert.True(t, resp.ApiResponse.Success)
+		mockHistory.AssertExpectations(t)
+	})
+
+	t.Run("negative limit", func(t *testing.T) {
+		server := NewAuthServer(new(MockLoginUsecase), new(MockAuthService), WithLoginHistory(new(MockLoginHistoryUsecase)))
+		resp, err := server.GetLoginHistory(context.Background(), &proto.GetLoginHistoryRequest{AccessToken: "Bearer access", Limit: -1})
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(http.StatusBadRequest), resp.ApiResponse.Code)
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		mockAuthService.On("VerifyAccessToken", "Bearer bad").Return(nil, assert.AnError)
+
+		server := NewAuthServer(new(MockLoginUsecase), mockAuthService, WithLoginHistory(new(MockLoginHistoryUsecase)))
+		resp, err := server.GetLoginHistory(context.Background(), &proto.GetLoginHistoryRequest{AccessToken: "Bearer bad"})
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(http.StatusUnauthorized), resp.ApiResponse.Code)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		server := NewAuthServer(new(MockLoginUsecase), new(MockAuthService))
+		resp, err := server.GetLoginHistory(context.Background(), &proto.GetLoginHistoryRequest{AccessToken: "Bearer access"})
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(http.StatusServiceUnavailable), resp.ApiResponse.Code)
+	})
+}
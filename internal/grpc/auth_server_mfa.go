@@ -0,0 +1,170 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"hub-user-service/internal/auth/token"
+	"hub-user-service/internal/grpc/proto"
+	loginHistoryModel "hub-user-service/internal/loginhistory/domain/model"
+	mfaUsecase "hub-user-service/internal/mfa/application/usecase"
+)
+
+// newAPIResponse builds the common response envelope
+func newAPIResponse(success bool, message string, code int32) *proto.APIResponse {
+	return &proto.APIResponse{
+		Success:   success,
+		Message:   message,
+		Code:      code,
+		Timestamp: time.Now().Unix(),
+	}
+}
+
+// mfaChallenge answers a login whose first factor (methods) was accepted but which needs a second factor
+func (s *AuthServer) mfaChallenge(ctx context.Context, email string, userID string, methods []string) *proto.LoginResponse {
+	challenge, err := s.authService.CreateMFAChallenge(email, userID, methods)
+	if err != nil {
+		log.Printf("Failed to create MFA challenge for user %s: %v", userID, err)
+		return &proto.LoginResponse{ApiResponse: newAPIResponse(false, "failed to create MFA challenge", http.StatusInternalServerError)}
+	}
+
+	s.recordLogin(ctx, loginHistoryModel.LoginAttempt{UserID: userID, Email: email, Methods: methods, Outcome: loginHistoryModel.OutcomeMFARequired})
+
+	return &proto.LoginResponse{
+		ApiResponse:       newAPIResponse(true, "mfa required", http.StatusOK),
+		MfaRequired:       true,
+		MfaChallengeToken: challenge,
+		UserInfo:          &proto.UserInfo{UserId: userID},
+	}
+}
+
+// mfaErrorResponse maps TOTP use case errors to the response envelope without leaking internals
+func mfaErrorResponse(action string, err error) *proto.APIResponse {
+	switch {
+	case errors.Is(err, mfaUsecase.ErrInvalidCode), errors.Is(err, mfaUsecase.ErrCodeReused):
+		return newAPIResponse(false, err.Error(), http.StatusUnauthorized)
+	case errors.Is(err, mfaUsecase.ErrTooManyAttempts):
+		return newAPIResponse(false, err.Error(), http.StatusTooManyRequests)
+	case errors.Is(err, mfaUsecase.ErrAlreadyEnrolled):
+		return newAPIResponse(false, err.Error(), http.StatusConflict)
+	case errors.Is(err, mfaUsecase.ErrNotEnrolled):
+		return newAPIResponse(false, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, mfaUsecase.ErrMFANotConfigured):
+		return newAPIResponse(false, err.Error(), http.StatusServiceUnavailable)
+	default:
+		log.Printf("Failed to %s: %v", action, err)
+		return newAPIResponse(false, "failed to "+action, http.StatusInternalServerError)
+	}
+}
+
+// BeginTOTPEnrollment creates a pending authenticator app secret for the caller
+func (s *AuthServer) BeginTOTPEnrollment(ctx context.Context, req *proto.BeginTOTPEnrollmentRequest) (*proto.BeginTOTPEnrollmentResponse, error) {
+	if s.totp == nil {
+		return &proto.BeginTOTPEnrollmentResponse{ApiResponse: mfaErrorResponse("", mfaUsecase.ErrMFANotConfigured)}, nil
+	}
+
+	identity, err := s.verifyAccessToken(ctx, req.AccessToken)
+	if err != nil {
+		return &proto.BeginTOTPEnrollmentResponse{ApiResponse: newAPIResponse(false, "invalid access token", http.StatusUnauthorized)}, nil
+	}
+
+	enrollment, err := s.totp.BeginEnrollment(ctx, identity.UserID, identity.UserName)
+	if err != nil {
+		return &proto.BeginTOTPEnrollmentResponse{ApiResponse: mfaErrorResponse("begin TOTP enrollment", err)}, nil
+	}
+
+	return &proto.BeginTOTPEnrollmentResponse{
+		ApiResponse: newAPIResponse(true, "scan the QR code and confirm with a code", http.StatusOK),
+		OtpauthUri:  enrollment.URI,
+		Secret:      enrollment.Secret,
+	}, nil
+}
+
+// ConfirmTOTPEnrollment enables MFA for the caller
+func (s *AuthServer) ConfirmTOTPEnrollment(ctx context.Context, req *proto.ConfirmTOTPEnrollmentRequest) (*proto.ConfirmTOTPEnrollmentResponse, error) {
+	if s.totp == nil {
+		return &proto.ConfirmTOTPEnrollmentResponse{ApiResponse: mfaErrorResponse("", mfaUsecase.ErrMFANotConfigured)}, nil
+	}
+	if req.Code == "" {
+		return &proto.ConfirmTOTPEnrollmentResponse{ApiResponse: newAPIResponse(false, "code is required", http.StatusBadRequest)}, nil
+	}
+
+	identity, err := s.verifyAccessToken(ctx, req.AccessToken)
+	if err != nil {
+		return &proto.ConfirmTOTPEnrollmentResponse{ApiResponse: newAPIResponse(false, "invalid access token", http.StatusUnauthorized)}, nil
+	}
+
+	codes, err := s.totp.ConfirmEnrollment(ctx, identity.UserID, req.Code)
+	if err != nil {
+		return &proto.ConfirmTOTPEnrollmentResponse{ApiResponse: mfaErrorResponse("confirm TOTP enrollment", err)}, nil
+	}
+
+	log.Printf("🔐 TOTP enabled for user %s", identity.UserID)
+	return &proto.ConfirmTOTPEnrollmentResponse{
+		ApiResponse:   newAPIResponse(true, "TOTP enabled, store the recovery codes in a safe place", http.StatusOK),
+		RecoveryCodes: codes,
+	}, nil
+}
+
+// VerifyMFA completes a login with the code of the user's authenticator app
+func (s *AuthServer) VerifyMFA(ctx context.Context, req *proto.VerifyMFARequest) (*proto.LoginResponse, error) {
+	if s.totp == nil {
+		return &proto.LoginResponse{ApiResponse: mfaErrorResponse("", mfaUsecase.ErrMFANotConfigured)}, nil
+	}
+	if req.Code == "" {
+		return &proto.LoginResponse{ApiResponse: newAPIResponse(false, "code is required", http.StatusBadRequest)}, nil
+	}
+
+	identity, err := s.authService.VerifyMFAChallenge(req.MfaChallengeToken)
+	if err != nil {
+		return &proto.LoginResponse{ApiResponse: newAPIResponse(false, "invalid or expired MFA challenge", http.StatusUnauthorized)}, nil
+	}
+
+	verification, err := s.totp.Verify(ctx, identity.UserID, req.Code)
+	if err != nil {
+		if errors.Is(err, mfaUsecase.ErrInvalidCode) || errors.Is(err, mfaUsecase.ErrCodeReused) || errors.Is(err, mfaUsecase.ErrTooManyAttempts) {
+			methods := append(append([]string{}, identity.Authentication.Methods...), token.MethodOTP)
+			s.recordLogin(ctx, loginHistoryModel.LoginAttempt{UserID: identity.UserID, Email: identity.UserName, Methods: methods, Outcome: loginHistoryModel.OutcomeFailure})
+		}
+		return &proto.LoginResponse{ApiResponse: mfaErrorResponse("verify MFA code", err)}, nil
+	}
+
+	authn := identity.Authentication.WithSecondFactor(token.MethodOTP, time.Now())
+	resp := s.completeLogin(ctx, identity.UserName, identity.UserID, authn)
+	if verification.RecoveryCodeUsed && resp.ApiResponse.Success {
+		resp.RecoveryCodeUsed = true
+		resp.RecoveryCodesRemaining = int32(verification.RecoveryCodesRemaining)
+		resp.ApiResponse.Message = fmt.Sprintf("recovery code accepted, %d remaining", verification.RecoveryCodesRemaining)
+	}
+	return resp, nil
+}
+
+// RegenerateRecoveryCodes replaces the caller's recovery codes after verifying a second factor code
+func (s *AuthServer) RegenerateRecoveryCodes(ctx context.Context, req *proto.RegenerateRecoveryCodesRequest) (*proto.RegenerateRecoveryCodesResponse, error) {
+	if s.totp == nil {
+		return &proto.RegenerateRecoveryCodesResponse{ApiResponse: mfaErrorResponse("", mfaUsecase.ErrMFANotConfigured)}, nil
+	}
+	if req.Code == "" {
+		return &proto.RegenerateRecoveryCodesResponse{ApiResponse: newAPIResponse(false, "code is required", http.StatusBadRequest)}, nil
+	}
+
+	identity, err := s.verifyAccessToken(ctx, req.AccessToken)
+	if err != nil {
+		return &proto.RegenerateRecoveryCodesResponse{ApiResponse: newAPIResponse(false, "invalid access token", http.StatusUnauthorized)}, nil
+	}
+
+	codes, err := s.totp.RegenerateRecoveryCodes(ctx, identity.UserID, req.Code)
+	if err != nil {
+		return &proto.RegenerateRecoveryCodesResponse{ApiResponse: mfaErrorResponse("regenerate recovery codes", err)}, nil
+	}
+
+	log.Printf("🔐 Recovery codes regenerated for user %s", identity.UserID)
+	return &proto.RegenerateRecoveryCodesResponse{
+		ApiResponse:   newAPIResponse(true, "recovery codes regenerated, previous codes are no longer valid", http.StatusOK),
+		RecoveryCodes: codes,
+	}, nil
+}
@@ -0,0 +1,284 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"hub-user-service/internal/auth"
+	"hub-user-service/internal/events"
+	"hub-user-service/internal/grpc/proto"
+	"hub-user-service/internal/login/application/usecase"
+	mfaUsecase "hub-user-service/internal/mfa/application/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockTOTPUsecase mocks the TOTP use case
+type MockTOTPUsecase struct {
+	mock.Mock
+}
+
+func (m *MockTOTPUsecase) BeginEnrollment(ctx context.Context, userID string, accountName string) (*mfaUsecase.Enrollment, error) {
+	args := m.Called(ctx, userID, accountName)
+	enrollment, _ := args.Get(0).(*mfaUsecase.Enrollment)
+	return enrollment, args.Error(1)
+}
+
+func (m *MockTOTPUsecase) ConfirmEnrollment(ctx context.Context, userID string, code string) ([]string, error) {
+	args := m.Called(ctx, userID, code)
+	codes, _ := args.Get(0).([]string)
+	return codes, args.Error(1)
+}
+
+func (m *MockTOTPUsecase) Verify(ctx context.Context, userID string, code string) (*mfaUsecase.Verification, error) {
+	args := m.Called(ctx, userID, code)
+	verification, _ := args.Get(0).(*mfaUsecase.Verification)
+	return verification, args.Error(1)
+}
+
+func (m *MockTOTPUsecase) RegenerateRecoveryCodes(ctx context.Context, userID string, code string) ([]string, error) {
+	args := m.Called(ctx, userID, code)
+	codes, _ := args.Get(0).([]string)
+	return codes, args.Error(1)
+}
+
+func (m *MockTOTPUsecase) IsEnabled(ctx context.Context, userID string) (bool, error) {
+	args := m.Called(ctx, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func TestAuthServer_Login_MFAEnabledReturnsChallenge(t *testing.T) {
+	mockLoginUsecase := new(MockLoginUsecase)
+	mockAuthService := new(MockAuthService)
+
+	mockLoginUsecase.On("Login", mock.Anything, "test@example.com", "password123", mock.Anything).Return(createTestUserForGRPC(), usecase.LoginNeedsMFA, nil)
+	mockAuthService.On("CreateMFAChallenge", "test@example.com", "user123", mock.Anything).Return("challenge-token", nil)
+
+	broker := events.NewBroker(10, 10)
+	sub, _ := broker.Subscribe(context.Background(), "", events.Filter{})
+	defer sub.Close()
+
+	server := NewAuthServer(mockLoginUsecase, mockAuthService, WithEventPublisher(broker))
+
+	resp, err := server.Login(context.Background(), &proto.LoginRequest{Email: "test@example.com", Password: "password123"})
+
+	require.NoError(t, err)
+	assert.True(t, resp.ApiResponse.Success)
+	assert.True(t, resp.MfaRequired)
+	assert.Equal(t, "challenge-token", resp.MfaChallengeToken)
+	assert.Empty(t, resp.Token)
+	assert.Equal(t, "user123", resp.UserInfo.UserId)
+	mockAuthService.AssertNotCalled(t, "CreateAuthenticatedToken", mock.Anything, mock.Anything, mock.Anything)
+
+	select {
+	case event := <-sub.Events():
+		t.Fatalf("unexpected event before the second factor: %v", event.Type)
+	default:
+	}
+}
+
+func TestAuthServer_Login_MFACheckFails(t *testing.T) {
+	mockLoginUsecase := new(MockLoginUsecase)
+	mockAuthService := new(MockAuthService)
+
+	mockLoginUsecase.On("Login", mock.Anything, "test@example.com", "password123", mock.Anything).
+		Return(createTestUserForGRPC(), usecase.LoginDecision(""), fmt.Errorf("%w: connection refused", usecase.ErrLoginCheck))
+
+	server := NewAuthServer(mockLoginUsecase, mockAuthService)
+
+	resp, err := server.Login(context.Background(), &proto.LoginRequest{Email: "test@example.com", Password: "password123"})
+
+	require.NoError(t, err)
+	assert.False(t, resp.ApiResponse.Success)
+	assert.Equal(t, int32(http.StatusInternalServerError), resp.ApiResponse.Code)
+	assert.Empty(t, resp.Token)
+}
+
+func TestAuthServer_VerifyMFA(t *testing.T) {
+	identity := &auth.Identity{UserID: "user123", UserName: "test@example.com"}
+
+	t.Run("valid code issues the access token", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		mockTOTP := new(MockTOTPUsecase)
+		mockAuthService.On("VerifyMFAChallenge", "challenge-token").Return(identity, nil)
+		mockAuthService.On("CreateAuthenticatedToken", "test@example.com", "user123", mock.Anything).Return("mock-jwt-token-123", nil)
+		mockTOTP.On("Verify", mock.Anything, "user123", "123456").Return(&mfaUsecase.Verification{}, nil)
+
+		server := NewAuthServer(new(MockLoginUsecase), mockAuthService, WithTOTP(mockTOTP))
+		resp, err := server.VerifyMFA(context.Background(), &proto.VerifyMFARequest{MfaChallengeToken: "challenge-token", Code: "123456"})
+
+		require.NoError(t, err)
+		assert.True(t, resp.ApiResponse.Success)
+		assert.Equal(t, "mock-jwt-token-123", resp.Token)
+		assert.False(t, resp.MfaRequired)
+		assert.False(t, resp.RecoveryCodeUsed)
+	})
+
+	t.Run("recovery code reports the remaining count", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		mockTOTP := new(MockTOTPUsecase)
+		mockAuthService.On("VerifyMFAChallenge", "challenge-token").Return(identity, nil)
+		mockAuthService.On("CreateAuthenticatedToken", "test@example.com", "user123", mock.Anything).Return("mock-jwt-token-123", nil)
+		mockTOTP.On("Verify", mock.Anything, "user123", "abcde-12345").
+			Return(&mfaUsecase.Verification{RecoveryCodeUsed: true, RecoveryCodesRemaining: 3}, nil)
+
+		server := NewAuthServer(new(MockLoginUsecase), mockAuthService, WithTOTP(mockTOTP))
+		resp, err := server.VerifyMFA(context.Background(), &proto.VerifyMFARequest{MfaChallengeToken: "challenge-token", Code: "abcde-12345"})
+
+		require.NoError(t, err)
+		assert.True(t, resp.ApiResponse.Success)
+		assert.Equal(t, "mock-jwt-token-123", resp.Token)
+		assert.True(t, resp.RecoveryCodeUsed)
+		assert.Equal(t, int32(3), resp.RecoveryCodesRemaining)
+		assert.Equal(t, "recovery code accepted, 3 remaining", resp.ApiResponse.Message)
+	})
+
+	t.Run("invalid code", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		mockTOTP := new(MockTOTPUsecase)
+		mockAuthService.On("VerifyMFAChallenge", "challenge-token").Return(identity, nil)
+		mockTOTP.On("Verify", mock.Anything, "user123", "000000").Return(nil, mfaUsecase.ErrInvalidCode)
+
+		server := NewAuthServer(new(MockLoginUsecase), mockAuthService, WithTOTP(mockTOTP))
+		resp, err := server.VerifyMFA(context.Background(), &proto.VerifyMFARequest{MfaChallengeToken: "challenge-token", Code: "000000"})
+
+		require.NoError(t, err)
+		assert.False(t, resp.ApiResponse.Success)
+		assert.Equal(t, int32(http.StatusUnauthorized), resp.ApiResponse.Code)
+		assert.Empty(t, resp.Token)
+	})
+
+	t.Run("too many attempts", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		mockTOTP := new(MockTOTPUsecase)
+		mockAuthService.On("VerifyMFAChallenge", "challenge-token").Return(identity, nil)
+		mockTOTP.On("Verify", mock.Anything, "user123", "000000").Return(nil, mfaUsecase.ErrTooManyAttempts)
+
+		server := NewAuthServer(new(MockLoginUsecase), mockAuthService, WithTOTP(mockTOTP))
+		resp, err := server.VerifyMFA(context.Background(), &proto.VerifyMFARequest{MfaChallengeToken: "challenge-token", Code: "000000"})
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(http.StatusTooManyRequests), resp.ApiResponse.Code)
+	})
+
+	t.Run("invalid challenge", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		mockTOTP := new(MockTOTPUsecase)
+		mockAuthService.On("VerifyMFAChallenge", "forged").Return(nil, errors.New("invalid token"))
+
+		server := NewAuthServer(new(MockLoginUsecase), mockAuthService, WithTOTP(mockTOTP))
+		resp, err := server.VerifyMFA(context.Background(), &proto.VerifyMFARequest{MfaChallengeToken: "forged", Code: "123456"})
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(http.StatusUnauthorized), resp.ApiResponse.Code)
+		mockTOTP.AssertNotCalled(t, "Verify", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("not configured", func(t *testing.T) {
+		server := NewAuthServer(new(MockLoginUsecase), new(MockAuthService))
+		resp, err := server.VerifyMFA(context.Background(), &proto.VerifyMFARequest{MfaChallengeToken: "challenge-token", Code: "123456"})
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(http.StatusServiceUnavailable), resp.ApiResponse.Code)
+	})
+}
+
+func TestAuthServer_BeginTOTPEnrollment(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		mockTOTP := new(MockTOTPUsecase)
+		mockAuthService.On("VerifyAccessToken", "Bearer access").Return(&auth.Identity{UserID: "user123", UserName: "test@example.com"}, nil)
+		mockTOTP.On("BeginEnrollment", mock.Anything, "user123", "test@example.com").
+			Return(&mfaUsecase.Enrollment{URI: "otpauth://totp/x", Secret: "JBSWY3DPEHPK3PXP"}, nil)
+
+		server := NewAuthServer(new(MockLoginUsecase), mockAuthService, WithTOTP(mockTOTP))
+		resp, err := server.BeginTOTPEnrollment(context.Background(), &proto.BeginTOTPEnrollmentRequest{AccessToken: "Bearer access"})
+
+		require.NoError(t, err)
+		assert.True(t, resp.ApiResponse.Success)
+		assert.Equal(t, "otpauth://totp/x", resp.OtpauthUri)
+		assert.Equal(t, "JBSWY3DPEHPK3PXP", resp.Secret)
+	})
+
+	t.Run("invalid access token", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		mockAuthService.On("VerifyAccessToken", "Bearer expired").Return(nil, errors.New("token expired"))
+
+		server := NewAuthServer(new(MockLoginUsecase), mockAuthService, WithTOTP(new(MockTOTPUsecase)))
+		resp, err := server.BeginTOTPEnrollment(context.Background(), &proto.BeginTOTPEnrollmentRequest{AccessToken: "Bearer expired"})
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(http.StatusUnauthorized), resp.ApiResponse.Code)
+		assert.Empty(t, resp.Secret)
+	})
+
+	t.Run("already enrolled", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		mockTOTP := new(MockTOTPUsecase)
+		mockAuthService.On("VerifyAccessToken", "Bearer access").Return(&auth.Identity{UserID: "user123", UserName: "test@example.com"}, nil)
+		mockTOTP.On("BeginEnrollment", mock.Anything, "user123", "test@example.com").Return(nil, mfaUsecase.ErrAlreadyEnrolled)
+
+		server := NewAuthServer(new(MockLoginUsecase), mockAuthService, WithTOTP(mockTOTP))
+		resp, err := server.BeginTOTPEnrollment(context.Background(), &proto.BeginTOTPEnrollmentRequest{AccessToken: "Bearer access"})
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(http.StatusConflict), resp.ApiResponse.Code)
+	})
+}
+
+func TestAuthServer_ConfirmTOTPEnrollment(t *testing.T) {
+	mockAuthService := new(MockAuthService)
+	mockTOTP := new(MockTOTPUsecase)
+	mockAuthService.On("VerifyAccessToken", "Bearer access").Return(&auth.Identity{UserID: "user123", UserName: "test@example.com"}, nil)
+	mockTOTP.On("ConfirmEnrollment", mock.Anything, "user123", "123456").Return([]string{"abcde-12345"}, nil)
+	mockTOTP.On("ConfirmEnrollment", mock.Anything, "user123", "000000").Return(nil, mfaUsecase.ErrInvalidCode)
+
+	server := NewAuthServer(new(MockLoginUsecase), mockAuthService, WithTOTP(mockTOTP))
+
+	resp, err := server.ConfirmTOTPEnrollment(context.Background(), &proto.ConfirmTOTPEnrollmentRequest{AccessToken: "Bearer access", Code: "123456"})
+	require.NoError(t, err)
+	assert.True(t, resp.ApiResponse.Success)
+	assert.Equal(t, []string{"abcde-12345"}, resp.RecoveryCodes)
+
+	resp, err = server.ConfirmTOTPEnrollment(context.Background(), &proto.ConfirmTOTPEnrollmentRequest{AccessToken: "Bearer access", Code: "000000"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(http.StatusUnauthorized), resp.ApiResponse.Code)
+
+	resp, err = server.ConfirmTOTPEnrollment(context.Background(), &proto.ConfirmTOTPEnrollmentRequest{AccessToken: "Bearer access"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(http.StatusBadRequest), resp.ApiResponse.Code)
+}
+
+func TestAuthServer_RegenerateRecoveryCodes(t *testing.T) {
+	mockAuthService := new(MockAuthService)
+	mockTOTP := new(MockTOTPUsecase)
+	mockAuthService.On("VerifyAccessToken", "Bearer access").Return(&auth.Identity{UserID: "user123", UserName: "test@example.com"}, nil)
+	mockAuthService.On("VerifyAccessToken", "Bearer expired").Return(nil, errors.New("token expired"))
+	mockTOTP.On("RegenerateRecoveryCodes", mock.Anything, "user123", "123456").Return([]string{"abcde-12345", "fghjk-67890"}, nil)
+	mockTOTP.On("RegenerateRecoveryCodes", mock.Anything, "user123", "000000").Return(nil, mfaUsecase.ErrInvalidCode)
+
+	server := NewAuthServer(new(MockLoginUsecase), mockAuthService, WithTOTP(mockTOTP))
+
+	resp, err := server.RegenerateRecoveryCodes(context.Background(), &proto.RegenerateRecoveryCodesRequest{AccessToken: "Bearer access", Code: "123456"})
+	require.NoError(t, err)
+	assert.True(t, resp.ApiResponse.Success)
+	assert.Equal(t, []string{"abcde-12345", "fghjk-67890"}, resp.RecoveryCodes)
+
+	resp, err = server.RegenerateRecoveryCodes(context.Background(), &proto.RegenerateRecoveryCodesRequest{AccessToken: "Bearer access", Code: "000000"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(http.StatusUnauthorized), resp.ApiResponse.Code)
+	assert.Empty(t, resp.RecoveryCodes)
+
+	resp, err = server.RegenerateRecoveryCodes(context.Background(), &proto.RegenerateRecoveryCodesRequest{AccessToken: "Bearer expired", Code: "123456"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(http.StatusUnauthorized), resp.ApiResponse.Code)
+
+	resp, err = server.RegenerateRecoveryCodes(context.Background(), &proto.RegenerateRecoveryCodesRequest{AccessToken: "Bearer access"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(http.StatusBadRequest), resp.ApiResponse.Code)
+}
@@ -0,0 +1,134 @@
+package grpc
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"hub-user-service/internal/auth/token"
+	"hub-user-service/internal/grpc/proto"
+	loginHistoryModel "hub-user-service/internal/loginhistory/domain/model"
+	passkeyUsecase "hub-user-service/internal/passkey/application/usecase"
+)
+
+// errPasskeysDisabled is answered when the server runs without WEBAUTHN_ENABLED
+var errPasskeysDisabled = errors.New("passkeys are not enabled")
+
+// passkeyErrorResponse maps passkey use case errors to the response envelope without leaking internals
+func passkeyErrorResponse(action string, err error) *proto.APIResponse {
+	switch {
+	case errors.Is(err, passkeyUsecase.ErrCeremonyNotFound), errors.Is(err, passkeyUsecase.ErrInvalidResponse):
+		return newAPIResponse(false, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, passkeyUsecase.ErrCloneDetected), errors.Is(err, passkeyUsecase.ErrAccountInactive):
+		return newAPIResponse(false, err.Error(), http.StatusUnauthorized)
+	case errors.Is(err, passkeyUsecase.ErrAttestationNotAllowed):
+		return newAPIResponse(false, err.Error(), http.StatusForbidden)
+	case errors.Is(err, passkeyUsecase.ErrCredentialExists):
+		return newAPIResponse(false, err.Error(), http.StatusConflict)
+	case errors.Is(err, errPasskeysDisabled):
+		return newAPIResponse(false, err.Error(), http.StatusServiceUnavailable)
+	default:
+		log.Printf("Failed to %s: %v", action, err)
+		return newAPIResponse(false, "failed to "+action, http.StatusInternalServerError)
+	}
+}
+
+// BeginPasskeyRegistration starts registering a passkey for the caller
+func (s *AuthServer) BeginPasskeyRegistration(ctx context.Context, req *proto.BeginPasskeyRegistrationRequest) (*proto.BeginPasskeyRegistrationResponse, error) {
+	if s.passkeys == nil {
+		return &proto.BeginPasskeyRegistrationResponse{ApiResponse: passkeyErrorResponse("", errPasskeysDisabled)}, nil
+	}
+
+	identity, err := s.verifyAccessToken(ctx, req.AccessToken)
+	if err != nil {
+		return &proto.BeginPasskeyRegistrationResponse{ApiResponse: newAPIResponse(false, "invalid access token", http.StatusUnauthorized)}, nil
+	}
+
+	options, err := s.passkeys.BeginRegistration(ctx, identity.UserID, identity.UserName)
+	if err != nil {
+		return &proto.BeginPasskeyRegistrationResponse{ApiResponse: passkeyErrorResponse("begin passkey registration", err)}, nil
+	}
+
+	return &proto.BeginPasskeyRegistrationResponse{
+		ApiResponse: newAPIResponse(true, "create the credential with the returned options", http.StatusOK),
+		CeremonyId:  options.CeremonyID,
+		OptionsJson: string(options.Options),
+	}, nil
+}
+
+// FinishPasskeyRegistration stores the passkey created by the caller's authenticator
+func (s *AuthServer) FinishPasskeyRegistration(ctx context.Context, req *proto.FinishPasskeyRegistrationRequest) (*proto.FinishPasskeyRegistrationResponse, error) {
+	if s.passkeys == nil {
+		return &proto.FinishPasskeyRegistrationResponse{ApiResponse: passkeyErrorResponse("", errPasskeysDisabled)}, nil
+	}
+	if req.CeremonyId == "" || req.CredentialJson == "" {
+		return &proto.FinishPasskeyRegistrationResponse{ApiResponse: newAPIResponse(false, "ceremony_id and credential_json are required", http.StatusBadRequest)}, nil
+	}
+
+	identity, err := s.verifyAccessToken(ctx, req.AccessToken)
+	if err != nil {
+		return &proto.FinishPasskeyRegistrationResponse{ApiResponse: newAPIResponse(false, "invalid access token", http.StatusUnauthorized)}, nil
+	}
+
+	credential, err := s.passkeys.FinishRegistration(ctx, identity.UserID, req.CeremonyId, []byte(req.CredentialJson), req.Name)
+	if err != nil {
+		return &proto.FinishPasskeyRegistrationResponse{ApiResponse: passkeyErrorResponse("finish passkey registration", err)}, nil
+	}
+
+	log.Printf("🔑 Passkey registered for user %s", identity.UserID)
+	return &proto.FinishPasskeyRegistrationResponse{
+		ApiResponse:  newAPIResponse(true, "passkey registered", http.StatusOK),
+		CredentialId: base64.RawURLEncoding.EncodeToString(credential.ID),
+	}, nil
+}
+
+// BeginPasskeyLogin starts a passwordless login
+func (s *AuthServer) BeginPasskeyLogin(ctx context.Context, req *proto.BeginPasskeyLoginRequest) (*proto.BeginPasskeyLoginResponse, error) {
+	if s.passkeys == nil {
+		return &proto.BeginPasskeyLoginResponse{ApiResponse: passkeyErrorResponse("", errPasskeysDisabled)}, nil
+	}
+
+	options, err := s.passkeys.BeginLogin(ctx)
+	if err != nil {
+		return &proto.BeginPasskeyLoginResponse{ApiResponse: passkeyErrorResponse("begin passkey login", err)}, nil
+	}
+
+	return &proto.BeginPasskeyLoginResponse{
+		ApiResponse: newAPIResponse(true, "sign the challenge with a passkey", http.StatusOK),
+		CeremonyId:  options.CeremonyID,
+		OptionsJson: string(options.Options),
+	}, nil
+}
+
+// FinishPasskeyLogin verifies the assertion and issues the same token as Login
+func (s *AuthServer) FinishPasskeyLogin(ctx context.Context, req *proto.FinishPasskeyLoginRequest) (*proto.LoginResponse, error) {
+	if s.passkeys == nil {
+		return &proto.LoginResponse{ApiResponse: passkeyErrorResponse("", errPasskeysDisabled)}, nil
+	}
+	if req.CeremonyId == "" || req.CredentialJson == "" {
+		return &proto.LoginResponse{ApiResponse: newAPIResponse(false, "ceremony_id and credential_json are required", http.StatusBadRequest)}, nil
+	}
+
+	result, err := s.passkeys.FinishLogin(ctx, req.CeremonyId, []byte(req.CredentialJson))
+	if err != nil {
+		if errors.Is(err, passkeyUsecase.ErrInvalidResponse) || errors.Is(err, passkeyUsecase.ErrCloneDetected) || errors.Is(err, passkeyUsecase.ErrAccountInactive) {
+			s.recordLogin(ctx, loginHistoryModel.LoginAttempt{Methods: []string{token.MethodHardwareKey}, Outcome: loginHistoryModel.OutcomeFailure})
+		}
+		return &proto.LoginResponse{ApiResponse: passkeyErrorResponse("finish passkey login", err)}, nil
+	}
+	user := result.User
+
+	// A passkey without user verification only proves possession, enrolled users still need their second factor
+	return s.authenticate(ctx, user.GetEmailString(), user.ID, passkeyAuthentication(result.UserVerified, time.Now())), nil
+}
+
+// passkeyAuthentication describes a passkey login; user verification (PIN or biometrics) makes it multi-factor
+func passkeyAuthentication(userVerified bool, at time.Time) token.Authentication {
+	if userVerified {
+		return token.Authentication{Methods: []string{token.MethodHardwareKey, token.MethodMultiFactor}, Level: token.LevelMultiFactor, Time: at}
+	}
+	return token.Authentication{Methods: []string{token.MethodHardwareKey}, Level: token.LevelSingleFactor, Time: at}
+}
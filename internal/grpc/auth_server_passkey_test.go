@@ -0,0 +1,184 @@
+package grpc
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"hub-user-service/internal/auth"
+	"hub-user-service/internal/grpc/proto"
+	passkeyUsecase "hub-user-service/internal/passkey/application/usecase"
+	"hub-user-service/internal/passkey/domain/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockPasskeyUsecase mocks the passkey use case
+type MockPasskeyUsecase struct {
+	mock.Mock
+}
+
+func (m *MockPasskeyUsecase) BeginRegistration(ctx context.Context, userID string, userName string) (*passkeyUsecase.CeremonyOptions, error) {
+	args := m.Called(ctx, userID, userName)
+	options, _ := args.Get(0).(*passkeyUsecase.CeremonyOptions)
+	return options, args.Error(1)
+}
+
+func (m *MockPasskeyUsecase) FinishRegistration(ctx context.Context, userID string, ceremonyID string, response []byte, name string) (*model.Credential, error) {
+	args := m.Called(ctx, userID, ceremonyID, string(response), name)
+	credential, _ := args.Get(0).(*model.Credential)
+	return credential, args.Error(1)
+}
+
+func (m *MockPasskeyUsecase) BeginLogin(ctx context.Context) (*passkeyUsecase.CeremonyOptions, error) {
+	args := m.Called(ctx)
+	options, _ := args.Get(0).(*passkeyUsecase.CeremonyOptions)
+	return options, args.Error(1)
+}
+
+func (m *MockPasskeyUsecase) FinishLogin(ctx context.Context, ceremonyID string, response []byte) (*passkeyUsecase.LoginResult, error) {
+	args := m.Called(ctx, ceremonyID, string(response))
+	result, _ := args.Get(0).(*passkeyUsecase.LoginResult)
+	return result, args.Error(1)
+}
+
+func TestAuthServer_BeginPasskeyRegistration(t *testing.T) {
+	mockAuthService := new(MockAuthService)
+	mockPasskeys := new(MockPasskeyUsecase)
+	mockAuthService.On("VerifyAccessToken", "Bearer access").Return(&auth.Identity{UserID: "user123", UserName: "test@example.com"}, nil)
+	mockPasskeys.On("BeginRegistration", mock.Anything, "user123", "test@example.com").
+		Return(&passkeyUsecase.CeremonyOptions{CeremonyID: "c1", Options: []byte(`{"publicKey":{}}`)}, nil)
+
+	server := NewAuthServer(new(MockLoginUsecase), mockAuthService, WithPasskeys(mockPasskeys))
+	resp, err := server.BeginPasskeyRegistration(context.Background(), &proto.BeginPasskeyRegistrationRequest{AccessToken: "Bearer access"})
+
+	require.NoError(t, err)
+	assert.True(t, resp.ApiResponse.Success)
+	assert.Equal(t, "c1", resp.CeremonyId)
+	assert.Equal(t, `{"publicKey":{}}`, resp.OptionsJson)
+}
+
+func TestAuthServer_FinishPasskeyRegistration(t *testing.T) {
+	identity := &auth.Identity{UserID: "user123", UserName: "test@example.com"}
+
+	t.Run("success", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		mockPasskeys := new(MockPasskeyUsecase)
+		mockAuthService.On("VerifyAccessToken", "Bearer access").Return(identity, nil)
+		mockPasskeys.On("FinishRegistration", mock.Anything, "user123", "c1", "{}", "Laptop").
+			Return(&model.Credential{ID: []byte{0xfb, 0xff}}, nil)
+
+		server := NewAuthServer(new(MockLoginUsecase), mockAuthService, WithPasskeys(mockPasskeys))
+		resp, err := server.FinishPasskeyRegistration(context.Background(), &proto.FinishPasskeyRegistrationRequest{
+			AccessToken: "Bearer access", CeremonyId: "c1", CredentialJson: "{}", Name: "Laptop",
+		})
+
+		require.NoError(t, err)
+		assert.True(t, resp.ApiResponse.Success)
+		assert.Equal(t, "-_8", resp.CredentialId)
+	})
+
+	t.Run("attestation rejected by policy", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		mockPasskeys := new(MockPasskeyUsecase)
+		mockAuthService.On("VerifyAccessToken", "Bearer access").Return(identity, nil)
+		mockPasskeys.On("FinishRegistration", mock.Anything, "user123", "c1", "{}", "").
+			Return(nil, passkeyUsecase.ErrAttestationNotAllowed)
+
+		server := NewAuthServer(new(MockLoginUsecase), mockAuthService, WithPasskeys(mockPasskeys))
+		resp, err := server.FinishPasskeyRegistration(context.Background(), &proto.FinishPasskeyRegistrationRequest{
+			AccessToken: "Bearer access", CeremonyId: "c1", CredentialJson: "{}",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(http.StatusForbidden), resp.ApiResponse.Code)
+	})
+
+	t.Run("invalid access token", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		mockPasskeys := new(MockPasskeyUsecase)
+		mockAuthService.On("VerifyAccessToken", "forged").Return(nil, assert.AnError)
+
+		server := NewAuthServer(new(MockLoginUsecase), mockAuthService, WithPasskeys(mockPasskeys))
+		resp, err := server.FinishPasskeyRegistration(context.Background(), &proto.FinishPasskeyRegistrationRequest{
+			AccessToken: "forged", CeremonyId: "c1", CredentialJson: "{}",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(http.StatusUnauthorized), resp.ApiResponse.Code)
+		mockPasskeys.AssertNotCalled(t, "FinishRegistration", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestAuthServer_FinishPasskeyLogin(t *testing.T) {
+	t.Run("verified passkey issues the access token", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		mockPasskeys := new(MockPasskeyUsecase)
+		mockTOTP := new(MockTOTPUsecase)
+		mockPasskeys.On("FinishLogin", mock.Anything, "c1", "{}").
+			Return(&passkeyUsecase.LoginResult{User: createTestUserForGRPC(), UserVerified: true}, nil)
+		mockAuthService.On("CreateAuthenticatedToken", "test@example.com", "user123", mock.Anything).Return("mock-jwt-token-123", nil)
+
+		server := NewAuthServer(new(MockLoginUsecase), mockAuthService, WithPasskeys(mockPasskeys), WithTOTP(mockTOTP))
+		resp, err := server.FinishPasskeyLogin(context.Background(), &proto.FinishPasskeyLoginRequest{CeremonyId: "c1", CredentialJson: "{}"})
+
+		require.NoError(t, err)
+		assert.True(t, resp.ApiResponse.Success)
+		assert.Equal(t, "mock-jwt-token-123", resp.Token)
+		assert.Equal(t, "test@example.com", resp.UserInfo.Email)
+		mockTOTP.AssertNotCalled(t, "IsEnabled", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unverified passkey requires the second factor", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		mockPasskeys := new(MockPasskeyUsecase)
+		mockTOTP := new(MockTOTPUsecase)
+		mockPasskeys.On("FinishLogin", mock.Anything, "c1", "{}").
+			Return(&passkeyUsecase.LoginResult{User: createTestUserForGRPC()}, nil)
+		mockTOTP.On("IsEnabled", mock.Anything, "user123").Return(true, nil)
+		mockAuthService.On("CreateMFAChallenge", "test@example.com", "user123", mock.Anything).Return("challenge-token", nil)
+
+		server := NewAuthServer(new(MockLoginUsecase), mockAuthService, WithPasskeys(mockPasskeys), WithTOTP(mockTOTP))
+		resp, err := server.FinishPasskeyLogin(context.Background(), &proto.FinishPasskeyLoginRequest{CeremonyId: "c1", CredentialJson: "{}"})
+
+		require.NoError(t, err)
+		assert.True(t, resp.MfaRequired)
+		assert.Equal(t, "challenge-token", resp.MfaChallengeToken)
+		assert.Empty(t, resp.Token)
+	})
+
+	t.Run("suspected clone", func(t *testing.T) {
+		mockPasskeys := new(MockPasskeyUsecase)
+		mockPasskeys.On("FinishLogin", mock.Anything, "c1", "{}").Return(nil, passkeyUsecase.ErrCloneDetected)
+
+		server := NewAuthServer(new(MockLoginUsecase), new(MockAuthService), WithPasskeys(mockPasskeys))
+		resp, err := server.FinishPasskeyLogin(context.Background(), &proto.FinishPasskeyLoginRequest{CeremonyId: "c1", CredentialJson: "{}"})
+
+		require.NoError(t, err)
+		assert.False(t, resp.ApiResponse.Success)
+		assert.Equal(t, int32(http.StatusUnauthorized), resp.ApiResponse.Code)
+		assert.Empty(t, resp.Token)
+	})
+
+	t.Run("missing fields", func(t *testing.T) {
+		server := NewAuthServer(new(MockLoginUsecase), new(MockAuthService), WithPasskeys(new(MockPasskeyUsecase)))
+		resp, err := server.FinishPasskeyLogin(context.Background(), &proto.FinishPasskeyLoginRequest{CeremonyId: "c1"})
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(http.StatusBadRequest), resp.ApiResponse.Code)
+	})
+}
+
+func TestAuthServer_PasskeysDisabled(t *testing.T) {
+	server := NewAuthServer(new(MockLoginUsecase), new(MockAuthService))
+
+	begin, err := server.BeginPasskeyLogin(context.Background(), &proto.BeginPasskeyLoginRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(http.StatusServiceUnavailable), begin.ApiResponse.Code)
+
+	finish, err := server.FinishPasskeyLogin(context.Background(), &proto.FinishPasskeyLoginRequest{CeremonyId: "c1", CredentialJson: "{}"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(http.StatusServiceUnavailable), finish.ApiResponse.Code)
+}
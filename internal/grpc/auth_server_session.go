@@ -0,0 +1,184 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"hub-user-service/internal/auth"
+	"hub-user-service/internal/events"
+	"hub-user-service/internal/grpc/clientinfo"
+	"hub-user-service/internal/grpc/proto"
+	sessionUsecase "hub-user-service/internal/session/application/usecase"
+	"hub-user-service/internal/session/domain/model"
+)
+
+// errSessionsDisabled is answered when the server runs without session tracking
+var errSessionsDisabled = errors.New("session management is not enabled")
+
+// sessionErrorResponse maps session use case errors to the response envelope without leaking internals
+func sessionErrorResponse(action string, err error) *proto.APIResponse {
+	switch {
+	case errors.Is(err, sessionUsecase.ErrSessionRevoked), errors.Is(err, sessionUsecase.ErrSessionExpired):
+		return newAPIResponse(false, err.Error(), http.StatusUnauthorized)
+	case errors.Is(err, sessionUsecase.ErrSessionNotFound):
+		return newAPIResponse(false, err.Error(), http.StatusNotFound)
+	case errors.Is(err, errSessionsDisabled):
+		return newAPIResponse(false, err.Error(), http.StatusServiceUnavailable)
+	default:
+		log.Printf("Failed to %s: %v", action, err)
+		return newAPIResponse(false, "failed to "+action, http.StatusInternalServerError)
+	}
+}
+
+// verifyAccessToken verifies an access token and rejects tokens of revoked sessions
+func (s *AuthServer) verifyAccessToken(ctx context.Context, accessToken string) (*auth.Identity, error) {
+	identity, err := s.authService.VerifyAccessToken(accessToken)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkSession(ctx, identity); err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
+
+// checkSession fails for tokens of revoked or timed out sessions; tokens without a session (issued by the
+// monolith or before session tracking) are accepted until they expire
+func (s *AuthServer) checkSession(ctx context.Context, identity *auth.Identity) error {
+	sessionID := identity.Authentication.SessionID
+	if s.sessions == nil || sessionID == "" {
+		return nil
+	}
+
+	err := s.sessions.Check(ctx, identity.UserID, sessionID)
+	if err != nil && !errors.Is(err, sessionUsecase.ErrSessionRevoked) && !errors.Is(err, sessionUsecase.ErrSessionExpired) {
+		log.Printf("Failed to check session %s of user %s: %v", sessionID, identity.UserID, err)
+	}
+	return err
+}
+
+// clientInfo describes the end client of the current call, believing the client type forwarded
+// by the caller only when the client trust holds
+func (s *AuthServer) clientInfo(ctx context.Context) model.ClientInfo {
+	return clientinfo.Resolve(ctx, s.clientTrust)
+}
+
+// ListSessions returns the caller's active sessions
+func (s *AuthServer) ListSessions(ctx context.Context, req *proto.ListSessionsRequest) (*proto.ListSessionsResponse, error) {
+	if s.sessions == nil {
+		return &proto.ListSessionsResponse{ApiResponse: sessionErrorResponse("", errSessionsDisabled)}, nil
+	}
+
+	identity, err := s.verifyAccessToken(ctx, req.AccessToken)
+	if err != nil {
+		return &proto.ListSessionsResponse{ApiResponse: newAPIResponse(false, "invalid access token", http.StatusUnauthorized)}, nil
+	}
+
+	sessions, err := s.sessions.List(ctx, identity.UserID)
+	if err != nil {
+		return &proto.ListSessionsResponse{ApiResponse: sessionErrorResponse("list sessions", err)}, nil
+	}
+
+	resp := &proto.ListSessionsResponse{ApiResponse: newAPIResponse(true, "sessions retrieved", http.StatusOK)}
+	for _, session := range sessions {
+		resp.Sessions = append(resp.Sessions, &proto.Session{
+			SessionId:  session.ID,
+			Device:     session.Device,
+			IpAddress:  session.IPAddress,
+			UserAgent:  session.UserAgent,
+			CreatedAt:  session.CreatedAt.Unix(),
+			LastSeenAt: session.LastSeenAt.Unix(),
+			Current:    session.ID == identity.Authentication.SessionID,
+			ClientType: string(session.ClientType),
+		})
+	}
+	return resp, nil
+}
+
+// RevokeSession ends one of the caller's sessions, including the current one (logout)
+func (s *AuthServer) RevokeSession(ctx context.Context, req *proto.RevokeSessionRequest) (*proto.RevokeSessionResponse, error) {
+	if s.sessions == nil {
+		return &proto.RevokeSessionResponse{ApiResponse: sessionErrorResponse("", errSessionsDisabled)}, nil
+	}
+	if req.SessionId == "" {
+		return &proto.RevokeSessionResponse{ApiResponse: newAPIResponse(false, "session_id is required", http.StatusBadRequest)}, nil
+	}
+
+	identity, err := s.verifyAccessToken(ctx, req.AccessToken)
+	if err != nil {
+		return &proto.RevokeSessionResponse{ApiResponse: newAPIResponse(false, "invalid access token", http.StatusUnauthorized)}, nil
+	}
+
+	if err := s.sessions.Revoke(ctx, identity.UserID, req.SessionId); err != nil {
+		return &proto.RevokeSessionResponse{ApiResponse: sessionErrorResponse("revoke session", err)}, nil
+	}
+
+	s.publishLogouts(ctx, identity.UserID, []string{req.SessionId})
+	return &proto.RevokeSessionResponse{ApiResponse: newAPIResponse(true, "session revoked", http.StatusOK)}, nil
+}
+
+// RevokeAllOtherSessions ends every session of the caller except the current one
+func (s *AuthServer) RevokeAllOtherSessions(ctx context.Context, req *proto.RevokeAllOtherSessionsRequest) (*proto.RevokeAllOtherSessionsResponse, error) {
+	if s.sessions == nil {
+		return &proto.RevokeAllOtherSessionsResponse{ApiResponse: sessionErrorResponse("", errSessionsDisabled)}, nil
+	}
+
+	identity, err := s.verifyAccessToken(ctx, req.AccessToken)
+	if err != nil {
+		return &proto.RevokeAllOtherSessionsResponse{ApiResponse: newAPIResponse(false, "invalid access token", http.StatusUnauthorized)}, nil
+	}
+
+	revoked, err := s.sessions.RevokeOthers(ctx, identity.UserID, identity.Authentication.SessionID)
+	if err != nil {
+		return &proto.RevokeAllOtherSessionsResponse{ApiResponse: sessionErrorResponse("revoke sessions", err)}, nil
+	}
+
+	s.publishLogouts(ctx, identity.UserID, revoked)
+	return &proto.RevokeAllOtherSessionsResponse{
+		ApiResponse:  newAPIResponse(true, "other sessions revoked", http.StatusOK),
+		RevokedCount: int32(len(revoked)),
+	}, nil
+}
+
+// RefreshToken issues a new access token for the session of a possibly expired one, extending
+// the session's idle timeout; the session's absolute timeout still applies
+func (s *AuthServer) RefreshToken(ctx context.Context, req *proto.RefreshTokenRequest) (*proto.RefreshTokenResponse, error) {
+	if s.sessions == nil {
+		return &proto.RefreshTokenResponse{ApiResponse: sessionErrorResponse("", errSessionsDisabled)}, nil
+	}
+
+	identity, err := s.authService.VerifyRefreshableToken(req.AccessToken)
+	if err != nil {
+		return &proto.RefreshTokenResponse{ApiResponse: newAPIResponse(false, "invalid access token", http.StatusUnauthorized)}, nil
+	}
+
+	authn := identity.Authentication
+	if err := s.sessions.Refresh(ctx, identity.UserID, authn.SessionID, identity.ExpiresAt); err != nil {
+		return &proto.RefreshTokenResponse{ApiResponse: sessionErrorResponse("refresh session", err)}, nil
+	}
+
+	// The new token keeps auth_time, so refreshing does not satisfy max_age requirements
+	refreshed, err := s.authService.CreateAuthenticatedToken(identity.UserName, identity.UserID, authn)
+	if err != nil {
+		log.Printf("Failed to create refreshed token for user %s: %v", identity.UserID, err)
+		return &proto.RefreshTokenResponse{ApiResponse: newAPIResponse(false, "failed to create token", http.StatusInternalServerError)}, nil
+	}
+
+	return &proto.RefreshTokenResponse{
+		ApiResponse: newAPIResponse(true, "token refreshed", http.StatusOK),
+		Token:       refreshed,
+	}, nil
+}
+
+// publishLogouts publishes a logout event per revoked session
+func (s *AuthServer) publishLogouts(ctx context.Context, userID string, sessionIDs []string) {
+	for _, sessionID := range sessionIDs {
+		log.Printf("🚪 Session %s of user %s revoked", sessionID, userID)
+		event := events.Event{Type: events.EventLogout, UserID: userID, Attributes: map[string]string{"session_id": sessionID}}
+		if err := s.eventPublisher.Publish(ctx, event); err != nil {
+			log.Printf("Failed to publish logout event for user %s: %v", userID, err)
+		}
+	}
+}
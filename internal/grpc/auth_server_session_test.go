@@ -0,0 +1,397 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"hub-user-service/internal/auth"
+	"hub-user-service/internal/auth/token"
+	"hub-user-service/internal/events"
+	"hub-user-service/internal/grpc/clientinfo"
+	"hub-user-service/internal/grpc/proto"
+	"hub-user-service/internal/login/application/usecase"
+	sessionUsecase "hub-user-service/internal/session/application/usecase"
+	"hub-user-service/internal/session/domain/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// MockSessionUsecase mocks the session use case
+type MockSessionUsecase struct {
+	mock.Mock
+}
+
+func (m *MockSessionUsecase) Start(ctx context.Context, userID string, client model.ClientInfo) (*model.Session, error) {
+	args := m.Called(ctx, userID, client)
+	session, _ := args.Get(0).(*model.Session)
+	return session, args.Error(1)
+}
+
+func (m *MockSessionUsecase) Check(ctx context.Context, userID string, sessionID string) error {
+	return m.Called(ctx, userID, sessionID).Error(0)
+}
+
+func (m *MockSessionUsecase) Refresh(ctx context.Context, userID string, sessionID string, tokenExpiresAt time.Time) error {
+	return m.Called(ctx, userID, sessionID, tokenExpiresAt).Error(0)
+}
+
+func (m *MockSessionUsecase) List(ctx context.Context, userID string) ([]*model.Session, error) {
+	args := m.Called(ctx, userID)
+	sessions, _ := args.Get(0).([]*model.Session)
+	return sessions, args.Error(1)
+}
+
+func (m *MockSessionUsecase) Revoke(ctx context.Context, userID string, sessionID string) error {
+	return m.Called(ctx, userID, sessionID).Error(0)
+}
+
+func (m *MockSessionUsecase) RevokeOthers(ctx context.Context, userID string, currentID string) ([]string, error) {
+	args := m.Called(ctx, userID, currentID)
+	ids, _ := args.Get(0).([]string)
+	return ids, args.Error(1)
+}
+
+// withSession matches an authentication bound to the given session
+func withSession(sessionID string) interface{} {
+	return mock.MatchedBy(func(authn token.Authentication) bool { return authn.SessionID == sessionID })
+}
+
+// trustGateway trusts the client metadata of every call, as if it came from the gateway
+func trustGateway(context.Context) bool { return true }
+
+// sessionIdentity is the caller of the session RPCs, logged in on session s1
+var sessionIdentity = &auth.Identity{UserID: "user123", UserName: "test@example.com", Authentication: token.Authentication{SessionID: "s1"}}
+
+func TestAuthServer_LoginStartsSession(t *testing.T) {
+	mockLoginUsecase := new(MockLoginUsecase)
+	mockAuthService := new(MockAuthService)
+	mockSessions := new(MockSessionUsecase)
+	mockLoginUsecase.On("Login", mock.Anything, "test@example.com", "password123", mock.Anything).Return(createTestUserForGRPC(), usecase.LoginAllowed, nil)
+	mockSessions.On("Start", mock.Anything, "user123", model.ClientInfo{Type: model.ClientMobile, IPAddress: "203.0.113.7", UserAgent: "Mozilla/5.0", Device: "Ada's laptop"}).
+		Return(&model.Session{ID: "s1"}, nil)
+	mockAuthService.On("CreateAuthenticatedToken", "test@example.com", "user123", withSession("s1")).Return("access", nil)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		clientinfo.ForwardedForMetadataKey, "203.0.113.7, 10.0.0.1",
+		clientinfo.UserAgentMetadataKey, "Mozilla/5.0",
+		clientinfo.DeviceNameMetadataKey, "Ada's laptop",
+		clientinfo.ClientTypeMetadataKey, "mobile",
+	))
+	server := NewAuthServer(mockLoginUsecase, mockAuthService, WithSessions(mockSessions), WithClientTrust(trustGateway))
+	resp, err := server.Login(ctx, &proto.LoginRequest{Email: "test@example.com", Password: "password123"})
+
+	require.NoError(t, err)
+	assert.True(t, resp.ApiResponse.Success)
+	assert.Equal(t, "access", resp.Token)
+	mockSessions.AssertExpectations(t)
+}
+
+func TestAuthServer_LoginFailsWithoutSession(t *testing.T) {
+	mockLoginUsecase := new(MockLoginUsecase)
+	mockAuthService := new(MockAuthService)
+	mockSessions := new(MockSessionUsecase)
+	mockLoginUsecase.On("Login", mock.Anything, "test@example.com", "password123", mock.Anything).Return(createTestUserForGRPC(), usecase.LoginAllowed, nil)
+	mockSessions.On("Start", mock.Anything, "user123", mock.Anything).Return(nil, assert.AnError)
+
+	server := NewAuthServer(mockLoginUsecase, mockAuthService, WithSessions(mockSessions))
+	resp, err := server.Login(context.Background(), &proto.LoginRequest{Email: "test@example.com", Password: "password123"})
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(http.StatusInternalServerError), resp.ApiResponse.Code)
+	assert.Empty(t, resp.Token)
+	mockAuthService.AssertNotCalled(t, "CreateAuthenticatedToken", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthServer_LoginIgnoresClientMetadataOfUntrustedCaller(t *testing.T) {
+	mockLoginUsecase := new(MockLoginUsecase)
+	mockAuthService := new(MockAuthService)
+	mockSessions := new(MockSessionUsecase)
+	mockLoginUsecase.On("Login", mock.Anything, "test@example.com", "password123", mock.Anything).Return(createTestUserForGRPC(), usecase.LoginAllowed, nil)
+	mockSessions.On("Start", mock.Anything, "user123", model.ClientInfo{IPAddress: "198.51.100.4"}).Return(&model.Session{ID: "s1"}, nil)
+	mockAuthService.On("CreateAuthenticatedToken", "test@example.com", "user123", withSession("s1")).Return("access", nil)
+
+	// A mobile client type would grant the longer mobile session timeouts
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("198.51.100.4"), Port: 52100}})
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(clientinfo.ForwardedForMetadataKey, "203.0.113.7", clientinfo.ClientTypeMetadataKey, "mobile"))
+	server := NewAuthServer(mockLoginUsecase, mockAuthService, WithSessions(mockSessions))
+	resp, err := server.Login(ctx, &proto.LoginRequest{Email: "test@example.com", Password: "password123"})
+
+	require.NoError(t, err)
+	assert.True(t, resp.ApiResponse.Success)
+	mockSessions.AssertExpectations(t)
+}
+
+func TestAuthServer_ValidateTokenChecksSession(t *testing.T) {
+	t.Run("active session", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		mockSessions := new(MockSessionUsecase)
+		mockAuthService.On("VerifyAccessToken", "Bearer access").Return(sessionIdentity, nil)
+		mockSessions.On("Check", mock.Anything, "user123", "s1").Return(nil)
+
+		resp, err := NewAuthServer(new(MockLoginUsecase), mockAuthService, WithSessions(mockSessions)).
+			ValidateToken(context.Background(), &proto.ValidateTokenRequest{Token: "Bearer access"})
+
+		require.NoError(t, err)
+		assert.True(t, resp.IsValid)
+	})
+
+	t.Run("revoked session", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		mockSessions := new(MockSessionUsecase)
+		mockAuthService.On("VerifyAccessToken", "Bearer access").Return(sessionIdentity, nil)
+		mockSessions.On("Check", mock.Anything, "user123", "s1").Return(sessionUsecase.ErrSessionRevoked)
+
+		resp, err := NewAuthServer(new(MockLoginUsecase), mockAuthService, WithSessions(mockSessions)).
+			ValidateToken(context.Background(), &proto.ValidateTokenRequest{Token: "Bearer access"})
+
+		require.NoError(t, err)
+		assert.False(t, resp.IsValid)
+		assert.Equal(t, int32(http.StatusUnauthorized), resp.ApiResponse.Code)
+		assert.Equal(t, "session has been revoked", resp.ApiResponse.Message)
+	})
+
+	t.Run("timed out session", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		mockSessions := new(MockSessionUsecase)
+		mockAuthService.On("VerifyAccessToken", "Bearer access").Return(sessionIdentity, nil)
+		mockSessions.On("Check", mock.Anything, "user123", "s1").Return(sessionUsecase.ErrSessionExpired)
+
+		resp, err := NewAuthServer(new(MockLoginUsecase), mockAuthService, WithSessions(mockSessions)).
+			ValidateToken(context.Background(), &proto.ValidateTokenRequest{Token: "Bearer access"})
+
+		require.NoError(t, err)
+		assert.False(t, resp.IsValid)
+		assert.Equal(t, int32(http.StatusUnauthorized), resp.ApiResponse.Code)
+		assert.Equal(t, "session has expired", resp.ApiResponse.Message)
+	})
+
+	t.Run("session store unavailable", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		mockSessions := new(MockSessionUsecase)
+		mockAuthService.On("VerifyAccessToken", "Bearer access").Return(sessionIdentity, nil)
+		mockSessions.On("Check", mock.Anything, "user123", "s1").Return(assert.AnError)
+
+		resp, err := NewAuthServer(new(MockLoginUsecase), mockAuthService, WithSessions(mockSessions)).
+			ValidateToken(context.Background(), &proto.ValidateTokenRequest{Token: "Bearer access"})
+
+		require.NoError(t, err)
+		assert.False(t, resp.IsValid)
+		assert.Equal(t, int32(http.StatusInternalServerError), resp.ApiResponse.Code)
+	})
+
+	t.Run("tokens without session", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		mockSessions := new(MockSessionUsecase)
+		mockAuthService.On("VerifyAccessToken", "Bearer monolith").Return(&auth.Identity{UserID: "user123"}, nil)
+
+		resp, err := NewAuthServer(new(MockLoginUsecase), mockAuthService, WithSessions(mockSessions)).
+			ValidateToken(context.Background(), &proto.ValidateTokenRequest{Token: "Bearer monolith"})
+
+		require.NoError(t, err)
+		assert.True(t, resp.IsValid)
+		mockSessions.AssertNotCalled(t, "Check", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestAuthServer_RevokedSessionCanNotManageAccount(t *testing.T) {
+	mockAuthService := new(MockAuthService)
+	mockSessions := new(MockSessionUsecase)
+	mockTOTP := new(MockTOTPUsecase)
+	mockAuthService.On("VerifyAccessToken", "Bearer access").Return(sessionIdentity, nil)
+	mockSessions.On("Check", mock.Anything, "user123", "s1").Return(sessionUsecase.ErrSessionRevoked)
+
+	server := NewAuthServer(new(MockLoginUsecase), mockAuthService, WithSessions(mockSessions), WithTOTP(mockTOTP))
+	resp, err := server.BeginTOTPEnrollment(context.Background(), &proto.BeginTOTPEnrollmentRequest{AccessToken: "Bearer access"})
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(http.StatusUnauthorized), resp.ApiResponse.Code)
+	mockTOTP.AssertNotCalled(t, "BeginEnrollment", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthServer_StepUpKeepsSession(t *testing.T) {
+	mockLoginUsecase := new(MockLoginUsecase)
+	mockAuthService := new(MockAuthService)
+	mockSessions := new(MockSessionUsecase)
+	mockAuthService.On("VerifyAccessToken", "Bearer access").Return(sessionIdentity, nil)
+	mockSessions.On("Check", mock.Anything, "user123", "s1").Return(nil)
+	mockLoginUsecase.On("Execute", mock.Anything, "test@example.com", "password123").Return(createTestUserForGRPC(), nil)
+	mockAuthService.On("CreateStepUpToken", "test@example.com", "user123", withSession("s1")).Return("elevated", nil)
+
+	server := NewAuthServer(mockLoginUsecase, mockAuthService, WithSessions(mockSessions))
+	resp, err := server.StepUp(context.Background(), &proto.StepUpRequest{AccessToken: "Bearer access", Password: "password123"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "elevated", resp.Token)
+	mockAuthService.AssertExpectations(t)
+}
+
+func TestAuthServer_ListSessions(t *testing.T) {
+	mockAuthService := new(MockAuthService)
+	mockSessions := new(MockSessionUsecase)
+	seen := time.Unix(1800000000, 0)
+	mockAuthService.On("VerifyAccessToken", "Bearer access").Return(sessionIdentity, nil)
+	mockSessions.On("Check", mock.Anything, "user123", "s1").Return(nil)
+	mockSessions.On("List", mock.Anything, "user123").Return([]*model.Session{
+		{ID: "s2", ClientType: model.ClientMobile, Device: "Safari on iOS", IPAddress: "203.0.113.9", CreatedAt: seen.Add(-time.Hour), LastSeenAt: seen},
+		{ID: "s1", Device: "Chrome on macOS", LastSeenAt: seen.Add(-time.Minute)},
+	}, nil)
+
+	server := NewAuthServer(new(MockLoginUsecase), mockAuthService, WithSessions(mockSessions))
+	resp, err := server.ListSessions(context.Background(), &proto.ListSessionsRequest{AccessToken: "Bearer access"})
+
+	require.NoError(t, err)
+	require.True(t, resp.ApiResponse.Success)
+	require.Len(t, resp.Sessions, 2)
+	assert.Equal(t, "s2", resp.Sessions[0].SessionId)
+	assert.Equal(t, "Safari on iOS", resp.Sessions[0].Device)
+	assert.Equal(t, "mobile", resp.Sessions[0].ClientType)
+	assert.Equal(t, "203.0.113.9", resp.Sessions[0].IpAddress)
+	assert.Equal(t, seen.Unix(), resp.Sessions[0].LastSeenAt)
+	assert.Equal(t, seen.Add(-time.Hour).Unix(), resp.Sessions[0].CreatedAt)
+	assert.False(t, resp.Sessions[0].Current)
+	assert.True(t, resp.Sessions[1].Current)
+}
+
+func TestAuthServer_RevokeSession(t *testing.T) {
+	t.Run("publishes a logout event", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		mockSessions := new(MockSessionUsecase)
+		mockAuthService.On("VerifyAccessToken", "Bearer access").Return(sessionIdentity, nil)
+		mockSessions.On("Check", mock.Anything, "user123", "s1").Return(nil)
+		mockSessions.On("Revoke", mock.Anything, "user123", "s2").Return(nil)
+		broker := events.NewBroker(10, 10)
+		sub, _ := broker.Subscribe(context.Background(), "", events.Filter{})
+		defer sub.Close()
+
+		server := NewAuthServer(new(MockLoginUsecase), mockAuthService, WithSessions(mockSessions), WithEventPublisher(broker))
+		resp, err := server.RevokeSession(context.Background(), &proto.RevokeSessionRequest{AccessToken: "Bearer access", SessionId: "s2"})
+
+		require.NoError(t, err)
+		assert.True(t, resp.ApiResponse.Success)
+		event := <-sub.Events()
+		assert.Equal(t, events.EventLogout, event.Type)
+		assert.Equal(t, "user123", event.UserID)
+		assert.Equal(t, "s2", event.Attributes["session_id"])
+	})
+
+	t.Run("unknown session", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		mockSessions := new(MockSessionUsecase)
+		mockAuthService.On("VerifyAccessToken", "Bearer access").Return(sessionIdentity, nil)
+		mockSessions.On("Check", mock.Anything, "user123", "s1").Return(nil)
+		mockSessions.On("Revoke", mock.Anything, "user123", "s9").Return(sessionUsecase.ErrSessionNotFound)
+
+		server := NewAuthServer(new(MockLoginUsecase), mockAuthService, WithSessions(mockSessions))
+		resp, err := server.RevokeSession(context.Background(), &proto.RevokeSessionRequest{AccessToken: "Bearer access", SessionId: "s9"})
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(http.StatusNotFound), resp.ApiResponse.Code)
+	})
+
+	t.Run("missing session id", func(t *testing.T) {
+		server := NewAuthServer(new(MockLoginUsecase), new(MockAuthService), WithSessions(new(MockSessionUsecase)))
+		resp, err := server.RevokeSession(context.Background(), &proto.RevokeSessionRequest{AccessToken: "Bearer access"})
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(http.StatusBadRequest), resp.ApiResponse.Code)
+	})
+}
+
+func TestAuthServer_RevokeAllOtherSessions(t *testing.T) {
+	mockAuthService := new(MockAuthService)
+	mockSessions := new(MockSessionUsecase)
+	mockAuthService.On("VerifyAccessToken", "Bearer access").Return(sessionIdentity, nil)
+	mockSessions.On("Check", mock.Anything, "user123", "s1").Return(nil)
+	mockSessions.On("RevokeOthers", mock.Anything, "user123", "s1").Return([]string{"s2", "s3"}, nil)
+	broker := events.NewBroker(10, 10)
+	sub, _ := broker.Subscribe(context.Background(), "", events.Filter{})
+	defer sub.Close()
+
+	server := NewAuthServer(new(MockLoginUsecase), mockAuthService, WithSessions(mockSessions), WithEventPublisher(broker))
+	resp, err := server.RevokeAllOtherSessions(context.Background(), &proto.RevokeAllOtherSessionsRequest{AccessToken: "Bearer access"})
+
+	require.NoError(t, err)
+	assert.True(t, resp.ApiResponse.Success)
+	assert.Equal(t, int32(2), resp.RevokedCount)
+	assert.Equal(t, "s2", (<-sub.Events()).Attributes["session_id"])
+	assert.Equal(t, "s3", (<-sub.Events()).Attributes["session_id"])
+}
+
+func TestAuthServer_RefreshToken(t *testing.T) {
+	expiredAt := time.Unix(1800000000, 0)
+	expired := &auth.Identity{
+		UserID:         "user123",
+		UserName:       "test@example.com",
+		Authentication: token.Authentication{Methods: []string{"pwd"}, Level: "aal1", Time: expiredAt.Add(-time.Hour), SessionID: "s1"},
+		ExpiresAt:      expiredAt,
+	}
+
+	t.Run("issues a token with the same authentication", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		mockSessions := new(MockSessionUsecase)
+		mockAuthService.On("VerifyRefreshableToken", "Bearer expired").Return(expired, nil)
+		mockSessions.On("Refresh", mock.Anything, "user123", "s1", expiredAt).Return(nil)
+		mockAuthService.On("CreateAuthenticatedToken", "test@example.com", "user123", expired.Authentication).Return("refreshed", nil)
+
+		server := NewAuthServer(new(MockLoginUsecase), mockAuthService, WithSessions(mockSessions))
+		resp, err := server.RefreshToken(context.Background(), &proto.RefreshTokenRequest{AccessToken: "Bearer expired"})
+
+		require.NoError(t, err)
+		assert.True(t, resp.ApiResponse.Success)
+		assert.Equal(t, "refreshed", resp.Token)
+	})
+
+	t.Run("timed out session", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		mockSessions := new(MockSessionUsecase)
+		mockAuthService.On("VerifyRefreshableToken", "Bearer expired").Return(expired, nil)
+		mockSessions.On("Refresh", mock.Anything, "user123", "s1", expiredAt).Return(sessionUsecase.ErrSessionExpired)
+
+		server := NewAuthServer(new(MockLoginUsecase), mockAuthService, WithSessions(mockSessions))
+		resp, err := server.RefreshToken(context.Background(), &proto.RefreshTokenRequest{AccessToken: "Bearer expired"})
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(http.StatusUnauthorized), resp.ApiResponse.Code)
+		assert.Equal(t, "session has expired", resp.ApiResponse.Message)
+		assert.Empty(t, resp.Token)
+		mockAuthService.AssertNotCalled(t, "CreateAuthenticatedToken", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("tokens without session", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		mockAuthService.On("VerifyRefreshableToken", "Bearer monolith").Return(nil, assert.AnError)
+
+		server := NewAuthServer(new(MockLoginUsecase), mockAuthService, WithSessions(new(MockSessionUsecase)))
+		resp, err := server.RefreshToken(context.Background(), &proto.RefreshTokenRequest{AccessToken: "Bearer monolith"})
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(http.StatusUnauthorized), resp.ApiResponse.Code)
+	})
+}
+
+func TestAuthServer_SessionsDisabled(t *testing.T) {
+	server := NewAuthServer(new(MockLoginUsecase), new(MockAuthService))
+
+	list, err := server.ListSessions(context.Background(), &proto.ListSessionsRequest{AccessToken: "Bearer access"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(http.StatusServiceUnavailable), list.ApiResponse.Code)
+
+	revoke, err := server.RevokeSession(context.Background(), &proto.RevokeSessionRequest{AccessToken: "Bearer access", SessionId: "s1"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(http.StatusServiceUnavailable), revoke.ApiResponse.Code)
+
+	others, err := server.RevokeAllOtherSessions(context.Background(), &proto.RevokeAllOtherSessionsRequest{AccessToken: "Bearer access"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(http.StatusServiceUnavailable), others.ApiResponse.Code)
+
+	refresh, err := server.RefreshToken(context.Background(), &proto.RefreshTokenRequest{AccessToken: "Bearer access"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(http.StatusServiceUnavailable), refresh.ApiResponse.Code)
+}
@@ -0,0 +1,63 @@
+package grpc
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"hub-user-service/internal/auth/token"
+	"hub-user-service/internal/grpc/proto"
+)
+
+// StepUp re-verifies the caller and issues a short-lived token for sensitive operations. Users with
+// MFA prove their second factor (the session already proves the first one), others their password.
+func (s *AuthServer) StepUp(ctx context.Context, req *proto.StepUpRequest) (*proto.StepUpResponse, error) {
+	identity, err := s.verifyAccessToken(ctx, req.AccessToken)
+	if err != nil {
+		return &proto.StepUpResponse{ApiResponse: newAPIResponse(false, "invalid access token", http.StatusUnauthorized)}, nil
+	}
+
+	mfaEnabled := false
+	if s.totp != nil {
+		mfaEnabled, err = s.totp.IsEnabled(ctx, identity.UserID)
+		if err != nil {
+			log.Printf("Failed to check MFA for user %s: %v", identity.UserID, err)
+			return &proto.StepUpResponse{ApiResponse: newAPIResponse(false, "failed to check MFA", http.StatusInternalServerError)}, nil
+		}
+	}
+
+	var authn token.Authentication
+	switch {
+	case mfaEnabled && req.Code == "":
+		return &proto.StepUpResponse{ApiResponse: newAPIResponse(false, "code is required", http.StatusBadRequest)}, nil
+	case mfaEnabled:
+		if _, err := s.totp.Verify(ctx, identity.UserID, req.Code); err != nil {
+			return &proto.StepUpResponse{ApiResponse: mfaErrorResponse("verify MFA code", err)}, nil
+		}
+		authn = token.Authentication{Methods: []string{token.MethodOTP}, Level: token.LevelMultiFactor, Time: time.Now()}
+	case req.Password == "":
+		return &proto.StepUpResponse{ApiResponse: newAPIResponse(false, "password is required", http.StatusBadRequest)}, nil
+	default:
+		user, err := s.loginUsecase.Execute(ctx, identity.UserName, req.Password)
+		if err != nil || user.ID != identity.UserID {
+			return &proto.StepUpResponse{ApiResponse: newAPIResponse(false, "invalid credentials", http.StatusUnauthorized)}, nil
+		}
+		authn = token.PasswordAuthentication(time.Now())
+	}
+
+	// The elevated token belongs to the same session, revoking the session revokes it too
+	authn.SessionID = identity.Authentication.SessionID
+	elevated, err := s.authService.CreateStepUpToken(identity.UserName, identity.UserID, authn)
+	if err != nil {
+		log.Printf("Failed to create step-up token for user %s: %v", identity.UserID, err)
+		return &proto.StepUpResponse{ApiResponse: newAPIResponse(false, "failed to create token", http.StatusInternalServerError)}, nil
+	}
+
+	log.Printf("🔐 Step-up authentication (%s) for user %s", authn.Level, identity.UserID)
+	return &proto.StepUpResponse{
+		ApiResponse: newAPIResponse(true, "step-up successful", http.StatusOK),
+		Token:       elevated,
+		Acr:         authn.Level,
+	}, nil
+}
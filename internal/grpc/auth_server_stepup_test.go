@@ -0,0 +1,211 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"hub-user-service/internal/auth"
+	"hub-user-service/internal/auth/token"
+	"hub-user-service/internal/grpc/proto"
+	"hub-user-service/internal/login/application/usecase"
+	mfaUsecase "hub-user-service/internal/mfa/application/usecase"
+	passkeyUsecase "hub-user-service/internal/passkey/application/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// withLevel matches an authentication of the given assurance level and methods
+func withLevel(level string, methods ...string) interface{} {
+	return mock.MatchedBy(func(authn token.Authentication) bool {
+		return authn.Level == level && assert.ObjectsAreEqual(methods, authn.Methods) && time.Since(authn.Time) < time.Minute
+	})
+}
+
+func TestAuthServer_TokensCarryTheAuthentication(t *testing.T) {
+	t.Run("password login", func(t *testing.T) {
+		mockLoginUsecase := new(MockLoginUsecase)
+		mockAuthService := new(MockAuthService)
+		mockLoginUsecase.On("Login", mock.Anything, "test@example.com", "password123", mock.Anything).Return(createTestUserForGRPC(), usecase.LoginAllowed, nil)
+		mockAuthService.On("CreateAuthenticatedToken", "test@example.com", "user123", withLevel(token.LevelSingleFactor, "pwd")).Return("access", nil)
+
+		resp, err := NewAuthServer(mockLoginUsecase, mockAuthService).Login(context.Background(), &proto.LoginRequest{Email: "test@example.com", Password: "password123"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "access", resp.Token)
+		mockAuthService.AssertExpectations(t)
+	})
+
+	t.Run("second factor after a passkey", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		mockTOTP := new(MockTOTPUsecase)
+		challenge := &auth.Identity{UserID: "user123", UserName: "test@example.com", Authentication: token.Authentication{Methods: []string{"hwk"}}}
+		mockAuthService.On("VerifyMFAChallenge", "challenge-token").Return(challenge, nil)
+		mockTOTP.On("Verify", mock.Anything, "user123", "123456").Return(&mfaUsecase.Verification{}, nil)
+		mockAuthService.On("CreateAuthenticatedToken", "test@example.com", "user123", withLevel(token.LevelMultiFactor, "hwk", "otp", "mfa")).Return("access", nil)
+
+		server := NewAuthServer(new(MockLoginUsecase), mockAuthService, WithTOTP(mockTOTP))
+		resp, err := server.VerifyMFA(context.Background(), &proto.VerifyMFARequest{MfaChallengeToken: "challenge-token", Code: "123456"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "access", resp.Token)
+		mockAuthService.AssertExpectations(t)
+	})
+
+	t.Run("user verified passkey", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		mockPasskeys := new(MockPasskeyUsecase)
+		mockPasskeys.On("FinishLogin", mock.Anything, "c1", "{}").
+			Return(&passkeyUsecase.LoginResult{User: createTestUserForGRPC(), UserVerified: true}, nil)
+		mockAuthService.On("CreateAuthenticatedToken", "test@example.com", "user123", withLevel(token.LevelMultiFactor, "hwk", "mfa")).Return("access", nil)
+
+		server := NewAuthServer(new(MockLoginUsecase), mockAuthService, WithPasskeys(mockPasskeys))
+		resp, err := server.FinishPasskeyLogin(context.Background(), &proto.FinishPasskeyLoginRequest{CeremonyId: "c1", CredentialJson: "{}"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "access", resp.Token)
+		mockAuthService.AssertExpectations(t)
+	})
+}
+
+func TestAuthServer_ValidateToken_Assurance(t *testing.T) {
+	authTime := time.Now().Add(-9 * time.Minute).Truncate(time.Second)
+	identity := &auth.Identity{
+		UserID:         "user123",
+		Authentication: token.PasswordAuthentication(authTime),
+		ExpiresAt:      authTime.Add(10 * time.Minute),
+	}
+
+	tests := []struct {
+		name           string
+		requiredACR    string
+		maxAge         int64
+		valid          bool
+		stepUpRequired bool
+		code           int32
+	}{
+		{"no requirement", "", 0, true, false, http.StatusOK},
+		{"level met", token.LevelSingleFactor, 0, true, false, http.StatusOK},
+		{"level too low", token.LevelMultiFactor, 0, false, true, http.StatusForbidden},
+		{"recent enough", "", 600, true, false, http.StatusOK},
+		{"too old", "", 300, false, true, http.StatusForbidden},
+		{"unknown level", "aal9", 0, false, false, http.StatusBadRequest},
+		{"negative max age", "", -1, false, false, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAuthService := new(MockAuthService)
+			mockAuthService.On("VerifyAccessToken", "Bearer access").Return(identity, nil)
+
+			resp, err := NewAuthServer(new(MockLoginUsecase), mockAuthService).ValidateToken(context.Background(),
+				&proto.ValidateTokenRequest{Token: "Bearer access", RequiredAcr: tt.requiredACR, MaxAgeSeconds: tt.maxAge})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.valid, resp.IsValid)
+			assert.Equal(t, tt.stepUpRequired, resp.StepUpRequired)
+			assert.Equal(t, tt.code, resp.ApiResponse.Code)
+			if tt.code != http.StatusBadRequest {
+				assert.Equal(t, token.LevelSingleFactor, resp.Acr)
+				assert.Equal(t, []string{"pwd"}, resp.Amr)
+				assert.Equal(t, authTime.Unix(), resp.AuthTime)
+				assert.Equal(t, authTime.Add(10*time.Minute).Unix(), resp.ExpiresAt)
+			}
+		})
+	}
+}
+
+func TestAuthServer_StepUp(t *testing.T) {
+	identity := &auth.Identity{UserID: "user123", UserName: "test@example.com"}
+
+	t.Run("password for users without MFA", func(t *testing.T) {
+		mockLoginUsecase := new(MockLoginUsecase)
+		mockAuthService := new(MockAuthService)
+		mockTOTP := new(MockTOTPUsecase)
+		mockAuthService.On("VerifyAccessToken", "Bearer access").Return(identity, nil)
+		mockTOTP.On("IsEnabled", mock.Anything, "user123").Return(false, nil)
+		mockLoginUsecase.On("Execute", mock.Anything, "test@example.com", "password123").Return(createTestUserForGRPC(), nil)
+		mockAuthService.On("CreateStepUpToken", "test@example.com", "user123", withLevel(token.LevelSingleFactor, "pwd")).Return("elevated", nil)
+
+		server := NewAuthServer(mockLoginUsecase, mockAuthService, WithTOTP(mockTOTP))
+		resp, err := server.StepUp(context.Background(), &proto.StepUpRequest{AccessToken: "Bearer access", Password: "password123"})
+
+		require.NoError(t, err)
+		assert.True(t, resp.ApiResponse.Success)
+		assert.Equal(t, "elevated", resp.Token)
+		assert.Equal(t, token.LevelSingleFactor, resp.Acr)
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		mockLoginUsecase := new(MockLoginUsecase)
+		mockAuthService := new(MockAuthService)
+		mockAuthService.On("VerifyAccessToken", "Bearer access").Return(identity, nil)
+		mockLoginUsecase.On("Execute", mock.Anything, "test@example.com", "wrong").Return(nil, errors.New("invalid credentials"))
+
+		resp, err := NewAuthServer(mockLoginUsecase, mockAuthService).StepUp(context.Background(), &proto.StepUpRequest{AccessToken: "Bearer access", Password: "wrong"})
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(http.StatusUnauthorized), resp.ApiResponse.Code)
+		assert.Empty(t, resp.Token)
+		mockAuthService.AssertNotCalled(t, "CreateStepUpToken", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("second factor for users with MFA", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		mockTOTP := new(MockTOTPUsecase)
+		mockAuthService.On("VerifyAccessToken", "Bearer access").Return(identity, nil)
+		mockTOTP.On("IsEnabled", mock.Anything, "user123").Return(true, nil)
+		mockTOTP.On("Verify", mock.Anything, "user123", "123456").Return(&mfaUsecase.Verification{}, nil)
+		mockAuthService.On("CreateStepUpToken", "test@example.com", "user123", withLevel(token.LevelMultiFactor, "otp")).Return("elevated", nil)
+
+		server := NewAuthServer(new(MockLoginUsecase), mockAuthService, WithTOTP(mockTOTP))
+		resp, err := server.StepUp(context.Background(), &proto.StepUpRequest{AccessToken: "Bearer access", Code: "123456"})
+
+		require.NoError(t, err)
+		assert.True(t, resp.ApiResponse.Success)
+		assert.Equal(t, token.LevelMultiFactor, resp.Acr)
+	})
+
+	t.Run("users with MFA can not step up with the password alone", func(t *testing.T) {
+		mockLoginUsecase := new(MockLoginUsecase)
+		mockAuthService := new(MockAuthService)
+		mockTOTP := new(MockTOTPUsecase)
+		mockAuthService.On("VerifyAccessToken", "Bearer access").Return(identity, nil)
+		mockTOTP.On("IsEnabled", mock.Anything, "user123").Return(true, nil)
+
+		server := NewAuthServer(mockLoginUsecase, mockAuthService, WithTOTP(mockTOTP))
+		resp, err := server.StepUp(context.Background(), &proto.StepUpRequest{AccessToken: "Bearer access", Password: "password123"})
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(http.StatusBadRequest), resp.ApiResponse.Code)
+		mockLoginUsecase.AssertNotCalled(t, "Execute", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid code", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		mockTOTP := new(MockTOTPUsecase)
+		mockAuthService.On("VerifyAccessToken", "Bearer access").Return(identity, nil)
+		mockTOTP.On("IsEnabled", mock.Anything, "user123").Return(true, nil)
+		mockTOTP.On("Verify", mock.Anything, "user123", "000000").Return(nil, mfaUsecase.ErrInvalidCode)
+
+		server := NewAuthServer(new(MockLoginUsecase), mockAuthService, WithTOTP(mockTOTP))
+		resp, err := server.StepUp(context.Background(), &proto.StepUpRequest{AccessToken: "Bearer access", Code: "000000"})
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(http.StatusUnauthorized), resp.ApiResponse.Code)
+		assert.Empty(t, resp.Token)
+	})
+
+	t.Run("invalid access token", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		mockAuthService.On("VerifyAccessToken", "forged").Return(nil, errors.New("invalid token"))
+
+		resp, err := NewAuthServer(new(MockLoginUsecase), mockAuthService).StepUp(context.Background(), &proto.StepUpRequest{AccessToken: "forged", Password: "password123"})
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(http.StatusUnauthorized), resp.ApiResponse.Code)
+	})
+}
@@ -6,7 +6,11 @@ import (
 	"net/http"
 	"testing"
 
+	"hub-user-service/internal/auth"
+	"hub-user-service/internal/auth/token"
+	"hub-user-service/internal/events"
 	"hub-user-service/internal/grpc/proto"
+	"hub-user-service/internal/login/application/usecase"
 	"hub-user-service/internal/login/domain/model"
 	"hub-user-service/internal/login/domain/valueobject"
 
@@ -23,14 +27,20 @@ type MockLoginUsecase struct {
 	mock.Mock
 }
 
-func (m *MockLoginUsecase) Execute(email string, password string) (*model.User, error) {
-	args := m.Called(email, password)
+func (m *MockLoginUsecase) Execute(ctx context.Context, email string, password string) (*model.User, error) {
+	args := m.Called(ctx, email, password)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*model.User), args.Error(1)
 }
 
+func (m *MockLoginUsecase) Login(ctx context.Context, email string, password string, client usecase.LoginClient) (*model.User, usecase.LoginDecision, error) {
+	args := m.Called(ctx, email, password, client)
+	user, _ := args.Get(0).(*model.User)
+	return user, args.Get(1).(usecase.LoginDecision), args.Error(2)
+}
+
 // MockAuthService mocks the auth service
 type MockAuthService struct {
 	mock.Mock
@@ -46,6 +56,39 @@ func (m *MockAuthService) CreateToken(userName string, userId string) (string, e
 	return args.String(0), args.Error(1)
 }
 
+func (m *MockAuthService) CreateAuthenticatedToken(userName string, userId string, authn token.Authentication) (string, error) {
+	args := m.Called(userName, userId, authn)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockAuthService) CreateStepUpToken(userName string, userId string, authn token.Authentication) (string, error) {
+	args := m.Called(userName, userId, authn)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockAuthService) VerifyAccessToken(tokenString string) (*auth.Identity, error) {
+	args := m.Called(tokenString)
+	identity, _ := args.Get(0).(*auth.Identity)
+	return identity, args.Error(1)
+}
+
+func (m *MockAuthService) VerifyRefreshableToken(tokenString string) (*auth.Identity, error) {
+	args := m.Called(tokenString)
+	identity, _ := args.Get(0).(*auth.Identity)
+	return identity, args.Error(1)
+}
+
+func (m *MockAuthService) CreateMFAChallenge(userName string, userId string, methods []string) (string, error) {
+	args := m.Called(userName, userId, methods)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockAuthService) VerifyMFAChallenge(challenge string) (*auth.Identity, error) {
+	args := m.Called(challenge)
+	identity, _ := args.Get(0).(*auth.Identity)
+	return identity, args.Error(1)
+}
+
 // Helper function to create a test user
 func createTestUserForGRPC() *model.User {
 	email := valueobject.NewEmailFromRepository("test@example.com")
@@ -68,8 +111,8 @@ func TestAuthServer_Login_Success(t *testing.T) {
 
 	testUser := createTestUserForGRPC()
 
-	mockLoginUsecase.On("Execute", "test@example.com", "password123").Return(testUser, nil)
-	mockAuthService.On("CreateToken", "test@example.com", "user123").Return("mock-jwt-token-123", nil)
+	mockLoginUsecase.On("Login", mock.Anything, "test@example.com", "password123", mock.Anything).Return(testUser, usecase.LoginAllowed, nil)
+	mockAuthService.On("CreateAuthenticatedToken", "test@example.com", "user123", mock.Anything).Return("mock-jwt-token-123", nil)
 
 	server := NewAuthServer(mockLoginUsecase, mockAuthService)
 	ctx := context.Background()
@@ -96,6 +139,37 @@ func TestAuthServer_Login_Success(t *testing.T) {
 	mockAuthService.AssertExpectations(t)
 }
 
+func TestAuthServer_Login_PublishesLoginEvent(t *testing.T) {
+	// Arrange
+	mockLoginUsecase := new(MockLoginUsecase)
+	mockAuthService := new(MockAuthService)
+
+	testUser := createTestUserForGRPC()
+
+	mockLoginUsecase.On("Login", mock.Anything, "test@example.com", "password123", mock.Anything).Return(testUser, usecase.LoginAllowed, nil)
+	mockAuthService.On("CreateAuthenticatedToken", "test@example.com", "user123", mock.Anything).Return("mock-jwt-token-123", nil)
+
+	broker := events.NewBroker(10, 10)
+	sub, _ := broker.Subscribe(context.Background(), "", events.Filter{})
+	defer sub.Close()
+
+	server := NewAuthServer(mockLoginUsecase, mockAuthService, WithEventPublisher(broker))
+
+	// Act
+	resp, err := server.Login(context.Background(), &proto.LoginRequest{
+		Email:    "test@example.com",
+		Password: "password123",
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, resp.ApiResponse.Success)
+
+	event := <-sub.Events()
+	assert.Equal(t, events.EventLogin, event.Type)
+	assert.Equal(t, "user123", event.UserID)
+}
+
 func TestAuthServer_Login_EmptyEmail(t *testing.T) {
 	// Arrange
 	mockLoginUsecase := new(MockLoginUsecase)
@@ -121,7 +195,7 @@ func TestAuthServer_Login_EmptyEmail(t *testing.T) {
 
 	// Ensure usecase was not called
 	mockLoginUsecase.AssertNotCalled(t, "Execute")
-	mockAuthService.AssertNotCalled(t, "CreateToken")
+	mockAuthService.AssertNotCalled(t, "CreateAuthenticatedToken")
 }
 
 func TestAuthServer_Login_InvalidCredentials(t *testing.T) {
@@ -129,7 +203,7 @@ func TestAuthServer_Login_InvalidCredentials(t *testing.T) {
 	mockLoginUsecase := new(MockLoginUsecase)
 	mockAuthService := new(MockAuthService)
 
-	mockLoginUsecase.On("Execute", "test@example.com", "wrongpassword").Return(nil, errors.New("invalid password"))
+	mockLoginUsecase.On("Login", mock.Anything, "test@example.com", "wrongpassword", mock.Anything).Return(nil, usecase.LoginDecision(""), errors.New("invalid password"))
 
 	server := NewAuthServer(mockLoginUsecase, mockAuthService)
 	ctx := context.Background()
@@ -150,7 +224,7 @@ func TestAuthServer_Login_InvalidCredentials(t *testing.T) {
 	assert.Equal(t, int32(http.StatusUnauthorized), resp.ApiResponse.Code)
 
 	mockLoginUsecase.AssertExpectations(t)
-	mockAuthService.AssertNotCalled(t, "CreateToken")
+	mockAuthService.AssertNotCalled(t, "CreateAuthenticatedToken")
 }
 
 func TestAuthServer_Login_TokenGenerationFailure(t *testing.T) {
@@ -160,8 +234,8 @@ func TestAuthServer_Login_TokenGenerationFailure(t *testing.T) {
 
 	testUser := createTestUserForGRPC()
 
-	mockLoginUsecase.On("Execute", "test@example.com", "password123").Return(testUser, nil)
-	mockAuthService.On("CreateToken", "test@example.com", "user123").Return("", errors.New("failed to sign token"))
+	mockLoginUsecase.On("Login", mock.Anything, "test@example.com", "password123", mock.Anything).Return(testUser, usecase.LoginAllowed, nil)
+	mockAuthService.On("CreateAuthenticatedToken", "test@example.com", "user123", mock.Anything).Return("", errors.New("failed to sign token"))
 
 	server := NewAuthServer(mockLoginUsecase, mockAuthService)
 	ctx := context.Background()
@@ -194,7 +268,7 @@ func TestAuthServer_ValidateToken_Success(t *testing.T) {
 	mockLoginUsecase := new(MockLoginUsecase)
 	mockAuthService := new(MockAuthService)
 
-	mockAuthService.On("VerifyToken", "valid-token-123", mock.Anything).Return("user456", nil)
+	mockAuthService.On("VerifyAccessToken", "valid-token-123").Return(&auth.Identity{UserID: "user456"}, nil)
 
 	server := NewAuthServer(mockLoginUsecase, mockAuthService)
 	ctx := context.Background()
@@ -240,7 +314,7 @@ func TestAuthServer_ValidateToken_EmptyToken(t *testing.T) {
 	assert.Contains(t, resp.ApiResponse.Message, "token")
 	assert.Equal(t, int32(http.StatusBadRequest), resp.ApiResponse.Code)
 
-	mockAuthService.AssertNotCalled(t, "VerifyToken")
+	mockAuthService.AssertNotCalled(t, "VerifyAccessToken", mock.Anything)
 }
 
 func TestAuthServer_ValidateToken_InvalidToken(t *testing.T) {
@@ -248,7 +322,7 @@ func TestAuthServer_ValidateToken_InvalidToken(t *testing.T) {
 	mockLoginUsecase := new(MockLoginUsecase)
 	mockAuthService := new(MockAuthService)
 
-	mockAuthService.On("VerifyToken", "invalid-token", mock.Anything).Return("", errors.New("invalid token signature"))
+	mockAuthService.On("VerifyAccessToken", "invalid-token").Return(nil, errors.New("invalid token signature"))
 
 	server := NewAuthServer(mockLoginUsecase, mockAuthService)
 	ctx := context.Background()
@@ -285,11 +359,11 @@ func TestAuthServer_CompleteAuthenticationFlow(t *testing.T) {
 	generatedToken := "complete-flow-token-123"
 
 	// Setup mocks for login
-	mockLoginUsecase.On("Execute", "test@example.com", "password123").Return(testUser, nil)
-	mockAuthService.On("CreateToken", "test@example.com", "user123").Return(generatedToken, nil)
+	mockLoginUsecase.On("Login", mock.Anything, "test@example.com", "password123", mock.Anything).Return(testUser, usecase.LoginAllowed, nil)
+	mockAuthService.On("CreateAuthenticatedToken", "test@example.com", "user123", mock.Anything).Return(generatedToken, nil)
 
 	// Setup mocks for token validation
-	mockAuthService.On("VerifyToken", generatedToken, mock.Anything).Return("user123", nil)
+	mockAuthService.On("VerifyAccessToken", generatedToken).Return(&auth.Identity{UserID: "user123"}, nil)
 
 	server := NewAuthServer(mockLoginUsecase, mockAuthService)
 	ctx := context.Background()
@@ -0,0 +1,114 @@
+// Package clientinfo describes the end client of a gRPC call from the metadata forwarded by a
+// trusted gateway, since the gRPC peer and user agent are the gateway's own
+package clientinfo
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"strings"
+
+	"hub-user-service/internal/auth/serviceauth"
+	"hub-user-service/internal/session/domain/model"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// Metadata set by the gateway to describe the end user's client
+const (
+	ForwardedForMetadataKey = "x-forwarded-for"
+	UserAgentMetadataKey    = "x-user-agent"
+	DeviceNameMetadataKey   = "x-device-name"
+	// ClientTypeMetadataKey selects the session timeouts: web (default), mobile or api
+	ClientTypeMetadataKey = "x-client-type"
+)
+
+// Trust reports whether the caller of ctx relays end user calls, so that the client IP address
+// and type it forwards can be believed; a nil Trust trusts no caller
+type Trust func(ctx context.Context) bool
+
+// TrustGateways trusts service clients registered as gateways and callers connecting from one
+// of the proxies networks
+func TrustGateways(proxies []netip.Prefix) Trust {
+	return func(ctx context.Context) bool {
+		if client, ok := serviceauth.FromContext(ctx); ok && client.Gateway {
+			return true
+		}
+		addr, ok := peerAddr(ctx)
+		if !ok {
+			return false
+		}
+		for _, proxy := range proxies {
+			if proxy.Contains(addr) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Resolve describes the end client of the call in ctx
+// The user agent and device name only label sessions and are always taken from the metadata. The
+// client type selects the session timeouts: it is the one of the calling service client when its
+// registration sets one, else the forwarded one when trust holds, else empty (web).
+func Resolve(ctx context.Context, trust Trust) model.ClientInfo {
+	client := model.ClientInfo{
+		IPAddress: IPAddress(ctx, trust),
+		UserAgent: first(ctx, UserAgentMetadataKey),
+		Device:    first(ctx, DeviceNameMetadataKey),
+	}
+
+	if service, ok := serviceauth.FromContext(ctx); ok && service.ClientType != "" {
+		client.Type = model.ParseClientType(service.ClientType)
+	} else if value := first(ctx, ClientTypeMetadataKey); value != "" && trusted(ctx, trust) {
+		client.Type = model.ParseClientType(value)
+	}
+	return client
+}
+
+// IPAddress returns the end client IP address: the first x-forwarded-for address when trust holds,
+// else the peer address, since any caller can claim another client's address in the metadata
+func IPAddress(ctx context.Context, trust Trust) string {
+	// The first address is the original client, proxies append theirs
+	if forwarded := strings.TrimSpace(strings.Split(first(ctx, ForwardedForMetadataKey), ",")[0]); forwarded != "" && trusted(ctx, trust) {
+		return forwarded
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+			return host
+		}
+		return p.Addr.String()
+	}
+	return ""
+}
+
+// trusted reports whether trust holds for the caller of ctx
+func trusted(ctx context.Context, trust Trust) bool {
+	return trust != nil && trust(ctx)
+}
+
+// peerAddr returns the IP address of the gRPC peer
+func peerAddr(ctx context.Context) (netip.Addr, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return netip.Addr{}, false
+	}
+	addrPort, err := netip.ParseAddrPort(p.Addr.String())
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addrPort.Addr().Unmap(), true
+}
+
+// first returns the first value of the incoming metadata key
+func first(ctx context.Context, key string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
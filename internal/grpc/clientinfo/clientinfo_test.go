@@ -0,0 +1,77 @@
+package clientinfo
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"testing"
+
+	"hub-user-service/internal/auth/serviceauth"
+	"hub-user-service/internal/session/domain/model"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// callFrom is an incoming call from the peer IP address carrying the given metadata pairs
+func callFrom(ip string, pairs ...string) context.Context {
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 52100}})
+	return metadata.NewIncomingContext(ctx, metadata.Pairs(pairs...))
+}
+
+func TestTrustGateways(t *testing.T) {
+	trust := TrustGateways([]netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")})
+
+	assert.True(t, trust(callFrom("10.1.2.3")), "trusted proxy")
+	assert.True(t, trust(callFrom("::ffff:10.1.2.3")), "IPv4-mapped trusted proxy")
+	assert.False(t, trust(callFrom("203.0.113.7")), "other peer")
+	assert.False(t, trust(context.Background()), "no peer")
+
+	gateway := serviceauth.NewContext(callFrom("203.0.113.7"), &serviceauth.Client{ID: "hub-monolith", Gateway: true})
+	assert.True(t, trust(gateway), "gateway service client")
+	service := serviceauth.NewContext(callFrom("203.0.113.7"), &serviceauth.Client{ID: "billing"})
+	assert.False(t, trust(service), "other service client")
+}
+
+func TestResolve(t *testing.T) {
+	pairs := []string{
+		ForwardedForMetadataKey, "203.0.113.7, 10.0.0.1",
+		UserAgentMetadataKey, "Mozilla/5.0",
+		DeviceNameMetadataKey, "Ada's laptop",
+		ClientTypeMetadataKey, "mobile",
+	}
+	trustAll := func(context.Context) bool { return true }
+	trustNone := func(context.Context) bool { return false }
+
+	t.Run("trusted caller", func(t *testing.T) {
+		assert.Equal(t, model.ClientInfo{Type: model.ClientMobile, IPAddress: "203.0.113.7", UserAgent: "Mozilla/5.0", Device: "Ada's laptop"},
+			Resolve(callFrom("10.0.0.1", pairs...), trustAll))
+	})
+
+	t.Run("untrusted caller cannot choose the client type or address", func(t *testing.T) {
+		assert.Equal(t, model.ClientInfo{IPAddress: "10.0.0.1", UserAgent: "Mozilla/5.0", Device: "Ada's laptop"},
+			Resolve(callFrom("10.0.0.1", pairs...), trustNone))
+		assert.Equal(t, model.ClientInfo{IPAddress: "10.0.0.1", UserAgent: "Mozilla/5.0", Device: "Ada's laptop"},
+			Resolve(callFrom("10.0.0.1", pairs...), nil))
+	})
+
+	t.Run("service client type wins", func(t *testing.T) {
+		ctx := serviceauth.NewContext(callFrom("10.0.0.1", pairs...), &serviceauth.Client{ID: "cli", ClientType: "api"})
+		assert.Equal(t, model.ClientAPI, Resolve(ctx, trustAll).Type)
+	})
+
+	t.Run("falls back to the peer", func(t *testing.T) {
+		assert.Equal(t, model.ClientInfo{IPAddress: "192.0.2.1"}, Resolve(callFrom("192.0.2.1"), trustAll))
+		assert.Equal(t, model.ClientInfo{}, Resolve(context.Background(), trustAll))
+	})
+}
+
+func TestIPAddress(t *testing.T) {
+	trust := TrustGateways([]netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")})
+
+	assert.Equal(t, "203.0.113.7", IPAddress(callFrom("10.0.0.1", ForwardedForMetadataKey, "203.0.113.7, 10.0.0.2"), trust), "trusted proxy")
+	assert.Equal(t, "198.51.100.4", IPAddress(callFrom("198.51.100.4", ForwardedForMetadataKey, "203.0.113.7"), trust), "spoofed by a client")
+	assert.Equal(t, "10.0.0.1", IPAddress(callFrom("10.0.0.1", ForwardedForMetadataKey, " "), trust), "empty header")
+	assert.Empty(t, IPAddress(context.Background(), trust), "no peer")
+}
@@ -0,0 +1,126 @@
+package interceptor
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path"
+	"strings"
+	"time"
+
+	"hub-user-service/internal/auth/serviceauth"
+	"hub-user-service/internal/grpc/clientinfo"
+	"hub-user-service/internal/ratelimit"
+
+	"google.golang.org/grpc"
+)
+
+// RateLimitKey selects what a quota counts calls by
+type RateLimitKey string
+
+const (
+	// RateLimitByIP counts calls per client IP: the first x-forwarded-for address set by a
+	// trusted gateway, or the peer address
+	RateLimitByIP RateLimitKey = "ip"
+	// RateLimitByEmail counts calls per email in the request; requests without one are not counted
+	RateLimitByEmail RateLimitKey = "email"
+	// RateLimitByClient counts calls per service client; calls without service auth are not counted
+	RateLimitByClient RateLimitKey = "client"
+)
+
+// AllMethods is the RateLimitQuota method that counts the calls to every method together
+const AllMethods = "*"
+
+// RateLimitQuota limits the calls to Method (its name without the service, e.g. "Login") per Key
+type RateLimitQuota struct {
+	Method string
+	Key    RateLimitKey
+	Limit  ratelimit.Limit
+}
+
+// NewRateLimitQuota allows count calls per period, all of which may come at once
+func NewRateLimitQuota(method string, key RateLimitKey, count int, period time.Duration) RateLimitQuota {
+	return RateLimitQuota{Method: method, Key: key, Limit: ratelimit.Limit{Rate: float64(count) / period.Seconds(), Burst: count}}
+}
+
+// RateLimitInterceptor applies token bucket quotas per method to every call, by client IP,
+// email or service client; it must run after ServiceAuthInterceptor to count by client
+type RateLimitInterceptor struct {
+	limiter ratelimit.Limiter
+	quotas  map[string][]RateLimitQuota
+	trust   clientinfo.Trust
+}
+
+// NewRateLimitInterceptor creates a rate limit interceptor enforcing quotas with limiter; the
+// client IP forwarded in the metadata is only counted for the calls trust holds for
+func NewRateLimitInterceptor(limiter ratelimit.Limiter, quotas []RateLimitQuota, trust clientinfo.Trust) *RateLimitInterceptor {
+	byMethod := make(map[string][]RateLimitQuota)
+	for _, quota := range quotas {
+		byMethod[quota.Method] = append(byMethod[quota.Method], quota)
+	}
+	return &RateLimitInterceptor{limiter: limiter, quotas: byMethod, trust: trust}
+}
+
+// Unary returns the unary server interceptor
+func (i *RateLimitInterceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := i.check(ctx, info.FullMethod, req); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// Stream returns the stream server interceptor; streams are counted when they are opened
+func (i *RateLimitInterceptor) Stream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := i.check(ss.Context(), info.FullMethod, nil); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// check takes a token from every quota of the method and of all methods
+func (i *RateLimitInterceptor) check(ctx context.Context, fullMethod string, req interface{}) error {
+	method := path.Base(fullMethod)
+	quotas := make([]RateLimitQuota, 0, len(i.quotas[method])+len(i.quotas[AllMethods]))
+	quotas = append(append(quotas, i.quotas[method]...), i.quotas[AllMethods]...)
+
+	for _, quota := range quotas {
+		value := i.keyValue(ctx, quota.Key, req)
+		if value == "" {
+			continue
+		}
+
+		result, err := i.limiter.Allow(ctx, fmt.Sprintf("rate:%s:%s:%s", quota.Method, quota.Key, value), quota.Limit)
+		if err != nil {
+			// Fail open: an unavailable limiter backend must not take the service down, but the
+			// other quotas are still enforced
+			log.Printf("Rate limiter error for %s: %v", method, err)
+			continue
+		}
+		if !result.Allowed {
+			log.Printf("🚦 Rate limit exceeded for %s by %s", method, quota.Key)
+			return resourceExhausted(ctx, result, fmt.Sprintf("rate limit exceeded for %s, retry in %s", method, result.RetryAfter.Round(time.Second)))
+		}
+	}
+	return nil
+}
+
+// keyValue returns the value of key for the call, empty when it has none
+func (i *RateLimitInterceptor) keyValue(ctx context.Context, key RateLimitKey, req interface{}) string {
+	switch key {
+	case RateLimitByIP:
+		return clientinfo.IPAddress(ctx, i.trust)
+	case RateLimitByEmail:
+		if r, ok := req.(interface{ GetEmail() string }); ok {
+			return strings.ToLower(strings.TrimSpace(r.GetEmail()))
+		}
+	case RateLimitByClient:
+		if client, ok := serviceauth.FromContext(ctx); ok {
+			return client.ID
+		}
+	}
+	return ""
+}
@@ -0,0 +1,175 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"hub-user-service/internal/auth/serviceauth"
+	"hub-user-service/internal/grpc/clientinfo"
+	"hub-user-service/internal/ratelimit"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// emailRequest stands in for the request messages carrying an email
+type emailRequest struct {
+	email string
+}
+
+func (r *emailRequest) GetEmail() string {
+	return r.email
+}
+
+// fakeTransportStream records the trailer set by the interceptor
+type fakeTransportStream struct {
+	trailer metadata.MD
+}
+
+func (f *fakeTransportStream) Method() string                  { return loginMethod }
+func (f *fakeTransportStream) SetHeader(md metadata.MD) error  { return nil }
+func (f *fakeTransportStream) SendHeader(md metadata.MD) error { return nil }
+func (f *fakeTransportStream) SetTrailer(md metadata.MD) error {
+	f.trailer = metadata.Join(f.trailer, md)
+	return nil
+}
+
+func contextFromIP(ip string) context.Context {
+	return peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 50000}})
+}
+
+func callRateLimited(i *RateLimitInterceptor, ctx context.Context, method string, req interface{}) error {
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+	_, err := i.Unary()(ctx, req, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+	return err
+}
+
+func TestRateLimitInterceptor_Keys(t *testing.T) {
+	limiter := &stubLimiter{result: ratelimit.Result{Allowed: true}}
+	interceptor := NewRateLimitInterceptor(limiter, []RateLimitQuota{
+		NewRateLimitQuota("Login", RateLimitByIP, 10, time.Minute),
+		NewRateLimitQuota("Login", RateLimitByEmail, 5, time.Minute),
+		NewRateLimitQuota("ValidateToken", RateLimitByClient, 100, time.Minute),
+	}, clientinfo.TrustGateways([]netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}))
+
+	require.NoError(t, callRateLimited(interceptor, contextFromIP("198.51.100.7"), loginMethod, &emailRequest{email: " Jane@Example.com "}))
+	assert.Equal(t, []string{"rate:Login:ip:198.51.100.7", "rate:Login:email:jane@example.com"}, limiter.keys)
+
+	limiter.keys = nil
+	ctx := metadata.NewIncomingContext(contextFromIP("10.0.0.2"), metadata.Pairs(clientinfo.ForwardedForMetadataKey, "203.0.113.9, 10.0.0.1"))
+	require.NoError(t, callRateLimited(interceptor, ctx, loginMethod, &emailRequest{}))
+	assert.Equal(t, []string{"rate:Login:ip:203.0.113.9"}, limiter.keys, "the client IP forwarded by a trusted proxy is counted and an empty email is skipped")
+
+	limiter.keys = nil
+	ctx = metadata.NewIncomingContext(contextFromIP("198.51.100.7"), metadata.Pairs(clientinfo.ForwardedForMetadataKey, "203.0.113.9"))
+	require.NoError(t, callRateLimited(interceptor, ctx, loginMethod, &emailRequest{}))
+	assert.Equal(t, []string{"rate:Login:ip:198.51.100.7"}, limiter.keys, "a client cannot spread its calls over forwarded IPs")
+
+	limiter.keys = nil
+	ctx = serviceauth.NewContext(context.Background(), &serviceauth.Client{ID: "order-service"})
+	require.NoError(t, callRateLimited(interceptor, ctx, validateMethod, nil))
+	assert.Equal(t, []string{"rate:ValidateToken:client:order-service"}, limiter.keys)
+
+	limiter.keys = nil
+	require.NoError(t, callRateLimited(interceptor, contextFromIP("198.51.100.7"), healthMethod, nil))
+	assert.Empty(t, limiter.keys, "methods without quotas are not counted")
+}
+
+func TestRateLimitInterceptor_AllMethods(t *testing.T) {
+	limiter := &stubLimiter{result: ratelimit.Result{Allowed: true}}
+	interceptor := NewRateLimitInterceptor(limiter, []RateLimitQuota{
+		NewRateLimitQuota(AllMethods, RateLimitByIP, 100, time.Minute),
+		NewRateLimitQuota("Login", RateLimitByIP, 10, time.Minute),
+	}, nil)
+
+	require.NoError(t, callRateLimited(interceptor, contextFromIP("198.51.100.7"), loginMethod, nil))
+	assert.Equal(t, []string{"rate:Login:ip:198.51.100.7", "rate:*:ip:198.51.100.7"}, limiter.keys)
+}
+
+func TestRateLimitInterceptor_Exhausted(t *testing.T) {
+	interceptor := NewRateLimitInterceptor(ratelimit.NewMemoryLimiter(), []RateLimitQuota{
+		NewRateLimitQuota("Login", RateLimitByEmail, 2, time.Minute),
+	}, nil)
+	ctx := contextFromIP("198.51.100.7")
+
+	require.NoError(t, callRateLimited(interceptor, ctx, loginMethod, &emailRequest{email: "jane@example.com"}))
+	require.NoError(t, callRateLimited(interceptor, ctx, loginMethod, &emailRequest{email: "JANE@example.com"}))
+
+	err := callRateLimited(interceptor, ctx, loginMethod, &emailRequest{email: "jane@example.com"})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	require.NoError(t, callRateLimited(interceptor, ctx, loginMethod, &emailRequest{email: "john@example.com"}), "other emails keep their own quota")
+}
+
+func TestRateLimitInterceptor_RetryAfterTrailer(t *testing.T) {
+	interceptor := NewRateLimitInterceptor(&stubLimiter{result: ratelimit.Result{Allowed: false, RetryAfter: 2500 * time.Millisecond}}, []RateLimitQuota{
+		NewRateLimitQuota("Login", RateLimitByIP, 1, time.Minute),
+	}, nil)
+	transport := &fakeTransportStream{}
+	ctx := grpc.NewContextWithServerTransportStream(contextFromIP("198.51.100.7"), transport)
+
+	err := callRateLimited(interceptor, ctx, loginMethod, nil)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Equal(t, []string{"2"}, transport.trailer.Get(RetryAfterMetadataKey))
+}
+
+// brokenKeyLimiter fails for one key and delegates the others to Limiter
+type brokenKeyLimiter struct {
+	ratelimit.Limiter
+	broken string
+}
+
+func (l *brokenKeyLimiter) Allow(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	if key == l.broken {
+		return ratelimit.Result{}, errors.New("backend down")
+	}
+	return l.Limiter.Allow(ctx, key, limit)
+}
+
+func TestRateLimitInterceptor_FailsOpen(t *testing.T) {
+	interceptor := NewRateLimitInterceptor(&stubLimiter{err: errors.New("backend down")}, []RateLimitQuota{
+		NewRateLimitQuota("Login", RateLimitByIP, 1, time.Minute),
+	}, nil)
+
+	assert.NoError(t, callRateLimited(interceptor, contextFromIP("198.51.100.7"), loginMethod, nil))
+
+	// A failing quota does not skip the next ones
+	limiter := &brokenKeyLimiter{Limiter: ratelimit.NewMemoryLimiter(), broken: "rate:Login:ip:198.51.100.7"}
+	interceptor = NewRateLimitInterceptor(limiter, []RateLimitQuota{
+		NewRateLimitQuota("Login", RateLimitByIP, 1, time.Minute),
+		NewRateLimitQuota("Login", RateLimitByEmail, 1, time.Minute),
+	}, nil)
+	require.NoError(t, callRateLimited(interceptor, contextFromIP("198.51.100.7"), loginMethod, &emailRequest{email: "jane@example.com"}))
+	err := callRateLimited(interceptor, contextFromIP("198.51.100.7"), loginMethod, &emailRequest{email: "jane@example.com"})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestRateLimitInterceptor_Stream(t *testing.T) {
+	limiter := &stubLimiter{result: ratelimit.Result{Allowed: false, RetryAfter: time.Second}}
+	interceptor := NewRateLimitInterceptor(limiter, []RateLimitQuota{
+		NewRateLimitQuota("Watch", RateLimitByIP, 1, time.Minute),
+		NewRateLimitQuota("Watch", RateLimitByEmail, 1, time.Minute),
+	}, nil)
+	stream := &fakeServerStream{ctx: contextFromIP("198.51.100.7")}
+
+	called := false
+	err := interceptor.Stream()(nil, stream, &grpc.StreamServerInfo{FullMethod: "/hub_investments.AuthService/Watch"}, func(srv interface{}, ss grpc.ServerStream) error {
+		called = true
+		return nil
+	})
+
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.False(t, called)
+	assert.Equal(t, []string{"rate:Watch:ip:198.51.100.7"}, limiter.keys)
+}
@@ -0,0 +1,24 @@
+package interceptor
+
+import (
+	"context"
+
+	"hub-user-service/internal/database"
+
+	"google.golang.org/grpc"
+)
+
+// ReadYourWrites scopes read-replica stickiness to a unary call: once the handler writes,
+// its later reads go to the primary (see database.WithReadYourWrites)
+func ReadYourWrites() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(database.WithReadYourWrites(ctx), req)
+	}
+}
+
+// ReadYourWritesStream scopes read-replica stickiness to a streaming call
+func ReadYourWritesStream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &wrappedStream{ServerStream: ss, ctx: database.WithReadYourWrites(ss.Context())})
+	}
+}
@@ -0,0 +1,76 @@
+package interceptor
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"hub-user-service/internal/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// namedDatabase answers GetContext with its own name
+type namedDatabase struct {
+	database.Database
+	name string
+}
+
+func (n *namedDatabase) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	*dest.(*string) = n.name
+	return nil
+}
+
+func (n *namedDatabase) ExecContext(ctx context.Context, query string, args ...interface{}) (database.Result, error) {
+	return nil, nil
+}
+
+func (n *namedDatabase) BeginTx(ctx context.Context, opts *sql.TxOptions) (database.Transaction, error) {
+	return nil, nil
+}
+
+func readFrom(t *testing.T, ctx context.Context, db database.Database) string {
+	t.Helper()
+	var served string
+	require.NoError(t, db.GetContext(ctx, &served, "SELECT 1"))
+	return served
+}
+
+func TestReadYourWrites_StickyWithinCall(t *testing.T) {
+	db := database.NewRoutingDatabase(&namedDatabase{name: "primary"}, []database.Database{&namedDatabase{name: "replica"}}, 0)
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		assert.Equal(t, "replica", readFrom(t, ctx, db))
+		_, err := db.ExecContext(ctx, "UPDATE users SET name = $1", "Ada")
+		require.NoError(t, err)
+		assert.Equal(t, "primary", readFrom(t, ctx, db))
+		return nil, nil
+	}
+
+	interceptor := ReadYourWrites()
+	_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: loginMethod}, handler)
+	require.NoError(t, err)
+
+	// A new call starts without stickiness
+	_, err = interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: loginMethod},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			assert.Equal(t, "replica", readFrom(t, ctx, db))
+			return nil, nil
+		})
+	require.NoError(t, err)
+}
+
+func TestReadYourWritesStream_InstallsMarker(t *testing.T) {
+	db := database.NewRoutingDatabase(&namedDatabase{name: "primary"}, []database.Database{&namedDatabase{name: "replica"}}, 0)
+	stream := &wrappedStream{ctx: context.Background()}
+
+	err := ReadYourWritesStream()(nil, stream, &grpc.StreamServerInfo{}, func(srv interface{}, ss grpc.ServerStream) error {
+		_, err := db.BeginTx(ss.Context(), nil)
+		require.NoError(t, err)
+		assert.Equal(t, "primary", readFrom(t, ss.Context(), db))
+		return nil
+	})
+	require.NoError(t, err)
+}
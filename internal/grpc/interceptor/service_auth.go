@@ -0,0 +1,138 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+
+	"hub-user-service/internal/auth/serviceauth"
+	"hub-user-service/internal/ratelimit"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// RetryAfterMetadataKey is the trailer key carrying the number of seconds to wait after ResourceExhausted
+const RetryAfterMetadataKey = "retry-after"
+
+// ServiceAuthInterceptor identifies internal callers by their signed service token,
+// checks the RPC against the caller's allowed methods and enforces its rate limit
+type ServiceAuthInterceptor struct {
+	authenticator *serviceauth.Authenticator
+	limiter       ratelimit.Limiter
+	exempt        map[string]bool
+}
+
+// NewServiceAuthInterceptor creates a new service auth interceptor
+// exemptMethods are full method names that skip authentication (e.g. health checks)
+func NewServiceAuthInterceptor(authenticator *serviceauth.Authenticator, limiter ratelimit.Limiter, exemptMethods ...string) *ServiceAuthInterceptor {
+	exempt := make(map[string]bool, len(exemptMethods))
+	for _, method := range exemptMethods {
+		exempt[method] = true
+	}
+
+	return &ServiceAuthInterceptor{
+		authenticator: authenticator,
+		limiter:       limiter,
+		exempt:        exempt,
+	}
+}
+
+// Unary returns the unary server interceptor
+func (i *ServiceAuthInterceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := i.authorize(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// Stream returns the stream server interceptor
+func (i *ServiceAuthInterceptor) Stream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := i.authorize(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &wrappedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authorize authenticates the caller and returns a context carrying the service client
+func (i *ServiceAuthInterceptor) authorize(ctx context.Context, fullMethod string) (context.Context, error) {
+	if i.exempt[fullMethod] {
+		return ctx, nil
+	}
+
+	client, err := i.authenticator.Authenticate(bearerToken(ctx))
+	if err != nil {
+		if errors.Is(err, serviceauth.ErrMissingToken) {
+			return nil, status.Error(codes.Unauthenticated, "service token is required")
+		}
+		log.Printf("Service authentication failed for %s: %v", fullMethod, err)
+		return nil, status.Error(codes.Unauthenticated, "invalid service token")
+	}
+
+	if !client.CanInvoke(fullMethod) {
+		log.Printf("Service client %s is not allowed to call %s", client.ID, fullMethod)
+		return nil, status.Errorf(codes.PermissionDenied, "client %s is not allowed to call %s", client.ID, fullMethod)
+	}
+
+	result, err := i.limiter.Allow(ctx, "service:"+client.ID, client.Limit())
+	if err != nil {
+		// Fail open: an unavailable limiter backend must not take the service down
+		log.Printf("Rate limiter error for service client %s: %v", client.ID, err)
+	} else if !result.Allowed {
+		return nil, resourceExhausted(ctx, result, "rate limit exceeded for client "+client.ID)
+	}
+
+	return serviceauth.NewContext(ctx, client), nil
+}
+
+// bearerToken extracts the token from the "authorization: Bearer <token>" metadata entry
+func bearerToken(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return ""
+	}
+
+	const prefix = "bearer "
+	value := values[0]
+	if len(value) < len(prefix) || !strings.EqualFold(value[:len(prefix)], prefix) {
+		return ""
+	}
+	return strings.TrimSpace(value[len(prefix):])
+}
+
+// resourceExhausted builds a ResourceExhausted status and attaches retry-after metadata
+func resourceExhausted(ctx context.Context, result ratelimit.Result, message string) error {
+	seconds := int64(result.RetryAfter.Seconds())
+	if result.RetryAfter > 0 && seconds == 0 {
+		seconds = 1
+	}
+	_ = grpc.SetTrailer(ctx, metadata.Pairs(RetryAfterMetadataKey, strconv.FormatInt(seconds, 10)))
+
+	return status.Error(codes.ResourceExhausted, message)
+}
+
+// wrappedStream overrides the stream context so handlers see values added by interceptors
+type wrappedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the wrapped context
+func (w *wrappedStream) Context() context.Context {
+	return w.ctx
+}
@@ -0,0 +1,158 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"hub-user-service/internal/auth/serviceauth"
+	"hub-user-service/internal/ratelimit"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	loginMethod    = "/hub_investments.AuthService/Login"
+	validateMethod = "/hub_investments.AuthService/ValidateToken"
+	healthMethod   = "/grpc.health.v1.Health/Check"
+)
+
+// stubLimiter returns a fixed result for every call
+type stubLimiter struct {
+	result ratelimit.Result
+	err    error
+	keys   []string
+}
+
+func (s *stubLimiter) Allow(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	s.keys = append(s.keys, key)
+	return s.result, s.err
+}
+
+func newTestInterceptor(t *testing.T, limiter ratelimit.Limiter) *ServiceAuthInterceptor {
+	registry, err := serviceauth.NewRegistry([]serviceauth.Client{
+		{ID: "order-service", Secret: "order-secret", AllowedMethods: []string{validateMethod}},
+	})
+	require.NoError(t, err)
+	return NewServiceAuthInterceptor(serviceauth.NewAuthenticator(registry), limiter, healthMethod)
+}
+
+func contextWithToken(t *testing.T, clientID, secret string) context.Context {
+	token, err := serviceauth.SignToken(clientID, secret, time.Minute)
+	require.NoError(t, err)
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+}
+
+func callUnary(i *ServiceAuthInterceptor, ctx context.Context, method string) (*serviceauth.Client, error) {
+	var seen *serviceauth.Client
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		seen, _ = serviceauth.FromContext(ctx)
+		return "ok", nil
+	}
+	_, err := i.Unary()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+	return seen, err
+}
+
+func TestServiceAuthInterceptor_AllowsAuthorizedClient(t *testing.T) {
+	limiter := &stubLimiter{result: ratelimit.Result{Allowed: true}}
+	interceptor := newTestInterceptor(t, limiter)
+
+	client, err := callUnary(interceptor, contextWithToken(t, "order-service", "order-secret"), validateMethod)
+
+	assert.NoError(t, err)
+	require.NotNil(t, client)
+	assert.Equal(t, "order-service", client.ID)
+	assert.Equal(t, []string{"service:order-service"}, limiter.keys)
+}
+
+func TestServiceAuthInterceptor_MissingToken(t *testing.T) {
+	interceptor := newTestInterceptor(t, &stubLimiter{result: ratelimit.Result{Allowed: true}})
+
+	_, err := callUnary(interceptor, context.Background(), validateMethod)
+
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestServiceAuthInterceptor_InvalidToken(t *testing.T) {
+	interceptor := newTestInterceptor(t, &stubLimiter{result: ratelimit.Result{Allowed: true}})
+
+	_, err := callUnary(interceptor, contextWithToken(t, "order-service", "wrong-secret"), validateMethod)
+
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestServiceAuthInterceptor_MethodNotAllowed(t *testing.T) {
+	interceptor := newTestInterceptor(t, &stubLimiter{result: ratelimit.Result{Allowed: true}})
+
+	_, err := callUnary(interceptor, contextWithToken(t, "order-service", "order-secret"), loginMethod)
+
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestServiceAuthInterceptor_RateLimited(t *testing.T) {
+	interceptor := newTestInterceptor(t, &stubLimiter{result: ratelimit.Result{Allowed: false, RetryAfter: 1500 * time.Millisecond}})
+
+	_, err := callUnary(interceptor, contextWithToken(t, "order-service", "order-secret"), validateMethod)
+
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestServiceAuthInterceptor_LimiterErrorFailsOpen(t *testing.T) {
+	interceptor := newTestInterceptor(t, &stubLimiter{err: errors.New("backend down")})
+
+	client, err := callUnary(interceptor, contextWithToken(t, "order-service", "order-secret"), validateMethod)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, client)
+}
+
+func TestServiceAuthInterceptor_ExemptMethod(t *testing.T) {
+	limiter := &stubLimiter{}
+	interceptor := newTestInterceptor(t, limiter)
+
+	_, err := callUnary(interceptor, context.Background(), healthMethod)
+
+	assert.NoError(t, err)
+	assert.Empty(t, limiter.keys)
+}
+
+// fakeServerStream is a minimal grpc.ServerStream for stream interceptor tests
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (f *fakeServerStream) Context() context.Context {
+	return f.ctx
+}
+
+func TestServiceAuthInterceptor_Stream(t *testing.T) {
+	interceptor := newTestInterceptor(t, &stubLimiter{result: ratelimit.Result{Allowed: true}})
+	stream := &fakeServerStream{ctx: contextWithToken(t, "order-service", "order-secret")}
+
+	var seen *serviceauth.Client
+	err := interceptor.Stream()(nil, stream, &grpc.StreamServerInfo{FullMethod: validateMethod}, func(srv interface{}, ss grpc.ServerStream) error {
+		seen, _ = serviceauth.FromContext(ss.Context())
+		return nil
+	})
+
+	assert.NoError(t, err)
+	require.NotNil(t, seen)
+	assert.Equal(t, "order-service", seen.ID)
+}
+
+func TestBearerToken(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "bearer abc.def"))
+	assert.Equal(t, "abc.def", bearerToken(ctx))
+
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Basic abc"))
+	assert.Equal(t, "", bearerToken(ctx))
+
+	assert.Equal(t, "", bearerToken(context.Background()))
+}
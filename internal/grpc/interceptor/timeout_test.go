@@ -0,0 +1,47 @@
+package interceptor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+func deadlineSeenByHandler(t *testing.T, ctx context.Context, timeout time.Duration) (time.Time, bool) {
+	var deadline time.Time
+	var ok bool
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		deadline, ok = ctx.Deadline()
+		return nil, nil
+	}
+
+	_, err := DefaultTimeout(timeout)(ctx, nil, &grpc.UnaryServerInfo{FullMethod: loginMethod}, handler)
+	assert.NoError(t, err)
+	return deadline, ok
+}
+
+func TestDefaultTimeout_AppliesWhenMissing(t *testing.T) {
+	deadline, ok := deadlineSeenByHandler(t, context.Background(), 5*time.Second)
+
+	assert.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(5*time.Second), deadline, time.Second)
+}
+
+func TestDefaultTimeout_KeepsClientDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	want, _ := ctx.Deadline()
+
+	deadline, ok := deadlineSeenByHandler(t, ctx, 5*time.Second)
+
+	assert.True(t, ok)
+	assert.Equal(t, want, deadline)
+}
+
+func TestDefaultTimeout_Disabled(t *testing.T) {
+	_, ok := deadlineSeenByHandler(t, context.Background(), 0)
+
+	assert.False(t, ok)
+}
@@ -0,0 +1,315 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: user_events.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type UserEventType int32
+
+const (
+	UserEventType_USER_EVENT_TYPE_UNSPECIFIED      UserEventType = 0
+	UserEventType_USER_EVENT_TYPE_LOGIN            UserEventType = 1
+	UserEventType_USER_EVENT_TYPE_LOGOUT           UserEventType = 2
+	UserEventType_USER_EVENT_TYPE_PASSWORD_CHANGED UserEventType = 3
+	UserEventType_USER_EVENT_TYPE_EMAIL_CHANGED    UserEventType = 4
+)
+
+// Enum value maps for UserEventType.
+var (
+	UserEventType_name = map[int32]string{
+		0: "USER_EVENT_TYPE_UNSPECIFIED",
+		1: "USER_EVENT_TYPE_LOGIN",
+		2: "USER_EVENT_TYPE_LOGOUT",
+		3: "USER_EVENT_TYPE_PASSWORD_CHANGED",
+		4: "USER_EVENT_TYPE_EMAIL_CHANGED",
+	}
+	UserEventType_value = map[string]int32{
+		"USER_EVENT_TYPE_UNSPECIFIED":      0,
+		"USER_EVENT_TYPE_LOGIN":            1,
+		"USER_EVENT_TYPE_LOGOUT":           2,
+		"USER_EVENT_TYPE_PASSWORD_CHANGED": 3,
+		"USER_EVENT_TYPE_EMAIL_CHANGED":    4,
+	}
+)
+
+func (x UserEventType) Enum() *UserEventType {
+	p := new(UserEventType)
+	*p = x
+	return p
+}
+
+func (x UserEventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (UserEventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_user_events_proto_enumTypes[0].Descriptor()
+}
+
+func (UserEventType) Type() protoreflect.EnumType {
+	return &file_user_events_proto_enumTypes[0]
+}
+
+func (x UserEventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use UserEventType.Descriptor instead.
+func (UserEventType) EnumDescriptor() ([]byte, []int) {
+	return file_user_events_proto_rawDescGZIP(), []int{0}
+}
+
+type WatchUserEventsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// resume_token of the last event the consumer processed; empty starts with new events only
+	ResumeToken string `protobuf:"bytes,1,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`
+	// event_types restricts the stream to these types; empty means all types
+	EventTypes []UserEventType `protobuf:"varint,2,rep,packed,name=event_types,json=eventTypes,proto3,enum=hub_investments.UserEventType" json:"event_types,omitempty"`
+	// user_ids restricts the stream to these users; empty means all users
+	UserIds       []string `protobuf:"bytes,3,rep,name=user_ids,json=userIds,proto3" json:"user_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchUserEventsRequest) Reset() {
+	*x = WatchUserEventsRequest{}
+	mi := &file_user_events_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchUserEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchUserEventsRequest) ProtoMessage() {}
+
+func (x *WatchUserEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_events_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchUserEventsRequest.ProtoReflect.Descriptor instead.
+func (*WatchUserEventsRequest) Descriptor() ([]byte, []int) {
+	return file_user_events_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *WatchUserEventsRequest) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
+func (x *WatchUserEventsRequest) GetEventTypes() []UserEventType {
+	if x != nil {
+		return x.EventTypes
+	}
+	return nil
+}
+
+func (x *WatchUserEventsRequest) GetUserIds() []string {
+	if x != nil {
+		return x.UserIds
+	}
+	return nil
+}
+
+type UserEvent struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	EventId    string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	Type       UserEventType          `protobuf:"varint,2,opt,name=type,proto3,enum=hub_investments.UserEventType" json:"type,omitempty"`
+	UserId     string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	OccurredAt int64                  `protobuf:"varint,4,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	// resume_token can be passed to WatchUserEvents to continue after this event
+	ResumeToken   string            `protobuf:"bytes,5,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`
+	Attributes    map[string]string `protobuf:"bytes,6,rep,name=attributes,proto3" json:"attributes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserEvent) Reset() {
+	*x = UserEvent{}
+	mi := &file_user_events_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserEvent) ProtoMessage() {}
+
+func (x *UserEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_user_events_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserEvent.ProtoReflect.Descriptor instead.
+func (*UserEvent) Descriptor() ([]byte, []int) {
+	return file_user_events_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *UserEvent) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *UserEvent) GetType() UserEventType {
+	if x != nil {
+		return x.Type
+	}
+	return UserEventType_USER_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *UserEvent) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UserEvent) GetOccurredAt() int64 {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return 0
+}
+
+func (x *UserEvent) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
+func (x *UserEvent) GetAttributes() map[string]string {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+var File_user_events_proto protoreflect.FileDescriptor
+
+const file_user_events_proto_rawDesc = "" +
+	"\n" +
+	"\x11user_events.proto\x12\x0fhub_investments\"\x97\x01\n" +
+	"\x16WatchUserEventsRequest\x12!\n" +
+	"\fresume_token\x18\x01 \x01(\tR\vresumeToken\x12?\n" +
+	"\vevent_types\x18\x02 \x03(\x0e2\x1e.hub_investments.UserEventTypeR\n" +
+	"eventTypes\x12\x19\n" +
+	"\buser_ids\x18\x03 \x03(\tR\auserIds\"\xc2\x02\n" +
+	"\tUserEvent\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\tR\aeventId\x122\n" +
+	"\x04type\x18\x02 \x01(\x0e2\x1e.hub_investments.UserEventTypeR\x04type\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x1f\n" +
+	"\voccurred_at\x18\x04 \x01(\x03R\n" +
+	"occurredAt\x12!\n" +
+	"\fresume_token\x18\x05 \x01(\tR\vresumeToken\x12J\n" +
+	"\n" +
+	"attributes\x18\x06 \x03(\v2*.hub_investments.UserEvent.AttributesEntryR\n" +
+	"attributes\x1a=\n" +
+	"\x0fAttributesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01*\xe7\x01\n" +
+	"\rUserEventType\x12\x1f\n" +
+	"\x1bUSER_EVENT_TYPE_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15USER_EVENT_TYPE_LOGIN\x10\x01\x12\x1a\n" +
+	"\x16USER_EVENT_TYPE_LOGOUT\x10\x02\x12$\n" +
+	" USER_EVENT_TYPE_PASSWORD_CHANGED\x10\x03\x12!\n" +
+	"\x1dUSER_EVENT_TYPE_EMAIL_CHANGED\x10\x04\"\x04\b\x05\x10\x06*\x16USER_EVENT_TYPE_LOCKED*\x17USER_EVENT_TYPE_DELETED2l\n" +
+	"\x10UserEventService\x12X\n" +
+	"\x0fWatchUserEvents\x12'.hub_investments.WatchUserEventsRequest\x1a\x1a.hub_investments.UserEvent0\x01B\tZ\a./protob\x06proto3"
+
+var (
+	file_user_events_proto_rawDescOnce sync.Once
+	file_user_events_proto_rawDescData []byte
+)
+
+func file_user_events_proto_rawDescGZIP() []byte {
+	file_user_events_proto_rawDescOnce.Do(func() {
+		file_user_events_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_user_events_proto_rawDesc), len(file_user_events_proto_rawDesc)))
+	})
+	return file_user_events_proto_rawDescData
+}
+
+var file_user_events_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_user_events_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_user_events_proto_goTypes = []any{
+	(UserEventType)(0),             // 0: hub_investments.UserEventType
+	(*WatchUserEventsRequest)(nil), // 1: hub_investments.WatchUserEventsRequest
+	(*UserEvent)(nil),              // 2: hub_investments.UserEvent
+	nil,                            // 3: hub_investments.UserEvent.AttributesEntry
+}
+var file_user_events_proto_depIdxs = []int32{
+	0, // 0: hub_investments.WatchUserEventsRequest.event_types:type_name -> hub_investments.UserEventType
+	0, // 1: hub_investments.UserEvent.type:type_name -> hub_investments.UserEventType
+	3, // 2: hub_investments.UserEvent.attributes:type_name -> hub_investments.UserEvent.AttributesEntry
+	1, // 3: hub_investments.UserEventService.WatchUserEvents:input_type -> hub_investments.WatchUserEventsRequest
+	2, // 4: hub_investments.UserEventService.WatchUserEvents:output_type -> hub_investments.UserEvent
+	4, // [4:5] is the sub-list for method output_type
+	3, // [3:4] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_user_events_proto_init() }
+func file_user_events_proto_init() {
+	if File_user_events_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_user_events_proto_rawDesc), len(file_user_events_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_user_events_proto_goTypes,
+		DependencyIndexes: file_user_events_proto_depIdxs,
+		EnumInfos:         file_user_events_proto_enumTypes,
+		MessageInfos:      file_user_events_proto_msgTypes,
+	}.Build()
+	File_user_events_proto = out.File
+	file_user_events_proto_goTypes = nil
+	file_user_events_proto_depIdxs = nil
+}
@@ -0,0 +1,132 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: user_events.proto
+
+package proto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	UserEventService_WatchUserEvents_FullMethodName = "/hub_investments.UserEventService/WatchUserEvents"
+)
+
+// UserEventServiceClient is the client API for UserEventService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// UserEventService streams user and session lifecycle events so downstream
+// services can invalidate cached user data without polling
+type UserEventServiceClient interface {
+	// WatchUserEvents streams events as they happen, optionally resuming after a previous event
+	WatchUserEvents(ctx context.Context, in *WatchUserEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[UserEvent], error)
+}
+
+type userEventServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewUserEventServiceClient(cc grpc.ClientConnInterface) UserEventServiceClient {
+	return &userEventServiceClient{cc}
+}
+
+func (c *userEventServiceClient) WatchUserEvents(ctx context.Context, in *WatchUserEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[UserEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &UserEventService_ServiceDesc.Streams[0], UserEventService_WatchUserEvents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchUserEventsRequest, UserEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type UserEventService_WatchUserEventsClient = grpc.ServerStreamingClient[UserEvent]
+
+// UserEventServiceServer is the server API for UserEventService service.
+// All implementations must embed UnimplementedUserEventServiceServer
+// for forward compatibility.
+//
+// UserEventService streams user and session lifecycle events so downstream
+// services can invalidate cached user data without polling
+type UserEventServiceServer interface {
+	// WatchUserEvents streams events as they happen, optionally resuming after a previous event
+	WatchUserEvents(*WatchUserEventsRequest, grpc.ServerStreamingServer[UserEvent]) error
+	mustEmbedUnimplementedUserEventServiceServer()
+}
+
+// UnimplementedUserEventServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedUserEventServiceServer struct{}
+
+func (UnimplementedUserEventServiceServer) WatchUserEvents(*WatchUserEventsRequest, grpc.ServerStreamingServer[UserEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchUserEvents not implemented")
+}
+func (UnimplementedUserEventServiceServer) mustEmbedUnimplementedUserEventServiceServer() {}
+func (UnimplementedUserEventServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeUserEventServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to UserEventServiceServer will
+// result in compilation errors.
+type UnsafeUserEventServiceServer interface {
+	mustEmbedUnimplementedUserEventServiceServer()
+}
+
+func RegisterUserEventServiceServer(s grpc.ServiceRegistrar, srv UserEventServiceServer) {
+	// If the following call pancis, it indicates UnimplementedUserEventServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&UserEventService_ServiceDesc, srv)
+}
+
+func _UserEventService_WatchUserEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchUserEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(UserEventServiceServer).WatchUserEvents(m, &grpc.GenericServerStream[WatchUserEventsRequest, UserEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type UserEventService_WatchUserEventsServer = grpc.ServerStreamingServer[UserEvent]
+
+// UserEventService_ServiceDesc is the grpc.ServiceDesc for UserEventService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var UserEventService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "hub_investments.UserEventService",
+	HandlerType: (*UserEventServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchUserEvents",
+			Handler:       _UserEventService_WatchUserEvents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "user_events.proto",
+}
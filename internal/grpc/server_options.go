@@ -0,0 +1,54 @@
+package grpc
+
+import (
+	"log"
+	"time"
+
+	"hub-user-service/internal/config"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+)
+
+// NewServerOptions builds the gRPC server options (keepalive, message limits, concurrency)
+// from configuration and logs the effective values
+func NewServerOptions(cfg *config.Config) []grpc.ServerOption {
+	serverParams := keepalive.ServerParameters{
+		Time:                  cfg.GRPCKeepaliveTime,
+		Timeout:               cfg.GRPCKeepaliveTimeout,
+		MaxConnectionIdle:     cfg.GRPCMaxConnectionIdle,
+		MaxConnectionAge:      cfg.GRPCMaxConnectionAge,
+		MaxConnectionAgeGrace: cfg.GRPCMaxConnectionAgeGrace,
+	}
+
+	enforcementPolicy := keepalive.EnforcementPolicy{
+		MinTime:             cfg.GRPCKeepaliveMinTime,
+		PermitWithoutStream: cfg.GRPCKeepalivePermitWithoutStream,
+	}
+
+	log.Printf("gRPC server options:")
+	log.Printf("  Keepalive: time=%s timeout=%s", serverParams.Time, serverParams.Timeout)
+	log.Printf("  Keepalive enforcement: min_time=%s permit_without_stream=%t", enforcementPolicy.MinTime, enforcementPolicy.PermitWithoutStream)
+	log.Printf("  Max connection idle: %s", durationOrNever(serverParams.MaxConnectionIdle))
+	log.Printf("  Max connection age: %s (grace %s)", durationOrNever(serverParams.MaxConnectionAge), durationOrNever(serverParams.MaxConnectionAgeGrace))
+	log.Printf("  Max message size: recv=%d send=%d bytes", cfg.GRPCMaxRecvMsgSize, cfg.GRPCMaxSendMsgSize)
+	log.Printf("  Max concurrent streams: %d", cfg.GRPCMaxConcurrentStreams)
+	log.Printf("  Connection timeout: %s", cfg.GRPCConnectionTimeout)
+
+	return []grpc.ServerOption{
+		grpc.KeepaliveParams(serverParams),
+		grpc.KeepaliveEnforcementPolicy(enforcementPolicy),
+		grpc.MaxRecvMsgSize(cfg.GRPCMaxRecvMsgSize),
+		grpc.MaxSendMsgSize(cfg.GRPCMaxSendMsgSize),
+		grpc.MaxConcurrentStreams(uint32(cfg.GRPCMaxConcurrentStreams)),
+		grpc.ConnectionTimeout(cfg.GRPCConnectionTimeout),
+	}
+}
+
+// durationOrNever formats zero durations, which gRPC treats as infinite, as "never"
+func durationOrNever(d time.Duration) string {
+	if d == 0 {
+		return "never"
+	}
+	return d.String()
+}
@@ -0,0 +1,68 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"hub-user-service/internal/config"
+	"hub-user-service/internal/grpc/proto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func testServerConfig() *config.Config {
+	return &config.Config{
+		GRPCKeepaliveTime:                time.Hour,
+		GRPCKeepaliveTimeout:             20 * time.Second,
+		GRPCKeepaliveMinTime:             30 * time.Second,
+		GRPCKeepalivePermitWithoutStream: true,
+		GRPCMaxConnectionAge:             30 * time.Minute,
+		GRPCMaxConnectionAgeGrace:        time.Minute,
+		GRPCMaxRecvMsgSize:               1024,
+		GRPCMaxSendMsgSize:               1024,
+		GRPCMaxConcurrentStreams:         10,
+		GRPCConnectionTimeout:            5 * time.Second,
+	}
+}
+
+func TestNewServerOptions(t *testing.T) {
+	options := NewServerOptions(testServerConfig())
+
+	assert.Len(t, options, 6)
+}
+
+func TestNewServerOptions_EnforcesMaxRecvMsgSize(t *testing.T) {
+	// Arrange
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(NewServerOptions(testServerConfig())...)
+	proto.RegisterAuthServiceServer(server, NewAuthServer(new(MockLoginUsecase), new(MockAuthService)))
+	go server.Serve(listener)
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Act - request larger than the 1 KiB receive limit
+	_, err = proto.NewAuthServiceClient(conn).ValidateToken(ctx, &proto.ValidateTokenRequest{
+		Token: strings.Repeat("x", 2048),
+	})
+
+	// Assert
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
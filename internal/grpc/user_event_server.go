@@ -0,0 +1,97 @@
+package grpc
+
+import (
+	"errors"
+	"log"
+
+	"hub-user-service/internal/events"
+	"hub-user-service/internal/grpc/proto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// eventTypeToProto maps domain event types to their protobuf enum values
+var eventTypeToProto = map[events.EventType]proto.UserEventType{
+	events.EventLogin:           proto.UserEventType_USER_EVENT_TYPE_LOGIN,
+	events.EventLogout:          proto.UserEventType_USER_EVENT_TYPE_LOGOUT,
+	events.EventPasswordChanged: proto.UserEventType_USER_EVENT_TYPE_PASSWORD_CHANGED,
+	events.EventEmailChanged:    proto.UserEventType_USER_EVENT_TYPE_EMAIL_CHANGED,
+}
+
+// UserEventServer implements the gRPC UserEventService interface
+type UserEventServer struct {
+	proto.UnimplementedUserEventServiceServer
+	stream events.Stream
+}
+
+// NewUserEventServer creates a new UserEventServer instance
+func NewUserEventServer(stream events.Stream) *UserEventServer {
+	return &UserEventServer{stream: stream}
+}
+
+// WatchUserEvents streams user lifecycle events until the client disconnects
+func (s *UserEventServer) WatchUserEvents(req *proto.WatchUserEventsRequest, stream proto.UserEventService_WatchUserEventsServer) error {
+	types := make([]events.EventType, 0, len(req.EventTypes))
+	for _, protoType := range req.EventTypes {
+		eventType, ok := eventTypeFromProto(protoType)
+		if !ok {
+			return status.Errorf(codes.InvalidArgument, "unsupported event type: %s", protoType)
+		}
+		types = append(types, eventType)
+	}
+
+	sub, err := s.stream.Subscribe(stream.Context(), req.ResumeToken, events.NewFilter(types, req.UserIds))
+	if err != nil {
+		switch {
+		case errors.Is(err, events.ErrResumeTokenExpired):
+			return status.Error(codes.OutOfRange, "resume token expired: resynchronise and watch without a resume token")
+		case errors.Is(err, events.ErrInvalidResumeToken):
+			return status.Error(codes.InvalidArgument, "invalid resume token")
+		default:
+			return status.Errorf(codes.Internal, "failed to subscribe: %v", err)
+		}
+	}
+	defer sub.Close()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event, ok := <-sub.Events():
+			if !ok {
+				err := sub.Err()
+				log.Printf("User event subscriber dropped: %v", err)
+				if errors.Is(err, events.ErrSubscriberTooSlow) {
+					return status.Error(codes.Unavailable, "subscriber fell behind: reconnect with the last resume token")
+				}
+				return status.Error(codes.Unavailable, "event stream interrupted: reconnect with the last resume token")
+			}
+			if err := stream.Send(s.toProto(event)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// toProto converts a domain event to its protobuf representation
+func (s *UserEventServer) toProto(event events.Event) *proto.UserEvent {
+	return &proto.UserEvent{
+		EventId:     event.ID,
+		Type:        eventTypeToProto[event.Type],
+		UserId:      event.UserID,
+		OccurredAt:  event.OccurredAt.Unix(),
+		ResumeToken: s.stream.ResumeToken(event),
+		Attributes:  event.Attributes,
+	}
+}
+
+// eventTypeFromProto maps a protobuf enum value to its domain event type
+func eventTypeFromProto(protoType proto.UserEventType) (events.EventType, bool) {
+	for eventType, candidate := range eventTypeToProto {
+		if candidate == protoType {
+			return eventType, true
+		}
+	}
+	return "", false
+}
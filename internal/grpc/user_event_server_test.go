@@ -0,0 +1,122 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"hub-user-service/internal/events"
+	"hub-user-service/internal/grpc/proto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeUserEventStream collects sent events and cancels once enough were received
+type fakeUserEventStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	cancel context.CancelFunc
+	want   int
+	sent   []*proto.UserEvent
+}
+
+func newFakeUserEventStream(want int) *fakeUserEventStream {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	return &fakeUserEventStream{ctx: ctx, cancel: cancel, want: want}
+}
+
+func (f *fakeUserEventStream) Context() context.Context {
+	return f.ctx
+}
+
+func (f *fakeUserEventStream) Send(event *proto.UserEvent) error {
+	f.sent = append(f.sent, event)
+	if len(f.sent) >= f.want {
+		f.cancel()
+	}
+	return nil
+}
+
+func TestUserEventServer_WatchUserEvents_ReplaysAndFilters(t *testing.T) {
+	broker := events.NewBroker(10, 10)
+	server := NewUserEventServer(broker)
+
+	// Capture a resume token for the first event
+	sub, _ := broker.Subscribe(context.Background(), "", events.Filter{})
+	broker.Publish(context.Background(), events.Event{Type: events.EventLogin, UserID: "user-1"})
+	first := <-sub.Events()
+	sub.Close()
+
+	broker.Publish(context.Background(), events.Event{Type: events.EventLogin, UserID: "user-2"})
+	broker.Publish(context.Background(), events.Event{Type: events.EventPasswordChanged, UserID: "user-1"})
+
+	stream := newFakeUserEventStream(1)
+	err := server.WatchUserEvents(&proto.WatchUserEventsRequest{
+		ResumeToken: broker.ResumeToken(first),
+		UserIds:     []string{"user-1"},
+	}, stream)
+
+	assert.NoError(t, err)
+	require.Len(t, stream.sent, 1)
+	assert.Equal(t, proto.UserEventType_USER_EVENT_TYPE_PASSWORD_CHANGED, stream.sent[0].Type)
+	assert.Equal(t, "user-1", stream.sent[0].UserId)
+	assert.NotEmpty(t, stream.sent[0].ResumeToken)
+	assert.NotZero(t, stream.sent[0].OccurredAt)
+}
+
+func TestUserEventServer_WatchUserEvents_LiveEvents(t *testing.T) {
+	broker := events.NewBroker(10, 10)
+	server := NewUserEventServer(broker)
+	stream := newFakeUserEventStream(1)
+
+	go func() {
+		// Publish until the subscriber is registered and receives the event
+		for stream.ctx.Err() == nil {
+			broker.Publish(context.Background(), events.Event{Type: events.EventLogout, UserID: "user-1"})
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	err := server.WatchUserEvents(&proto.WatchUserEventsRequest{
+		EventTypes: []proto.UserEventType{proto.UserEventType_USER_EVENT_TYPE_LOGOUT},
+	}, stream)
+
+	assert.NoError(t, err)
+	require.NotEmpty(t, stream.sent)
+	assert.Equal(t, proto.UserEventType_USER_EVENT_TYPE_LOGOUT, stream.sent[0].Type)
+}
+
+func TestUserEventServer_WatchUserEvents_Errors(t *testing.T) {
+	broker := events.NewBroker(1, 10)
+	server := NewUserEventServer(broker)
+
+	sub, _ := broker.Subscribe(context.Background(), "", events.Filter{})
+	broker.Publish(context.Background(), events.Event{Type: events.EventLogin, UserID: "user-1"})
+	first := <-sub.Events()
+	sub.Close()
+	broker.Publish(context.Background(), events.Event{Type: events.EventLogin, UserID: "user-1"})
+	broker.Publish(context.Background(), events.Event{Type: events.EventLogin, UserID: "user-1"})
+
+	tests := []struct {
+		name string
+		req  *proto.WatchUserEventsRequest
+		code codes.Code
+	}{
+		{"invalid token", &proto.WatchUserEventsRequest{ResumeToken: "not-a-token"}, codes.InvalidArgument},
+		{"expired token", &proto.WatchUserEventsRequest{ResumeToken: broker.ResumeToken(first)}, codes.OutOfRange},
+		{"unspecified type", &proto.WatchUserEventsRequest{
+			EventTypes: []proto.UserEventType{proto.UserEventType_USER_EVENT_TYPE_UNSPECIFIED},
+		}, codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := server.WatchUserEvents(tt.req, newFakeUserEventStream(1))
+			assert.Equal(t, tt.code, status.Code(err))
+		})
+	}
+}
@@ -1,26 +1,64 @@
 package usecase
 
 import (
+	"context"
 	"errors"
 	"hub-user-service/internal/login/domain/model"
 	"hub-user-service/internal/login/domain/repository"
+	"log"
+	"time"
 )
 
 type IDoLoginUsecase interface {
-	Execute(email string, password string) (*model.User, error)
+	// Execute only checks the credentials, as when re-entering the password to step up
+	Execute(ctx context.Context, email string, password string) (*model.User, error)
+	// Login checks the credentials and then decides through the login gate whether the login completes
+	Login(ctx context.Context, email string, password string, client LoginClient) (*model.User, LoginDecision, error)
 }
 
 type DoLoginUsecase struct {
 	repo repository.ILoginRepository
+	gate ILoginGateUsecase
+	now  func() time.Time
 }
 
-func NewDoLoginUsecase(repo repository.ILoginRepository) IDoLoginUsecase {
-	return &DoLoginUsecase{repo: repo}
+// DoLoginOption configures optional DoLoginUsecase collaborators
+type DoLoginOption func(*DoLoginUsecase)
+
+// WithLoginGate asks the gate for a second factor or a risk decision after the password is accepted
+func WithLoginGate(gate ILoginGateUsecase) DoLoginOption {
+	return func(u *DoLoginUsecase) {
+		u.gate = gate
+	}
+}
+
+func NewDoLoginUsecase(repo repository.ILoginRepository, opts ...DoLoginOption) IDoLoginUsecase {
+	usecase := &DoLoginUsecase{repo: repo, now: time.Now}
+	for _, opt := range opts {
+		opt(usecase)
+	}
+	return usecase
+}
+
+func (u *DoLoginUsecase) Login(ctx context.Context, email string, password string, client LoginClient) (*model.User, LoginDecision, error) {
+	user, err := u.Execute(ctx, email, password)
+	if err != nil {
+		return user, "", err
+	}
+	if u.gate == nil {
+		return user, LoginAllowed, nil
+	}
+
+	decision, err := u.gate.Check(ctx, FirstFactor{UserID: user.ID, Client: client, Time: u.now()})
+	if err != nil {
+		return user, "", err
+	}
+	return user, decision, nil
 }
 
-func (u *DoLoginUsecase) Execute(email string, password string) (*model.User, error) {
+func (u *DoLoginUsecase) Execute(ctx context.Context, email string, password string) (*model.User, error) {
 
-	user, err := u.repo.GetUserByEmail(email)
+	user, err := u.repo.GetUserByEmail(ctx, email)
 	if err != nil {
 		return &model.User{}, err
 	}
@@ -34,8 +72,23 @@ func (u *DoLoginUsecase) Execute(email string, password string) (*model.User, er
 	}
 
 	if !user.Password.EqualsString(password) {
+		u.recordLogin(ctx, user.ID, false)
 		return &model.User{}, errors.New("invalid password")
 	}
 
+	// Checked after the password so the account status is not disclosed to unauthenticated callers
+	if !user.IsActive() {
+		u.recordLogin(ctx, user.ID, false)
+		return &model.User{}, errors.New("account is not active")
+	}
+
+	u.recordLogin(ctx, user.ID, true)
 	return user, nil
 }
+
+// recordLogin updates the login timestamps of the user; a failure is logged and does not fail the login
+func (u *DoLoginUsecase) recordLogin(ctx context.Context, userID string, succeeded bool) {
+	if err := u.repo.RecordLogin(ctx, userID, succeeded, u.now()); err != nil {
+		log.Printf("Failed to record login of user %s: %v", userID, err)
+	}
+}
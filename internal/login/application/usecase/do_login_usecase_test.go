@@ -1,10 +1,12 @@
 package usecase
 
 import (
+	"context"
 	"errors"
 	"hub-user-service/internal/login/domain/model"
 	"hub-user-service/internal/login/domain/valueobject"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -14,11 +16,24 @@ type LoginRepositoryMock struct {
 	mock.Mock
 }
 
-func (l *LoginRepositoryMock) GetUserByEmail(email string) (*model.User, error) {
-	args := l.Called(email)
+func (l *LoginRepositoryMock) GetUserByEmail(ctx context.Context, email string) (*model.User, error) {
+	args := l.Called(ctx, email)
 	return args.Get(0).(*model.User), args.Error(1)
 }
 
+func (l *LoginRepositoryMock) GetUserByID(ctx context.Context, id string) (*model.User, error) {
+	args := l.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.User), args.Error(1)
+}
+
+func (l *LoginRepositoryMock) RecordLogin(ctx context.Context, id string, succeeded bool, at time.Time) error {
+	args := l.Called(ctx, id, succeeded, at)
+	return args.Error(0)
+}
+
 func TestDoLoginUsecase_Execute_Success(t *testing.T) {
 	// Arrange
 	repo := &LoginRepositoryMock{}
@@ -26,12 +41,14 @@ func TestDoLoginUsecase_Execute_Success(t *testing.T) {
 		Email:    valueobject.NewEmailFromRepository("myemail@myemail.com"),
 		ID:       "1",
 		Password: valueobject.NewPasswordFromRepository("123456"),
+		Status:   model.UserStatusActive,
 	}
-	repo.On("GetUserByEmail", "myemail@myemail.com").Return(expectedData, nil)
+	repo.On("GetUserByEmail", mock.Anything, "myemail@myemail.com").Return(expectedData, nil)
+	repo.On("RecordLogin", mock.Anything, "1", true, mock.Anything).Return(nil)
 	usecase := NewDoLoginUsecase(repo)
 
 	// Act
-	result, err := usecase.Execute("myemail@myemail.com", "123456")
+	result, err := usecase.Execute(context.Background(), "myemail@myemail.com", "123456")
 
 	// Assert
 	assert.NoError(t, err)
@@ -44,11 +61,11 @@ func TestDoLoginUsecase_Execute_Success(t *testing.T) {
 func TestDoLoginUsecase_Execute_UserNotFound(t *testing.T) {
 	// Arrange
 	repo := &LoginRepositoryMock{}
-	repo.On("GetUserByEmail", "notfound@myemail.com").Return((*model.User)(nil), errors.New("user not found"))
+	repo.On("GetUserByEmail", mock.Anything, "notfound@myemail.com").Return((*model.User)(nil), errors.New("user not found"))
 	usecase := NewDoLoginUsecase(repo)
 
 	// Act
-	result, err := usecase.Execute("notfound@myemail.com", "123456")
+	result, err := usecase.Execute(context.Background(), "notfound@myemail.com", "123456")
 
 	// Assert
 	assert.Error(t, err)
@@ -64,12 +81,14 @@ func TestDoLoginUsecase_Execute_InvalidPassword(t *testing.T) {
 		Email:    valueobject.NewEmailFromRepository("myemail@myemail.com"),
 		ID:       "1",
 		Password: valueobject.NewPasswordFromRepository("correctpassword"),
+		Status:   model.UserStatusActive,
 	}
-	repo.On("GetUserByEmail", "myemail@myemail.com").Return(userData, nil)
+	repo.On("GetUserByEmail", mock.Anything, "myemail@myemail.com").Return(userData, nil)
+	repo.On("RecordLogin", mock.Anything, "1", false, mock.Anything).Return(nil)
 	usecase := NewDoLoginUsecase(repo)
 
 	// Act
-	result, err := usecase.Execute("myemail@myemail.com", "wrongpassword")
+	result, err := usecase.Execute(context.Background(), "myemail@myemail.com", "wrongpassword")
 
 	// Assert
 	assert.Error(t, err)
@@ -78,14 +97,60 @@ func TestDoLoginUsecase_Execute_InvalidPassword(t *testing.T) {
 	repo.AssertExpectations(t)
 }
 
+func TestDoLoginUsecase_Execute_InactiveAccount(t *testing.T) {
+	for _, status := range []model.UserStatus{model.UserStatusLocked, model.UserStatusDisabled, model.UserStatusPendingVerification} {
+		t.Run(string(status), func(t *testing.T) {
+			// Arrange
+			repo := &LoginRepositoryMock{}
+			userData := &model.User{
+				Email:    valueobject.NewEmailFromRepository("myemail@myemail.com"),
+				ID:       "1",
+				Password: valueobject.NewPasswordFromRepository("123456"),
+				Status:   status,
+			}
+			repo.On("GetUserByEmail", mock.Anything, "myemail@myemail.com").Return(userData, nil)
+			repo.On("RecordLogin", mock.Anything, "1", false, mock.Anything).Return(nil)
+			usecase := NewDoLoginUsecase(repo)
+
+			// Act
+			result, err := usecase.Execute(context.Background(), "myemail@myemail.com", "123456")
+
+			// Assert
+			assert.EqualError(t, err, "account is not active")
+			assert.Equal(t, &model.User{}, result)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDoLoginUsecase_Execute_InactiveAccountWrongPassword(t *testing.T) {
+	// Arrange
+	repo := &LoginRepositoryMock{}
+	userData := &model.User{
+		Email:    valueobject.NewEmailFromRepository("myemail@myemail.com"),
+		ID:       "1",
+		Password: valueobject.NewPasswordFromRepository("123456"),
+		Status:   model.UserStatusLocked,
+	}
+	repo.On("GetUserByEmail", mock.Anything, "myemail@myemail.com").Return(userData, nil)
+	repo.On("RecordLogin", mock.Anything, "1", false, mock.Anything).Return(nil)
+	usecase := NewDoLoginUsecase(repo)
+
+	// Act
+	_, err := usecase.Execute(context.Background(), "myemail@myemail.com", "wrongpassword")
+
+	// Assert: the account status is not revealed without the correct password
+	assert.EqualError(t, err, "invalid password")
+}
+
 func TestDoLoginUsecase_Execute_NilUser(t *testing.T) {
 	// Arrange
 	repo := &LoginRepositoryMock{}
-	repo.On("GetUserByEmail", "myemail@myemail.com").Return((*model.User)(nil), nil)
+	repo.On("GetUserByEmail", mock.Anything, "myemail@myemail.com").Return((*model.User)(nil), nil)
 	usecase := NewDoLoginUsecase(repo)
 
 	// Act
-	result, err := usecase.Execute("myemail@myemail.com", "123456")
+	result, err := usecase.Execute(context.Background(), "myemail@myemail.com", "123456")
 
 	// Assert
 	assert.Error(t, err)
@@ -93,3 +158,42 @@ func TestDoLoginUsecase_Execute_NilUser(t *testing.T) {
 	assert.Equal(t, &model.User{}, result)
 	repo.AssertExpectations(t)
 }
+
+func TestDoLoginUsecase_Execute_PropagatesContext(t *testing.T) {
+	// Arrange
+	repo := &LoginRepositoryMock{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	repo.On("GetUserByEmail", ctx, "myemail@myemail.com").Return((*model.User)(nil), context.Canceled)
+	usecase := NewDoLoginUsecase(repo)
+
+	// Act
+	_, err := usecase.Execute(ctx, "myemail@myemail.com", "123456")
+
+	// Assert
+	assert.ErrorIs(t, err, context.Canceled)
+	repo.AssertExpectations(t)
+}
+
+func TestDoLoginUsecase_Execute_RecordLoginFailureDoesNotFailLogin(t *testing.T) {
+	// Arrange
+	repo := &LoginRepositoryMock{}
+	userData := &model.User{
+		Email:    valueobject.NewEmailFromRepository("myemail@myemail.com"),
+		ID:       "1",
+		Password: valueobject.NewPasswordFromRepository("123456"),
+		Status:   model.UserStatusActive,
+	}
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	repo.On("GetUserByEmail", mock.Anything, "myemail@myemail.com").Return(userData, nil)
+	repo.On("RecordLogin", mock.Anything, "1", true, now).Return(errors.New("database down"))
+	usecase := &DoLoginUsecase{repo: repo, now: func() time.Time { return now }}
+
+	// Act
+	result, err := usecase.Execute(context.Background(), "myemail@myemail.com", "123456")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "1", result.ID)
+	repo.AssertExpectations(t)
+}
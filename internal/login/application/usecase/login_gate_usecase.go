@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	riskUsecase "hub-user-service/internal/risk/application/usecase"
+	riskModel "hub-user-service/internal/risk/domain/model"
+)
+
+// ErrLoginCheck is returned when a login cannot be checked after its first factor was accepted
+var ErrLoginCheck = errors.New("failed to check login")
+
+// LoginDecision is how a login continues once its first factor was accepted
+type LoginDecision string
+
+const (
+	LoginAllowed  LoginDecision = "allow"
+	LoginNeedsMFA LoginDecision = "mfa"
+	LoginDenied   LoginDecision = "deny"
+)
+
+// LoginClient describes the client a login comes from
+type LoginClient struct {
+	IPAddress string
+	UserAgent string
+	Device    string
+}
+
+// FirstFactor is a login whose first factor (password, passkey or login code) was accepted
+type FirstFactor struct {
+	UserID string
+	// MultiFactor is set when the first factor already counts as multi-factor (a verified passkey)
+	MultiFactor bool
+	Client      LoginClient
+	Time        time.Time
+}
+
+// SecondFactors tells whether a user enrolled a second factor
+type SecondFactors interface {
+	IsEnabled(ctx context.Context, userID string) (bool, error)
+}
+
+// ILoginGateUsecase decides whether a login may complete, needs a second factor or is denied
+type ILoginGateUsecase interface {
+	Check(ctx context.Context, login FirstFactor) (LoginDecision, error)
+}
+
+type LoginGateUsecase struct {
+	secondFactors SecondFactors
+	risk          riskUsecase.IRiskUsecase
+}
+
+// NewLoginGateUsecase creates the login gate; without second factors no login steps up and
+// without risk no login is assessed
+func NewLoginGateUsecase(secondFactors SecondFactors, risk riskUsecase.IRiskUsecase) ILoginGateUsecase {
+	return &LoginGateUsecase{secondFactors: secondFactors, risk: risk}
+}
+
+// Check requires the second factor of enrolled users unless the first one is already multi-factor;
+// the risk assessment may then deny the login, or deny it instead of asking for a second factor
+// the user cannot give
+func (u *LoginGateUsecase) Check(ctx context.Context, login FirstFactor) (LoginDecision, error) {
+	stepUp := false
+	if u.secondFactors != nil && !login.MultiFactor {
+		enabled, err := u.secondFactors.IsEnabled(ctx, login.UserID)
+		if err != nil {
+			return "", fmt.Errorf("%w: MFA of user %s: %v", ErrLoginCheck, login.UserID, err)
+		}
+		stepUp = enabled
+	}
+
+	if u.risk != nil {
+		assessment, err := u.risk.Assess(ctx, riskModel.Login{
+			UserID:      login.UserID,
+			IPAddress:   login.Client.IPAddress,
+			UserAgent:   login.Client.UserAgent,
+			Device:      login.Client.Device,
+			MultiFactor: login.MultiFactor,
+			CanStepUp:   stepUp,
+			Time:        login.Time,
+		})
+		if err != nil {
+			return "", fmt.Errorf("%w: risk of user %s: %v", ErrLoginCheck, login.UserID, err)
+		}
+		if assessment.Decision == riskModel.DecisionDeny {
+			return LoginDenied, nil
+		}
+	}
+
+	if stepUp {
+		return LoginNeedsMFA, nil
+	}
+	return LoginAllowed, nil
+}
@@ -0,0 +1,166 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"hub-user-service/internal/login/domain/model"
+	"hub-user-service/internal/login/domain/valueobject"
+	riskModel "hub-user-service/internal/risk/domain/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type SecondFactorsMock struct {
+	mock.Mock
+}
+
+func (s *SecondFactorsMock) IsEnabled(ctx context.Context, userID string) (bool, error) {
+	args := s.Called(ctx, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+type RiskUsecaseMock struct {
+	mock.Mock
+}
+
+func (r *RiskUsecaseMock) Assess(ctx context.Context, login riskModel.Login) (*riskModel.Assessment, error) {
+	args := r.Called(ctx, login)
+	assessment, _ := args.Get(0).(*riskModel.Assessment)
+	return assessment, args.Error(1)
+}
+
+func TestLoginGateUsecase_Check(t *testing.T) {
+	at := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	login := FirstFactor{UserID: "1", Client: LoginClient{IPAddress: "203.0.113.7", UserAgent: "Mozilla/5.0", Device: "laptop"}, Time: at}
+
+	t.Run("allowed without second factors or risk", func(t *testing.T) {
+		decision, err := NewLoginGateUsecase(nil, nil).Check(context.Background(), login)
+
+		require.NoError(t, err)
+		assert.Equal(t, LoginAllowed, decision)
+	})
+
+	t.Run("enrolled users need the second factor", func(t *testing.T) {
+		secondFactors := &SecondFactorsMock{}
+		secondFactors.On("IsEnabled", mock.Anything, "1").Return(true, nil)
+
+		decision, err := NewLoginGateUsecase(secondFactors, nil).Check(context.Background(), login)
+
+		require.NoError(t, err)
+		assert.Equal(t, LoginNeedsMFA, decision)
+	})
+
+	t.Run("multi-factor first factor", func(t *testing.T) {
+		secondFactors := &SecondFactorsMock{}
+		multiFactor := login
+		multiFactor.MultiFactor = true
+
+		decision, err := NewLoginGateUsecase(secondFactors, nil).Check(context.Background(), multiFactor)
+
+		require.NoError(t, err)
+		assert.Equal(t, LoginAllowed, decision)
+		secondFactors.AssertNotCalled(t, "IsEnabled", mock.Anything, mock.Anything)
+	})
+
+	t.Run("assesses the client", func(t *testing.T) {
+		secondFactors := &SecondFactorsMock{}
+		risk := &RiskUsecaseMock{}
+		secondFactors.On("IsEnabled", mock.Anything, "1").Return(true, nil)
+		risk.On("Assess", mock.Anything, riskModel.Login{
+			UserID: "1", IPAddress: "203.0.113.7", UserAgent: "Mozilla/5.0", Device: "laptop", CanStepUp: true, Time: at,
+		}).Return(&riskModel.Assessment{Score: 40, Decision: riskModel.DecisionMFA}, nil)
+
+		decision, err := NewLoginGateUsecase(secondFactors, risk).Check(context.Background(), login)
+
+		require.NoError(t, err)
+		assert.Equal(t, LoginNeedsMFA, decision)
+		risk.AssertExpectations(t)
+	})
+
+	t.Run("denied", func(t *testing.T) {
+		secondFactors := &SecondFactorsMock{}
+		risk := &RiskUsecaseMock{}
+		secondFactors.On("IsEnabled", mock.Anything, "1").Return(true, nil)
+		risk.On("Assess", mock.Anything, mock.Anything).Return(&riskModel.Assessment{Score: 100, Decision: riskModel.DecisionDeny}, nil)
+
+		decision, err := NewLoginGateUsecase(secondFactors, risk).Check(context.Background(), login)
+
+		require.NoError(t, err)
+		assert.Equal(t, LoginDenied, decision)
+	})
+
+	t.Run("MFA check fails", func(t *testing.T) {
+		secondFactors := &SecondFactorsMock{}
+		secondFactors.On("IsEnabled", mock.Anything, "1").Return(false, assert.AnError)
+
+		_, err := NewLoginGateUsecase(secondFactors, nil).Check(context.Background(), login)
+
+		assert.ErrorIs(t, err, ErrLoginCheck)
+	})
+
+	t.Run("assessment fails", func(t *testing.T) {
+		risk := &RiskUsecaseMock{}
+		risk.On("Assess", mock.Anything, mock.Anything).Return(nil, assert.AnError)
+
+		_, err := NewLoginGateUsecase(nil, risk).Check(context.Background(), login)
+
+		assert.ErrorIs(t, err, ErrLoginCheck)
+	})
+}
+
+func TestDoLoginUsecase_Login(t *testing.T) {
+	user := &model.User{
+		Email:    valueobject.NewEmailFromRepository("myemail@myemail.com"),
+		ID:       "1",
+		Password: valueobject.NewPasswordFromRepository("123456"),
+		Status:   model.UserStatusActive,
+	}
+	client := LoginClient{IPAddress: "203.0.113.7"}
+
+	t.Run("consults the login gate", func(t *testing.T) {
+		repo := &LoginRepositoryMock{}
+		risk := &RiskUsecaseMock{}
+		repo.On("GetUserByEmail", mock.Anything, "myemail@myemail.com").Return(user, nil)
+		repo.On("RecordLogin", mock.Anything, "1", true, mock.Anything).Return(nil)
+		risk.On("Assess", mock.Anything, mock.MatchedBy(func(login riskModel.Login) bool {
+			return login.UserID == "1" && login.IPAddress == "203.0.113.7" && !login.MultiFactor && !login.Time.IsZero()
+		})).Return(&riskModel.Assessment{Score: 100, Decision: riskModel.DecisionDeny}, nil)
+
+		result, decision, err := NewDoLoginUsecase(repo, WithLoginGate(NewLoginGateUsecase(nil, risk))).
+			Login(context.Background(), "myemail@myemail.com", "123456", client)
+
+		require.NoError(t, err)
+		assert.Equal(t, "1", result.ID)
+		assert.Equal(t, LoginDenied, decision)
+		risk.AssertExpectations(t)
+	})
+
+	t.Run("allowed without a gate", func(t *testing.T) {
+		repo := &LoginRepositoryMock{}
+		repo.On("GetUserByEmail", mock.Anything, "myemail@myemail.com").Return(user, nil)
+		repo.On("RecordLogin", mock.Anything, "1", true, mock.Anything).Return(nil)
+
+		_, decision, err := NewDoLoginUsecase(repo).Login(context.Background(), "myemail@myemail.com", "123456", client)
+
+		require.NoError(t, err)
+		assert.Equal(t, LoginAllowed, decision)
+	})
+
+	t.Run("wrong password skips the gate", func(t *testing.T) {
+		repo := &LoginRepositoryMock{}
+		risk := &RiskUsecaseMock{}
+		repo.On("GetUserByEmail", mock.Anything, "myemail@myemail.com").Return(user, nil)
+		repo.On("RecordLogin", mock.Anything, "1", false, mock.Anything).Return(nil)
+
+		_, _, err := NewDoLoginUsecase(repo, WithLoginGate(NewLoginGateUsecase(nil, risk))).
+			Login(context.Background(), "myemail@myemail.com", "wrong", client)
+
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, ErrLoginCheck)
+		risk.AssertNotCalled(t, "Assess", mock.Anything, mock.Anything)
+	})
+}
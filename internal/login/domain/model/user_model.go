@@ -1,14 +1,34 @@
 package model
 
 import (
+	"time"
+
 	"hub-user-service/internal/login/domain/valueobject"
 )
 
+// UserStatus is the account status stored in users.status
+type UserStatus string
+
+const (
+	UserStatusActive              UserStatus = "active"
+	UserStatusLocked              UserStatus = "locked"
+	UserStatusDisabled            UserStatus = "disabled"
+	UserStatusPendingVerification UserStatus = "pending_verification"
+)
+
 // User represents a user entity in the domain
+// ID is the legacy integer id shared with the monolith (JWT userId claim);
+// PublicID is the stable UUID to expose to new consumers
 type User struct {
-	ID       string                `json:"id"`
-	Email    *valueobject.Email    `json:"email"`
-	Password *valueobject.Password `json:"-"` // Never serialize password
+	ID                string                `json:"id"`
+	PublicID          string                `json:"public_id,omitempty"`
+	Name              string                `json:"name,omitempty"`
+	Email             *valueobject.Email    `json:"email"`
+	Password          *valueobject.Password `json:"-"` // Never serialize password
+	Status            UserStatus            `json:"status"`
+	LastLoginAt       *time.Time            `json:"last_login_at,omitempty"`
+	LastFailedLoginAt *time.Time            `json:"last_failed_login_at,omitempty"`
+	CreatedAt         time.Time             `json:"created_at,omitempty"`
 }
 
 // NewUser creates a new User with validated email and password
@@ -27,6 +47,7 @@ func NewUser(id, email, password string) (*User, error) {
 		ID:       id,
 		Email:    emailVO,
 		Password: passwordVO,
+		Status:   UserStatusActive,
 	}, nil
 }
 
@@ -42,9 +63,15 @@ func NewUserFromRepository(id, email, password string) *User {
 		ID:       id,
 		Email:    emailVO,
 		Password: passwordVO,
+		Status:   UserStatusActive,
 	}
 }
 
+// IsActive reports whether the account is allowed to log in
+func (u *User) IsActive() bool {
+	return u.Status == UserStatusActive
+}
+
 // GetEmailString returns the email as a string for compatibility
 func (u *User) GetEmailString() string {
 	if u.Email == nil {
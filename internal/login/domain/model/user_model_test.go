@@ -95,6 +95,17 @@ func TestNewUser_InvalidPassword(t *testing.T) {
 	}
 }
 
+func TestUser_IsActive(t *testing.T) {
+	user := NewUserFromRepository("user123", "test@example.com", "hashed")
+	assert.Equal(t, UserStatusActive, user.Status)
+	assert.True(t, user.IsActive())
+
+	for _, status := range []UserStatus{UserStatusLocked, UserStatusDisabled, UserStatusPendingVerification, ""} {
+		user.Status = status
+		assert.False(t, user.IsActive(), "status %q", status)
+	}
+}
+
 func TestUser_GetEmailString(t *testing.T) {
 	user, err := NewUser("user123", "Test@Example.COM", "TestPass123!")
 
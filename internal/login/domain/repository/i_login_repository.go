@@ -1,7 +1,16 @@
 package repository
 
-import "hub-user-service/internal/login/domain/model"
+import (
+	"context"
+	"time"
+
+	"hub-user-service/internal/login/domain/model"
+)
 
 type ILoginRepository interface {
-	GetUserByEmail(email string) (*model.User, error)
+	GetUserByEmail(ctx context.Context, email string) (*model.User, error)
+	// GetUserByID loads a user authenticated by other means than the email, such as a passkey
+	GetUserByID(ctx context.Context, id string) (*model.User, error)
+	// RecordLogin sets the last successful or failed login time of the user
+	RecordLogin(ctx context.Context, id string, succeeded bool, at time.Time) error
 }
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"hub-user-service/internal/login/domain/model"
+)
+
+// IUserRepository writes users copied from the monolith during the cutover
+type IUserRepository interface {
+	// UpsertUser inserts user or updates the copied columns of the user with the same ID
+	UpsertUser(ctx context.Context, user *model.User) error
+	// GetUsersByIDs returns the users among ids that exist, in no particular order
+	GetUsersByIDs(ctx context.Context, ids []string) ([]*model.User, error)
+	// CountUsers returns the number of stored users
+	CountUsers(ctx context.Context) (int, error)
+	// SyncIDSequence moves the ID sequence past the largest copied ID
+	SyncIDSequence(ctx context.Context) error
+}
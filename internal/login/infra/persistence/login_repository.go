@@ -1,39 +1,91 @@
 package persistence
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"hub-user-service/internal/database"
 	"hub-user-service/internal/login/domain/model"
 	"hub-user-service/internal/login/domain/repository"
+	"time"
 )
 
 type LoginRepository struct {
-	db database.Database
+	db database.Querier
 }
 
 // userDTO represents the database structure for user data
 type userDTO struct {
-	ID       string `db:"id"`
-	Email    string `db:"email"`
-	Password string `db:"password"`
+	ID                string       `db:"id"`
+	UUID              string       `db:"uuid"`
+	Name              string       `db:"name"`
+	Email             string       `db:"email"`
+	Password          string       `db:"password"`
+	Status            string       `db:"status"`
+	LastLoginAt       sql.NullTime `db:"last_login_at"`
+	LastFailedLoginAt sql.NullTime `db:"last_failed_login_at"`
+	CreatedAt         sql.NullTime `db:"created_at"`
 }
 
-func NewLoginRepository(db database.Database) repository.ILoginRepository {
+// NewLoginRepository creates a login repository on a database or a transaction
+func NewLoginRepository(db database.Querier) repository.ILoginRepository {
 	return &LoginRepository{db: db}
 }
 
-func (l *LoginRepository) GetUserByEmail(email string) (*model.User, error) {
-	query := "SELECT id, email, password FROM users WHERE email = $1"
+func (l *LoginRepository) GetUserByEmail(ctx context.Context, email string) (*model.User, error) {
+	query := "SELECT id, uuid, name, email, password, status, last_login_at, last_failed_login_at FROM users WHERE email = $1"
 
 	var userDB userDTO
-	err := l.db.Get(&userDB, query, email)
+	err := l.db.GetContext(ctx, &userDB, query, email)
 
 	if err != nil {
 		return nil, fmt.Errorf("user not found or database error: %w", err)
 	}
 
-	// Convert DTO to domain model without validation (data comes from trusted database)
-	user := model.NewUserFromRepository(userDB.ID, userDB.Email, userDB.Password)
+	return userDB.toModel(), nil
+}
+
+func (l *LoginRepository) GetUserByID(ctx context.Context, id string) (*model.User, error) {
+	query := "SELECT id, uuid, name, email, password, status, last_login_at, last_failed_login_at FROM users WHERE id = $1"
 
-	return user, nil
+	var userDB userDTO
+	if err := l.db.GetContext(ctx, &userDB, query, id); err != nil {
+		return nil, fmt.Errorf("user not found or database error: %w", err)
+	}
+
+	return userDB.toModel(), nil
+}
+
+func (l *LoginRepository) RecordLogin(ctx context.Context, id string, succeeded bool, at time.Time) error {
+	query := "UPDATE users SET last_failed_login_at = $2 WHERE id = $1"
+	if succeeded {
+		query = "UPDATE users SET last_login_at = $2 WHERE id = $1"
+	}
+
+	if _, err := l.db.ExecContext(ctx, query, id, at); err != nil {
+		return fmt.Errorf("failed to record login: %w", err)
+	}
+	return nil
+}
+
+// toModel converts the DTO to the domain model without validation (data comes from trusted database)
+func (d userDTO) toModel() *model.User {
+	user := model.NewUserFromRepository(d.ID, d.Email, d.Password)
+	user.PublicID = d.UUID
+	user.Name = d.Name
+	user.Status = model.UserStatus(d.Status)
+	user.LastLoginAt = nullTimePtr(d.LastLoginAt)
+	user.LastFailedLoginAt = nullTimePtr(d.LastFailedLoginAt)
+	if d.CreatedAt.Valid {
+		user.CreatedAt = d.CreatedAt.Time
+	}
+	return user
+}
+
+// nullTimePtr converts a nullable timestamp column to an optional time
+func nullTimePtr(value sql.NullTime) *time.Time {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Time
 }
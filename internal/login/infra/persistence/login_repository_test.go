@@ -5,10 +5,13 @@ import (
 	"database/sql"
 	"errors"
 	"hub-user-service/internal/database"
+	"hub-user-service/internal/login/domain/model"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockDatabase is a simplified mock that only implements the methods we need for testing
@@ -16,8 +19,8 @@ type MockDatabase struct {
 	mock.Mock
 }
 
-func (m *MockDatabase) Get(dest interface{}, query string, args ...interface{}) error {
-	mockArgs := m.Called(dest, query, args)
+func (m *MockDatabase) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	mockArgs := m.Called(ctx, dest, query, args)
 
 	// Check if we have a second argument with data to return
 	if len(mockArgs) > 1 && mockArgs.Get(1) != nil {
@@ -46,15 +49,23 @@ func (m *MockDatabase) Exec(query string, args ...interface{}) (database.Result,
 	return nil, nil
 }
 func (m *MockDatabase) ExecContext(ctx context.Context, query string, args ...interface{}) (database.Result, error) {
-	return nil, nil
+	mockArgs := m.Called(ctx, query, args)
+	return nil, mockArgs.Error(0)
 }
 func (m *MockDatabase) Begin() (database.Transaction, error) { return nil, nil }
 func (m *MockDatabase) BeginTx(ctx context.Context, opts *sql.TxOptions) (database.Transaction, error) {
 	return nil, nil
 }
+func (m *MockDatabase) Get(dest interface{}, query string, args ...interface{}) error    { return nil }
 func (m *MockDatabase) Select(dest interface{}, query string, args ...interface{}) error { return nil }
-func (m *MockDatabase) Ping() error                                                      { return nil }
-func (m *MockDatabase) Close() error                                                     { return nil }
+func (m *MockDatabase) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return nil
+}
+func (m *MockDatabase) Ping() error  { return nil }
+func (m *MockDatabase) Close() error { return nil }
+func (m *MockDatabase) Stats() sql.DBStats {
+	return sql.DBStats{}
+}
 
 func TestNewLoginRepository(t *testing.T) {
 	// Arrange
@@ -80,11 +91,12 @@ func TestLoginRepository_GetUserByEmail_Success(t *testing.T) {
 		Password: "hashedpassword123",
 	}
 
-	expectedQuery := "SELECT id, email, password FROM users WHERE email = $1"
+	expectedQuery := "SELECT id, uuid, name, email, password, status, last_login_at, last_failed_login_at FROM users WHERE email = $1"
 	expectedArgs := []interface{}{email}
 
 	// Mock successful database query
-	mockDB.On("Get",
+	mockDB.On("GetContext",
+		mock.Anything,
 		mock.AnythingOfType("*persistence.userDTO"),
 		expectedQuery,
 		expectedArgs,
@@ -93,7 +105,7 @@ func TestLoginRepository_GetUserByEmail_Success(t *testing.T) {
 	repo := NewLoginRepository(mockDB)
 
 	// Act
-	result, err := repo.GetUserByEmail(email)
+	result, err := repo.GetUserByEmail(context.Background(), email)
 
 	// Assert
 	assert.NoError(t, err)
@@ -103,17 +115,47 @@ func TestLoginRepository_GetUserByEmail_Success(t *testing.T) {
 	assert.NotNil(t, result.Password)
 }
 
+func TestLoginRepository_GetUserByID(t *testing.T) {
+	mockDB := &MockDatabase{}
+	defer mockDB.AssertExpectations(t)
+
+	expectedQuery := "SELECT id, uuid, name, email, password, status, last_login_at, last_failed_login_at FROM users WHERE id = $1"
+	mockDB.On("GetContext", mock.Anything, mock.AnythingOfType("*persistence.userDTO"), expectedQuery, []interface{}{"user123"}).
+		Return(nil, userDTO{ID: "user123", Email: "test@example.com", Password: "hashedpassword123", Status: "active"})
+
+	result, err := NewLoginRepository(mockDB).GetUserByID(context.Background(), "user123")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "user123", result.ID)
+	assert.Equal(t, "test@example.com", result.GetEmailString())
+}
+
+func TestLoginRepository_RecordLogin(t *testing.T) {
+	mockDB := &MockDatabase{}
+	defer mockDB.AssertExpectations(t)
+	at := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	mockDB.On("ExecContext", mock.Anything, "UPDATE users SET last_login_at = $2 WHERE id = $1", []interface{}{"user123", at}).Return(nil)
+	mockDB.On("ExecContext", mock.Anything, "UPDATE users SET last_failed_login_at = $2 WHERE id = $1", []interface{}{"user123", at}).Return(errors.New("connection refused"))
+	repo := NewLoginRepository(mockDB)
+
+	assert.NoError(t, repo.RecordLogin(context.Background(), "user123", true, at))
+	err := repo.RecordLogin(context.Background(), "user123", false, at)
+	assert.ErrorContains(t, err, "failed to record login")
+}
+
 func TestLoginRepository_GetUserByEmail_UserNotFound(t *testing.T) {
 	// Arrange
 	mockDB := &MockDatabase{}
 	defer mockDB.AssertExpectations(t)
 
 	email := "nonexistent@example.com"
-	expectedQuery := "SELECT id, email, password FROM users WHERE email = $1"
+	expectedQuery := "SELECT id, uuid, name, email, password, status, last_login_at, last_failed_login_at FROM users WHERE email = $1"
 	expectedArgs := []interface{}{email}
 
 	// Mock database returning no rows (user not found)
-	mockDB.On("Get",
+	mockDB.On("GetContext",
+		mock.Anything,
 		mock.AnythingOfType("*persistence.userDTO"),
 		expectedQuery,
 		expectedArgs,
@@ -122,7 +164,7 @@ func TestLoginRepository_GetUserByEmail_UserNotFound(t *testing.T) {
 	repo := NewLoginRepository(mockDB)
 
 	// Act
-	result, err := repo.GetUserByEmail(email)
+	result, err := repo.GetUserByEmail(context.Background(), email)
 
 	// Assert
 	assert.Error(t, err)
@@ -137,11 +179,12 @@ func TestLoginRepository_GetUserByEmail_DatabaseError(t *testing.T) {
 
 	email := "test@example.com"
 	databaseError := errors.New("connection timeout")
-	expectedQuery := "SELECT id, email, password FROM users WHERE email = $1"
+	expectedQuery := "SELECT id, uuid, name, email, password, status, last_login_at, last_failed_login_at FROM users WHERE email = $1"
 	expectedArgs := []interface{}{email}
 
 	// Mock database error
-	mockDB.On("Get",
+	mockDB.On("GetContext",
+		mock.Anything,
 		mock.AnythingOfType("*persistence.userDTO"),
 		expectedQuery,
 		expectedArgs,
@@ -150,7 +193,7 @@ func TestLoginRepository_GetUserByEmail_DatabaseError(t *testing.T) {
 	repo := NewLoginRepository(mockDB)
 
 	// Act
-	result, err := repo.GetUserByEmail(email)
+	result, err := repo.GetUserByEmail(context.Background(), email)
 
 	// Assert
 	assert.Error(t, err)
@@ -165,11 +208,12 @@ func TestLoginRepository_GetUserByEmail_EmptyEmail(t *testing.T) {
 	defer mockDB.AssertExpectations(t)
 
 	email := ""
-	expectedQuery := "SELECT id, email, password FROM users WHERE email = $1"
+	expectedQuery := "SELECT id, uuid, name, email, password, status, last_login_at, last_failed_login_at FROM users WHERE email = $1"
 	expectedArgs := []interface{}{email}
 
 	// Mock database returning no rows for empty email
-	mockDB.On("Get",
+	mockDB.On("GetContext",
+		mock.Anything,
 		mock.AnythingOfType("*persistence.userDTO"),
 		expectedQuery,
 		expectedArgs,
@@ -178,7 +222,7 @@ func TestLoginRepository_GetUserByEmail_EmptyEmail(t *testing.T) {
 	repo := NewLoginRepository(mockDB)
 
 	// Act
-	result, err := repo.GetUserByEmail(email)
+	result, err := repo.GetUserByEmail(context.Background(), email)
 
 	// Assert
 	assert.Error(t, err)
@@ -192,11 +236,12 @@ func TestLoginRepository_GetUserByEmail_InvalidEmailFormat(t *testing.T) {
 	defer mockDB.AssertExpectations(t)
 
 	email := "invalid-email-format"
-	expectedQuery := "SELECT id, email, password FROM users WHERE email = $1"
+	expectedQuery := "SELECT id, uuid, name, email, password, status, last_login_at, last_failed_login_at FROM users WHERE email = $1"
 	expectedArgs := []interface{}{email}
 
 	// Mock database returning no rows for invalid email
-	mockDB.On("Get",
+	mockDB.On("GetContext",
+		mock.Anything,
 		mock.AnythingOfType("*persistence.userDTO"),
 		expectedQuery,
 		expectedArgs,
@@ -205,7 +250,7 @@ func TestLoginRepository_GetUserByEmail_InvalidEmailFormat(t *testing.T) {
 	repo := NewLoginRepository(mockDB)
 
 	// Act
-	result, err := repo.GetUserByEmail(email)
+	result, err := repo.GetUserByEmail(context.Background(), email)
 
 	// Assert
 	assert.Error(t, err)
@@ -225,11 +270,12 @@ func TestLoginRepository_GetUserByEmail_DTOToModelMapping(t *testing.T) {
 		Password: "hashed_password_value",
 	}
 
-	expectedQuery := "SELECT id, email, password FROM users WHERE email = $1"
+	expectedQuery := "SELECT id, uuid, name, email, password, status, last_login_at, last_failed_login_at FROM users WHERE email = $1"
 	expectedArgs := []interface{}{email}
 
 	// Mock successful database query
-	mockDB.On("Get",
+	mockDB.On("GetContext",
+		mock.Anything,
 		mock.AnythingOfType("*persistence.userDTO"),
 		expectedQuery,
 		expectedArgs,
@@ -238,7 +284,7 @@ func TestLoginRepository_GetUserByEmail_DTOToModelMapping(t *testing.T) {
 	repo := NewLoginRepository(mockDB)
 
 	// Act
-	result, err := repo.GetUserByEmail(email)
+	result, err := repo.GetUserByEmail(context.Background(), email)
 
 	// Assert
 	assert.NoError(t, err)
@@ -254,6 +300,44 @@ func TestLoginRepository_GetUserByEmail_DTOToModelMapping(t *testing.T) {
 	assert.Equal(t, "hashed_password_value", result.GetPasswordString())
 }
 
+func TestLoginRepository_GetUserByEmail_MapsAccountColumns(t *testing.T) {
+	// Arrange
+	mockDB := &MockDatabase{}
+	defer mockDB.AssertExpectations(t)
+
+	lastLogin := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	expectedDTO := userDTO{
+		ID:          "42",
+		UUID:        "0b5a4a4e-3f3c-4d8e-9a57-1f3f0c7e9d21",
+		Name:        "Ada Lovelace",
+		Email:       "ada@example.com",
+		Password:    "hashed",
+		Status:      "locked",
+		LastLoginAt: sql.NullTime{Time: lastLogin, Valid: true},
+	}
+
+	mockDB.On("GetContext",
+		mock.Anything,
+		mock.AnythingOfType("*persistence.userDTO"),
+		mock.Anything,
+		[]interface{}{"ada@example.com"},
+	).Return(nil, expectedDTO)
+
+	repo := NewLoginRepository(mockDB)
+
+	// Act
+	result, err := repo.GetUserByEmail(context.Background(), "ada@example.com")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "42", result.ID)
+	assert.Equal(t, "0b5a4a4e-3f3c-4d8e-9a57-1f3f0c7e9d21", result.PublicID)
+	assert.Equal(t, "Ada Lovelace", result.Name)
+	assert.Equal(t, model.UserStatusLocked, result.Status)
+	assert.Equal(t, &lastLogin, result.LastLoginAt)
+	assert.Nil(t, result.LastFailedLoginAt)
+}
+
 func TestLoginRepository_GetUserByEmail_QueryStructure(t *testing.T) {
 	// Arrange
 	mockDB := &MockDatabase{}
@@ -267,11 +351,12 @@ func TestLoginRepository_GetUserByEmail_QueryStructure(t *testing.T) {
 	}
 
 	// Verify the exact query structure
-	expectedQuery := "SELECT id, email, password FROM users WHERE email = $1"
+	expectedQuery := "SELECT id, uuid, name, email, password, status, last_login_at, last_failed_login_at FROM users WHERE email = $1"
 	expectedArgs := []interface{}{email}
 
 	// Mock successful database query with exact expectations
-	mockDB.On("Get",
+	mockDB.On("GetContext",
+		mock.Anything,
 		mock.AnythingOfType("*persistence.userDTO"),
 		expectedQuery,
 		expectedArgs,
@@ -280,10 +365,63 @@ func TestLoginRepository_GetUserByEmail_QueryStructure(t *testing.T) {
 	repo := NewLoginRepository(mockDB)
 
 	// Act
-	result, err := repo.GetUserByEmail(email)
+	result, err := repo.GetUserByEmail(context.Background(), email)
 
 	// Assert
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
 	assert.Equal(t, "query123", result.ID)
 }
+
+func TestLoginRepository_GetUserByEmail_PropagatesContext(t *testing.T) {
+	// Arrange
+	mockDB := &MockDatabase{}
+	defer mockDB.AssertExpectations(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	mockDB.On("GetContext",
+		ctx,
+		mock.AnythingOfType("*persistence.userDTO"),
+		"SELECT id, uuid, name, email, password, status, last_login_at, last_failed_login_at FROM users WHERE email = $1",
+		[]interface{}{"test@example.com"},
+	).Return(context.DeadlineExceeded)
+
+	repo := NewLoginRepository(mockDB)
+
+	// Act
+	result, err := repo.GetUserByEmail(ctx, "test@example.com")
+
+	// Assert
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestLoginRepository_SQLite(t *testing.T) {
+	db, err := database.NewConnectionFactory(database.ConnectionConfig{Driver: "sqlite", SQLitePath: database.SQLiteInMemory}).CreateConnection()
+	require.NoError(t, err)
+	defer db.Close()
+	ctx := context.Background()
+
+	user := model.NewUserFromRepository("42", "dev@example.com", "DevPass123!")
+	user.Name = "Dev"
+	require.NoError(t, NewUserRepository(db).UpsertUser(ctx, user))
+
+	repo := NewLoginRepository(db)
+	at := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, repo.RecordLogin(ctx, "42", true, at))
+
+	found, err := repo.GetUserByEmail(ctx, "dev@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "42", found.ID)
+	assert.Equal(t, "Dev", found.Name)
+	assert.NotEmpty(t, found.PublicID)
+	assert.True(t, found.IsActive())
+	require.NotNil(t, found.LastLoginAt)
+	assert.True(t, at.Equal(*found.LastLoginAt))
+	assert.Nil(t, found.LastFailedLoginAt)
+
+	_, err = repo.GetUserByID(ctx, "43")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
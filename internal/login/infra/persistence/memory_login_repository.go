@@ -0,0 +1,173 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"hub-user-service/internal/login/domain/model"
+	"hub-user-service/internal/login/domain/valueobject"
+)
+
+// MemoryLoginRepository keeps users in process memory (DB_DRIVER=memory)
+// It is meant for tests and local development; data is lost on restart
+type MemoryLoginRepository struct {
+	mu    sync.RWMutex
+	users map[string]model.User
+}
+
+// memoryUserRecord is the seed file representation of a user
+type memoryUserRecord struct {
+	ID       string `json:"id"`
+	PublicID string `json:"public_id"`
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	Status   string `json:"status"`
+}
+
+// NewMemoryLoginRepository creates an in-memory repository holding users
+func NewMemoryLoginRepository(users ...*model.User) *MemoryLoginRepository {
+	repo := &MemoryLoginRepository{users: make(map[string]model.User)}
+	for _, user := range users {
+		repo.Save(user)
+	}
+	return repo
+}
+
+// LoadMemoryLoginRepository creates an in-memory repository seeded from a JSON file (see LoadSeedUsers)
+func LoadMemoryLoginRepository(path string) (*MemoryLoginRepository, error) {
+	users, err := LoadSeedUsers(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewMemoryLoginRepository(users...), nil
+}
+
+// LoadSeedUsers reads development users from a JSON file
+// of the form {"users": [{"id": "1", "email": "...", "password": "...", "status": "active"}]}
+func LoadSeedUsers(path string) ([]*model.User, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read users seed file: %w", err)
+	}
+
+	var seed struct {
+		Users []memoryUserRecord `json:"users"`
+	}
+	if err := json.Unmarshal(data, &seed); err != nil {
+		return nil, fmt.Errorf("failed to parse users seed file: %w", err)
+	}
+
+	users := make([]*model.User, 0, len(seed.Users))
+	for i, record := range seed.Users {
+		if record.ID == "" || record.Email == "" || record.Password == "" {
+			return nil, fmt.Errorf("seed user %d: id, email and password are required", i)
+		}
+
+		user := model.NewUserFromRepository(record.ID, record.Email, record.Password)
+		user.PublicID = record.PublicID
+		user.Name = record.Name
+		if record.Status != "" {
+			user.Status = model.UserStatus(record.Status)
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+// Save inserts or replaces the user with the same email
+func (r *MemoryLoginRepository) Save(user *model.User) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.users[user.GetEmailString()] = copyUser(user)
+}
+
+// Len returns the number of stored users
+func (r *MemoryLoginRepository) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.users)
+}
+
+// GetUserByEmail returns a copy of the user, mirroring the PostgreSQL repository errors
+func (r *MemoryLoginRepository) GetUserByEmail(ctx context.Context, email string) (*model.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("user not found or database error: %w", err)
+	}
+
+	r.mu.RLock()
+	user, ok := r.users[email]
+	r.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("user not found or database error: %w", sql.ErrNoRows)
+	}
+
+	found := copyUser(&user)
+	return &found, nil
+}
+
+// GetUserByID returns a copy of the user with the given ID
+func (r *MemoryLoginRepository) GetUserByID(ctx context.Context, id string) (*model.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("user not found or database error: %w", err)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, user := range r.users {
+		if user.ID == id {
+			found := copyUser(&user)
+			return &found, nil
+		}
+	}
+	return nil, fmt.Errorf("user not found or database error: %w", sql.ErrNoRows)
+}
+
+// RecordLogin sets the last successful or failed login time of the user with the given ID
+func (r *MemoryLoginRepository) RecordLogin(ctx context.Context, id string, succeeded bool, at time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("failed to record login: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for email, user := range r.users {
+		if user.ID != id {
+			continue
+		}
+		if succeeded {
+			user.LastLoginAt = &at
+		} else {
+			user.LastFailedLoginAt = &at
+		}
+		r.users[email] = user
+		return nil
+	}
+	return nil
+}
+
+// copyUser copies a user so callers cannot modify stored state
+func copyUser(user *model.User) model.User {
+	copied := *user
+	if user.Email != nil {
+		copied.Email = valueobject.NewEmailFromRepository(user.Email.Value())
+	}
+	if user.Password != nil {
+		copied.Password = valueobject.NewPasswordFromRepository(user.Password.Value())
+	}
+	if user.LastLoginAt != nil {
+		lastLogin := *user.LastLoginAt
+		copied.LastLoginAt = &lastLogin
+	}
+	if user.LastFailedLoginAt != nil {
+		lastFailed := *user.LastFailedLoginAt
+		copied.LastFailedLoginAt = &lastFailed
+	}
+	return copied
+}
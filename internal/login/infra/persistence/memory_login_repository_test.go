@@ -0,0 +1,109 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"hub-user-service/internal/login/domain/model"
+	"hub-user-service/internal/login/domain/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryLoginRepository_GetUserByEmail(t *testing.T) {
+	repo := NewMemoryLoginRepository(model.NewUserFromRepository("1", "dev@example.com", "DevPass123!"))
+	var _ repository.ILoginRepository = repo
+
+	user, err := repo.GetUserByEmail(context.Background(), "dev@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "1", user.ID)
+	assert.Equal(t, "DevPass123!", user.GetPasswordString())
+	assert.True(t, user.IsActive())
+
+	_, err = repo.GetUserByEmail(context.Background(), "missing@example.com")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.Contains(t, err.Error(), "user not found")
+}
+
+func TestMemoryLoginRepository_GetUserByID(t *testing.T) {
+	repo := NewMemoryLoginRepository(model.NewUserFromRepository("1", "dev@example.com", "DevPass123!"))
+
+	user, err := repo.GetUserByID(context.Background(), "1")
+	require.NoError(t, err)
+	assert.Equal(t, "dev@example.com", user.GetEmailString())
+
+	_, err = repo.GetUserByID(context.Background(), "2")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestMemoryLoginRepository_RecordLogin(t *testing.T) {
+	repo := NewMemoryLoginRepository(model.NewUserFromRepository("1", "dev@example.com", "DevPass123!"))
+	succeeded := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	failed := succeeded.Add(time.Minute)
+
+	require.NoError(t, repo.RecordLogin(context.Background(), "1", true, succeeded))
+	require.NoError(t, repo.RecordLogin(context.Background(), "1", false, failed))
+
+	user, err := repo.GetUserByID(context.Background(), "1")
+	require.NoError(t, err)
+	assert.Equal(t, succeeded, *user.LastLoginAt)
+	assert.Equal(t, failed, *user.LastFailedLoginAt)
+}
+
+func TestMemoryLoginRepository_ReturnsCopies(t *testing.T) {
+	repo := NewMemoryLoginRepository(model.NewUserFromRepository("1", "dev@example.com", "DevPass123!"))
+
+	user, err := repo.GetUserByEmail(context.Background(), "dev@example.com")
+	require.NoError(t, err)
+	user.Status = model.UserStatusLocked
+
+	stored, err := repo.GetUserByEmail(context.Background(), "dev@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, model.UserStatusActive, stored.Status)
+}
+
+func TestMemoryLoginRepository_HonorsContext(t *testing.T) {
+	repo := NewMemoryLoginRepository(model.NewUserFromRepository("1", "dev@example.com", "DevPass123!"))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := repo.GetUserByEmail(ctx, "dev@example.com")
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestLoadMemoryLoginRepository(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"users": [
+		{"id": "1", "public_id": "0b5a4a4e-3f3c-4d8e-9a57-1f3f0c7e9d21", "name": "Dev", "email": "dev@example.com", "password": "DevPass123!"},
+		{"id": "2", "email": "locked@example.com", "password": "DevPass123!", "status": "locked"}
+	]}`), 0o600))
+
+	repo, err := LoadMemoryLoginRepository(path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, repo.Len())
+
+	dev, err := repo.GetUserByEmail(context.Background(), "dev@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "0b5a4a4e-3f3c-4d8e-9a57-1f3f0c7e9d21", dev.PublicID)
+	assert.Equal(t, "Dev", dev.Name)
+	assert.Equal(t, model.UserStatusActive, dev.Status)
+
+	locked, err := repo.GetUserByEmail(context.Background(), "locked@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, model.UserStatusLocked, locked.Status)
+}
+
+func TestLoadMemoryLoginRepository_Errors(t *testing.T) {
+	_, err := LoadMemoryLoginRepository(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+
+	path := filepath.Join(t.TempDir(), "users.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"users": [{"id": "1", "email": "dev@example.com"}]}`), 0o600))
+	_, err = LoadMemoryLoginRepository(path)
+	assert.ErrorContains(t, err, "password")
+}
@@ -0,0 +1,74 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"hub-user-service/internal/database"
+	"hub-user-service/internal/login/domain/model"
+	"hub-user-service/internal/login/domain/repository"
+
+	"github.com/lib/pq"
+)
+
+type UserRepository struct {
+	db database.Querier
+}
+
+// NewUserRepository creates a user repository on a database or a transaction
+func NewUserRepository(db database.Querier) repository.IUserRepository {
+	return &UserRepository{db: db}
+}
+
+// UpsertUser keeps the monolith id so JWT userId claims stay valid. Status and login timestamps
+// belong to this service and are never overwritten; rows whose copied columns are unchanged are
+// not touched, so updated_at only moves when the source changed. A missing source created_at is
+// stored as NULL rather than the current time, so repeated runs compare equal.
+func (u *UserRepository) UpsertUser(ctx context.Context, user *model.User) error {
+	query := `INSERT INTO users (id, email, name, password, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (id) DO UPDATE SET
+			email = EXCLUDED.email, name = EXCLUDED.name, password = EXCLUDED.password, created_at = EXCLUDED.created_at
+		WHERE (users.email, users.name, users.password, users.created_at)
+			IS DISTINCT FROM (EXCLUDED.email, EXCLUDED.name, EXCLUDED.password, EXCLUDED.created_at)`
+
+	var createdAt interface{}
+	if !user.CreatedAt.IsZero() {
+		createdAt = user.CreatedAt
+	}
+
+	if _, err := u.db.ExecContext(ctx, query, user.ID, user.GetEmailString(), user.Name, user.GetPasswordString(), createdAt); err != nil {
+		return fmt.Errorf("failed to upsert user %s: %w", user.ID, err)
+	}
+	return nil
+}
+
+func (u *UserRepository) GetUsersByIDs(ctx context.Context, ids []string) ([]*model.User, error) {
+	query := "SELECT id, uuid, name, email, password, status, last_login_at, last_failed_login_at, created_at FROM users WHERE id = ANY($1::bigint[])"
+
+	var rows []userDTO
+	if err := u.db.SelectContext(ctx, &rows, query, pq.Array(ids)); err != nil {
+		return nil, fmt.Errorf("failed to load users: %w", err)
+	}
+
+	users := make([]*model.User, len(rows))
+	for i, row := range rows {
+		users[i] = row.toModel()
+	}
+	return users, nil
+}
+
+func (u *UserRepository) CountUsers(ctx context.Context) (int, error) {
+	var count int
+	if err := u.db.GetContext(ctx, &count, "SELECT COUNT(*) FROM users"); err != nil {
+		return 0, fmt.Errorf("failed to count users: %w", err)
+	}
+	return count, nil
+}
+
+func (u *UserRepository) SyncIDSequence(ctx context.Context) error {
+	query := "SELECT setval(pg_get_serial_sequence('users', 'id'), GREATEST(MAX(id), 1)) FROM users"
+	if _, err := u.db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to sync users id sequence: %w", err)
+	}
+	return nil
+}
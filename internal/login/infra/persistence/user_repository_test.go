@@ -0,0 +1,88 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"hub-user-service/internal/database"
+	"hub-user-service/internal/login/domain/model"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingQuerier records statements and answers SelectContext with rows
+type recordingQuerier struct {
+	database.Querier
+	query string
+	args  []interface{}
+	rows  []userDTO
+	err   error
+}
+
+func (q *recordingQuerier) ExecContext(ctx context.Context, query string, args ...interface{}) (database.Result, error) {
+	q.query, q.args = query, args
+	return nil, q.err
+}
+
+func (q *recordingQuerier) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	q.query, q.args = query, args
+	*dest.(*[]userDTO) = q.rows
+	return q.err
+}
+
+func TestUserRepository_UpsertUser(t *testing.T) {
+	db := &recordingQuerier{}
+	user := model.NewUserFromRepository("42", "ada@example.com", "hashed")
+	user.Name = "Ada"
+	user.CreatedAt = time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	err := NewUserRepository(db).UpsertUser(context.Background(), user)
+
+	require.NoError(t, err)
+	assert.Contains(t, db.query, "ON CONFLICT (id) DO UPDATE")
+	assert.NotContains(t, db.query, "status =", "status is owned by this service")
+	assert.Equal(t, []interface{}{"42", "ada@example.com", "Ada", "hashed", user.CreatedAt}, db.args)
+}
+
+func TestUserRepository_UpsertUser_KeepsMissingCreatedAt(t *testing.T) {
+	db := &recordingQuerier{}
+
+	err := NewUserRepository(db).UpsertUser(context.Background(), model.NewUserFromRepository("1", "a@example.com", "h"))
+
+	// A default such as CURRENT_TIMESTAMP would differ from the source on every run
+	require.NoError(t, err)
+	assert.Nil(t, db.args[4])
+	assert.NotContains(t, db.query, "CURRENT_TIMESTAMP")
+}
+
+func TestUserRepository_UpsertUser_WrapsErrors(t *testing.T) {
+	db := &recordingQuerier{err: &pq.Error{Code: "23505"}}
+
+	err := NewUserRepository(db).UpsertUser(context.Background(), model.NewUserFromRepository("7", "a@example.com", "h"))
+
+	var pqErr *pq.Error
+	assert.True(t, errors.As(err, &pqErr))
+	assert.Contains(t, err.Error(), "user 7")
+}
+
+func TestUserRepository_GetUsersByIDs(t *testing.T) {
+	created := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	db := &recordingQuerier{rows: []userDTO{
+		{ID: "1", Email: "a@example.com", Password: "h", Status: "active", CreatedAt: sql.NullTime{Time: created, Valid: true}},
+		{ID: "3", Email: "c@example.com", Password: "h", Status: "locked"},
+	}}
+
+	users, err := NewUserRepository(db).GetUsersByIDs(context.Background(), []string{"1", "2", "3"})
+
+	require.NoError(t, err)
+	require.Len(t, users, 2)
+	assert.Equal(t, created, users[0].CreatedAt)
+	assert.Equal(t, model.UserStatusLocked, users[1].Status)
+	assert.True(t, strings.HasSuffix(db.query, "WHERE id = ANY($1::bigint[])"))
+	assert.Equal(t, pq.Array([]string{"1", "2", "3"}), db.args[0])
+}
@@ -0,0 +1,225 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+
+	loginModel "hub-user-service/internal/login/domain/model"
+	loginRepository "hub-user-service/internal/login/domain/repository"
+	"hub-user-service/internal/logincode/domain/model"
+	"hub-user-service/internal/logincode/domain/repository"
+	"hub-user-service/internal/notification"
+	"hub-user-service/internal/ratelimit"
+)
+
+const (
+	// DeliveryCode emails a 6-digit code typed into the login form
+	DeliveryCode = "code"
+	// DeliveryLink emails a magic link carrying a random token
+	DeliveryLink = "link"
+
+	codeDigits = 6
+	linkBytes  = 32
+)
+
+var (
+	// ErrInvalidCode is returned for a wrong, expired, replaced or already used code
+	ErrInvalidCode = errors.New("invalid or expired login code")
+	// ErrTooManyRequests is returned once too many codes were requested for the email
+	ErrTooManyRequests = errors.New("too many login codes requested, try again later")
+	// ErrTooManyAttempts is returned once too many codes were checked for the email
+	ErrTooManyAttempts = errors.New("too many attempts, try again later")
+)
+
+type ILoginCodeUsecase interface {
+	// RequestCode emails a new login code to the user, invalidating the previous one. Unknown and
+	// inactive accounts get no email but the same result, so the call does not reveal accounts.
+	RequestCode(ctx context.Context, email string) error
+	// VerifyCode consumes the code and returns the authenticated user
+	VerifyCode(ctx context.Context, email string, code string) (*loginModel.User, error)
+}
+
+// LoginCodeConfig configures LoginCodeUsecase
+type LoginCodeConfig struct {
+	// Delivery is DeliveryCode or DeliveryLink
+	Delivery string
+	// LinkURL is the web app page receiving magic links; email and code are added to its query
+	LinkURL string
+	// TTL is the lifetime of a code
+	TTL time.Duration
+	// Limiter bounds the codes requested and checked per email; nil disables the limits
+	Limiter ratelimit.Limiter
+	// MaxRequests codes are sent per RequestWindow
+	MaxRequests   int
+	RequestWindow time.Duration
+	// MaxAttempts codes are checked per TTL, and a code is deleted after MaxAttempts wrong guesses
+	// so it cannot be guessed over its lifetime however many replicas share the limiter
+	MaxAttempts int
+}
+
+type LoginCodeUsecase struct {
+	users  loginRepository.ILoginRepository
+	codes  repository.ILoginCodeRepository
+	sender notification.Sender
+	config LoginCodeConfig
+	now    func() time.Time
+}
+
+// NewLoginCodeUsecase creates the passwordless login use case sending codes through sender
+func NewLoginCodeUsecase(users loginRepository.ILoginRepository, codes repository.ILoginCodeRepository, sender notification.Sender, config LoginCodeConfig) ILoginCodeUsecase {
+	return &LoginCodeUsecase{users: users, codes: codes, sender: sender, config: config, now: time.Now}
+}
+
+func (u *LoginCodeUsecase) RequestCode(ctx context.Context, email string) error {
+	if err := u.allow(ctx, "login-code:request:", email, u.config.MaxRequests, u.config.RequestWindow, ErrTooManyRequests); err != nil {
+		return err
+	}
+
+	user, err := u.users.GetUserByEmail(ctx, email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !user.IsActive() {
+		return nil
+	}
+
+	code, err := u.generate()
+	if err != nil {
+		return err
+	}
+	now := u.now()
+	err = u.codes.SaveLoginCode(ctx, &model.LoginCode{
+		UserID:    user.ID,
+		CodeHash:  hashCode(user.ID, code),
+		ExpiresAt: now.Add(u.config.TTL),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return err
+	}
+
+	message, err := u.message(user.GetEmailString(), code)
+	if err != nil {
+		return err
+	}
+	return u.sender.Send(ctx, message)
+}
+
+func (u *LoginCodeUsecase) VerifyCode(ctx context.Context, email string, code string) (*loginModel.User, error) {
+	if err := u.allow(ctx, "login-code:verify:", email, u.config.MaxAttempts, u.config.TTL, ErrTooManyAttempts); err != nil {
+		return nil, err
+	}
+
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return nil, ErrInvalidCode
+	}
+	user, err := u.users.GetUserByEmail(ctx, email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrInvalidCode
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	consumed, err := u.codes.ConsumeLoginCode(ctx, user.ID, hashCode(user.ID, code), u.now())
+	if err != nil {
+		return nil, err
+	}
+	if !consumed {
+		if u.config.MaxAttempts > 0 {
+			if _, err := u.codes.RecordFailedAttempt(ctx, user.ID, u.config.MaxAttempts); err != nil {
+				return nil, err
+			}
+		}
+		return nil, ErrInvalidCode
+	}
+	// Codes are only sent to active accounts, but the account may have been locked since
+	if !user.IsActive() {
+		return nil, ErrInvalidCode
+	}
+	return user, nil
+}
+
+// generate returns a random 6-digit code or magic link token
+func (u *LoginCodeUsecase) generate() (string, error) {
+	if u.config.Delivery == DeliveryLink {
+		token := make([]byte, linkBytes)
+		if _, err := rand.Read(token); err != nil {
+			return "", fmt.Errorf("failed to generate login token: %w", err)
+		}
+		return base64.RawURLEncoding.EncodeToString(token), nil
+	}
+
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return "", fmt.Errorf("failed to generate login code: %w", err)
+	}
+	return fmt.Sprintf("%0*d", codeDigits, n.Int64()), nil
+}
+
+// message builds the email carrying code
+func (u *LoginCodeUsecase) message(email string, code string) (notification.Message, error) {
+	minutes := int(u.config.TTL.Round(time.Minute) / time.Minute)
+	if u.config.Delivery != DeliveryLink {
+		return notification.Message{
+			To:      email,
+			Subject: "Your Hub Investments login code",
+			Body: fmt.Sprintf("Your login code is %s\n\nIt expires in %d minutes. If you did not try to log in, ignore this email.",
+				code, minutes),
+		}, nil
+	}
+
+	link, err := url.Parse(u.config.LinkURL)
+	if err != nil {
+		return notification.Message{}, fmt.Errorf("invalid login link URL: %w", err)
+	}
+	query := link.Query()
+	query.Set("email", email)
+	query.Set("code", code)
+	link.RawQuery = query.Encode()
+	return notification.Message{
+		To:      email,
+		Subject: "Your Hub Investments login link",
+		Body: fmt.Sprintf("Log in to Hub Investments with this link:\n\n%s\n\nIt expires in %d minutes and works once. If you did not try to log in, ignore this email.",
+			link.String(), minutes),
+	}, nil
+}
+
+// allow takes one call from the email's budget, returning limited once it is exhausted
+func (u *LoginCodeUsecase) allow(ctx context.Context, prefix string, email string, max int, window time.Duration, limited error) error {
+	if u.config.Limiter == nil || max <= 0 || window <= 0 {
+		return nil
+	}
+
+	limit := ratelimit.Limit{
+		Rate:  float64(max) / window.Seconds(),
+		Burst: max,
+	}
+	result, err := u.config.Limiter.Allow(ctx, prefix+strings.ToLower(strings.TrimSpace(email)), limit)
+	if err != nil {
+		return err
+	}
+	if !result.Allowed {
+		return limited
+	}
+	return nil
+}
+
+// hashCode binds code to the user, so a stored hash is useless for another account
+func hashCode(userID string, code string) []byte {
+	sum := sha256.Sum256([]byte(userID + ":" + code))
+	return sum[:]
+}
@@ -0,0 +1,232 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	loginModel "hub-user-service/internal/login/domain/model"
+	loginPersistence "hub-user-service/internal/login/infra/persistence"
+	"hub-user-service/internal/logincode/infra/persistence"
+	"hub-user-service/internal/notification"
+	"hub-user-service/internal/ratelimit"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testNow = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+// outbox keeps the sent messages
+type outbox struct {
+	messages []notification.Message
+	err      error
+}
+
+func (o *outbox) Send(ctx context.Context, message notification.Message) error {
+	if o.err != nil {
+		return o.err
+	}
+	o.messages = append(o.messages, message)
+	return nil
+}
+
+// last returns the code in the last message
+func (o *outbox) last(t *testing.T) string {
+	t.Helper()
+	require.NotEmpty(t, o.messages)
+	return regexp.MustCompile(`code is (\d{6})`).FindStringSubmatch(o.messages[len(o.messages)-1].Body)[1]
+}
+
+type loginCodeFixture struct {
+	usecase *LoginCodeUsecase
+	users   *loginPersistence.MemoryLoginRepository
+	outbox  *outbox
+}
+
+func newFixture(configure func(*LoginCodeConfig)) *loginCodeFixture {
+	config := LoginCodeConfig{Delivery: DeliveryCode, TTL: 10 * time.Minute}
+	if configure != nil {
+		configure(&config)
+	}
+
+	users := loginPersistence.NewMemoryLoginRepository(loginModel.NewUserFromRepository("42", "ada@example.com", "DevPass123!"))
+	sent := &outbox{}
+	uc := NewLoginCodeUsecase(users, persistence.NewMemoryLoginCodeRepository(), sent, config).(*LoginCodeUsecase)
+	uc.now = func() time.Time { return testNow }
+	return &loginCodeFixture{usecase: uc, users: users, outbox: sent}
+}
+
+func TestLoginCodeUsecase_CodeLogin(t *testing.T) {
+	f := newFixture(nil)
+	ctx := context.Background()
+
+	require.NoError(t, f.usecase.RequestCode(ctx, "ada@example.com"))
+	require.Len(t, f.outbox.messages, 1)
+	assert.Equal(t, "ada@example.com", f.outbox.messages[0].To)
+	assert.Contains(t, f.outbox.messages[0].Body, "expires in 10 minutes")
+	code := f.outbox.last(t)
+
+	_, err := f.usecase.VerifyCode(ctx, "ada@example.com", "000000")
+	if code != "000000" {
+		assert.ErrorIs(t, err, ErrInvalidCode)
+	}
+
+	user, err := f.usecase.VerifyCode(ctx, "ada@example.com", " "+code+" ")
+	require.NoError(t, err)
+	assert.Equal(t, "42", user.ID)
+
+	_, err = f.usecase.VerifyCode(ctx, "ada@example.com", code)
+	assert.ErrorIs(t, err, ErrInvalidCode, "a code is accepted once")
+}
+
+func TestLoginCodeUsecase_MagicLink(t *testing.T) {
+	f := newFixture(func(c *LoginCodeConfig) {
+		c.Delivery = DeliveryLink
+		c.LinkURL = "https://app.hubinvestments.com/login/link?source=email"
+	})
+	ctx := context.Background()
+
+	require.NoError(t, f.usecase.RequestCode(ctx, "ada@example.com"))
+	require.Len(t, f.outbox.messages, 1)
+	link, err := url.Parse(regexp.MustCompile(`https://\S+`).FindString(f.outbox.messages[0].Body))
+	require.NoError(t, err)
+	assert.Equal(t, "/login/link", link.Path)
+	assert.Equal(t, "email", link.Query().Get("source"))
+	assert.Equal(t, "ada@example.com", link.Query().Get("email"))
+	token := link.Query().Get("code")
+	assert.Len(t, token, 43)
+
+	user, err := f.usecase.VerifyCode(ctx, link.Query().Get("email"), token)
+	require.NoError(t, err)
+	assert.Equal(t, "42", user.ID)
+}
+
+func TestLoginCodeUsecase_NewCodeReplacesPrevious(t *testing.T) {
+	f := newFixture(nil)
+	ctx := context.Background()
+
+	require.NoError(t, f.usecase.RequestCode(ctx, "ada@example.com"))
+	first := f.outbox.last(t)
+	require.NoError(t, f.usecase.RequestCode(ctx, "ada@example.com"))
+	second := f.outbox.last(t)
+
+	if first != second {
+		_, err := f.usecase.VerifyCode(ctx, "ada@example.com", first)
+		assert.ErrorIs(t, err, ErrInvalidCode)
+	}
+	_, err := f.usecase.VerifyCode(ctx, "ada@example.com", second)
+	assert.NoError(t, err)
+}
+
+func TestLoginCodeUsecase_ExpiredCode(t *testing.T) {
+	f := newFixture(nil)
+	ctx := context.Background()
+	require.NoError(t, f.usecase.RequestCode(ctx, "ada@example.com"))
+
+	f.usecase.now = func() time.Time { return testNow.Add(10 * time.Minute) }
+	_, err := f.usecase.VerifyCode(ctx, "ada@example.com", f.outbox.last(t))
+	assert.ErrorIs(t, err, ErrInvalidCode)
+}
+
+func TestLoginCodeUsecase_DoesNotRevealAccounts(t *testing.T) {
+	f := newFixture(nil)
+	ctx := context.Background()
+	locked := loginModel.NewUserFromRepository("7", "locked@example.com", "DevPass123!")
+	locked.Status = loginModel.UserStatusLocked
+	f.users.Save(locked)
+
+	assert.NoError(t, f.usecase.RequestCode(ctx, "nobody@example.com"))
+	assert.NoError(t, f.usecase.RequestCode(ctx, "locked@example.com"))
+	assert.Empty(t, f.outbox.messages)
+
+	_, err := f.usecase.VerifyCode(ctx, "nobody@example.com", "123456")
+	assert.ErrorIs(t, err, ErrInvalidCode)
+}
+
+func TestLoginCodeUsecase_RejectsAccountLockedAfterRequest(t *testing.T) {
+	f := newFixture(nil)
+	ctx := context.Background()
+	require.NoError(t, f.usecase.RequestCode(ctx, "ada@example.com"))
+
+	user, err := f.users.GetUserByEmail(ctx, "ada@example.com")
+	require.NoError(t, err)
+	user.Status = loginModel.UserStatusLocked
+	f.users.Save(user)
+
+	_, err = f.usecase.VerifyCode(ctx, "ada@example.com", f.outbox.last(t))
+	assert.ErrorIs(t, err, ErrInvalidCode)
+}
+
+func TestLoginCodeUsecase_LimitsRequestsAndAttempts(t *testing.T) {
+	f := newFixture(func(c *LoginCodeConfig) {
+		c.Limiter = ratelimit.NewMemoryLimiter()
+		c.MaxRequests = 2
+		c.RequestWindow = time.Hour
+		c.MaxAttempts = 2
+	})
+	ctx := context.Background()
+
+	require.NoError(t, f.usecase.RequestCode(ctx, "ada@example.com"))
+	require.NoError(t, f.usecase.RequestCode(ctx, "ADA@example.com "))
+	assert.ErrorIs(t, f.usecase.RequestCode(ctx, "ada@example.com"), ErrTooManyRequests, "the limit ignores case")
+	assert.NoError(t, f.usecase.RequestCode(ctx, "nobody@example.com"), "emails have separate limits")
+
+	code := f.outbox.last(t)
+	wrong := "000000"
+	if code == wrong {
+		wrong = "111111"
+	}
+	_, err := f.usecase.VerifyCode(ctx, "ada@example.com", wrong)
+	assert.ErrorIs(t, err, ErrInvalidCode)
+	_, err = f.usecase.VerifyCode(ctx, "ada@example.com", wrong)
+	assert.ErrorIs(t, err, ErrInvalidCode)
+	_, err = f.usecase.VerifyCode(ctx, "ada@example.com", code)
+	assert.ErrorIs(t, err, ErrTooManyAttempts)
+}
+
+func TestLoginCodeUsecase_DeletesCodeAfterMaxAttempts(t *testing.T) {
+	// Without a limiter, e.g. when every replica has its own buckets, the code itself runs out
+	f := newFixture(func(c *LoginCodeConfig) {
+		c.MaxAttempts = 3
+	})
+	ctx := context.Background()
+	require.NoError(t, f.usecase.RequestCode(ctx, "ada@example.com"))
+
+	code := f.outbox.last(t)
+	wrong := "000000"
+	if code == wrong {
+		wrong = "111111"
+	}
+	for i := 0; i < 3; i++ {
+		_, err := f.usecase.VerifyCode(ctx, "ada@example.com", wrong)
+		assert.ErrorIs(t, err, ErrInvalidCode)
+	}
+	_, err := f.usecase.VerifyCode(ctx, "ada@example.com", code)
+	assert.ErrorIs(t, err, ErrInvalidCode, "the right code no longer works")
+
+	require.NoError(t, f.usecase.RequestCode(ctx, "ada@example.com"))
+	_, err = f.usecase.VerifyCode(ctx, "ada@example.com", f.outbox.last(t))
+	assert.NoError(t, err, "a new code gets a fresh budget")
+}
+
+func TestLoginCodeUsecase_SendFailure(t *testing.T) {
+	f := newFixture(nil)
+	f.outbox.err = errors.New("smtp unavailable")
+
+	assert.Error(t, f.usecase.RequestCode(context.Background(), "ada@example.com"))
+}
+
+func TestLoginCodeUsecase_GeneratesSixDigitCodes(t *testing.T) {
+	uc := newFixture(nil).usecase
+	for i := 0; i < 100; i++ {
+		code, err := uc.generate()
+		require.NoError(t, err)
+		assert.Len(t, code, codeDigits)
+		assert.Empty(t, strings.Trim(code, "0123456789"))
+	}
+}
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// LoginCode is the one-time code (or magic link token) emailed to a user for passwordless login
+type LoginCode struct {
+	UserID string
+	// CodeHash is the SHA-256 hash of the code bound to the user; the code itself is never stored
+	CodeHash  []byte
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	// FailedAttempts counts the wrong codes checked since the code was sent
+	FailedAttempts int
+}
+
+// IsExpired reports whether the code can no longer be used
+func (c *LoginCode) IsExpired(now time.Time) bool {
+	return !now.Before(c.ExpiresAt)
+}
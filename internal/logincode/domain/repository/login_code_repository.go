@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"hub-user-service/internal/logincode/domain/model"
+)
+
+type ILoginCodeRepository interface {
+	// SaveLoginCode stores the user's code, replacing the previous one so only the latest code works
+	SaveLoginCode(ctx context.Context, code *model.LoginCode) error
+	// ConsumeLoginCode atomically removes the user's unexpired code with this hash, so each code
+	// is accepted once; it returns false if there is none
+	ConsumeLoginCode(ctx context.Context, userID string, hash []byte, now time.Time) (bool, error)
+	// RecordFailedAttempt counts a wrong code checked against the user's code and deletes the code
+	// once maxAttempts are reached; it returns true when the code was deleted
+	RecordFailedAttempt(ctx context.Context, userID string, maxAttempts int) (bool, error)
+}
@@ -0,0 +1,68 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"hub-user-service/internal/database"
+	"hub-user-service/internal/logincode/domain/model"
+	"hub-user-service/internal/logincode/domain/repository"
+)
+
+type LoginCodeRepository struct {
+	db database.Querier
+}
+
+// NewLoginCodeRepository creates a login code repository on a database or a transaction
+func NewLoginCodeRepository(db database.Querier) repository.ILoginCodeRepository {
+	return &LoginCodeRepository{db: db}
+}
+
+func (r *LoginCodeRepository) SaveLoginCode(ctx context.Context, code *model.LoginCode) error {
+	query := `INSERT INTO login_codes (user_id, code_hash, expires_at, created_at) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id) DO UPDATE SET code_hash = EXCLUDED.code_hash, expires_at = EXCLUDED.expires_at, created_at = EXCLUDED.created_at,
+			failed_attempts = 0`
+
+	if _, err := r.db.ExecContext(ctx, query, code.UserID, code.CodeHash, code.ExpiresAt, code.CreatedAt); err != nil {
+		return fmt.Errorf("failed to save login code: %w", err)
+	}
+	return nil
+}
+
+func (r *LoginCodeRepository) ConsumeLoginCode(ctx context.Context, userID string, hash []byte, now time.Time) (bool, error) {
+	query := "DELETE FROM login_codes WHERE user_id = $1 AND code_hash = $2 AND expires_at > $3"
+
+	result, err := r.db.ExecContext(ctx, query, userID, hash, now)
+	if err != nil {
+		return false, fmt.Errorf("failed to consume login code: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to read affected rows: %w", err)
+	}
+	return affected == 1, nil
+}
+
+func (r *LoginCodeRepository) RecordFailedAttempt(ctx context.Context, userID string, maxAttempts int) (bool, error) {
+	// The row lock of the UPDATE gives every concurrent failure its own count
+	var attempts int
+	query := "UPDATE login_codes SET failed_attempts = failed_attempts + 1 WHERE user_id = $1 RETURNING failed_attempts"
+	err := r.db.GetContext(database.WithWrite(ctx), &attempts, query, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to record login code attempt: %w", err)
+	}
+	if attempts < maxAttempts {
+		return false, nil
+	}
+
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM login_codes WHERE user_id = $1 AND failed_attempts >= $2", userID, maxAttempts); err != nil {
+		return false, fmt.Errorf("failed to delete login code: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,139 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"hub-user-service/internal/database/databasetest"
+	"hub-user-service/internal/logincode/domain/model"
+	"hub-user-service/internal/logincode/domain/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// loginCodeSchema is the SQLite version of the login_codes table
+const loginCodeSchema = `
+INSERT INTO users (id, email, name, password) VALUES (42, 'dev@example.com', 'Dev', 'DevPass123!');
+CREATE TABLE login_codes (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    code_hash BLOB NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    failed_attempts INTEGER NOT NULL DEFAULT 0
+);`
+
+var testNow = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+func TestLoginCodeRepository_SaveReplacesPreviousCode(t *testing.T) {
+	db := &databasetest.MockQuerier{}
+	code := &model.LoginCode{UserID: "42", CodeHash: []byte{1}, ExpiresAt: testNow.Add(10 * time.Minute), CreatedAt: testNow}
+	args := []interface{}{"42", []byte{1}, testNow.Add(10 * time.Minute), testNow}
+	db.On("ExecContext", mock.Anything, databasetest.QueryContaining("ON CONFLICT (user_id) DO UPDATE"), args).Return(databasetest.Result(1), nil).Once()
+	db.On("ExecContext", mock.Anything, mock.Anything, args).Return(nil, errors.New("connection reset")).Once()
+
+	require.NoError(t, NewLoginCodeRepository(db).SaveLoginCode(context.Background(), code))
+	assert.Error(t, NewLoginCodeRepository(db).SaveLoginCode(context.Background(), code))
+	db.AssertExpectations(t)
+}
+
+func TestLoginCodeRepository_ConsumeIsSingleUse(t *testing.T) {
+	repo := NewLoginCodeRepository(databasetest.OpenSQLite(t, loginCodeSchema))
+	ctx := context.Background()
+	expires := testNow.Add(10 * time.Minute)
+	require.NoError(t, repo.SaveLoginCode(ctx, &model.LoginCode{UserID: "42", CodeHash: []byte{1}, ExpiresAt: expires, CreatedAt: testNow}))
+	require.NoError(t, repo.SaveLoginCode(ctx, &model.LoginCode{UserID: "42", CodeHash: []byte{2}, ExpiresAt: expires, CreatedAt: testNow}))
+
+	consumed, err := repo.ConsumeLoginCode(ctx, "42", []byte{1}, testNow)
+	require.NoError(t, err)
+	assert.False(t, consumed, "a new code replaces the previous one")
+	consumed, err = repo.ConsumeLoginCode(ctx, "42", []byte{2}, expires)
+	require.NoError(t, err)
+	assert.False(t, consumed, "expired codes are refused")
+
+	// Concurrent logins with the same code: exactly one consumes it
+	var accepted atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if consumed, err := repo.ConsumeLoginCode(ctx, "42", []byte{2}, testNow); err == nil && consumed {
+				accepted.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), accepted.Load())
+}
+
+func TestLoginCodeRepository_RecordFailedAttempt(t *testing.T) {
+	repo := NewLoginCodeRepository(databasetest.OpenSQLite(t, loginCodeSchema))
+	ctx := context.Background()
+	code := &model.LoginCode{UserID: "42", CodeHash: []byte{1}, ExpiresAt: testNow.Add(10 * time.Minute), CreatedAt: testNow}
+	require.NoError(t, repo.SaveLoginCode(ctx, code))
+
+	deleted, err := repo.RecordFailedAttempt(ctx, "42", 2)
+	require.NoError(t, err)
+	assert.False(t, deleted, "the code is kept below the limit")
+
+	// A new code starts counting again
+	require.NoError(t, repo.SaveLoginCode(ctx, code))
+	deleted, err = repo.RecordFailedAttempt(ctx, "42", 2)
+	require.NoError(t, err)
+	assert.False(t, deleted)
+	deleted, err = repo.RecordFailedAttempt(ctx, "42", 2)
+	require.NoError(t, err)
+	assert.True(t, deleted)
+
+	consumed, err := repo.ConsumeLoginCode(ctx, "42", []byte{1}, testNow)
+	require.NoError(t, err)
+	assert.False(t, consumed, "the code is gone after max attempts")
+	deleted, err = repo.RecordFailedAttempt(ctx, "42", 2)
+	require.NoError(t, err, "no code to count against")
+	assert.False(t, deleted)
+}
+
+func TestMemoryLoginCodeRepository_Lifecycle(t *testing.T) {
+	var repo repository.ILoginCodeRepository = NewMemoryLoginCodeRepository()
+	ctx := context.Background()
+	expires := testNow.Add(10 * time.Minute)
+
+	require.NoError(t, repo.SaveLoginCode(ctx, &model.LoginCode{UserID: "42", CodeHash: []byte{1}, ExpiresAt: expires}))
+	require.NoError(t, repo.SaveLoginCode(ctx, &model.LoginCode{UserID: "42", CodeHash: []byte{2}, ExpiresAt: expires}))
+
+	consumed, _ := repo.ConsumeLoginCode(ctx, "42", []byte{1}, testNow)
+	assert.False(t, consumed, "a new code replaces the previous one")
+	consumed, _ = repo.ConsumeLoginCode(ctx, "7", []byte{2}, testNow)
+	assert.False(t, consumed, "codes belong to their user")
+	consumed, _ = repo.ConsumeLoginCode(ctx, "42", []byte{2}, expires)
+	assert.False(t, consumed, "expired codes are refused")
+
+	consumed, err := repo.ConsumeLoginCode(ctx, "42", []byte{2}, testNow)
+	require.NoError(t, err)
+	assert.True(t, consumed)
+	consumed, _ = repo.ConsumeLoginCode(ctx, "42", []byte{2}, testNow)
+	assert.False(t, consumed, "a code is accepted once")
+}
+
+func TestMemoryLoginCodeRepository_FailedAttempts(t *testing.T) {
+	repo := NewMemoryLoginCodeRepository()
+	ctx := context.Background()
+	require.NoError(t, repo.SaveLoginCode(ctx, &model.LoginCode{UserID: "42", CodeHash: []byte{1}, ExpiresAt: testNow.Add(time.Minute)}))
+
+	deleted, _ := repo.RecordFailedAttempt(ctx, "42", 2)
+	assert.False(t, deleted)
+	deleted, _ = repo.RecordFailedAttempt(ctx, "42", 2)
+	assert.True(t, deleted)
+
+	consumed, _ := repo.ConsumeLoginCode(ctx, "42", []byte{1}, testNow)
+	assert.False(t, consumed, "the code is gone after max attempts")
+	deleted, err := repo.RecordFailedAttempt(ctx, "42", 2)
+	require.NoError(t, err)
+	assert.False(t, deleted)
+}
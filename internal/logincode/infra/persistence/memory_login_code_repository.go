@@ -0,0 +1,60 @@
+package persistence
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+
+	"hub-user-service/internal/logincode/domain/model"
+)
+
+// MemoryLoginCodeRepository keeps login codes in process memory (DB_DRIVER=memory)
+type MemoryLoginCodeRepository struct {
+	mu    sync.Mutex
+	codes map[string]model.LoginCode
+}
+
+// NewMemoryLoginCodeRepository creates an empty in-memory login code repository
+func NewMemoryLoginCodeRepository() *MemoryLoginCodeRepository {
+	return &MemoryLoginCodeRepository{codes: make(map[string]model.LoginCode)}
+}
+
+func (r *MemoryLoginCodeRepository) SaveLoginCode(ctx context.Context, code *model.LoginCode) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *code
+	stored.CodeHash = append([]byte(nil), code.CodeHash...)
+	r.codes[code.UserID] = stored
+	return nil
+}
+
+func (r *MemoryLoginCodeRepository) ConsumeLoginCode(ctx context.Context, userID string, hash []byte, now time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	code, ok := r.codes[userID]
+	if !ok || code.IsExpired(now) || !bytes.Equal(code.CodeHash, hash) {
+		return false, nil
+	}
+	delete(r.codes, userID)
+	return true, nil
+}
+
+func (r *MemoryLoginCodeRepository) RecordFailedAttempt(ctx context.Context, userID string, maxAttempts int) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	code, ok := r.codes[userID]
+	if !ok {
+		return false, nil
+	}
+	code.FailedAttempts++
+	if code.FailedAttempts >= maxAttempts {
+		delete(r.codes, userID)
+		return true, nil
+	}
+	r.codes[userID] = code
+	return false, nil
+}
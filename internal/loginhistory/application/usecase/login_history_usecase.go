@@ -0,0 +1,142 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"hub-user-service/internal/geoip"
+	loginRepository "hub-user-service/internal/login/domain/repository"
+	"hub-user-service/internal/loginhistory/domain/model"
+	"hub-user-service/internal/loginhistory/domain/repository"
+	"hub-user-service/internal/notification"
+	sessionModel "hub-user-service/internal/session/domain/model"
+)
+
+// maxEmailLength is the size of the email column of login_history
+const maxEmailLength = 255
+
+type ILoginHistoryUsecase interface {
+	// Record adds an attempt to the history, filling in its user, location, device and
+	// fingerprint, and notifies the user of a successful login from a new device
+	Record(ctx context.Context, attempt *model.LoginAttempt) error
+	// List returns up to limit attempts of the user older than the attempt beforeID (all when 0),
+	// most recent first
+	List(ctx context.Context, userID string, beforeID int64, limit int) ([]*model.LoginAttempt, error)
+}
+
+// LoginHistoryConfig configures LoginHistoryUsecase
+type LoginHistoryConfig struct {
+	// NotifyNewDevices emails the user when a login succeeds from a device not seen before;
+	// the first login of an account is not notified
+	NotifyNewDevices bool
+}
+
+type LoginHistoryUsecase struct {
+	users   loginRepository.ILoginRepository
+	repo    repository.ILoginHistoryRepository
+	locator geoip.Locator
+	sender  notification.Sender
+	config  LoginHistoryConfig
+	now     func() time.Time
+}
+
+// NewLoginHistoryUsecase creates the login history use case
+func NewLoginHistoryUsecase(users loginRepository.ILoginRepository, repo repository.ILoginHistoryRepository, locator geoip.Locator, sender notification.Sender, config LoginHistoryConfig) ILoginHistoryUsecase {
+	return &LoginHistoryUsecase{users: users, repo: repo, locator: locator, sender: sender, config: config, now: time.Now}
+}
+
+func (u *LoginHistoryUsecase) Record(ctx context.Context, attempt *model.LoginAttempt) error {
+	if attempt.CreatedAt.IsZero() {
+		attempt.CreatedAt = u.now()
+	}
+	if attempt.UserID == "" && attempt.Email != "" {
+		attempt.UserID = u.userID(ctx, attempt.Email)
+	}
+
+	location := u.locator.Locate(attempt.IPAddress)
+	attempt.Country, attempt.Region, attempt.City = location.Country, location.Region, location.City
+	attempt.DeviceFingerprint = Fingerprint(attempt.UserAgent, attempt.Device)
+	if attempt.Device == "" {
+		attempt.Device = sessionModel.DescribeDevice(attempt.UserAgent)
+	}
+	attempt.Email = sessionModel.Truncate(attempt.Email, maxEmailLength)
+	attempt.Device = sessionModel.Truncate(attempt.Device, sessionModel.MaxDeviceLength)
+	attempt.IPAddress = sessionModel.Truncate(attempt.IPAddress, sessionModel.MaxIPAddressLength)
+	attempt.UserAgent = sessionModel.Truncate(attempt.UserAgent, sessionModel.MaxUserAgentLength)
+
+	// The device history is read before the attempt is recorded, or every device would be known
+	var devices *model.DeviceHistory
+	if u.config.NotifyNewDevices && attempt.Outcome == model.OutcomeSuccess && attempt.UserID != "" {
+		var err error
+		if devices, err = u.repo.GetDeviceHistory(ctx, attempt.UserID, attempt.DeviceFingerprint); err != nil {
+			log.Printf("Failed to read the devices of user %s: %v", attempt.UserID, err)
+		}
+	}
+
+	if err := u.repo.RecordAttempt(ctx, attempt); err != nil {
+		return err
+	}
+
+	if devices != nil && devices.HasLogins && !devices.KnownDevice && attempt.Email != "" {
+		log.Printf("📱 New device for user %s: %s", attempt.UserID, attempt.Device)
+		if err := u.sender.Send(ctx, newDeviceMessage(attempt)); err != nil {
+			log.Printf("Failed to notify user %s of a new device: %v", attempt.UserID, err)
+		}
+	}
+	return nil
+}
+
+func (u *LoginHistoryUsecase) List(ctx context.Context, userID string, beforeID int64, limit int) ([]*model.LoginAttempt, error) {
+	return u.repo.ListAttempts(ctx, userID, beforeID, limit)
+}
+
+// userID attributes an attempt to the user with this email; unknown emails stay unattributed
+func (u *LoginHistoryUsecase) userID(ctx context.Context, email string) string {
+	user, err := u.users.GetUserByEmail(ctx, email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ""
+	}
+	if err != nil {
+		log.Printf("Failed to attribute a login attempt: %v", err)
+		return ""
+	}
+	if user == nil {
+		return ""
+	}
+	return user.ID
+}
+
+// Fingerprint identifies a device by its user agent and the device name chosen by the client;
+// the IP address is left out since it changes on mobile networks
+func Fingerprint(userAgent string, device string) string {
+	sum := sha256.Sum256([]byte(userAgent + "\n" + device))
+	return hex.EncodeToString(sum[:16])
+}
+
+// newDeviceMessage is the email sent for a login from a new device
+func newDeviceMessage(attempt *model.LoginAttempt) notification.Message {
+	details := []string{"Device: " + attempt.Device}
+	location := geoip.Location{Country: attempt.Country, Region: attempt.Region, City: attempt.City}
+	if location != (geoip.Location{}) {
+		details = append(details, "Location: "+location.String())
+	}
+	if attempt.IPAddress != "" {
+		details = append(details, "IP address: "+attempt.IPAddress)
+	}
+	details = append(details, "Time: "+attempt.CreatedAt.UTC().Format("2 Jan 2006 15:04 MST"))
+
+	return notification.Message{
+		To:      attempt.Email,
+		Subject: "New sign-in to your Hub Investments account",
+		Body: fmt.Sprintf("Your account was just used to sign in from a new device.\n\n%s\n\n"+
+			"If this was you, you can ignore this email. If not, change your password and end the session from your account settings.",
+			strings.Join(details, "\n")),
+	}
+}
@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"hub-user-service/internal/geoip"
+	loginModel "hub-user-service/internal/login/domain/model"
+	loginPersistence "hub-user-service/internal/login/infra/persistence"
+	"hub-user-service/internal/loginhistory/domain/model"
+	"hub-user-service/internal/loginhistory/infra/persistence"
+	"hub-user-service/internal/notification"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testNow = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+const (
+	chromeOnMac = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/129.0.0.0 Safari/537.36"
+	safariOnIOS = "Mozilla/5.0 (iPhone; CPU iPhone OS 18_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/18.0 Mobile/15E148 Safari/604.1"
+)
+
+// outbox keeps the sent messages
+type outbox struct {
+	messages []notification.Message
+	err      error
+}
+
+func (o *outbox) Send(ctx context.Context, message notification.Message) error {
+	if o.err != nil {
+		return o.err
+	}
+	o.messages = append(o.messages, message)
+	return nil
+}
+
+type historyFixture struct {
+	usecase *LoginHistoryUsecase
+	repo    *persistence.MemoryLoginHistoryRepository
+	outbox  *outbox
+}
+
+func newFixture(t *testing.T, config LoginHistoryConfig) *historyFixture {
+	t.Helper()
+	locations, err := geoip.ParseDatabase(strings.NewReader("network,country,region,city\n203.0.113.0/24,PT,Lisbon,Lisbon\n"))
+	require.NoError(t, err)
+
+	users := loginPersistence.NewMemoryLoginRepository(loginModel.NewUserFromRepository("42", "ada@example.com", "DevPass123!"))
+	repo := persistence.NewMemoryLoginHistoryRepository()
+	sent := &outbox{}
+	uc := NewLoginHistoryUsecase(users, repo, locations, sent, config).(*LoginHistoryUsecase)
+	uc.now = func() time.Time { return testNow }
+	return &historyFixture{usecase: uc, repo: repo, outbox: sent}
+}
+
+// login records a successful login of user 42 from a browser
+func (f *historyFixture) login(t *testing.T, userAgent string) {
+	t.Helper()
+	require.NoError(t, f.usecase.Record(context.Background(), &model.LoginAttempt{
+		UserID: "42", Email: "ada@example.com", Methods: []string{"pwd"}, Outcome: model.OutcomeSuccess,
+		IPAddress: "203.0.113.7", UserAgent: userAgent,
+	}))
+}
+
+func TestLoginHistoryUsecase_RecordEnrichesAttempts(t *testing.T) {
+	f := newFixture(t, LoginHistoryConfig{})
+	ctx := context.Background()
+
+	failed := &model.LoginAttempt{Email: "ada@example.com", Methods: []string{"pwd"}, Outcome: model.OutcomeFailure, IPAddress: "203.0.113.7", UserAgent: chromeOnMac}
+	require.NoError(t, f.usecase.Record(ctx, failed))
+	unknown := &model.LoginAttempt{Email: "nobody@example.com", Methods: []string{"pwd"}, Outcome: model.OutcomeFailure, IPAddress: "192.0.2.1"}
+	require.NoError(t, f.usecase.Record(ctx, unknown))
+
+	assert.Equal(t, "42", failed.UserID, "failures are attributed by email")
+	assert.Equal(t, "Chrome on macOS", failed.Device)
+	assert.Equal(t, "PT", failed.Country)
+	assert.Equal(t, "Lisbon", failed.City)
+	assert.Equal(t, Fingerprint(chromeOnMac, ""), failed.DeviceFingerprint)
+	assert.Equal(t, testNow, failed.CreatedAt)
+	assert.Empty(t, unknown.UserID)
+	assert.Empty(t, unknown.Country)
+
+	attempts, err := f.usecase.List(ctx, "42", 0, 10)
+	require.NoError(t, err)
+	require.Len(t, attempts, 1, "only the user's own attempts")
+	assert.Equal(t, model.OutcomeFailure, attempts[0].Outcome)
+}
+
+func TestLoginHistoryUsecase_NotifiesNewDevices(t *testing.T) {
+	f := newFixture(t, LoginHistoryConfig{NotifyNewDevices: true})
+
+	f.login(t, chromeOnMac)
+	assert.Empty(t, f.outbox.messages, "the first login of an account is not notified")
+
+	f.login(t, chromeOnMac)
+	assert.Empty(t, f.outbox.messages, "known device")
+
+	f.login(t, safariOnIOS)
+	require.Len(t, f.outbox.messages, 1)
+	message := f.outbox.messages[0]
+	assert.Equal(t, "ada@example.com", message.To)
+	assert.Equal(t, "New sign-in to your Hub Investments account", message.Subject)
+	assert.Contains(t, message.Body, "Device: Safari on iOS")
+	assert.Contains(t, message.Body, "Location: Lisbon, Lisbon, PT")
+	assert.Contains(t, message.Body, "IP address: 203.0.113.7")
+	assert.Contains(t, message.Body, "Time: 18 Oct 2026 12:00 UTC")
+
+	f.login(t, safariOnIOS)
+	assert.Len(t, f.outbox.messages, 1)
+}
+
+func TestLoginHistoryUsecase_NewDeviceNotificationRules(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		f := newFixture(t, LoginHistoryConfig{})
+		f.login(t, chromeOnMac)
+		f.login(t, safariOnIOS)
+		assert.Empty(t, f.outbox.messages)
+	})
+
+	t.Run("failed attempts neither notify nor make a device known", func(t *testing.T) {
+		f := newFixture(t, LoginHistoryConfig{NotifyNewDevices: true})
+		f.login(t, chromeOnMac)
+		require.NoError(t, f.usecase.Record(context.Background(), &model.LoginAttempt{
+			UserID: "42", Email: "ada@example.com", Outcome: model.OutcomeFailure, UserAgent: safariOnIOS,
+		}))
+		assert.Empty(t, f.outbox.messages)
+
+		f.login(t, safariOnIOS)
+		assert.Len(t, f.outbox.messages, 1)
+	})
+
+	t.Run("a failed notification does not fail the login", func(t *testing.T) {
+		f := newFixture(t, LoginHistoryConfig{NotifyNewDevices: true})
+		f.outbox.err = errors.New("relay down")
+		f.login(t, chromeOnMac)
+		f.login(t, safariOnIOS)
+	})
+}
+
+func TestFingerprint(t *testing.T) {
+	assert.Len(t, Fingerprint(chromeOnMac, ""), 32)
+	assert.Equal(t, Fingerprint(chromeOnMac, ""), Fingerprint(chromeOnMac, ""))
+	assert.NotEqual(t, Fingerprint(chromeOnMac, ""), Fingerprint(chromeOnMac, "Work laptop"))
+	assert.NotEqual(t, Fingerprint(chromeOnMac, ""), Fingerprint(safariOnIOS, ""))
+}
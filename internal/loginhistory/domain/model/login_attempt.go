@@ -0,0 +1,48 @@
+package model
+
+import "time"
+
+// Outcome is the result of a login attempt
+type Outcome string
+
+const (
+	// OutcomeSuccess is a completed login
+	OutcomeSuccess Outcome = "success"
+	// OutcomeMFARequired is an accepted first factor, the login continues with a second factor
+	OutcomeMFARequired Outcome = "mfa_required"
+	// OutcomeFailure is a rejected password, code or passkey
+	OutcomeFailure Outcome = "failure"
+	// OutcomeDenied is an accepted first factor refused by the risk assessment
+	OutcomeDenied Outcome = "denied"
+)
+
+// LoginAttempt is an entry of the login history
+type LoginAttempt struct {
+	ID int64
+	// UserID is empty when the attempt could not be attributed to a user (unknown email, passkey)
+	UserID string
+	// Email is the address the attempt was made for, as typed
+	Email string
+	// Methods are the authentication methods tried (amr values, e.g. pwd or pwd otp mfa)
+	Methods []string
+	Outcome Outcome
+	// Device is a label for the device, e.g. "Chrome on macOS"
+	Device    string
+	IPAddress string
+	UserAgent string
+	// Country, Region and City are the coarse location of the IP address, empty when unknown
+	Country string
+	Region  string
+	City    string
+	// DeviceFingerprint identifies the device across logins, to detect sign-ins from new devices
+	DeviceFingerprint string
+	CreatedAt         time.Time
+}
+
+// DeviceHistory tells whether a user logged in before, and from a given device
+type DeviceHistory struct {
+	// HasLogins is set when the user has at least one successful login
+	HasLogins bool
+	// KnownDevice is set when one of them was from the device
+	KnownDevice bool
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"hub-user-service/internal/loginhistory/domain/model"
+)
+
+type ILoginHistoryRepository interface {
+	// RecordAttempt appends an attempt to the history
+	RecordAttempt(ctx context.Context, attempt *model.LoginAttempt) error
+	// ListAttempts returns up to limit attempts of the user older than the attempt beforeID (all
+	// when 0), most recent first
+	ListAttempts(ctx context.Context, userID string, beforeID int64, limit int) ([]*model.LoginAttempt, error)
+	// GetDeviceHistory tells whether the user logged in successfully before, and from the device
+	GetDeviceHistory(ctx context.Context, userID string, fingerprint string) (*model.DeviceHistory, error)
+	// GetLastSuccess returns the most recent successful login of the user, nil when there is none
+	GetLastSuccess(ctx context.Context, userID string) (*model.LoginAttempt, error)
+	// CountFailures returns the number of failed attempts of the user since the given time
+	CountFailures(ctx context.Context, userID string, since time.Time) (int, error)
+}
@@ -0,0 +1,133 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"hub-user-service/internal/database"
+	"hub-user-service/internal/loginhistory/domain/model"
+	"hub-user-service/internal/loginhistory/domain/repository"
+)
+
+type LoginHistoryRepository struct {
+	db database.Querier
+}
+
+// loginAttemptDTO represents the login_history table
+type loginAttemptDTO struct {
+	ID                int64          `db:"id"`
+	UserID            sql.NullString `db:"user_id"`
+	Email             string         `db:"email"`
+	Methods           string         `db:"methods"`
+	Outcome           string         `db:"outcome"`
+	Device            string         `db:"device"`
+	IPAddress         string         `db:"ip_address"`
+	UserAgent         string         `db:"user_agent"`
+	Country           string         `db:"country"`
+	Region            string         `db:"region"`
+	City              string         `db:"city"`
+	DeviceFingerprint string         `db:"device_fingerprint"`
+	CreatedAt         time.Time      `db:"created_at"`
+}
+
+// deviceHistoryDTO is the result of the device history query
+type deviceHistoryDTO struct {
+	HasLogins   bool `db:"has_logins"`
+	KnownDevice bool `db:"known_device"`
+}
+
+// NewLoginHistoryRepository creates a login history repository on a database or a transaction
+func NewLoginHistoryRepository(db database.Querier) repository.ILoginHistoryRepository {
+	return &LoginHistoryRepository{db: db}
+}
+
+const loginAttemptColumns = "id, user_id, email, methods, outcome, device, ip_address, user_agent, country, region, city, device_fingerprint, created_at"
+
+func (r *LoginHistoryRepository) RecordAttempt(ctx context.Context, attempt *model.LoginAttempt) error {
+	query := `INSERT INTO login_history (user_id, email, methods, outcome, device, ip_address, user_agent,
+		country, region, city, device_fingerprint, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
+
+	userID := sql.NullString{String: attempt.UserID, Valid: attempt.UserID != ""}
+	_, err := r.db.ExecContext(ctx, query, userID, attempt.Email, strings.Join(attempt.Methods, " "), string(attempt.Outcome),
+		attempt.Device, attempt.IPAddress, attempt.UserAgent, attempt.Country, attempt.Region, attempt.City,
+		attempt.DeviceFingerprint, attempt.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record login attempt: %w", err)
+	}
+	return nil
+}
+
+func (r *LoginHistoryRepository) ListAttempts(ctx context.Context, userID string, beforeID int64, limit int) ([]*model.LoginAttempt, error) {
+	query := "SELECT " + loginAttemptColumns + " FROM login_history WHERE user_id = $1 AND ($2 = 0 OR id < $2) ORDER BY id DESC LIMIT $3"
+
+	var dtos []loginAttemptDTO
+	if err := r.db.SelectContext(ctx, &dtos, query, userID, beforeID, limit); err != nil {
+		return nil, fmt.Errorf("failed to list login attempts: %w", err)
+	}
+
+	attempts := make([]*model.LoginAttempt, len(dtos))
+	for i, dto := range dtos {
+		attempts[i] = dto.toModel()
+	}
+	return attempts, nil
+}
+
+func (r *LoginHistoryRepository) GetDeviceHistory(ctx context.Context, userID string, fingerprint string) (*model.DeviceHistory, error) {
+	query := `SELECT
+		EXISTS (SELECT 1 FROM login_history WHERE user_id = $1 AND outcome = 'success') AS has_logins,
+		EXISTS (SELECT 1 FROM login_history WHERE user_id = $1 AND outcome = 'success' AND device_fingerprint = $2) AS known_device`
+
+	var dto deviceHistoryDTO
+	if err := r.db.GetContext(ctx, &dto, query, userID, fingerprint); err != nil {
+		return nil, fmt.Errorf("failed to get device history: %w", err)
+	}
+	return &model.DeviceHistory{HasLogins: dto.HasLogins, KnownDevice: dto.KnownDevice}, nil
+}
+
+func (r *LoginHistoryRepository) GetLastSuccess(ctx context.Context, userID string) (*model.LoginAttempt, error) {
+	query := "SELECT " + loginAttemptColumns + " FROM login_history WHERE user_id = $1 AND outcome = 'success' ORDER BY id DESC LIMIT 1"
+
+	var dto loginAttemptDTO
+	err := r.db.GetContext(ctx, &dto, query, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last login: %w", err)
+	}
+	return dto.toModel(), nil
+}
+
+func (r *LoginHistoryRepository) CountFailures(ctx context.Context, userID string, since time.Time) (int, error) {
+	query := "SELECT COUNT(*) FROM login_history WHERE user_id = $1 AND outcome = 'failure' AND created_at >= $2"
+
+	var count int
+	if err := r.db.GetContext(ctx, &count, query, userID, since); err != nil {
+		return 0, fmt.Errorf("failed to count failed logins: %w", err)
+	}
+	return count, nil
+}
+
+// toModel converts the DTO to the domain model
+func (d loginAttemptDTO) toModel() *model.LoginAttempt {
+	return &model.LoginAttempt{
+		ID:                d.ID,
+		UserID:            d.UserID.String,
+		Email:             d.Email,
+		Methods:           strings.Fields(d.Methods),
+		Outcome:           model.Outcome(d.Outcome),
+		Device:            d.Device,
+		IPAddress:         d.IPAddress,
+		UserAgent:         d.UserAgent,
+		Country:           d.Country,
+		Region:            d.Region,
+		City:              d.City,
+		DeviceFingerprint: d.DeviceFingerprint,
+		CreatedAt:         d.CreatedAt,
+	}
+}
@@ -0,0 +1,145 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"hub-user-service/internal/database/databasetest"
+	"hub-user-service/internal/loginhistory/domain/model"
+	"hub-user-service/internal/loginhistory/domain/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var testNow = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+func TestLoginHistoryRepository_RecordAttempt(t *testing.T) {
+	db := &databasetest.MockQuerier{}
+	db.On("ExecContext", mock.Anything, databasetest.QueryContaining("INSERT INTO login_history"), mock.MatchedBy(func(args []interface{}) bool {
+		return args[0] == sql.NullString{String: "42", Valid: true} && args[2] == "pwd otp mfa" && args[3] == "success"
+	})).Return(databasetest.Result(1), nil).Once()
+	db.On("ExecContext", mock.Anything, mock.Anything, mock.MatchedBy(func(args []interface{}) bool {
+		return args[0] == sql.NullString{}
+	})).Return(databasetest.Result(1), nil).Once()
+	repo := NewLoginHistoryRepository(db)
+
+	require.NoError(t, repo.RecordAttempt(context.Background(), &model.LoginAttempt{
+		UserID: "42", Email: "ada@example.com", Methods: []string{"pwd", "otp", "mfa"}, Outcome: model.OutcomeSuccess,
+		Country: "PT", DeviceFingerprint: "f1", CreatedAt: testNow,
+	}))
+	// Unattributed attempts have no user
+	require.NoError(t, repo.RecordAttempt(context.Background(), &model.LoginAttempt{Email: "nobody@example.com", Outcome: model.OutcomeFailure}))
+	db.AssertExpectations(t)
+}
+
+func TestLoginHistoryRepository_ListAttempts(t *testing.T) {
+	db := &databasetest.MockQuerier{}
+	db.On("SelectContext", mock.Anything, mock.Anything, databasetest.QueryContaining("($2 = 0 OR id < $2) ORDER BY id DESC LIMIT $3"), []interface{}{"42", int64(9), 20}).
+		Run(databasetest.Fill([]loginAttemptDTO{
+			{ID: 2, UserID: sql.NullString{String: "42", Valid: true}, Methods: "pwd", Outcome: "failure", City: "Lisbon", CreatedAt: testNow},
+		})).Return(nil)
+
+	attempts, err := NewLoginHistoryRepository(db).ListAttempts(context.Background(), "42", 9, 20)
+
+	require.NoError(t, err)
+	require.Len(t, attempts, 1)
+	assert.Equal(t, "42", attempts[0].UserID)
+	assert.Equal(t, []string{"pwd"}, attempts[0].Methods)
+	assert.Equal(t, model.OutcomeFailure, attempts[0].Outcome)
+	assert.Equal(t, "Lisbon", attempts[0].City)
+	db.AssertExpectations(t)
+}
+
+func TestLoginHistoryRepository_GetDeviceHistory(t *testing.T) {
+	db := &databasetest.MockQuerier{}
+	db.On("GetContext", mock.Anything, mock.Anything, databasetest.QueryContaining("outcome = 'success' AND device_fingerprint = $2"), []interface{}{"42", "f1"}).
+		Run(databasetest.Fill(deviceHistoryDTO{HasLogins: true})).Return(nil)
+
+	history, err := NewLoginHistoryRepository(db).GetDeviceHistory(context.Background(), "42", "f1")
+
+	require.NoError(t, err)
+	assert.Equal(t, &model.DeviceHistory{HasLogins: true}, history)
+}
+
+func TestLoginHistoryRepository_GetLastSuccess(t *testing.T) {
+	db := &databasetest.MockQuerier{}
+	lastSuccess := databasetest.QueryContaining("outcome = 'success' ORDER BY id DESC LIMIT 1")
+	db.On("GetContext", mock.Anything, mock.Anything, lastSuccess, []interface{}{"42"}).
+		Run(databasetest.Fill(loginAttemptDTO{ID: 5, UserID: sql.NullString{String: "42", Valid: true}, Outcome: "success", IPAddress: "203.0.113.7", CreatedAt: testNow})).
+		Return(nil)
+	db.On("GetContext", mock.Anything, mock.Anything, lastSuccess, []interface{}{"7"}).Return(sql.ErrNoRows)
+	repo := NewLoginHistoryRepository(db)
+
+	last, err := repo.GetLastSuccess(context.Background(), "42")
+	require.NoError(t, err)
+	require.NotNil(t, last)
+	assert.Equal(t, int64(5), last.ID)
+	assert.Equal(t, "203.0.113.7", last.IPAddress)
+
+	last, err = repo.GetLastSuccess(context.Background(), "7")
+	require.NoError(t, err)
+	assert.Nil(t, last, "no login yet")
+}
+
+func TestLoginHistoryRepository_CountFailures(t *testing.T) {
+	db := &databasetest.MockQuerier{}
+	db.On("GetContext", mock.Anything, mock.Anything, databasetest.QueryContaining("outcome = 'failure' AND created_at >= $2"), []interface{}{"42", testNow}).
+		Run(databasetest.Fill(3)).Return(nil)
+
+	count, err := NewLoginHistoryRepository(db).CountFailures(context.Background(), "42", testNow)
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+}
+
+func TestMemoryLoginHistoryRepository(t *testing.T) {
+	var repo repository.ILoginHistoryRepository = NewMemoryLoginHistoryRepository()
+	ctx := context.Background()
+
+	history, err := repo.GetDeviceHistory(ctx, "42", "laptop")
+	require.NoError(t, err)
+	assert.Equal(t, &model.DeviceHistory{}, history)
+
+	for i, attempt := range []model.LoginAttempt{
+		{UserID: "42", Outcome: model.OutcomeFailure, DeviceFingerprint: "phone"},
+		{UserID: "42", Outcome: model.OutcomeSuccess, DeviceFingerprint: "laptop"},
+		{UserID: "7", Outcome: model.OutcomeSuccess, DeviceFingerprint: "phone"},
+		{Email: "nobody@example.com", Outcome: model.OutcomeFailure},
+	} {
+		attempt.CreatedAt = testNow.Add(time.Duration(i) * time.Minute)
+		require.NoError(t, repo.RecordAttempt(ctx, &attempt))
+	}
+
+	history, _ = repo.GetDeviceHistory(ctx, "42", "laptop")
+	assert.Equal(t, &model.DeviceHistory{HasLogins: true, KnownDevice: true}, history)
+	history, _ = repo.GetDeviceHistory(ctx, "42", "phone")
+	assert.Equal(t, &model.DeviceHistory{HasLogins: true}, history, "failed attempts do not make a device known")
+
+	attempts, err := repo.ListAttempts(ctx, "42", 0, 10)
+	require.NoError(t, err)
+	require.Len(t, attempts, 2)
+	assert.Equal(t, model.OutcomeSuccess, attempts[0].Outcome, "most recent first")
+
+	attempts, _ = repo.ListAttempts(ctx, "42", attempts[0].ID, 10)
+	require.Len(t, attempts, 1, "only attempts older than before ID")
+	assert.Equal(t, model.OutcomeFailure, attempts[0].Outcome)
+
+	attempts, _ = repo.ListAttempts(ctx, "42", 0, 1)
+	assert.Len(t, attempts, 1)
+
+	last, err := repo.GetLastSuccess(ctx, "42")
+	require.NoError(t, err)
+	assert.Equal(t, "laptop", last.DeviceFingerprint)
+	last, _ = repo.GetLastSuccess(ctx, "99")
+	assert.Nil(t, last)
+
+	failures, err := repo.CountFailures(ctx, "42", testNow)
+	require.NoError(t, err)
+	assert.Equal(t, 1, failures)
+	failures, _ = repo.CountFailures(ctx, "42", testNow.Add(time.Second))
+	assert.Zero(t, failures, "only failures since the given time")
+}
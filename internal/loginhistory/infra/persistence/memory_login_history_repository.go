@@ -0,0 +1,96 @@
+package persistence
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"hub-user-service/internal/loginhistory/domain/model"
+)
+
+// MemoryLoginHistoryRepository keeps the login history in process memory (DB_DRIVER=memory)
+type MemoryLoginHistoryRepository struct {
+	mu       sync.Mutex
+	attempts []model.LoginAttempt
+}
+
+// NewMemoryLoginHistoryRepository creates an empty in-memory login history
+func NewMemoryLoginHistoryRepository() *MemoryLoginHistoryRepository {
+	return &MemoryLoginHistoryRepository{}
+}
+
+func (r *MemoryLoginHistoryRepository) RecordAttempt(ctx context.Context, attempt *model.LoginAttempt) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := copyAttempt(attempt)
+	stored.ID = int64(len(r.attempts) + 1)
+	r.attempts = append(r.attempts, stored)
+	attempt.ID = stored.ID
+	return nil
+}
+
+func (r *MemoryLoginHistoryRepository) ListAttempts(ctx context.Context, userID string, beforeID int64, limit int) ([]*model.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var attempts []*model.LoginAttempt
+	// IDs follow the insertion order, newest last
+	for i := len(r.attempts) - 1; i >= 0 && len(attempts) < limit; i-- {
+		attempt := &r.attempts[i]
+		if attempt.UserID == userID && (beforeID == 0 || attempt.ID < beforeID) {
+			found := copyAttempt(attempt)
+			attempts = append(attempts, &found)
+		}
+	}
+	return attempts, nil
+}
+
+func (r *MemoryLoginHistoryRepository) GetDeviceHistory(ctx context.Context, userID string, fingerprint string) (*model.DeviceHistory, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	history := &model.DeviceHistory{}
+	for _, attempt := range r.attempts {
+		if attempt.UserID == userID && attempt.Outcome == model.OutcomeSuccess {
+			history.HasLogins = true
+			if attempt.DeviceFingerprint == fingerprint {
+				history.KnownDevice = true
+			}
+		}
+	}
+	return history, nil
+}
+
+func (r *MemoryLoginHistoryRepository) GetLastSuccess(ctx context.Context, userID string) (*model.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := len(r.attempts) - 1; i >= 0; i-- {
+		if r.attempts[i].UserID == userID && r.attempts[i].Outcome == model.OutcomeSuccess {
+			found := copyAttempt(&r.attempts[i])
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *MemoryLoginHistoryRepository) CountFailures(ctx context.Context, userID string, since time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for _, attempt := range r.attempts {
+		if attempt.UserID == userID && attempt.Outcome == model.OutcomeFailure && !attempt.CreatedAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// copyAttempt copies attempt so callers can not modify the stored one
+func copyAttempt(attempt *model.LoginAttempt) model.LoginAttempt {
+	found := *attempt
+	found.Methods = append([]string(nil), attempt.Methods...)
+	return found
+}
@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"strconv"
+
+	"hub-user-service/internal/audit"
+	"hub-user-service/internal/mfa/domain/recovery"
+)
+
+// defaultRecoveryCodes is used when TOTPConfig.RecoveryCodes is not set
+const defaultRecoveryCodes = 10
+
+func (u *TOTPUsecase) RegenerateRecoveryCodes(ctx context.Context, userID string, code string) ([]string, error) {
+	if _, err := u.Verify(ctx, userID, code); err != nil {
+		return nil, err
+	}
+
+	codes, err := u.replaceRecoveryCodes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	u.audit(ctx, audit.Entry{
+		Action:     audit.ActionRecoveryCodesRegenerated,
+		UserID:     userID,
+		Attributes: map[string]string{"remaining": strconv.Itoa(len(codes))},
+	})
+	return codes, nil
+}
+
+// useRecoveryCode consumes a recovery code, counting the attempt against the limit
+func (u *TOTPUsecase) useRecoveryCode(ctx context.Context, userID string, code string) (*Verification, error) {
+	if err := u.checkAttempts(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	used, err := u.codes.UseRecoveryCode(ctx, userID, recovery.Hash(userID, code))
+	if err != nil {
+		return nil, err
+	}
+	if !used {
+		return nil, ErrInvalidCode
+	}
+
+	remaining, err := u.codes.CountRecoveryCodes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	u.audit(ctx, audit.Entry{
+		Action:     audit.ActionRecoveryCodeUsed,
+		UserID:     userID,
+		Attributes: map[string]string{"remaining": strconv.Itoa(remaining)},
+	})
+	return &Verification{RecoveryCodeUsed: true, RecoveryCodesRemaining: remaining}, nil
+}
+
+// replaceRecoveryCodes generates a new set of codes and stores their hashes
+func (u *TOTPUsecase) replaceRecoveryCodes(ctx context.Context, userID string) ([]string, error) {
+	count := u.config.RecoveryCodes
+	if count <= 0 {
+		count = defaultRecoveryCodes
+	}
+
+	codes, err := recovery.Generate(count)
+	if err != nil {
+		return nil, err
+	}
+	hashes := make([][]byte, len(codes))
+	for i, code := range codes {
+		hashes[i] = recovery.Hash(userID, code)
+	}
+
+	if err := u.codes.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// audit records entry; a failing audit sink must not lock users out, so errors are only logged
+func (u *TOTPUsecase) audit(ctx context.Context, entry audit.Entry) {
+	if err := u.config.Audit.Record(ctx, entry); err != nil {
+		log.Printf("Failed to record audit entry %s for user %s: %v", entry.Action, entry.UserID, err)
+	}
+}
@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"hub-user-service/internal/audit"
+	"hub-user-service/internal/mfa/domain/totp"
+	"hub-user-service/internal/ratelimit"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingAudit keeps the recorded entries
+type recordingAudit struct {
+	entries []audit.Entry
+}
+
+func (r *recordingAudit) Record(ctx context.Context, entry audit.Entry) error {
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+// enrollWithCodes begins and confirms an enrollment, returning the secret and recovery codes
+func (f *totpFixture) enrollWithCodes(t *testing.T, userID string) ([]byte, []string) {
+	t.Helper()
+	enrollment, err := f.usecase.BeginEnrollment(context.Background(), userID, "ada@example.com")
+	require.NoError(t, err)
+	secret := secretOf(t, enrollment)
+
+	codes, err := f.usecase.ConfirmEnrollment(context.Background(), userID, totp.Code(secret, totp.Step(testNow)-1))
+	require.NoError(t, err)
+	return secret, codes
+}
+
+func TestTOTPUsecase_ConfirmEnrollmentGeneratesRecoveryCodes(t *testing.T) {
+	recorder := &recordingAudit{}
+	f := newFixture(t, TOTPConfig{Skew: 1, RecoveryCodes: 8, Audit: recorder})
+
+	_, codes := f.enrollWithCodes(t, "42")
+
+	assert.Len(t, codes, 8)
+	remaining, err := f.codes.CountRecoveryCodes(context.Background(), "42")
+	require.NoError(t, err)
+	assert.Equal(t, 8, remaining)
+	require.Len(t, recorder.entries, 1)
+	assert.Equal(t, audit.ActionMFAEnabled, recorder.entries[0].Action)
+}
+
+func TestTOTPUsecase_VerifyWithRecoveryCode(t *testing.T) {
+	recorder := &recordingAudit{}
+	f := newFixture(t, TOTPConfig{Skew: 1, Audit: recorder})
+	ctx := context.Background()
+	_, codes := f.enrollWithCodes(t, "42")
+	require.Len(t, codes, defaultRecoveryCodes)
+
+	// Codes are accepted however the user typed them
+	verification, err := f.usecase.Verify(ctx, "42", strings.ToUpper(strings.ReplaceAll(codes[0], "-", " ")))
+	require.NoError(t, err)
+	assert.True(t, verification.RecoveryCodeUsed)
+	assert.Equal(t, defaultRecoveryCodes-1, verification.RecoveryCodesRemaining)
+
+	assert.ErrorIs(t, verifyErr(f.usecase, ctx, "42", codes[0]), ErrInvalidCode, "a recovery code is accepted once")
+	assert.ErrorIs(t, verifyErr(f.usecase, ctx, "42", "zzzzz-zzzzz"), ErrInvalidCode)
+
+	_, otherCodes := f.enrollWithCodes(t, "7")
+	assert.ErrorIs(t, verifyErr(f.usecase, ctx, "42", otherCodes[0]), ErrInvalidCode, "codes belong to their user")
+
+	require.Len(t, recorder.entries, 3)
+	used := recorder.entries[1]
+	assert.Equal(t, audit.ActionRecoveryCodeUsed, used.Action)
+	assert.Equal(t, "42", used.UserID)
+	assert.Equal(t, "9", used.Attributes["remaining"])
+}
+
+func TestTOTPUsecase_RegenerateRecoveryCodes(t *testing.T) {
+	recorder := &recordingAudit{}
+	f := newFixture(t, TOTPConfig{Skew: 1, Audit: recorder})
+	ctx := context.Background()
+	secret, old := f.enrollWithCodes(t, "42")
+
+	_, err := f.usecase.RegenerateRecoveryCodes(ctx, "42", "000000")
+	assert.ErrorIs(t, err, ErrInvalidCode)
+
+	codes, err := f.usecase.RegenerateRecoveryCodes(ctx, "42", totp.Code(secret, totp.Step(testNow)))
+	require.NoError(t, err)
+	assert.Len(t, codes, defaultRecoveryCodes)
+	assert.NotEqual(t, old, codes)
+
+	assert.ErrorIs(t, verifyErr(f.usecase, ctx, "42", old[1]), ErrInvalidCode, "previous codes are invalidated")
+
+	// A recovery code also proves the second factor, e.g. after losing the device
+	codes, err = f.usecase.RegenerateRecoveryCodes(ctx, "42", codes[0])
+	require.NoError(t, err)
+	remaining, _ := f.codes.CountRecoveryCodes(ctx, "42")
+	assert.Equal(t, len(codes), remaining)
+
+	last := recorder.entries[len(recorder.entries)-1]
+	assert.Equal(t, audit.ActionRecoveryCodesRegenerated, last.Action)
+}
+
+func TestTOTPUsecase_RecoveryCodesCountAgainstAttempts(t *testing.T) {
+	f := newFixture(t, TOTPConfig{
+		Skew:          1,
+		Limiter:       ratelimit.NewMemoryLimiter(),
+		MaxAttempts:   2,
+		AttemptWindow: 5 * time.Minute,
+	})
+	ctx := context.Background()
+	_, codes := f.enrollWithCodes(t, "42")
+
+	assert.ErrorIs(t, verifyErr(f.usecase, ctx, "42", "zzzzz-zzzzz"), ErrInvalidCode)
+	assert.ErrorIs(t, verifyErr(f.usecase, ctx, "42", codes[0]), ErrTooManyAttempts)
+}
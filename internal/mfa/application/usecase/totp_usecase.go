@@ -0,0 +1,249 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"hub-user-service/internal/audit"
+	"hub-user-service/internal/mfa/domain/model"
+	"hub-user-service/internal/mfa/domain/recovery"
+	"hub-user-service/internal/mfa/domain/repository"
+	"hub-user-service/internal/mfa/domain/totp"
+	"hub-user-service/internal/mfa/infra/crypto"
+	"hub-user-service/internal/ratelimit"
+)
+
+var (
+	// ErrMFANotConfigured is returned when no encryption key is configured (MFA_ENCRYPTION_KEY)
+	ErrMFANotConfigured = errors.New("MFA is not configured")
+	// ErrAlreadyEnrolled is returned when starting an enrollment while a confirmed factor exists
+	ErrAlreadyEnrolled = errors.New("TOTP is already enabled")
+	// ErrNotEnrolled is returned when the user has no factor to confirm or verify
+	ErrNotEnrolled = errors.New("TOTP is not enabled")
+	// ErrInvalidCode is returned for a wrong or expired code
+	ErrInvalidCode = errors.New("invalid code")
+	// ErrCodeReused is returned for a code whose time step was already used
+	ErrCodeReused = errors.New("code was already used")
+	// ErrTooManyAttempts is returned once the user exceeded the attempts allowed
+	ErrTooManyAttempts = errors.New("too many attempts, try again later")
+)
+
+// Enrollment is a started TOTP enrollment, shown once to the user
+type Enrollment struct {
+	// URI is the otpauth:// key URI, rendered as a QR code
+	URI string
+	// Secret is the base32 secret for manual entry
+	Secret string
+}
+
+// Verification describes how a second factor was proven
+type Verification struct {
+	// RecoveryCodeUsed is set when a recovery code was accepted instead of a TOTP code
+	RecoveryCodeUsed bool
+	// RecoveryCodesRemaining is the number of unused recovery codes, set with RecoveryCodeUsed
+	RecoveryCodesRemaining int
+}
+
+type ITOTPUsecase interface {
+	// BeginEnrollment creates a new pending secret for the user, replacing a previous pending one
+	BeginEnrollment(ctx context.Context, userID string, accountName string) (*Enrollment, error)
+	// ConfirmEnrollment enables MFA once the user proves the app generates valid codes and
+	// returns the recovery codes, which are only shown this once
+	ConfirmEnrollment(ctx context.Context, userID string, code string) ([]string, error)
+	// Verify checks a TOTP code or a recovery code for a user with MFA enabled, rejecting reused codes
+	Verify(ctx context.Context, userID string, code string) (*Verification, error)
+	// RegenerateRecoveryCodes replaces the user's recovery codes once code (TOTP or recovery) is verified
+	RegenerateRecoveryCodes(ctx context.Context, userID string, code string) ([]string, error)
+	// IsEnabled reports whether login requires a second factor; it needs no encryption key
+	IsEnabled(ctx context.Context, userID string) (bool, error)
+}
+
+// TOTPConfig configures TOTPUsecase
+type TOTPConfig struct {
+	// Issuer is shown by authenticator apps next to the account name
+	Issuer string
+	// Skew is the number of time steps accepted before and after the current one
+	Skew int
+	// Limiter bounds the codes checked per user, against brute force; nil disables the limit
+	Limiter ratelimit.Limiter
+	// MaxAttempts codes are checked per AttemptWindow
+	MaxAttempts   int
+	AttemptWindow time.Duration
+	// RecoveryCodes is the number of recovery codes generated per user
+	RecoveryCodes int
+	// Audit records recovery code use and regeneration; nil disables auditing
+	Audit audit.Recorder
+}
+
+type TOTPUsecase struct {
+	repo   repository.ITOTPRepository
+	codes  repository.IRecoveryCodeRepository
+	box    crypto.ISecretBox
+	config TOTPConfig
+	now    func() time.Time
+}
+
+// NewTOTPUsecase creates the TOTP use case; box may be nil when MFA_ENCRYPTION_KEY is not set,
+// in which case only IsEnabled works
+func NewTOTPUsecase(repo repository.ITOTPRepository, codes repository.IRecoveryCodeRepository, box crypto.ISecretBox, config TOTPConfig) ITOTPUsecase {
+	if config.Audit == nil {
+		config.Audit = audit.NopRecorder{}
+	}
+	return &TOTPUsecase{repo: repo, codes: codes, box: box, config: config, now: time.Now}
+}
+
+func (u *TOTPUsecase) BeginEnrollment(ctx context.Context, userID string, accountName string) (*Enrollment, error) {
+	if u.box == nil {
+		return nil, ErrMFANotConfigured
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := u.box.Seal(secret, []byte(userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt TOTP secret: %w", err)
+	}
+
+	err = u.repo.SavePendingTOTPFactor(ctx, &model.TOTPFactor{UserID: userID, EncryptedSecret: sealed})
+	if errors.Is(err, repository.ErrTOTPAlreadyConfirmed) {
+		return nil, ErrAlreadyEnrolled
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &Enrollment{
+		URI:    totp.URI(u.config.Issuer, accountName, secret),
+		Secret: totp.EncodeSecret(secret),
+	}, nil
+}
+
+func (u *TOTPUsecase) ConfirmEnrollment(ctx context.Context, userID string, code string) ([]string, error) {
+	factor, err := u.factor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if factor.IsConfirmed() {
+		return nil, ErrAlreadyEnrolled
+	}
+
+	step, err := u.check(ctx, factor, code)
+	if err != nil {
+		return nil, err
+	}
+
+	// Store the codes first: codes of a pending factor are harmless, MFA without codes is not
+	codes, err := u.replaceRecoveryCodes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.repo.ConfirmTOTPFactor(ctx, userID, step); err != nil {
+		if errors.Is(err, repository.ErrTOTPFactorNotFound) {
+			return nil, ErrNotEnrolled
+		}
+		return nil, err
+	}
+
+	u.audit(ctx, audit.Entry{Action: audit.ActionMFAEnabled, UserID: userID})
+	return codes, nil
+}
+
+func (u *TOTPUsecase) Verify(ctx context.Context, userID string, code string) (*Verification, error) {
+	factor, err := u.factor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !factor.IsConfirmed() {
+		return nil, ErrNotEnrolled
+	}
+
+	if recovery.IsCode(code) {
+		return u.useRecoveryCode(ctx, userID, code)
+	}
+
+	step, err := u.check(ctx, factor, code)
+	if err != nil {
+		return nil, err
+	}
+
+	// The conditional update makes concurrent use of the same code succeed only once
+	fresh, err := u.repo.UseTOTPStep(ctx, userID, step)
+	if err != nil {
+		return nil, err
+	}
+	if !fresh {
+		return nil, ErrCodeReused
+	}
+	return &Verification{}, nil
+}
+
+func (u *TOTPUsecase) IsEnabled(ctx context.Context, userID string) (bool, error) {
+	factor, err := u.repo.GetTOTPFactor(ctx, userID)
+	if errors.Is(err, repository.ErrTOTPFactorNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return factor.IsConfirmed(), nil
+}
+
+// factor loads the user's factor once MFA is known to be usable
+func (u *TOTPUsecase) factor(ctx context.Context, userID string) (*model.TOTPFactor, error) {
+	if u.box == nil {
+		return nil, ErrMFANotConfigured
+	}
+
+	factor, err := u.repo.GetTOTPFactor(ctx, userID)
+	if errors.Is(err, repository.ErrTOTPFactorNotFound) {
+		return nil, ErrNotEnrolled
+	}
+	return factor, err
+}
+
+// check decrypts the secret and matches code, counting the attempt against the limit.
+// The returned step is not yet recorded as used.
+func (u *TOTPUsecase) check(ctx context.Context, factor *model.TOTPFactor, code string) (int64, error) {
+	if err := u.checkAttempts(ctx, factor.UserID); err != nil {
+		return 0, err
+	}
+
+	secret, err := u.box.Open(factor.EncryptedSecret, []byte(factor.UserID))
+	if err != nil {
+		return 0, fmt.Errorf("failed to decrypt TOTP secret: %w", err)
+	}
+
+	step, ok := totp.Verify(secret, code, u.now(), u.config.Skew)
+	if !ok {
+		return 0, ErrInvalidCode
+	}
+	if step <= factor.LastUsedStep {
+		return 0, ErrCodeReused
+	}
+	return step, nil
+}
+
+// checkAttempts takes one attempt from the user's budget
+func (u *TOTPUsecase) checkAttempts(ctx context.Context, userID string) error {
+	if u.config.Limiter == nil || u.config.MaxAttempts <= 0 || u.config.AttemptWindow <= 0 {
+		return nil
+	}
+
+	limit := ratelimit.Limit{
+		Rate:  float64(u.config.MaxAttempts) / u.config.AttemptWindow.Seconds(),
+		Burst: u.config.MaxAttempts,
+	}
+	result, err := u.config.Limiter.Allow(ctx, "mfa:totp:"+userID, limit)
+	if err != nil {
+		return err
+	}
+	if !result.Allowed {
+		return ErrTooManyAttempts
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"hub-user-service/internal/mfa/domain/totp"
+	"hub-user-service/internal/mfa/infra/crypto"
+	"hub-user-service/internal/mfa/infra/persistence"
+	"hub-user-service/internal/ratelimit"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testNow = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+type totpFixture struct {
+	usecase *TOTPUsecase
+	repo    *persistence.MemoryTOTPRepository
+	codes   *persistence.MemoryRecoveryCodeRepository
+}
+
+func newFixture(t *testing.T, config TOTPConfig) *totpFixture {
+	t.Helper()
+	box, err := crypto.NewSecretBox(bytes.Repeat([]byte{1}, crypto.KeySize))
+	require.NoError(t, err)
+
+	repo := persistence.NewMemoryTOTPRepository()
+	codes := persistence.NewMemoryRecoveryCodeRepository()
+	if config.Issuer == "" {
+		config.Issuer = "Hub Investments"
+	}
+	uc := NewTOTPUsecase(repo, codes, box, config).(*TOTPUsecase)
+	uc.now = func() time.Time { return testNow }
+	return &totpFixture{usecase: uc, repo: repo, codes: codes}
+}
+
+// enroll begins and confirms an enrollment, returning the shared secret
+func (f *totpFixture) enroll(t *testing.T, userID string) []byte {
+	t.Helper()
+	enrollment, err := f.usecase.BeginEnrollment(context.Background(), userID, "ada@example.com")
+	require.NoError(t, err)
+	secret := secretOf(t, enrollment)
+
+	require.NoError(t, confirmErr(f.usecase, context.Background(), userID, totp.Code(secret, totp.Step(testNow)-1)))
+	return secret
+}
+
+// confirmErr returns the error of ConfirmEnrollment
+func confirmErr(uc ITOTPUsecase, ctx context.Context, userID string, code string) error {
+	_, err := uc.ConfirmEnrollment(ctx, userID, code)
+	return err
+}
+
+// verifyErr returns the error of Verify
+func verifyErr(uc ITOTPUsecase, ctx context.Context, userID string, code string) error {
+	_, err := uc.Verify(ctx, userID, code)
+	return err
+}
+
+func secretOf(t *testing.T, enrollment *Enrollment) []byte {
+	t.Helper()
+	uri, err := url.Parse(enrollment.URI)
+	require.NoError(t, err)
+	assert.Equal(t, enrollment.Secret, uri.Query().Get("secret"))
+
+	secret, err := totp.DecodeSecret(enrollment.Secret)
+	require.NoError(t, err)
+	return secret
+}
+
+func TestTOTPUsecase_EnrollmentFlow(t *testing.T) {
+	f := newFixture(t, TOTPConfig{Skew: 1})
+	ctx := context.Background()
+
+	enabled, err := f.usecase.IsEnabled(ctx, "42")
+	require.NoError(t, err)
+	assert.False(t, enabled)
+
+	enrollment, err := f.usecase.BeginEnrollment(ctx, "42", "ada@example.com")
+	require.NoError(t, err)
+	assert.Contains(t, enrollment.URI, "otpauth://totp/Hub%20Investments:ada@example.com?")
+
+	stored, err := f.repo.GetTOTPFactor(ctx, "42")
+	require.NoError(t, err)
+	assert.NotContains(t, string(stored.EncryptedSecret), string(secretOf(t, enrollment)), "secret is encrypted at rest")
+
+	enabled, _ = f.usecase.IsEnabled(ctx, "42")
+	assert.False(t, enabled, "pending enrollment does not enable MFA")
+
+	assert.ErrorIs(t, confirmErr(f.usecase, ctx, "42", "000000"), ErrInvalidCode)
+	require.NoError(t, confirmErr(f.usecase, ctx, "42", totp.Code(secretOf(t, enrollment), totp.Step(testNow))))
+
+	enabled, _ = f.usecase.IsEnabled(ctx, "42")
+	assert.True(t, enabled)
+
+	_, err = f.usecase.BeginEnrollment(ctx, "42", "ada@example.com")
+	assert.ErrorIs(t, err, ErrAlreadyEnrolled)
+}
+
+func TestTOTPUsecase_RestartingEnrollmentReplacesPendingSecret(t *testing.T) {
+	f := newFixture(t, TOTPConfig{Skew: 1})
+	ctx := context.Background()
+
+	first, err := f.usecase.BeginEnrollment(ctx, "42", "ada@example.com")
+	require.NoError(t, err)
+	second, err := f.usecase.BeginEnrollment(ctx, "42", "ada@example.com")
+	require.NoError(t, err)
+
+	assert.NotEqual(t, first.Secret, second.Secret)
+	assert.ErrorIs(t, confirmErr(f.usecase, ctx, "42", totp.Code(secretOf(t, first), totp.Step(testNow))), ErrInvalidCode)
+}
+
+func TestTOTPUsecase_VerifyRejectsReplay(t *testing.T) {
+	f := newFixture(t, TOTPConfig{Skew: 1})
+	ctx := context.Background()
+	secret := f.enroll(t, "42")
+
+	// The step used for confirmation can not be used to log in
+	assert.ErrorIs(t, verifyErr(f.usecase, ctx, "42", totp.Code(secret, totp.Step(testNow)-1)), ErrCodeReused)
+
+	code := totp.Code(secret, totp.Step(testNow))
+	require.NoError(t, verifyErr(f.usecase, ctx, "42", code))
+	assert.ErrorIs(t, verifyErr(f.usecase, ctx, "42", code), ErrCodeReused)
+
+	require.NoError(t, verifyErr(f.usecase, ctx, "42", totp.Code(secret, totp.Step(testNow)+1)))
+}
+
+func TestTOTPUsecase_VerifyErrors(t *testing.T) {
+	f := newFixture(t, TOTPConfig{Skew: 1})
+	ctx := context.Background()
+
+	assert.ErrorIs(t, verifyErr(f.usecase, ctx, "42", "123456"), ErrNotEnrolled)
+
+	_, err := f.usecase.BeginEnrollment(ctx, "42", "ada@example.com")
+	require.NoError(t, err)
+	assert.ErrorIs(t, verifyErr(f.usecase, ctx, "42", "123456"), ErrNotEnrolled, "pending factors can not be used to log in")
+
+	f.enroll(t, "7")
+	assert.ErrorIs(t, verifyErr(f.usecase, ctx, "7", "123456"), ErrInvalidCode)
+}
+
+func TestTOTPUsecase_LimitsAttempts(t *testing.T) {
+	f := newFixture(t, TOTPConfig{
+		Skew:          1,
+		Limiter:       ratelimit.NewMemoryLimiter(),
+		MaxAttempts:   3,
+		AttemptWindow: 5 * time.Minute,
+	})
+	ctx := context.Background()
+	secret := f.enroll(t, "42")
+
+	assert.ErrorIs(t, verifyErr(f.usecase, ctx, "42", "000000"), ErrInvalidCode)
+	assert.ErrorIs(t, verifyErr(f.usecase, ctx, "42", "000001"), ErrInvalidCode)
+	assert.ErrorIs(t, verifyErr(f.usecase, ctx, "42", totp.Code(secret, totp.Step(testNow))), ErrTooManyAttempts)
+}
+
+func TestTOTPUsecase_WithoutEncryptionKey(t *testing.T) {
+	repo := persistence.NewMemoryTOTPRepository()
+	uc := NewTOTPUsecase(repo, persistence.NewMemoryRecoveryCodeRepository(), nil, TOTPConfig{})
+	ctx := context.Background()
+
+	_, err := uc.BeginEnrollment(ctx, "42", "ada@example.com")
+	assert.ErrorIs(t, err, ErrMFANotConfigured)
+	assert.ErrorIs(t, verifyErr(uc, ctx, "42", "123456"), ErrMFANotConfigured)
+
+	enabled, err := uc.IsEnabled(ctx, "42")
+	assert.NoError(t, err)
+	assert.False(t, enabled)
+}
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// TOTPFactor is a user's authenticator app enrollment
+// The shared secret is only held encrypted; LastUsedStep is the most recent accepted
+// time step, so a code can not be used twice
+type TOTPFactor struct {
+	UserID          string
+	EncryptedSecret []byte
+	ConfirmedAt     *time.Time
+	LastUsedStep    int64
+	CreatedAt       time.Time
+}
+
+// IsConfirmed reports whether enrollment completed, i.e. MFA is enforced at login
+func (f *TOTPFactor) IsConfirmed() bool {
+	return f.ConfirmedAt != nil
+}
@@ -0,0 +1,66 @@
+package recovery
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+)
+
+// CodeLength is the number of characters of a code, 50 random bits in the alphabet below
+const CodeLength = 10
+
+// alphabet is Crockford's base32 without i, l, o and u, so codes can be read back unambiguously
+const alphabet = "0123456789abcdefghjkmnpqrstvwxyz"
+
+// Generate returns count new codes formatted as xxxxx-xxxxx
+func Generate(count int) ([]string, error) {
+	codes := make([]string, count)
+	buf := make([]byte, CodeLength)
+	for i := range codes {
+		if _, err := rand.Read(buf); err != nil {
+			return nil, fmt.Errorf("failed to generate recovery code: %w", err)
+		}
+		var code strings.Builder
+		for j, b := range buf {
+			if j == CodeLength/2 {
+				code.WriteByte('-')
+			}
+			code.WriteByte(alphabet[b&31])
+		}
+		codes[i] = code.String()
+	}
+	return codes, nil
+}
+
+// Normalize drops separators and case so codes can be typed the way users copied them
+func Normalize(code string) string {
+	code = strings.ToLower(code)
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, code)
+}
+
+// IsCode reports whether code has the shape of a recovery code rather than a TOTP code
+func IsCode(code string) bool {
+	normalized := Normalize(code)
+	if len(normalized) != CodeLength {
+		return false
+	}
+	for _, r := range normalized {
+		if !strings.ContainsRune(alphabet, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// Hash returns the stored form of a code. Codes carry 50 random bits and attempts are rate
+// limited, so a fast hash bound to the user is enough and keeps lookups indexable.
+func Hash(userID string, code string) []byte {
+	sum := sha256.Sum256([]byte(userID + ":" + Normalize(code)))
+	return sum[:]
+}
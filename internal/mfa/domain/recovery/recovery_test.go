@@ -0,0 +1,40 @@
+package recovery
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	codes, err := Generate(10)
+	require.NoError(t, err)
+	require.Len(t, codes, 10)
+
+	format := regexp.MustCompile(`^[0-9a-hjkmnp-tv-z]{5}-[0-9a-hjkmnp-tv-z]{5}$`)
+	seen := map[string]bool{}
+	for _, code := range codes {
+		assert.Regexp(t, format, code)
+		assert.True(t, IsCode(code))
+		assert.False(t, seen[code], "codes are unique")
+		seen[code] = true
+	}
+}
+
+func TestIsCode(t *testing.T) {
+	assert.True(t, IsCode("abcde-12345"))
+	assert.True(t, IsCode("ABCDE 12345"))
+	assert.True(t, IsCode("abcde12345"))
+	assert.False(t, IsCode("123456"), "TOTP code")
+	assert.False(t, IsCode("abcde-1234"))
+	assert.False(t, IsCode("abcdi-12345"), "i is not in the alphabet")
+}
+
+func TestHash(t *testing.T) {
+	assert.Equal(t, Hash("42", "abcde-12345"), Hash("42", "ABCDE12345"))
+	assert.NotEqual(t, Hash("42", "abcde-12345"), Hash("43", "abcde-12345"), "hashes are bound to the user")
+	assert.NotEqual(t, Hash("42", "abcde-12345"), Hash("42", "abcde-12346"))
+	assert.Len(t, Hash("42", "abcde-12345"), 32)
+}
@@ -0,0 +1,14 @@
+package repository
+
+import "context"
+
+// IRecoveryCodeRepository stores the hashes of the users' MFA recovery codes
+type IRecoveryCodeRepository interface {
+	// ReplaceRecoveryCodes atomically replaces every code of the user, used or not
+	ReplaceRecoveryCodes(ctx context.Context, userID string, hashes [][]byte) error
+	// UseRecoveryCode atomically marks an unused code as used; it returns false if the user
+	// has no unused code with this hash
+	UseRecoveryCode(ctx context.Context, userID string, hash []byte) (bool, error)
+	// CountRecoveryCodes returns the number of unused codes
+	CountRecoveryCodes(ctx context.Context, userID string) (int, error)
+}
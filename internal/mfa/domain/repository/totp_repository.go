@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"hub-user-service/internal/mfa/domain/model"
+)
+
+var (
+	// ErrTOTPFactorNotFound is returned when the user has no (pending) TOTP factor
+	ErrTOTPFactorNotFound = errors.New("totp factor not found")
+	// ErrTOTPAlreadyConfirmed is returned when replacing a confirmed factor
+	ErrTOTPAlreadyConfirmed = errors.New("totp factor already confirmed")
+)
+
+type ITOTPRepository interface {
+	GetTOTPFactor(ctx context.Context, userID string) (*model.TOTPFactor, error)
+	// SavePendingTOTPFactor creates or replaces the user's unconfirmed factor
+	SavePendingTOTPFactor(ctx context.Context, factor *model.TOTPFactor) error
+	// ConfirmTOTPFactor enables the pending factor, recording step as used
+	ConfirmTOTPFactor(ctx context.Context, userID string, step int64) error
+	// UseTOTPStep atomically records step as used; it returns false if step is not newer
+	// than the last used step (a replayed code)
+	UseTOTPStep(ctx context.Context, userID string, step int64) (bool, error)
+}
@@ -0,0 +1,85 @@
+package totp
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret is the SHA1 test key of RFC 6238 appendix B
+var rfcSecret = []byte("12345678901234567890")
+
+func TestCode_RFC6238Vectors(t *testing.T) {
+	// RFC 6238 lists 8-digit codes; the 6-digit code is their last six digits
+	vectors := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+	for unix, want := range vectors {
+		assert.Equal(t, want, Code(rfcSecret, Step(time.Unix(unix, 0))), unix)
+	}
+}
+
+func TestVerify_AcceptsCodesWithinSkew(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	previous := Code(rfcSecret, Step(now)-1)
+
+	step, ok := Verify(rfcSecret, previous, now, 1)
+	assert.True(t, ok)
+	assert.Equal(t, Step(now)-1, step)
+
+	_, ok = Verify(rfcSecret, previous, now, 0)
+	assert.False(t, ok)
+
+	_, ok = Verify(rfcSecret, Code(rfcSecret, Step(now)-2), now, 1)
+	assert.False(t, ok)
+}
+
+func TestVerify_RejectsMalformedCodes(t *testing.T) {
+	now := time.Unix(59, 0)
+	for _, code := range []string{"", "28708", "2870820", "abcdef"} {
+		_, ok := Verify(rfcSecret, code, now, 1)
+		assert.False(t, ok, code)
+	}
+
+	_, ok := Verify(rfcSecret, " 287082 ", now, 0)
+	assert.True(t, ok)
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("Hub Investments", "ada@example.com", rfcSecret)
+
+	parsed, err := url.Parse(uri)
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", parsed.Scheme)
+	assert.Equal(t, "totp", parsed.Host)
+	assert.Equal(t, "/Hub Investments:ada@example.com", parsed.Path)
+	assert.Equal(t, "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", parsed.Query().Get("secret"))
+	assert.Equal(t, "Hub Investments", parsed.Query().Get("issuer"))
+	assert.Equal(t, "6", parsed.Query().Get("digits"))
+	assert.Equal(t, "30", parsed.Query().Get("period"))
+}
+
+func TestGenerateSecret(t *testing.T) {
+	a, err := GenerateSecret()
+	require.NoError(t, err)
+	b, err := GenerateSecret()
+	require.NoError(t, err)
+
+	assert.Len(t, a, SecretSize)
+	assert.NotEqual(t, a, b)
+}
+
+func TestDecodeSecret_AcceptsManualEntryFormatting(t *testing.T) {
+	secret, err := DecodeSecret("gezd gnbv gy3t qojq gezd gnbv gy3t qojq")
+
+	require.NoError(t, err)
+	assert.Equal(t, rfcSecret, secret)
+}
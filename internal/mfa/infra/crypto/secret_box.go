@@ -0,0 +1,84 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// KeySize is the AES-256 key length expected in MFA_ENCRYPTION_KEY
+const KeySize = 32
+
+// version prefixes every ciphertext so the format or key can be rotated later
+const version byte = 1
+
+// ISecretBox encrypts small secrets at rest
+type ISecretBox interface {
+	// Seal encrypts plaintext bound to associatedData (e.g. the owning user id)
+	Seal(plaintext, associatedData []byte) ([]byte, error)
+	// Open decrypts a Seal output; it fails if the ciphertext or associatedData was altered
+	Open(ciphertext, associatedData []byte) ([]byte, error)
+}
+
+// SecretBox implements ISecretBox with AES-256-GCM and a random nonce per secret
+type SecretBox struct {
+	aead cipher.AEAD
+}
+
+// NewSecretBox creates a secret box from a 32 byte key
+func NewSecretBox(key []byte) (*SecretBox, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("encryption key must be %d bytes, got %d", KeySize, len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &SecretBox{aead: aead}, nil
+}
+
+// ParseKey decodes a standard base64 encoded key
+func ParseKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("encryption key is not valid base64: %w", err)
+	}
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("encryption key must decode to %d bytes, got %d", KeySize, len(key))
+	}
+	return key, nil
+}
+
+// Seal returns version || nonce || ciphertext
+func (b *SecretBox) Seal(plaintext, associatedData []byte) ([]byte, error) {
+	nonce := make([]byte, b.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	out := append([]byte{version}, nonce...)
+	return b.aead.Seal(out, nonce, plaintext, associatedData), nil
+}
+
+// Open decrypts a Seal output
+func (b *SecretBox) Open(ciphertext, associatedData []byte) ([]byte, error) {
+	nonceSize := b.aead.NonceSize()
+	if len(ciphertext) < 1+nonceSize+b.aead.Overhead() || ciphertext[0] != version {
+		return nil, errors.New("unsupported ciphertext format")
+	}
+
+	nonce := ciphertext[1 : 1+nonceSize]
+	plaintext, err := b.aead.Open(nil, nonce, ciphertext[1+nonceSize:], associatedData)
+	if err != nil {
+		return nil, errors.New("failed to decrypt secret")
+	}
+	return plaintext, nil
+}
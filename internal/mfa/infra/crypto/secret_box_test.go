@@ -0,0 +1,72 @@
+package crypto
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBox(t *testing.T) *SecretBox {
+	t.Helper()
+	box, err := NewSecretBox(bytes.Repeat([]byte{7}, KeySize))
+	require.NoError(t, err)
+	return box
+}
+
+func TestSecretBox_RoundTrip(t *testing.T) {
+	box := newTestBox(t)
+
+	sealed, err := box.Seal([]byte("totp secret"), []byte("42"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(sealed), "totp secret")
+
+	opened, err := box.Open(sealed, []byte("42"))
+	require.NoError(t, err)
+	assert.Equal(t, "totp secret", string(opened))
+}
+
+func TestSecretBox_UsesFreshNonces(t *testing.T) {
+	box := newTestBox(t)
+
+	a, _ := box.Seal([]byte("secret"), nil)
+	b, _ := box.Seal([]byte("secret"), nil)
+
+	assert.NotEqual(t, a, b)
+}
+
+func TestSecretBox_RejectsTamperingAndOtherUsers(t *testing.T) {
+	box := newTestBox(t)
+	sealed, err := box.Seal([]byte("secret"), []byte("42"))
+	require.NoError(t, err)
+
+	_, err = box.Open(sealed, []byte("43"))
+	assert.Error(t, err, "ciphertext is bound to its user")
+
+	tampered := append([]byte(nil), sealed...)
+	tampered[len(tampered)-1] ^= 1
+	_, err = box.Open(tampered, []byte("42"))
+	assert.Error(t, err)
+
+	_, err = box.Open(sealed[:5], []byte("42"))
+	assert.Error(t, err)
+
+	other, err := NewSecretBox(bytes.Repeat([]byte{8}, KeySize))
+	require.NoError(t, err)
+	_, err = other.Open(sealed, []byte("42"))
+	assert.Error(t, err)
+}
+
+func TestParseKey(t *testing.T) {
+	key, err := ParseKey(base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, KeySize)))
+	require.NoError(t, err)
+	assert.Len(t, key, KeySize)
+
+	_, err = ParseKey("not base64!")
+	assert.Error(t, err)
+
+	_, err = ParseKey(base64.StdEncoding.EncodeToString([]byte("short")))
+	assert.Error(t, err)
+}
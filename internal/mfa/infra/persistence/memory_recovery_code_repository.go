@@ -0,0 +1,62 @@
+package persistence
+
+import (
+	"bytes"
+	"context"
+	"sync"
+)
+
+// memoryRecoveryCode is a stored code hash and whether it was used
+type memoryRecoveryCode struct {
+	hash []byte
+	used bool
+}
+
+// MemoryRecoveryCodeRepository keeps recovery codes in process memory (DB_DRIVER=memory)
+type MemoryRecoveryCodeRepository struct {
+	mu    sync.Mutex
+	codes map[string][]memoryRecoveryCode
+}
+
+// NewMemoryRecoveryCodeRepository creates an empty in-memory recovery code repository
+func NewMemoryRecoveryCodeRepository() *MemoryRecoveryCodeRepository {
+	return &MemoryRecoveryCodeRepository{codes: make(map[string][]memoryRecoveryCode)}
+}
+
+func (r *MemoryRecoveryCodeRepository) ReplaceRecoveryCodes(ctx context.Context, userID string, hashes [][]byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	codes := make([]memoryRecoveryCode, len(hashes))
+	for i, hash := range hashes {
+		codes[i] = memoryRecoveryCode{hash: append([]byte(nil), hash...)}
+	}
+	r.codes[userID] = codes
+	return nil
+}
+
+func (r *MemoryRecoveryCodeRepository) UseRecoveryCode(ctx context.Context, userID string, hash []byte) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, code := range r.codes[userID] {
+		if !code.used && bytes.Equal(code.hash, hash) {
+			r.codes[userID][i].used = true
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *MemoryRecoveryCodeRepository) CountRecoveryCodes(ctx context.Context, userID string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for _, code := range r.codes[userID] {
+		if !code.used {
+			count++
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,76 @@
+package persistence
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"hub-user-service/internal/mfa/domain/model"
+	"hub-user-service/internal/mfa/domain/repository"
+)
+
+// MemoryTOTPRepository keeps TOTP factors in process memory (DB_DRIVER=memory)
+type MemoryTOTPRepository struct {
+	mu      sync.Mutex
+	factors map[string]model.TOTPFactor
+}
+
+// NewMemoryTOTPRepository creates an empty in-memory TOTP repository
+func NewMemoryTOTPRepository() *MemoryTOTPRepository {
+	return &MemoryTOTPRepository{factors: make(map[string]model.TOTPFactor)}
+}
+
+func (r *MemoryTOTPRepository) GetTOTPFactor(ctx context.Context, userID string) (*model.TOTPFactor, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	factor, ok := r.factors[userID]
+	if !ok {
+		return nil, repository.ErrTOTPFactorNotFound
+	}
+	factor.EncryptedSecret = append([]byte(nil), factor.EncryptedSecret...)
+	return &factor, nil
+}
+
+func (r *MemoryTOTPRepository) SavePendingTOTPFactor(ctx context.Context, factor *model.TOTPFactor) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.factors[factor.UserID]; ok && existing.IsConfirmed() {
+		return repository.ErrTOTPAlreadyConfirmed
+	}
+	r.factors[factor.UserID] = model.TOTPFactor{
+		UserID:          factor.UserID,
+		EncryptedSecret: append([]byte(nil), factor.EncryptedSecret...),
+		CreatedAt:       time.Now(),
+	}
+	return nil
+}
+
+func (r *MemoryTOTPRepository) ConfirmTOTPFactor(ctx context.Context, userID string, step int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	factor, ok := r.factors[userID]
+	if !ok || factor.IsConfirmed() {
+		return repository.ErrTOTPFactorNotFound
+	}
+	now := time.Now()
+	factor.ConfirmedAt = &now
+	factor.LastUsedStep = step
+	r.factors[userID] = factor
+	return nil
+}
+
+func (r *MemoryTOTPRepository) UseTOTPStep(ctx context.Context, userID string, step int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	factor, ok := r.factors[userID]
+	if !ok || !factor.IsConfirmed() || factor.LastUsedStep >= step {
+		return false, nil
+	}
+	factor.LastUsedStep = step
+	r.factors[userID] = factor
+	return true, nil
+}
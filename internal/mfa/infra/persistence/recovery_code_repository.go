@@ -0,0 +1,55 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+
+	"hub-user-service/internal/database"
+	"hub-user-service/internal/mfa/domain/repository"
+
+	"github.com/lib/pq"
+)
+
+type RecoveryCodeRepository struct {
+	db database.Querier
+}
+
+// NewRecoveryCodeRepository creates a recovery code repository on a database or a transaction
+func NewRecoveryCodeRepository(db database.Querier) repository.IRecoveryCodeRepository {
+	return &RecoveryCodeRepository{db: db}
+}
+
+func (r *RecoveryCodeRepository) ReplaceRecoveryCodes(ctx context.Context, userID string, hashes [][]byte) error {
+	// A single statement, so a failure can not leave the user without codes
+	query := `WITH removed AS (DELETE FROM user_recovery_codes WHERE user_id = $1)
+		INSERT INTO user_recovery_codes (user_id, code_hash) SELECT $1, unnest($2::bytea[])`
+
+	if _, err := r.db.ExecContext(ctx, query, userID, pq.ByteaArray(hashes)); err != nil {
+		return fmt.Errorf("failed to replace recovery codes: %w", err)
+	}
+	return nil
+}
+
+func (r *RecoveryCodeRepository) UseRecoveryCode(ctx context.Context, userID string, hash []byte) (bool, error) {
+	query := "UPDATE user_recovery_codes SET used_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL"
+
+	result, err := r.db.ExecContext(ctx, query, userID, hash)
+	if err != nil {
+		return false, fmt.Errorf("failed to use recovery code: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to read affected rows: %w", err)
+	}
+	return affected == 1, nil
+}
+
+func (r *RecoveryCodeRepository) CountRecoveryCodes(ctx context.Context, userID string) (int, error) {
+	query := "SELECT COUNT(*) FROM user_recovery_codes WHERE user_id = $1 AND used_at IS NULL"
+
+	var count int
+	if err := r.db.GetContext(ctx, &count, query, userID); err != nil {
+		return 0, fmt.Errorf("failed to count recovery codes: %w", err)
+	}
+	return count, nil
+}
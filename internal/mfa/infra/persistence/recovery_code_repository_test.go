@@ -0,0 +1,83 @@
+package persistence
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"hub-user-service/internal/database/databasetest"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecoveryCodeRepository_ReplaceIsOneStatement(t *testing.T) {
+	db := &databasetest.MockQuerier{}
+	hashes := [][]byte{{1}, {2}}
+	db.On("ExecContext", mock.Anything, databasetest.QueryContaining("DELETE FROM user_recovery_codes", "unnest($2::bytea[])"), []interface{}{"42", pq.ByteaArray(hashes)}).
+		Return(databasetest.Result(2), nil).Once()
+
+	require.NoError(t, NewRecoveryCodeRepository(db).ReplaceRecoveryCodes(context.Background(), "42", hashes))
+	db.AssertExpectations(t)
+}
+
+func TestRecoveryCodeRepository_UseIsSingleUse(t *testing.T) {
+	db := databasetest.OpenSQLite(t, mfaSchema)
+	repo := NewRecoveryCodeRepository(db)
+	ctx := context.Background()
+	// ReplaceRecoveryCodes is PostgreSQL-only (unnest), the codes are inserted directly
+	_, err := db.ExecContext(ctx, "INSERT INTO user_recovery_codes (user_id, code_hash) VALUES (42, $1), (42, $2)", []byte{1}, []byte{2})
+	require.NoError(t, err)
+
+	// Concurrent logins with the same code: exactly one uses it
+	var accepted atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if used, err := repo.UseRecoveryCode(ctx, "42", []byte{1}); err == nil && used {
+				accepted.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), accepted.Load())
+
+	used, err := repo.UseRecoveryCode(ctx, "7", []byte{2})
+	require.NoError(t, err)
+	assert.False(t, used, "codes belong to their user")
+
+	count, err := repo.CountRecoveryCodes(ctx, "42")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestMemoryRecoveryCodeRepository_Lifecycle(t *testing.T) {
+	repo := NewMemoryRecoveryCodeRepository()
+	ctx := context.Background()
+
+	require.NoError(t, repo.ReplaceRecoveryCodes(ctx, "42", [][]byte{{1}, {2}, {3}}))
+	count, _ := repo.CountRecoveryCodes(ctx, "42")
+	assert.Equal(t, 3, count)
+
+	used, err := repo.UseRecoveryCode(ctx, "42", []byte{2})
+	require.NoError(t, err)
+	assert.True(t, used)
+	used, _ = repo.UseRecoveryCode(ctx, "42", []byte{2})
+	assert.False(t, used, "a code is accepted once")
+	used, _ = repo.UseRecoveryCode(ctx, "7", []byte{1})
+	assert.False(t, used, "codes belong to their user")
+
+	count, _ = repo.CountRecoveryCodes(ctx, "42")
+	assert.Equal(t, 2, count)
+
+	require.NoError(t, repo.ReplaceRecoveryCodes(ctx, "42", [][]byte{{4}}))
+	used, _ = repo.UseRecoveryCode(ctx, "42", []byte{1})
+	assert.False(t, used, "replaced codes are invalid")
+	count, _ = repo.CountRecoveryCodes(ctx, "42")
+	assert.Equal(t, 1, count)
+}
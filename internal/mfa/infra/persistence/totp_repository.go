@@ -0,0 +1,102 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"hub-user-service/internal/database"
+	"hub-user-service/internal/mfa/domain/model"
+	"hub-user-service/internal/mfa/domain/repository"
+)
+
+type TOTPRepository struct {
+	db database.Querier
+}
+
+// totpFactorDTO represents the user_totp table
+type totpFactorDTO struct {
+	UserID           string       `db:"user_id"`
+	SecretCiphertext []byte       `db:"secret_ciphertext"`
+	ConfirmedAt      sql.NullTime `db:"confirmed_at"`
+	LastUsedStep     int64        `db:"last_used_step"`
+	CreatedAt        sql.NullTime `db:"created_at"`
+}
+
+// NewTOTPRepository creates a TOTP repository on a database or a transaction
+func NewTOTPRepository(db database.Querier) repository.ITOTPRepository {
+	return &TOTPRepository{db: db}
+}
+
+func (r *TOTPRepository) GetTOTPFactor(ctx context.Context, userID string) (*model.TOTPFactor, error) {
+	query := "SELECT user_id, secret_ciphertext, confirmed_at, last_used_step, created_at FROM user_totp WHERE user_id = $1"
+
+	var dto totpFactorDTO
+	if err := r.db.GetContext(ctx, &dto, query, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrTOTPFactorNotFound
+		}
+		return nil, fmt.Errorf("failed to load totp factor: %w", err)
+	}
+
+	factor := &model.TOTPFactor{
+		UserID:          dto.UserID,
+		EncryptedSecret: dto.SecretCiphertext,
+		LastUsedStep:    dto.LastUsedStep,
+		CreatedAt:       dto.CreatedAt.Time,
+	}
+	if dto.ConfirmedAt.Valid {
+		factor.ConfirmedAt = &dto.ConfirmedAt.Time
+	}
+	return factor, nil
+}
+
+func (r *TOTPRepository) SavePendingTOTPFactor(ctx context.Context, factor *model.TOTPFactor) error {
+	query := `INSERT INTO user_totp (user_id, secret_ciphertext) VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET
+			secret_ciphertext = EXCLUDED.secret_ciphertext, last_used_step = 0, created_at = CURRENT_TIMESTAMP
+		WHERE user_totp.confirmed_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, factor.UserID, factor.EncryptedSecret)
+	if err != nil {
+		return fmt.Errorf("failed to save totp factor: %w", err)
+	}
+	return requireOneRow(result, repository.ErrTOTPAlreadyConfirmed)
+}
+
+func (r *TOTPRepository) ConfirmTOTPFactor(ctx context.Context, userID string, step int64) error {
+	query := "UPDATE user_totp SET confirmed_at = CURRENT_TIMESTAMP, last_used_step = $2 WHERE user_id = $1 AND confirmed_at IS NULL"
+
+	result, err := r.db.ExecContext(ctx, query, userID, step)
+	if err != nil {
+		return fmt.Errorf("failed to confirm totp factor: %w", err)
+	}
+	return requireOneRow(result, repository.ErrTOTPFactorNotFound)
+}
+
+func (r *TOTPRepository) UseTOTPStep(ctx context.Context, userID string, step int64) (bool, error) {
+	query := "UPDATE user_totp SET last_used_step = $2 WHERE user_id = $1 AND confirmed_at IS NOT NULL AND last_used_step < $2"
+
+	result, err := r.db.ExecContext(ctx, query, userID, step)
+	if err != nil {
+		return false, fmt.Errorf("failed to record totp step: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to read affected rows: %w", err)
+	}
+	return affected == 1, nil
+}
+
+// requireOneRow returns notFound unless the statement affected a row
+func requireOneRow(result database.Result, notFound error) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to read affected rows: %w", err)
+	}
+	if affected == 0 {
+		return notFound
+	}
+	return nil
+}
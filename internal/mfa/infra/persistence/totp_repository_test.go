@@ -0,0 +1,134 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"hub-user-service/internal/database/databasetest"
+	"hub-user-service/internal/mfa/domain/model"
+	"hub-user-service/internal/mfa/domain/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// mfaSchema is the SQLite version of the user_totp and user_recovery_codes tables
+const mfaSchema = `
+INSERT INTO users (id, email, name, password) VALUES (42, 'dev@example.com', 'Dev', 'DevPass123!');
+CREATE TABLE user_totp (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret_ciphertext BLOB NOT NULL,
+    confirmed_at TIMESTAMP,
+    last_used_step INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE TABLE user_recovery_codes (
+    id INTEGER PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash BLOB NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, code_hash)
+);`
+
+func TestTOTPRepository_GetTOTPFactor(t *testing.T) {
+	confirmed := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	db := &databasetest.MockQuerier{}
+	db.On("GetContext", mock.Anything, mock.Anything, databasetest.QueryContaining("FROM user_totp WHERE user_id = $1"), []interface{}{"42"}).
+		Run(databasetest.Fill(totpFactorDTO{
+			UserID:           "42",
+			SecretCiphertext: []byte{1, 2, 3},
+			ConfirmedAt:      sql.NullTime{Time: confirmed, Valid: true},
+			LastUsedStep:     99,
+		})).Return(nil)
+	db.On("GetContext", mock.Anything, mock.Anything, mock.Anything, []interface{}{"7"}).Return(sql.ErrNoRows)
+
+	factor, err := NewTOTPRepository(db).GetTOTPFactor(context.Background(), "42")
+
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1, 2, 3}, factor.EncryptedSecret)
+	assert.Equal(t, &confirmed, factor.ConfirmedAt)
+	assert.Equal(t, int64(99), factor.LastUsedStep)
+
+	_, err = NewTOTPRepository(db).GetTOTPFactor(context.Background(), "7")
+	assert.ErrorIs(t, err, repository.ErrTOTPFactorNotFound)
+}
+
+func TestTOTPRepository_SavePendingRefusesConfirmedFactor(t *testing.T) {
+	repo := NewTOTPRepository(databasetest.OpenSQLite(t, mfaSchema))
+	ctx := context.Background()
+
+	require.NoError(t, repo.SavePendingTOTPFactor(ctx, &model.TOTPFactor{UserID: "42", EncryptedSecret: []byte{1}}))
+	require.NoError(t, repo.SavePendingTOTPFactor(ctx, &model.TOTPFactor{UserID: "42", EncryptedSecret: []byte{2}}), "a pending enrollment is restarted")
+	require.NoError(t, repo.ConfirmTOTPFactor(ctx, "42", 10))
+	assert.ErrorIs(t, repo.ConfirmTOTPFactor(ctx, "42", 11), repository.ErrTOTPFactorNotFound, "confirmed once")
+
+	assert.ErrorIs(t, repo.SavePendingTOTPFactor(ctx, &model.TOTPFactor{UserID: "42", EncryptedSecret: []byte{3}}), repository.ErrTOTPAlreadyConfirmed)
+	factor, err := repo.GetTOTPFactor(ctx, "42")
+	require.NoError(t, err)
+	assert.Equal(t, []byte{2}, factor.EncryptedSecret, "the confirmed secret is kept")
+	assert.True(t, factor.IsConfirmed())
+}
+
+func TestTOTPRepository_UseTOTPStepRejectsReplays(t *testing.T) {
+	repo := NewTOTPRepository(databasetest.OpenSQLite(t, mfaSchema))
+	ctx := context.Background()
+
+	require.NoError(t, repo.SavePendingTOTPFactor(ctx, &model.TOTPFactor{UserID: "42", EncryptedSecret: []byte{1}}))
+	fresh, err := repo.UseTOTPStep(ctx, "42", 1000)
+	require.NoError(t, err)
+	assert.False(t, fresh, "pending factors can not be used")
+
+	require.NoError(t, repo.ConfirmTOTPFactor(ctx, "42", 1000))
+	fresh, err = repo.UseTOTPStep(ctx, "42", 1000)
+	require.NoError(t, err)
+	assert.False(t, fresh, "the confirmation code is spent")
+
+	// Concurrent logins with the same code: exactly one gets the step
+	var accepted atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if fresh, err := repo.UseTOTPStep(ctx, "42", 1001); err == nil && fresh {
+				accepted.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), accepted.Load())
+
+	fresh, err = repo.UseTOTPStep(ctx, "42", 999)
+	require.NoError(t, err)
+	assert.False(t, fresh, "earlier steps are replays")
+}
+
+func TestMemoryTOTPRepository_Lifecycle(t *testing.T) {
+	repo := NewMemoryTOTPRepository()
+	ctx := context.Background()
+
+	require.NoError(t, repo.SavePendingTOTPFactor(ctx, &model.TOTPFactor{UserID: "42", EncryptedSecret: []byte{1}}))
+	fresh, err := repo.UseTOTPStep(ctx, "42", 10)
+	require.NoError(t, err)
+	assert.False(t, fresh, "pending factors can not be used")
+
+	require.NoError(t, repo.ConfirmTOTPFactor(ctx, "42", 10))
+	assert.ErrorIs(t, repo.ConfirmTOTPFactor(ctx, "42", 11), repository.ErrTOTPFactorNotFound)
+	assert.ErrorIs(t, repo.SavePendingTOTPFactor(ctx, &model.TOTPFactor{UserID: "42"}), repository.ErrTOTPAlreadyConfirmed)
+
+	fresh, _ = repo.UseTOTPStep(ctx, "42", 10)
+	assert.False(t, fresh)
+	fresh, _ = repo.UseTOTPStep(ctx, "42", 11)
+	assert.True(t, fresh)
+
+	factor, err := repo.GetTOTPFactor(ctx, "42")
+	require.NoError(t, err)
+	assert.True(t, factor.IsConfirmed())
+	assert.Equal(t, int64(11), factor.LastUsedStep)
+}
@@ -0,0 +1,43 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Message is a notification sent to a user
+type Message struct {
+	// To is the recipient's email address
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender delivers notifications to users
+// Implementations must be safe for concurrent use
+type Sender interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// LogSender writes notifications to a log instead of delivering them (local development only,
+// the log then contains login codes)
+type LogSender struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewLogSender creates a sender writing "📧 NOTIFICATION" lines to w
+func NewLogSender(w io.Writer) *LogSender {
+	return &LogSender{w: w}
+}
+
+// Send implements Sender
+func (s *LogSender) Send(ctx context.Context, message Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := fmt.Fprintf(s.w, "📧 NOTIFICATION to=%s subject=%q\n%s\n", message.To, message.Subject, message.Body)
+	return err
+}
@@ -0,0 +1,61 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/smtp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogSender(t *testing.T) {
+	var out bytes.Buffer
+
+	err := NewLogSender(&out).Send(context.Background(), Message{To: "ada@example.com", Subject: "Your code", Body: "123456"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "📧 NOTIFICATION to=ada@example.com subject=\"Your code\"\n123456\n", out.String())
+}
+
+func TestSMTPSender_Send(t *testing.T) {
+	sender := NewSMTPSender(SMTPConfig{Host: "smtp.example.com", Port: "587", Username: "hub", Password: "secret", From: "no-reply@hubinvestments.com"})
+	var addr, from string
+	var to []string
+	var msg []byte
+	var auth smtp.Auth
+	sender.send = func(a string, au smtp.Auth, f string, t []string, m []byte) error {
+		addr, auth, from, to, msg = a, au, f, t, m
+		return nil
+	}
+
+	err := sender.Send(context.Background(), Message{To: "ada@example.com", Subject: "Código", Body: "line 1\nline 2"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "smtp.example.com:587", addr)
+	assert.NotNil(t, auth)
+	assert.Equal(t, "no-reply@hubinvestments.com", from)
+	assert.Equal(t, []string{"ada@example.com"}, to)
+	assert.Contains(t, string(msg), "To: ada@example.com\r\n")
+	assert.Contains(t, string(msg), "Subject: =?utf-8?q?C=C3=B3digo?=\r\n")
+	assert.Contains(t, string(msg), "\r\n\r\nline 1\r\nline 2\r\n")
+}
+
+func TestSMTPSender_Errors(t *testing.T) {
+	sender := NewSMTPSender(SMTPConfig{Host: "localhost", Port: "25"})
+	sender.send = func(string, smtp.Auth, string, []string, []byte) error { return errors.New("connection refused") }
+
+	assert.Error(t, sender.Send(context.Background(), Message{To: "ada@example.com"}))
+	assert.Error(t, sender.Send(context.Background(), Message{To: "ada@example.com\r\nBcc: eve@example.com"}), "header injection")
+
+	sender.send = func(string, smtp.Auth, string, []string, []byte) error {
+		time.Sleep(time.Second)
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, sender.Send(ctx, Message{To: "ada@example.com"}), context.DeadlineExceeded)
+}
@@ -0,0 +1,77 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// SMTPConfig configures SMTPSender
+type SMTPConfig struct {
+	Host string
+	Port string
+	// Username and Password authenticate with PLAIN auth, which net/smtp only sends over TLS
+	// or to localhost; empty Username skips authentication
+	Username string
+	Password string
+	// From is the sender address
+	From string
+}
+
+// SMTPSender delivers notifications as plain text emails
+type SMTPSender struct {
+	config SMTPConfig
+	send   func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewSMTPSender creates a sender delivering through the configured SMTP relay (STARTTLS when offered)
+func NewSMTPSender(config SMTPConfig) *SMTPSender {
+	return &SMTPSender{config: config, send: smtp.SendMail}
+}
+
+// Send implements Sender
+func (s *SMTPSender) Send(ctx context.Context, message Message) error {
+	if strings.ContainsAny(message.To, "\r\n") {
+		return fmt.Errorf("invalid recipient %q", message.To)
+	}
+
+	var auth smtp.Auth
+	if s.config.Username != "" {
+		auth = smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)
+	}
+
+	// net/smtp has no context support, the relay is expected to answer quickly
+	errs := make(chan error, 1)
+	go func() {
+		errs <- s.send(net.JoinHostPort(s.config.Host, s.config.Port), auth, s.config.From, []string{message.To}, s.format(message, time.Now()))
+	}()
+	select {
+	case err := <-errs:
+		if err != nil {
+			return fmt.Errorf("failed to send email: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// format builds the RFC 5322 message
+func (s *SMTPSender) format(message Message, now time.Time) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", s.config.From)
+	fmt.Fprintf(&b, "To: %s\r\n", message.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(message.Body, "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return b.Bytes()
+}
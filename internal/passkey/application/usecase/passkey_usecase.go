@@ -0,0 +1,344 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"hub-user-service/internal/audit"
+	loginModel "hub-user-service/internal/login/domain/model"
+	loginRepository "hub-user-service/internal/login/domain/repository"
+	"hub-user-service/internal/passkey/domain/model"
+	"hub-user-service/internal/passkey/domain/repository"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+)
+
+var (
+	// ErrCeremonyNotFound is returned for unknown, expired, already finished or mismatched ceremonies
+	ErrCeremonyNotFound = errors.New("passkey ceremony expired or not found")
+	// ErrInvalidResponse is returned when the authenticator response does not verify
+	ErrInvalidResponse = errors.New("invalid passkey response")
+	// ErrAttestationNotAllowed is returned when the attestation format is rejected by policy
+	ErrAttestationNotAllowed = errors.New("authenticator attestation not allowed")
+	// ErrCredentialExists is returned when the authenticator is already registered
+	ErrCredentialExists = errors.New("passkey already registered")
+	// ErrCloneDetected is returned when the signature counter did not increase
+	ErrCloneDetected = errors.New("passkey signature counter did not increase, the authenticator may be cloned")
+	// ErrAccountInactive is returned when the passkey belongs to a locked or deleted account
+	ErrAccountInactive = errors.New("account is not active")
+)
+
+// CeremonyOptions starts a ceremony in the browser
+type CeremonyOptions struct {
+	// CeremonyID is sent back with the authenticator response
+	CeremonyID string
+	// Options is the JSON passed to navigator.credentials.create() or get()
+	Options []byte
+}
+
+// LoginResult is the user a passkey login authenticated
+type LoginResult struct {
+	User *loginModel.User
+	// UserVerified is set when the authenticator verified the user (PIN or biometrics), making the
+	// passkey a second factor on its own
+	UserVerified bool
+}
+
+type IPasskeyUsecase interface {
+	// BeginRegistration starts registering a new passkey for an authenticated user
+	BeginRegistration(ctx context.Context, userID string, userName string) (*CeremonyOptions, error)
+	// FinishRegistration verifies the authenticator's attestation and stores the credential
+	FinishRegistration(ctx context.Context, userID string, ceremonyID string, response []byte, name string) (*model.Credential, error)
+	// BeginLogin starts a passkey login; the authenticator picks the account (discoverable credential)
+	BeginLogin(ctx context.Context) (*CeremonyOptions, error)
+	// FinishLogin verifies the assertion and returns the authenticated user
+	FinishLogin(ctx context.Context, ceremonyID string, response []byte) (*LoginResult, error)
+}
+
+// PasskeyConfig configures PasskeyUsecase
+type PasskeyConfig struct {
+	// RPID is the relying party ID, the registrable domain of the web app (e.g. hubinvestments.com)
+	RPID          string
+	RPDisplayName string
+	// RPOrigins are the origins allowed to run ceremonies (e.g. https://app.hubinvestments.com)
+	RPOrigins []string
+	// Attestation is the conveyance preference: none, indirect, direct or enterprise
+	Attestation string
+	// AllowedAttestationFormats restricts the accepted attestation statement formats; empty accepts any
+	AllowedAttestationFormats []string
+	// RequireUserVerification makes authenticators verify the user (PIN or biometrics) every time
+	RequireUserVerification bool
+	// CeremonyTimeout bounds the time between the begin and finish calls
+	CeremonyTimeout time.Duration
+	// Audit records registrations and suspected clones; nil disables auditing
+	Audit audit.Recorder
+}
+
+type PasskeyUsecase struct {
+	users       loginRepository.ILoginRepository
+	credentials repository.ICredentialRepository
+	ceremonies  repository.ICeremonyRepository
+	webAuthn    *webauthn.WebAuthn
+	config      PasskeyConfig
+	now         func() time.Time
+}
+
+// NewPasskeyUsecase creates the passkey use case; it fails on an invalid relying party configuration
+func NewPasskeyUsecase(users loginRepository.ILoginRepository, credentials repository.ICredentialRepository, ceremonies repository.ICeremonyRepository, config PasskeyConfig) (IPasskeyUsecase, error) {
+	timeout := webauthn.TimeoutConfig{Enforce: true, Timeout: config.CeremonyTimeout, TimeoutUVD: config.CeremonyTimeout}
+	webAuthn, err := webauthn.New(&webauthn.Config{
+		RPID:                  config.RPID,
+		RPDisplayName:         config.RPDisplayName,
+		RPOrigins:             config.RPOrigins,
+		AttestationPreference: protocol.ConveyancePreference(config.Attestation),
+		Timeouts:              webauthn.TimeoutsConfig{Login: timeout, Registration: timeout},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid WebAuthn configuration: %w", err)
+	}
+	if config.Audit == nil {
+		config.Audit = audit.NopRecorder{}
+	}
+
+	return &PasskeyUsecase{
+		users:       users,
+		credentials: credentials,
+		ceremonies:  ceremonies,
+		webAuthn:    webAuthn,
+		config:      config,
+		now:         time.Now,
+	}, nil
+}
+
+func (u *PasskeyUsecase) BeginRegistration(ctx context.Context, userID string, userName string) (*CeremonyOptions, error) {
+	user, err := u.loadUser(ctx, userID, userName)
+	if err != nil {
+		return nil, err
+	}
+
+	exclusions := make([]protocol.CredentialDescriptor, len(user.credentials))
+	for i, credential := range user.WebAuthnCredentials() {
+		exclusions[i] = credential.Descriptor()
+	}
+
+	creation, session, err := u.webAuthn.BeginRegistration(user,
+		webauthn.WithExclusions(exclusions),
+		webauthn.WithResidentKeyRequirement(protocol.ResidentKeyRequirementRequired),
+		webauthn.WithAuthenticatorSelection(protocol.AuthenticatorSelection{
+			ResidentKey:        protocol.ResidentKeyRequirementRequired,
+			RequireResidentKey: protocol.ResidentKeyRequired(),
+			UserVerification:   u.userVerification(),
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin passkey registration: %w", err)
+	}
+
+	return u.startCeremony(ctx, model.CeremonyRegistration, userID, session, creation)
+}
+
+func (u *PasskeyUsecase) FinishRegistration(ctx context.Context, userID string, ceremonyID string, response []byte, name string) (*model.Credential, error) {
+	session, err := u.takeCeremony(ctx, ceremonyID, model.CeremonyRegistration, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBody(bytes.NewReader(response))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+
+	user, err := u.loadUser(ctx, userID, "")
+	if err != nil {
+		return nil, err
+	}
+	created, err := u.webAuthn.CreateCredential(user, *session, parsed)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	if !u.attestationAllowed(created.AttestationType) {
+		return nil, fmt.Errorf("%w: %q", ErrAttestationNotAllowed, created.AttestationType)
+	}
+
+	credential := &model.Credential{
+		ID:              created.ID,
+		UserID:          userID,
+		Name:            name,
+		PublicKey:       created.PublicKey,
+		AttestationType: created.AttestationType,
+		AAGUID:          created.Authenticator.AAGUID,
+		SignCount:       created.Authenticator.SignCount,
+		UserVerified:    created.Flags.UserVerified,
+		BackupEligible:  created.Flags.BackupEligible,
+		BackupState:     created.Flags.BackupState,
+	}
+	for _, transport := range created.Transport {
+		credential.Transports = append(credential.Transports, string(transport))
+	}
+
+	if err := u.credentials.SaveCredential(ctx, credential); err != nil {
+		if errors.Is(err, repository.ErrCredentialExists) {
+			return nil, ErrCredentialExists
+		}
+		return nil, err
+	}
+
+	u.audit(ctx, audit.Entry{
+		Action: audit.ActionPasskeyRegistered,
+		UserID: userID,
+		Attributes: map[string]string{
+			"credential_id": base64.RawURLEncoding.EncodeToString(credential.ID),
+			"attestation":   credential.AttestationType,
+		},
+	})
+	return credential, nil
+}
+
+func (u *PasskeyUsecase) BeginLogin(ctx context.Context) (*CeremonyOptions, error) {
+	assertion, session, err := u.webAuthn.BeginDiscoverableLogin(webauthn.WithUserVerification(u.userVerification()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin passkey login: %w", err)
+	}
+
+	return u.startCeremony(ctx, model.CeremonyLogin, "", session, assertion)
+}
+
+func (u *PasskeyUsecase) FinishLogin(ctx context.Context, ceremonyID string, response []byte) (*LoginResult, error) {
+	session, err := u.takeCeremony(ctx, ceremonyID, model.CeremonyLogin, "")
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBody(bytes.NewReader(response))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+
+	// The user handle is the user ID given at registration
+	var userID string
+	handler := func(rawID, userHandle []byte) (webauthn.User, error) {
+		userID = string(userHandle)
+		return u.loadUser(ctx, userID, "")
+	}
+	verified, err := u.webAuthn.ValidateDiscoverableLogin(handler, *session, parsed)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+
+	fresh := false
+	if !verified.Authenticator.CloneWarning {
+		fresh, err = u.credentials.RecordCredentialUse(ctx, verified.ID, verified.Authenticator.SignCount, verified.Flags.BackupState, u.now())
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !fresh {
+		u.audit(ctx, audit.Entry{
+			Action:     audit.ActionPasskeyCloneSuspected,
+			UserID:     userID,
+			Attributes: map[string]string{"credential_id": base64.RawURLEncoding.EncodeToString(verified.ID)},
+		})
+		return nil, ErrCloneDetected
+	}
+
+	user, err := u.users.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive() {
+		return nil, ErrAccountInactive
+	}
+
+	return &LoginResult{User: user, UserVerified: verified.Flags.UserVerified}, nil
+}
+
+// startCeremony stores the session under a new random ID and encodes the browser options
+func (u *PasskeyUsecase) startCeremony(ctx context.Context, kind model.CeremonyKind, userID string, session *webauthn.SessionData, options interface{}) (*CeremonyOptions, error) {
+	id := make([]byte, 32)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("failed to generate ceremony ID: %w", err)
+	}
+	encodedSession, err := json.Marshal(session)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode ceremony session: %w", err)
+	}
+	encodedOptions, err := json.Marshal(options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode ceremony options: %w", err)
+	}
+
+	ceremony := &model.Ceremony{
+		ID:        base64.RawURLEncoding.EncodeToString(id),
+		Kind:      kind,
+		UserID:    userID,
+		Session:   encodedSession,
+		ExpiresAt: u.now().Add(u.config.CeremonyTimeout),
+	}
+	if err := u.ceremonies.SaveCeremony(ctx, ceremony); err != nil {
+		return nil, err
+	}
+	return &CeremonyOptions{CeremonyID: ceremony.ID, Options: encodedOptions}, nil
+}
+
+// takeCeremony consumes the ceremony, checking it was started for this kind and user
+func (u *PasskeyUsecase) takeCeremony(ctx context.Context, id string, kind model.CeremonyKind, userID string) (*webauthn.SessionData, error) {
+	ceremony, err := u.ceremonies.TakeCeremony(ctx, id, u.now())
+	if errors.Is(err, repository.ErrCeremonyNotFound) {
+		return nil, ErrCeremonyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if ceremony.Kind != kind || ceremony.UserID != userID {
+		return nil, ErrCeremonyNotFound
+	}
+
+	var session webauthn.SessionData
+	if err := json.Unmarshal(ceremony.Session, &session); err != nil {
+		return nil, fmt.Errorf("failed to decode ceremony session: %w", err)
+	}
+	return &session, nil
+}
+
+// loadUser builds the go-webauthn view of a user and their credentials
+func (u *PasskeyUsecase) loadUser(ctx context.Context, userID string, userName string) (*passkeyUser, error) {
+	credentials, err := u.credentials.ListCredentials(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &passkeyUser{id: userID, name: userName, credentials: credentials}, nil
+}
+
+func (u *PasskeyUsecase) userVerification() protocol.UserVerificationRequirement {
+	if u.config.RequireUserVerification {
+		return protocol.VerificationRequired
+	}
+	return protocol.VerificationPreferred
+}
+
+func (u *PasskeyUsecase) attestationAllowed(format string) bool {
+	if len(u.config.AllowedAttestationFormats) == 0 {
+		return true
+	}
+	for _, allowed := range u.config.AllowedAttestationFormats {
+		if allowed == format {
+			return true
+		}
+	}
+	return false
+}
+
+// audit records entry; a failing audit sink must not lock users out, so errors are only logged
+func (u *PasskeyUsecase) audit(ctx context.Context, entry audit.Entry) {
+	if err := u.config.Audit.Record(ctx, entry); err != nil {
+		log.Printf("Failed to record audit entry %s for user %s: %v", entry.Action, entry.UserID, err)
+	}
+}
@@ -0,0 +1,261 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"hub-user-service/internal/audit"
+	loginModel "hub-user-service/internal/login/domain/model"
+	loginPersistence "hub-user-service/internal/login/infra/persistence"
+	"hub-user-service/internal/passkey/infra/persistence"
+	"hub-user-service/internal/passkey/passkeytest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testOrigin = "https://app.hubinvestments.com"
+
+// recordingAudit keeps the recorded entries
+type recordingAudit struct {
+	entries []audit.Entry
+}
+
+func (r *recordingAudit) Record(ctx context.Context, entry audit.Entry) error {
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+type passkeyFixture struct {
+	usecase     *PasskeyUsecase
+	users       *loginPersistence.MemoryLoginRepository
+	credentials *persistence.MemoryCredentialRepository
+	audit       *recordingAudit
+}
+
+func newFixture(t *testing.T, configure func(*PasskeyConfig)) *passkeyFixture {
+	t.Helper()
+	recorder := &recordingAudit{}
+	config := PasskeyConfig{
+		RPID:                    "hubinvestments.com",
+		RPDisplayName:           "Hub Investments",
+		RPOrigins:               []string{testOrigin},
+		Attestation:             "none",
+		RequireUserVerification: true,
+		CeremonyTimeout:         5 * time.Minute,
+		Audit:                   recorder,
+	}
+	if configure != nil {
+		configure(&config)
+	}
+
+	users := loginPersistence.NewMemoryLoginRepository(loginModel.NewUserFromRepository("42", "ada@example.com", "DevPass123!"))
+	credentials := persistence.NewMemoryCredentialRepository()
+	uc, err := NewPasskeyUsecase(users, credentials, persistence.NewMemoryCeremonyRepository(), config)
+	require.NoError(t, err)
+	return &passkeyFixture{usecase: uc.(*PasskeyUsecase), users: users, credentials: credentials, audit: recorder}
+}
+
+// register runs a registration ceremony with authenticator
+func (f *passkeyFixture) register(t *testing.T, authenticator *passkeytest.Authenticator) error {
+	t.Helper()
+	ctx := context.Background()
+	options, err := f.usecase.BeginRegistration(ctx, "42", "ada@example.com")
+	require.NoError(t, err)
+	response, err := authenticator.Register(options.Options)
+	require.NoError(t, err)
+
+	_, err = f.usecase.FinishRegistration(ctx, "42", options.CeremonyID, response, "Laptop")
+	return err
+}
+
+// login runs a login ceremony with authenticator
+func (f *passkeyFixture) login(t *testing.T, authenticator *passkeytest.Authenticator) (*LoginResult, error) {
+	t.Helper()
+	ctx := context.Background()
+	options, err := f.usecase.BeginLogin(ctx)
+	require.NoError(t, err)
+	response, err := authenticator.Login(options.Options)
+	require.NoError(t, err)
+
+	return f.usecase.FinishLogin(ctx, options.CeremonyID, response)
+}
+
+func TestPasskeyUsecase_RegisterAndLogin(t *testing.T) {
+	f := newFixture(t, nil)
+	authenticator := passkeytest.NewAuthenticator(testOrigin)
+
+	require.NoError(t, f.register(t, authenticator))
+
+	stored, err := f.credentials.ListCredentials(context.Background(), "42")
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+	assert.Equal(t, authenticator.CredentialID(), stored[0].ID)
+	assert.Equal(t, "Laptop", stored[0].Name)
+	assert.Equal(t, "none", stored[0].AttestationType)
+	assert.Equal(t, uint32(1), stored[0].SignCount)
+	assert.Equal(t, []string{"internal", "hybrid"}, stored[0].Transports)
+
+	result, err := f.login(t, authenticator)
+	require.NoError(t, err)
+	assert.Equal(t, "42", result.User.ID)
+	assert.Equal(t, "ada@example.com", result.User.GetEmailString())
+	assert.True(t, result.UserVerified)
+
+	stored, _ = f.credentials.ListCredentials(context.Background(), "42")
+	assert.Equal(t, uint32(2), stored[0].SignCount)
+	assert.NotNil(t, stored[0].LastUsedAt)
+
+	require.Len(t, f.audit.entries, 1)
+	assert.Equal(t, audit.ActionPasskeyRegistered, f.audit.entries[0].Action)
+}
+
+func TestPasskeyUsecase_CeremoniesAreSingleUse(t *testing.T) {
+	f := newFixture(t, nil)
+	authenticator := passkeytest.NewAuthenticator(testOrigin)
+	require.NoError(t, f.register(t, authenticator))
+	ctx := context.Background()
+
+	options, err := f.usecase.BeginLogin(ctx)
+	require.NoError(t, err)
+	response, err := authenticator.Login(options.Options)
+	require.NoError(t, err)
+
+	_, err = f.usecase.FinishLogin(ctx, options.CeremonyID, response)
+	require.NoError(t, err)
+	_, err = f.usecase.FinishLogin(ctx, options.CeremonyID, response)
+	assert.ErrorIs(t, err, ErrCeremonyNotFound)
+}
+
+func TestPasskeyUsecase_CeremonyMismatch(t *testing.T) {
+	f := newFixture(t, nil)
+	ctx := context.Background()
+
+	login, err := f.usecase.BeginLogin(ctx)
+	require.NoError(t, err)
+	_, err = f.usecase.FinishRegistration(ctx, "42", login.CeremonyID, []byte("{}"), "")
+	assert.ErrorIs(t, err, ErrCeremonyNotFound, "a login ceremony can not register")
+
+	registration, err := f.usecase.BeginRegistration(ctx, "42", "ada@example.com")
+	require.NoError(t, err)
+	_, err = f.usecase.FinishRegistration(ctx, "7", registration.CeremonyID, []byte("{}"), "")
+	assert.ErrorIs(t, err, ErrCeremonyNotFound, "a ceremony belongs to the user who started it")
+}
+
+func TestPasskeyUsecase_CeremonyExpires(t *testing.T) {
+	f := newFixture(t, nil)
+	ctx := context.Background()
+
+	options, err := f.usecase.BeginLogin(ctx)
+	require.NoError(t, err)
+
+	f.usecase.now = func() time.Time { return time.Now().Add(6 * time.Minute) }
+	_, err = f.usecase.FinishLogin(ctx, options.CeremonyID, []byte("{}"))
+	assert.ErrorIs(t, err, ErrCeremonyNotFound)
+}
+
+func TestPasskeyUsecase_RejectsWrongOrigin(t *testing.T) {
+	f := newFixture(t, nil)
+
+	err := f.register(t, passkeytest.NewAuthenticator("https://phishing.example"))
+	assert.ErrorIs(t, err, ErrInvalidResponse)
+}
+
+func TestPasskeyUsecase_RequiresUserVerification(t *testing.T) {
+	f := newFixture(t, nil)
+	authenticator := passkeytest.NewAuthenticator(testOrigin)
+	authenticator.UserVerification = false
+
+	assert.ErrorIs(t, f.register(t, authenticator), ErrInvalidResponse)
+}
+
+func TestPasskeyUsecase_AttestationPolicy(t *testing.T) {
+	f := newFixture(t, func(config *PasskeyConfig) {
+		config.AllowedAttestationFormats = []string{"packed"}
+	})
+
+	assert.ErrorIs(t, f.register(t, passkeytest.NewAuthenticator(testOrigin)), ErrAttestationNotAllowed)
+
+	packed := passkeytest.NewAuthenticator(testOrigin)
+	packed.Format = "packed"
+	require.NoError(t, f.register(t, packed))
+
+	stored, _ := f.credentials.ListCredentials(context.Background(), "42")
+	require.Len(t, stored, 1)
+	assert.Equal(t, "packed", stored[0].AttestationType)
+}
+
+func TestPasskeyUsecase_RejectsDuplicateRegistration(t *testing.T) {
+	f := newFixture(t, nil)
+	authenticator := passkeytest.NewAuthenticator(testOrigin)
+	require.NoError(t, f.register(t, authenticator))
+
+	// A second registration of the same credential is refused, even if the browser ignored the exclusions
+	ctx := context.Background()
+	options, err := f.usecase.BeginRegistration(ctx, "42", "ada@example.com")
+	require.NoError(t, err)
+	assert.Contains(t, string(options.Options), "excludeCredentials")
+	response, err := authenticator.Login(options.Options)
+	require.NoError(t, err)
+	_, err = f.usecase.FinishRegistration(ctx, "42", options.CeremonyID, response, "")
+	assert.ErrorIs(t, err, ErrInvalidResponse)
+}
+
+func TestPasskeyUsecase_DetectsClonedAuthenticator(t *testing.T) {
+	f := newFixture(t, nil)
+	authenticator := passkeytest.NewAuthenticator(testOrigin)
+	require.NoError(t, f.register(t, authenticator))
+
+	_, err := f.login(t, authenticator)
+	require.NoError(t, err)
+
+	// A clone replays an older counter value
+	authenticator.SignCount = 0
+	_, err = f.login(t, authenticator)
+	assert.ErrorIs(t, err, ErrCloneDetected)
+
+	last := f.audit.entries[len(f.audit.entries)-1]
+	assert.Equal(t, audit.ActionPasskeyCloneSuspected, last.Action)
+	assert.Equal(t, "42", last.UserID)
+}
+
+func TestPasskeyUsecase_AuthenticatorsWithoutCounter(t *testing.T) {
+	f := newFixture(t, nil)
+	authenticator := passkeytest.NewAuthenticator(testOrigin)
+	authenticator.CountSignatures = false
+	require.NoError(t, f.register(t, authenticator))
+
+	for i := 0; i < 2; i++ {
+		_, err := f.login(t, authenticator)
+		require.NoError(t, err)
+	}
+}
+
+func TestPasskeyUsecase_RejectsInactiveAccount(t *testing.T) {
+	f := newFixture(t, nil)
+	authenticator := passkeytest.NewAuthenticator(testOrigin)
+	require.NoError(t, f.register(t, authenticator))
+
+	locked := loginModel.NewUserFromRepository("42", "ada@example.com", "DevPass123!")
+	locked.Status = loginModel.UserStatusLocked
+	f.users.Save(locked)
+
+	_, err := f.login(t, authenticator)
+	assert.ErrorIs(t, err, ErrAccountInactive)
+}
+
+func TestPasskeyUsecase_UnknownCredential(t *testing.T) {
+	f := newFixture(t, nil)
+	other := newFixture(t, nil)
+	authenticator := passkeytest.NewAuthenticator(testOrigin)
+	require.NoError(t, other.register(t, authenticator))
+
+	_, err := f.login(t, authenticator)
+	assert.ErrorIs(t, err, ErrInvalidResponse)
+}
+
+func TestNewPasskeyUsecase_InvalidConfig(t *testing.T) {
+	_, err := NewPasskeyUsecase(nil, nil, nil, PasskeyConfig{RPDisplayName: "Hub Investments"})
+	assert.Error(t, err)
+}
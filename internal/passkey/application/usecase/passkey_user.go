@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"hub-user-service/internal/passkey/domain/model"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+)
+
+// passkeyUser adapts a user and their stored credentials to webauthn.User.
+// The user handle is the user ID, so a passkey login can find the account without an email.
+type passkeyUser struct {
+	id          string
+	name        string
+	credentials []*model.Credential
+}
+
+func (p *passkeyUser) WebAuthnID() []byte {
+	return []byte(p.id)
+}
+
+func (p *passkeyUser) WebAuthnName() string {
+	return p.name
+}
+
+func (p *passkeyUser) WebAuthnDisplayName() string {
+	return p.name
+}
+
+func (p *passkeyUser) WebAuthnIcon() string {
+	return ""
+}
+
+func (p *passkeyUser) WebAuthnCredentials() []webauthn.Credential {
+	credentials := make([]webauthn.Credential, len(p.credentials))
+	for i, credential := range p.credentials {
+		transports := make([]protocol.AuthenticatorTransport, len(credential.Transports))
+		for j, transport := range credential.Transports {
+			transports[j] = protocol.AuthenticatorTransport(transport)
+		}
+		credentials[i] = webauthn.Credential{
+			ID:              credential.ID,
+			PublicKey:       credential.PublicKey,
+			AttestationType: credential.AttestationType,
+			Transport:       transports,
+			Flags: webauthn.CredentialFlags{
+				UserVerified:   credential.UserVerified,
+				BackupEligible: credential.BackupEligible,
+				BackupState:    credential.BackupState,
+			},
+			Authenticator: webauthn.Authenticator{
+				AAGUID:    credential.AAGUID,
+				SignCount: credential.SignCount,
+			},
+		}
+	}
+	return credentials
+}
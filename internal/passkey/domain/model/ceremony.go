@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// CeremonyKind distinguishes registration from login ceremonies
+type CeremonyKind string
+
+const (
+	CeremonyRegistration CeremonyKind = "registration"
+	CeremonyLogin        CeremonyKind = "login"
+)
+
+// Ceremony is the server side state of a WebAuthn ceremony between its begin and finish calls
+type Ceremony struct {
+	ID   string
+	Kind CeremonyKind
+	// UserID is the registering user; empty for passkey logins, where the authenticator picks the account
+	UserID string
+	// Session is the go-webauthn session data (challenge, user verification), JSON encoded
+	Session   []byte
+	ExpiresAt time.Time
+}
+
+// IsExpired reports whether the ceremony can no longer be finished
+func (c *Ceremony) IsExpired(now time.Time) bool {
+	return !now.Before(c.ExpiresAt)
+}
@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// Credential is a registered WebAuthn public key credential (passkey or security key)
+type Credential struct {
+	// ID is the credential ID chosen by the authenticator
+	ID     []byte
+	UserID string
+	// Name is the label the user gave the credential, e.g. "MacBook Touch ID"
+	Name string
+	// PublicKey is the COSE encoded credential public key
+	PublicKey []byte
+	// AttestationType is the attestation statement format presented at registration ("none", "packed", ...)
+	AttestationType string
+	AAGUID          []byte
+	// SignCount is the last signature counter seen; authenticators that do not count always report 0
+	SignCount      uint32
+	Transports     []string
+	UserVerified   bool
+	BackupEligible bool
+	BackupState    bool
+	CreatedAt      time.Time
+	LastUsedAt     *time.Time
+}
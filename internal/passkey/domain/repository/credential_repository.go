@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"hub-user-service/internal/passkey/domain/model"
+)
+
+var (
+	// ErrCredentialExists is returned when registering a credential ID that is already stored
+	ErrCredentialExists = errors.New("webauthn credential already registered")
+	// ErrCeremonyNotFound is returned for unknown, expired or already finished ceremonies
+	ErrCeremonyNotFound = errors.New("webauthn ceremony not found")
+)
+
+type ICredentialRepository interface {
+	// ListCredentials returns the user's credentials, oldest first
+	ListCredentials(ctx context.Context, userID string) ([]*model.Credential, error)
+	SaveCredential(ctx context.Context, credential *model.Credential) error
+	// RecordCredentialUse stores the new signature counter and backup state; it returns false if
+	// signCount is not greater than the stored counter (a replayed or cloned assertion), unless
+	// the authenticator does not count and both are 0
+	RecordCredentialUse(ctx context.Context, id []byte, signCount uint32, backupState bool, usedAt time.Time) (bool, error)
+}
+
+type ICeremonyRepository interface {
+	SaveCeremony(ctx context.Context, ceremony *model.Ceremony) error
+	// TakeCeremony atomically removes and returns an unexpired ceremony, so each one finishes once
+	TakeCeremony(ctx context.Context, id string, now time.Time) (*model.Ceremony, error)
+}
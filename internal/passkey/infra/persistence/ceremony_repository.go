@@ -0,0 +1,68 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"hub-user-service/internal/database"
+	"hub-user-service/internal/passkey/domain/model"
+	"hub-user-service/internal/passkey/domain/repository"
+)
+
+type CeremonyRepository struct {
+	db database.Querier
+}
+
+// ceremonyDTO represents the webauthn_ceremonies table
+type ceremonyDTO struct {
+	ID        string         `db:"id"`
+	Kind      string         `db:"kind"`
+	UserID    sql.NullString `db:"user_id"`
+	Session   []byte         `db:"session"`
+	ExpiresAt time.Time      `db:"expires_at"`
+}
+
+// NewCeremonyRepository creates a WebAuthn ceremony repository on a database or a transaction
+func NewCeremonyRepository(db database.Querier) repository.ICeremonyRepository {
+	return &CeremonyRepository{db: db}
+}
+
+func (r *CeremonyRepository) SaveCeremony(ctx context.Context, ceremony *model.Ceremony) error {
+	// Abandoned ceremonies are removed by later ones
+	query := `WITH expired AS (DELETE FROM webauthn_ceremonies WHERE expires_at < CURRENT_TIMESTAMP)
+		INSERT INTO webauthn_ceremonies (id, kind, user_id, session, expires_at) VALUES ($1, $2, $3, $4, $5)`
+
+	userID := sql.NullString{String: ceremony.UserID, Valid: ceremony.UserID != ""}
+	if _, err := r.db.ExecContext(ctx, query, ceremony.ID, string(ceremony.Kind), userID, ceremony.Session, ceremony.ExpiresAt); err != nil {
+		return fmt.Errorf("failed to save webauthn ceremony: %w", err)
+	}
+	return nil
+}
+
+func (r *CeremonyRepository) TakeCeremony(ctx context.Context, id string, now time.Time) (*model.Ceremony, error) {
+	query := "DELETE FROM webauthn_ceremonies WHERE id = $1 RETURNING id, kind, user_id, session, expires_at"
+
+	// A write returning rows must not be routed to a read-only replica or retried as a read
+	var dto ceremonyDTO
+	if err := r.db.GetContext(database.WithWrite(ctx), &dto, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrCeremonyNotFound
+		}
+		return nil, fmt.Errorf("failed to take webauthn ceremony: %w", err)
+	}
+
+	ceremony := &model.Ceremony{
+		ID:        dto.ID,
+		Kind:      model.CeremonyKind(dto.Kind),
+		UserID:    dto.UserID.String,
+		Session:   dto.Session,
+		ExpiresAt: dto.ExpiresAt,
+	}
+	if ceremony.IsExpired(now) {
+		return nil, repository.ErrCeremonyNotFound
+	}
+	return ceremony, nil
+}
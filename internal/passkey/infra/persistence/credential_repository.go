@@ -0,0 +1,121 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"hub-user-service/internal/database"
+	"hub-user-service/internal/passkey/domain/model"
+	"hub-user-service/internal/passkey/domain/repository"
+
+	"github.com/lib/pq"
+)
+
+// uniqueViolation is the SQLSTATE of a duplicate primary key
+const uniqueViolation = "23505"
+
+type CredentialRepository struct {
+	db database.Querier
+}
+
+// credentialDTO represents the webauthn_credentials table
+type credentialDTO struct {
+	ID              []byte         `db:"id"`
+	UserID          string         `db:"user_id"`
+	Name            string         `db:"name"`
+	PublicKey       []byte         `db:"public_key"`
+	AttestationType string         `db:"attestation_type"`
+	AAGUID          []byte         `db:"aaguid"`
+	SignCount       int64          `db:"sign_count"`
+	Transports      pq.StringArray `db:"transports"`
+	UserVerified    bool           `db:"user_verified"`
+	BackupEligible  bool           `db:"backup_eligible"`
+	BackupState     bool           `db:"backup_state"`
+	CreatedAt       sql.NullTime   `db:"created_at"`
+	LastUsedAt      sql.NullTime   `db:"last_used_at"`
+}
+
+// NewCredentialRepository creates a WebAuthn credential repository on a database or a transaction
+func NewCredentialRepository(db database.Querier) repository.ICredentialRepository {
+	return &CredentialRepository{db: db}
+}
+
+func (r *CredentialRepository) ListCredentials(ctx context.Context, userID string) ([]*model.Credential, error) {
+	query := `SELECT id, user_id, name, public_key, attestation_type, aaguid, sign_count, transports,
+		user_verified, backup_eligible, backup_state, created_at, last_used_at
+		FROM webauthn_credentials WHERE user_id = $1 ORDER BY created_at, id`
+
+	var dtos []credentialDTO
+	if err := r.db.SelectContext(ctx, &dtos, query, userID); err != nil {
+		return nil, fmt.Errorf("failed to list webauthn credentials: %w", err)
+	}
+
+	credentials := make([]*model.Credential, len(dtos))
+	for i, dto := range dtos {
+		credentials[i] = dto.toModel()
+	}
+	return credentials, nil
+}
+
+func (r *CredentialRepository) SaveCredential(ctx context.Context, credential *model.Credential) error {
+	query := `INSERT INTO webauthn_credentials
+		(id, user_id, name, public_key, attestation_type, aaguid, sign_count, transports, user_verified, backup_eligible, backup_state)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+
+	// A nil array is stored as NULL, not as the empty array the NOT NULL column expects
+	transports := pq.StringArray{}
+	transports = append(transports, credential.Transports...)
+
+	_, err := r.db.ExecContext(ctx, query,
+		credential.ID, credential.UserID, credential.Name, credential.PublicKey, credential.AttestationType,
+		credential.AAGUID, int64(credential.SignCount), transports,
+		credential.UserVerified, credential.BackupEligible, credential.BackupState)
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return repository.ErrCredentialExists
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save webauthn credential: %w", err)
+	}
+	return nil
+}
+
+func (r *CredentialRepository) RecordCredentialUse(ctx context.Context, id []byte, signCount uint32, backupState bool, usedAt time.Time) (bool, error) {
+	query := `UPDATE webauthn_credentials SET sign_count = $2, backup_state = $3, last_used_at = $4
+		WHERE id = $1 AND (sign_count < $2 OR (sign_count = 0 AND $2 = 0))`
+
+	result, err := r.db.ExecContext(ctx, query, id, int64(signCount), backupState, usedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to record webauthn credential use: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to read affected rows: %w", err)
+	}
+	return affected == 1, nil
+}
+
+func (d credentialDTO) toModel() *model.Credential {
+	credential := &model.Credential{
+		ID:              d.ID,
+		UserID:          d.UserID,
+		Name:            d.Name,
+		PublicKey:       d.PublicKey,
+		AttestationType: d.AttestationType,
+		AAGUID:          d.AAGUID,
+		SignCount:       uint32(d.SignCount),
+		Transports:      []string(d.Transports),
+		UserVerified:    d.UserVerified,
+		BackupEligible:  d.BackupEligible,
+		BackupState:     d.BackupState,
+		CreatedAt:       d.CreatedAt.Time,
+	}
+	if d.LastUsedAt.Valid {
+		credential.LastUsedAt = &d.LastUsedAt.Time
+	}
+	return credential
+}
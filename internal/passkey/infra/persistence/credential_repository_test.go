@@ -0,0 +1,182 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"hub-user-service/internal/database"
+	"hub-user-service/internal/database/databasetest"
+	"hub-user-service/internal/passkey/domain/model"
+	"hub-user-service/internal/passkey/domain/repository"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// passkeySchema is the SQLite version of the webauthn_credentials and webauthn_ceremonies tables
+const passkeySchema = `
+INSERT INTO users (id, email, name, password) VALUES (42, 'dev@example.com', 'Dev', 'DevPass123!');
+CREATE TABLE webauthn_credentials (
+    id BLOB PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL DEFAULT '',
+    public_key BLOB NOT NULL,
+    attestation_type TEXT NOT NULL,
+    aaguid BLOB,
+    sign_count INTEGER NOT NULL DEFAULT 0,
+    transports TEXT NOT NULL DEFAULT '{}',
+    user_verified BOOLEAN NOT NULL DEFAULT FALSE,
+    backup_eligible BOOLEAN NOT NULL DEFAULT FALSE,
+    backup_state BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMP
+);
+CREATE TABLE webauthn_ceremonies (
+    id TEXT PRIMARY KEY,
+    kind TEXT NOT NULL,
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    session BLOB NOT NULL,
+    expires_at TIMESTAMP NOT NULL
+);`
+
+func TestCredentialRepository_SaveMapsDuplicates(t *testing.T) {
+	db := &databasetest.MockQuerier{}
+	repo := NewCredentialRepository(db)
+	credential := &model.Credential{ID: []byte{1}, UserID: "42", SignCount: 3, Transports: []string{"usb"}}
+	saved := mock.MatchedBy(func(args []interface{}) bool {
+		return args[6] == int64(3) && assert.ObjectsAreEqual(pq.StringArray{"usb"}, args[7])
+	})
+	db.On("ExecContext", mock.Anything, databasetest.QueryContaining("INSERT INTO webauthn_credentials"), saved).Return(databasetest.Result(1), nil).Once()
+	db.On("ExecContext", mock.Anything, mock.Anything, mock.Anything).Return(nil, &pq.Error{Code: uniqueViolation}).Once()
+
+	require.NoError(t, repo.SaveCredential(context.Background(), credential))
+	assert.ErrorIs(t, repo.SaveCredential(context.Background(), credential), repository.ErrCredentialExists)
+	db.AssertExpectations(t)
+}
+
+func TestCredentialRepository_RecordUseRequiresIncreasingCounter(t *testing.T) {
+	repo := NewCredentialRepository(databasetest.OpenSQLite(t, passkeySchema))
+	ctx := context.Background()
+	require.NoError(t, repo.SaveCredential(ctx, &model.Credential{ID: []byte{1}, UserID: "42", PublicKey: []byte{9}, AttestationType: "none", SignCount: 3, Transports: []string{"usb"}}))
+
+	recorded, err := repo.RecordCredentialUse(ctx, []byte{1}, 5, true, time.Now())
+	require.NoError(t, err)
+	assert.True(t, recorded)
+
+	recorded, err = repo.RecordCredentialUse(ctx, []byte{1}, 5, true, time.Now())
+	require.NoError(t, err)
+	assert.False(t, recorded, "a replayed counter is a cloned authenticator")
+	recorded, err = repo.RecordCredentialUse(ctx, []byte{1}, 4, true, time.Now())
+	require.NoError(t, err)
+	assert.False(t, recorded)
+
+	credentials, err := repo.ListCredentials(ctx, "42")
+	require.NoError(t, err)
+	require.Len(t, credentials, 1)
+	assert.Equal(t, uint32(5), credentials[0].SignCount)
+	assert.Equal(t, []string{"usb"}, credentials[0].Transports)
+	assert.True(t, credentials[0].BackupState)
+}
+
+func TestCredentialRepository_RecordUseWithoutCounter(t *testing.T) {
+	repo := NewCredentialRepository(databasetest.OpenSQLite(t, passkeySchema))
+	ctx := context.Background()
+	require.NoError(t, repo.SaveCredential(ctx, &model.Credential{ID: []byte{1}, UserID: "42", PublicKey: []byte{9}, AttestationType: "none"}))
+
+	// Authenticators without a counter always send zero
+	for i := 0; i < 2; i++ {
+		recorded, err := repo.RecordCredentialUse(ctx, []byte{1}, 0, false, time.Now())
+		require.NoError(t, err)
+		assert.True(t, recorded)
+	}
+}
+
+func TestCredentialDTO_ToModel(t *testing.T) {
+	used := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	credential := credentialDTO{
+		ID:         []byte{1},
+		UserID:     "42",
+		SignCount:  7,
+		Transports: pq.StringArray{"internal"},
+		LastUsedAt: sql.NullTime{Time: used, Valid: true},
+	}.toModel()
+
+	assert.Equal(t, uint32(7), credential.SignCount)
+	assert.Equal(t, []string{"internal"}, credential.Transports)
+	assert.Equal(t, &used, credential.LastUsedAt)
+
+	assert.Nil(t, credentialDTO{}.toModel().LastUsedAt)
+}
+
+func TestCeremonyRepository_TakeCeremonyIsSingleUse(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	db := databasetest.OpenSQLite(t, passkeySchema)
+	repo := NewCeremonyRepository(db)
+	ctx := context.Background()
+	// SaveCeremony is PostgreSQL-only (a data-modifying CTE), the ceremonies are inserted directly
+	_, err := db.ExecContext(ctx, "INSERT INTO webauthn_ceremonies (id, kind, user_id, session, expires_at) VALUES ($1, $2, $3, $4, $5), ($6, $7, $8, $9, $10)",
+		"c1", string(model.CeremonyRegistration), 42, []byte("{}"), now.Add(time.Minute),
+		"c2", string(model.CeremonyLogin), nil, []byte("{}"), now.Add(-time.Minute))
+	require.NoError(t, err)
+
+	// Concurrent finish calls of one ceremony: exactly one gets it
+	var taken atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if ceremony, err := repo.TakeCeremony(ctx, "c1", now); err == nil {
+				assert.Equal(t, model.CeremonyRegistration, ceremony.Kind)
+				assert.Equal(t, "42", ceremony.UserID)
+				taken.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), taken.Load())
+
+	_, err = repo.TakeCeremony(ctx, "c2", now)
+	assert.ErrorIs(t, err, repository.ErrCeremonyNotFound, "expired ceremonies are not returned")
+	_, err = repo.TakeCeremony(ctx, "c2", now.Add(-2*time.Minute))
+	assert.ErrorIs(t, err, repository.ErrCeremonyNotFound, "and are removed")
+}
+
+// readOnlyDatabase is a hot standby replica rejecting every write
+type readOnlyDatabase struct {
+	database.Database
+}
+
+func (readOnlyDatabase) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return &pq.Error{Code: "25006", Message: "cannot execute DELETE in a read-only transaction"}
+}
+
+func TestCeremonyRepository_TakeCeremonyRunsOnPrimary(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	primary := databasetest.OpenSQLite(t, passkeySchema)
+	_, err := primary.ExecContext(context.Background(), "INSERT INTO webauthn_ceremonies (id, kind, session, expires_at) VALUES ($1, $2, $3, $4)",
+		"c1", string(model.CeremonyLogin), []byte("{}"), now.Add(time.Minute))
+	require.NoError(t, err)
+	db := database.NewRoutingDatabase(primary, []database.Database{readOnlyDatabase{}}, 0)
+
+	ceremony, err := NewCeremonyRepository(db).TakeCeremony(database.WithReadYourWrites(context.Background()), "c1", now)
+
+	require.NoError(t, err)
+	assert.Equal(t, "c1", ceremony.ID)
+}
+
+func TestCeremonyRepository_SaveLoginCeremonyWithoutUser(t *testing.T) {
+	db := &databasetest.MockQuerier{}
+	db.On("ExecContext", mock.Anything, databasetest.QueryContaining("INSERT INTO webauthn_ceremonies"), mock.MatchedBy(func(args []interface{}) bool {
+		return args[2] == sql.NullString{}
+	})).Return(databasetest.Result(1), nil)
+
+	require.NoError(t, NewCeremonyRepository(db).SaveCeremony(context.Background(), &model.Ceremony{ID: "c1", Kind: model.CeremonyLogin}))
+	db.AssertExpectations(t)
+}
@@ -0,0 +1,107 @@
+package persistence
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+
+	"hub-user-service/internal/passkey/domain/model"
+	"hub-user-service/internal/passkey/domain/repository"
+)
+
+// MemoryCredentialRepository keeps WebAuthn credentials in process memory (DB_DRIVER=memory)
+type MemoryCredentialRepository struct {
+	mu          sync.Mutex
+	credentials []model.Credential
+}
+
+// NewMemoryCredentialRepository creates an empty in-memory credential repository
+func NewMemoryCredentialRepository() *MemoryCredentialRepository {
+	return &MemoryCredentialRepository{}
+}
+
+func (r *MemoryCredentialRepository) ListCredentials(ctx context.Context, userID string) ([]*model.Credential, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var credentials []*model.Credential
+	for _, credential := range r.credentials {
+		if credential.UserID == userID {
+			copied := credential
+			credentials = append(credentials, &copied)
+		}
+	}
+	return credentials, nil
+}
+
+func (r *MemoryCredentialRepository) SaveCredential(ctx context.Context, credential *model.Credential) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.credentials {
+		if bytes.Equal(existing.ID, credential.ID) {
+			return repository.ErrCredentialExists
+		}
+	}
+	stored := *credential
+	stored.CreatedAt = time.Now()
+	r.credentials = append(r.credentials, stored)
+	return nil
+}
+
+func (r *MemoryCredentialRepository) RecordCredentialUse(ctx context.Context, id []byte, signCount uint32, backupState bool, usedAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, credential := range r.credentials {
+		if !bytes.Equal(credential.ID, id) {
+			continue
+		}
+		if signCount <= credential.SignCount && (signCount != 0 || credential.SignCount != 0) {
+			return false, nil
+		}
+		r.credentials[i].SignCount = signCount
+		r.credentials[i].BackupState = backupState
+		r.credentials[i].LastUsedAt = &usedAt
+		return true, nil
+	}
+	return false, nil
+}
+
+// MemoryCeremonyRepository keeps WebAuthn ceremonies in process memory (DB_DRIVER=memory)
+type MemoryCeremonyRepository struct {
+	mu         sync.Mutex
+	ceremonies map[string]model.Ceremony
+}
+
+// NewMemoryCeremonyRepository creates an empty in-memory ceremony repository
+func NewMemoryCeremonyRepository() *MemoryCeremonyRepository {
+	return &MemoryCeremonyRepository{ceremonies: make(map[string]model.Ceremony)}
+}
+
+func (r *MemoryCeremonyRepository) SaveCeremony(ctx context.Context, ceremony *model.Ceremony) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for id, existing := range r.ceremonies {
+		if existing.IsExpired(now) {
+			delete(r.ceremonies, id)
+		}
+	}
+	r.ceremonies[ceremony.ID] = *ceremony
+	return nil
+}
+
+func (r *MemoryCeremonyRepository) TakeCeremony(ctx context.Context, id string, now time.Time) (*model.Ceremony, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ceremony, ok := r.ceremonies[id]
+	delete(r.ceremonies, id)
+	if !ok || ceremony.IsExpired(now) {
+		return nil, repository.ErrCeremonyNotFound
+	}
+	return &ceremony, nil
+}
@@ -0,0 +1,68 @@
+package persistence
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"hub-user-service/internal/passkey/domain/model"
+	"hub-user-service/internal/passkey/domain/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryCredentialRepository_Lifecycle(t *testing.T) {
+	repo := NewMemoryCredentialRepository()
+	ctx := context.Background()
+
+	require.NoError(t, repo.SaveCredential(ctx, &model.Credential{ID: []byte{1}, UserID: "42", SignCount: 1}))
+	assert.ErrorIs(t, repo.SaveCredential(ctx, &model.Credential{ID: []byte{1}, UserID: "7"}), repository.ErrCredentialExists)
+
+	recorded, err := repo.RecordCredentialUse(ctx, []byte{1}, 2, true, time.Now())
+	require.NoError(t, err)
+	assert.True(t, recorded)
+	recorded, _ = repo.RecordCredentialUse(ctx, []byte{1}, 2, true, time.Now())
+	assert.False(t, recorded, "the counter must increase")
+	recorded, _ = repo.RecordCredentialUse(ctx, []byte{2}, 9, false, time.Now())
+	assert.False(t, recorded, "unknown credential")
+
+	credentials, err := repo.ListCredentials(ctx, "42")
+	require.NoError(t, err)
+	require.Len(t, credentials, 1)
+	assert.Equal(t, uint32(2), credentials[0].SignCount)
+	assert.True(t, credentials[0].BackupState)
+	assert.NotNil(t, credentials[0].LastUsedAt)
+
+	credentials, _ = repo.ListCredentials(ctx, "7")
+	assert.Empty(t, credentials)
+}
+
+func TestMemoryCredentialRepository_ZeroCounters(t *testing.T) {
+	repo := NewMemoryCredentialRepository()
+	ctx := context.Background()
+	require.NoError(t, repo.SaveCredential(ctx, &model.Credential{ID: []byte{1}, UserID: "42"}))
+
+	// Authenticators without a counter always report zero
+	recorded, err := repo.RecordCredentialUse(ctx, []byte{1}, 0, false, time.Now())
+	require.NoError(t, err)
+	assert.True(t, recorded)
+}
+
+func TestMemoryCeremonyRepository_TakeIsSingleUse(t *testing.T) {
+	repo := NewMemoryCeremonyRepository()
+	ctx := context.Background()
+	now := time.Now()
+
+	require.NoError(t, repo.SaveCeremony(ctx, &model.Ceremony{ID: "c1", Kind: model.CeremonyLogin, ExpiresAt: now.Add(time.Minute)}))
+	require.NoError(t, repo.SaveCeremony(ctx, &model.Ceremony{ID: "c2", Kind: model.CeremonyLogin, ExpiresAt: now.Add(time.Minute)}))
+
+	ceremony, err := repo.TakeCeremony(ctx, "c1", now)
+	require.NoError(t, err)
+	assert.Equal(t, "c1", ceremony.ID)
+	_, err = repo.TakeCeremony(ctx, "c1", now)
+	assert.ErrorIs(t, err, repository.ErrCeremonyNotFound)
+
+	_, err = repo.TakeCeremony(ctx, "c2", now.Add(2*time.Minute))
+	assert.ErrorIs(t, err, repository.ErrCeremonyNotFound)
+}
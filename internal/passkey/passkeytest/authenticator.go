@@ -0,0 +1,206 @@
+// Package passkeytest provides a software WebAuthn authenticator, so passkey ceremonies can be
+// tested end to end without hardware
+package passkeytest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+)
+
+// Authenticator flags (WebAuthn §6.1)
+const (
+	flagUserPresent    = 0x01
+	flagUserVerified   = 0x04
+	flagBackupEligible = 0x08
+	flagBackupState    = 0x10
+	flagAttestedData   = 0x40
+)
+
+// Authenticator is a platform authenticator holding one discoverable ES256 credential
+type Authenticator struct {
+	// Origin is reported in the client data, as a browser would
+	Origin string
+	// Format is the attestation statement format: "none" (default) or "packed" self attestation
+	Format string
+	// UserVerification reports that the user entered a PIN or used biometrics
+	UserVerification bool
+	// SignCount is incremented before each signature; keep it at 0 to mimic authenticators that do not count
+	SignCount uint32
+	// CountSignatures makes the authenticator increment SignCount
+	CountSignatures bool
+
+	key          *ecdsa.PrivateKey
+	credentialID []byte
+	userHandle   []byte
+}
+
+// NewAuthenticator creates an authenticator for origin with user verification and a counter
+func NewAuthenticator(origin string) *Authenticator {
+	return &Authenticator{Origin: origin, Format: "none", UserVerification: true, CountSignatures: true}
+}
+
+// CredentialID returns the ID of the credential created by Register
+func (a *Authenticator) CredentialID() []byte {
+	return a.credentialID
+}
+
+// Register answers navigator.credentials.create() options with a new credential
+func (a *Authenticator) Register(options []byte) ([]byte, error) {
+	var creation protocol.CredentialCreation
+	if err := json.Unmarshal(options, &creation); err != nil {
+		return nil, fmt.Errorf("invalid creation options: %w", err)
+	}
+	userID, ok := creation.Response.User.ID.(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid user id %v", creation.Response.User.ID)
+	}
+	userHandle, err := base64.RawURLEncoding.DecodeString(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user id: %w", err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	credentialID := make([]byte, 32)
+	if _, err := rand.Read(credentialID); err != nil {
+		return nil, err
+	}
+	a.key, a.credentialID, a.userHandle = key, credentialID, userHandle
+
+	clientData, err := a.clientData(protocol.CreateCeremony, creation.Response.Challenge)
+	if err != nil {
+		return nil, err
+	}
+	authData, err := a.authenticatorData(creation.Response.RelyingParty.ID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	statement := map[string]interface{}{}
+	if a.Format == "packed" {
+		signature, err := a.sign(authData, clientData)
+		if err != nil {
+			return nil, err
+		}
+		statement = map[string]interface{}{"alg": -7, "sig": signature}
+	}
+	attestationObject, err := webauthncbor.Marshal(map[string]interface{}{
+		"fmt":      a.Format,
+		"attStmt":  statement,
+		"authData": authData,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"id":    base64.RawURLEncoding.EncodeToString(credentialID),
+		"rawId": base64.RawURLEncoding.EncodeToString(credentialID),
+		"type":  "public-key",
+		"response": map[string]interface{}{
+			"clientDataJSON":    base64.RawURLEncoding.EncodeToString(clientData),
+			"attestationObject": base64.RawURLEncoding.EncodeToString(attestationObject),
+			"transports":        []string{"internal", "hybrid"},
+		},
+	})
+}
+
+// Login answers navigator.credentials.get() options with an assertion of the registered credential
+func (a *Authenticator) Login(options []byte) ([]byte, error) {
+	if a.key == nil {
+		return nil, fmt.Errorf("no credential registered")
+	}
+	var assertion protocol.CredentialAssertion
+	if err := json.Unmarshal(options, &assertion); err != nil {
+		return nil, fmt.Errorf("invalid request options: %w", err)
+	}
+
+	clientData, err := a.clientData(protocol.AssertCeremony, assertion.Response.Challenge)
+	if err != nil {
+		return nil, err
+	}
+	authData, err := a.authenticatorData(assertion.Response.RelyingPartyID, false)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := a.sign(authData, clientData)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"id":    base64.RawURLEncoding.EncodeToString(a.credentialID),
+		"rawId": base64.RawURLEncoding.EncodeToString(a.credentialID),
+		"type":  "public-key",
+		"response": map[string]interface{}{
+			"clientDataJSON":    base64.RawURLEncoding.EncodeToString(clientData),
+			"authenticatorData": base64.RawURLEncoding.EncodeToString(authData),
+			"signature":         base64.RawURLEncoding.EncodeToString(signature),
+			"userHandle":        base64.RawURLEncoding.EncodeToString(a.userHandle),
+		},
+	})
+}
+
+func (a *Authenticator) clientData(ceremony protocol.CeremonyType, challenge protocol.URLEncodedBase64) ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"type":      ceremony,
+		"challenge": base64.RawURLEncoding.EncodeToString(challenge),
+		"origin":    a.Origin,
+	})
+}
+
+// authenticatorData encodes rpIdHash, flags, counter and, at registration, the attested credential
+func (a *Authenticator) authenticatorData(rpID string, attested bool) ([]byte, error) {
+	if a.CountSignatures {
+		a.SignCount++
+	}
+
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	flags := byte(flagUserPresent | flagBackupEligible | flagBackupState)
+	if a.UserVerification {
+		flags |= flagUserVerified
+	}
+	if attested {
+		flags |= flagAttestedData
+	}
+
+	data := append([]byte{}, rpIDHash[:]...)
+	data = append(data, flags)
+	data = binary.BigEndian.AppendUint32(data, a.SignCount)
+	if !attested {
+		return data, nil
+	}
+
+	publicKey, err := webauthncbor.Marshal(map[int]interface{}{
+		1:  2,  // kty: EC2
+		3:  -7, // alg: ES256
+		-1: 1,  // crv: P-256
+		-2: a.key.X.FillBytes(make([]byte, 32)),
+		-3: a.key.Y.FillBytes(make([]byte, 32)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	data = append(data, make([]byte, 16)...) // AAGUID, all zeros for software authenticators
+	data = binary.BigEndian.AppendUint16(data, uint16(len(a.credentialID)))
+	data = append(data, a.credentialID...)
+	return append(data, publicKey...), nil
+}
+
+// sign signs authenticatorData || SHA-256(clientDataJSON)
+func (a *Authenticator) sign(authData, clientData []byte) ([]byte, error) {
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	return ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+}
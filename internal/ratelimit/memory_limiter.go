@@ -0,0 +1,81 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// bucket holds the token bucket state for a single key
+type bucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+// MemoryLimiter implements Limiter with per-process token buckets
+// Limits are not shared between replicas; use it for single-instance deployments and tests
+type MemoryLimiter struct {
+	mu       sync.Mutex
+	buckets  map[string]*bucket
+	now      func() time.Time
+	idleTTL  time.Duration
+	lastScan time.Time
+}
+
+// NewMemoryLimiter creates a new in-memory token bucket limiter
+func NewMemoryLimiter() *MemoryLimiter {
+	return &MemoryLimiter{
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+		idleTTL: 10 * time.Minute,
+	}
+}
+
+// Allow takes one token from the bucket identified by key
+func (m *MemoryLimiter) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	if limit.IsUnlimited() {
+		return Result{Allowed: true, Remaining: math.MaxInt32}, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.evictIdle(now)
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), lastSeen: now}
+		m.buckets[key] = b
+	}
+
+	// Refill tokens for the time elapsed since the last request
+	elapsed := now.Sub(b.lastSeen).Seconds()
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+elapsed*limit.Rate)
+	b.lastSeen = now
+
+	if b.tokens < 1 {
+		missing := 1 - b.tokens
+		retryAfter := time.Duration(missing / limit.Rate * float64(time.Second))
+		return Result{Allowed: false, Remaining: 0, RetryAfter: retryAfter}, nil
+	}
+
+	b.tokens--
+	return Result{Allowed: true, Remaining: int(b.tokens)}, nil
+}
+
+// evictIdle drops buckets that have not been used for idleTTL so the map cannot grow without bound
+// Caller must hold m.mu
+func (m *MemoryLimiter) evictIdle(now time.Time) {
+	if now.Sub(m.lastScan) < m.idleTTL {
+		return
+	}
+	m.lastScan = now
+
+	for key, b := range m.buckets {
+		if now.Sub(b.lastSeen) > m.idleTTL {
+			delete(m.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestLimiter(now *time.Time) *MemoryLimiter {
+	limiter := NewMemoryLimiter()
+	limiter.now = func() time.Time { return *now }
+	return limiter
+}
+
+func TestMemoryLimiter_AllowsUpToBurst(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	limiter := newTestLimiter(&now)
+	limit := Limit{Rate: 1, Burst: 3}
+
+	for i := 0; i < 3; i++ {
+		result, err := limiter.Allow(context.Background(), "client-a", limit)
+		assert.NoError(t, err)
+		assert.True(t, result.Allowed, "request %d should be allowed", i)
+	}
+
+	result, err := limiter.Allow(context.Background(), "client-a", limit)
+	assert.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, time.Second, result.RetryAfter)
+}
+
+func TestMemoryLimiter_RefillsOverTime(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	limiter := newTestLimiter(&now)
+	limit := Limit{Rate: 2, Burst: 1}
+
+	result, _ := limiter.Allow(context.Background(), "client-a", limit)
+	assert.True(t, result.Allowed)
+
+	result, _ = limiter.Allow(context.Background(), "client-a", limit)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 500*time.Millisecond, result.RetryAfter)
+
+	now = now.Add(500 * time.Millisecond)
+	result, _ = limiter.Allow(context.Background(), "client-a", limit)
+	assert.True(t, result.Allowed)
+}
+
+func TestMemoryLimiter_KeysAreIndependent(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	limiter := newTestLimiter(&now)
+	limit := Limit{Rate: 1, Burst: 1}
+
+	result, _ := limiter.Allow(context.Background(), "client-a", limit)
+	assert.True(t, result.Allowed)
+
+	result, _ = limiter.Allow(context.Background(), "client-b", limit)
+	assert.True(t, result.Allowed)
+}
+
+func TestMemoryLimiter_UnlimitedAlwaysAllows(t *testing.T) {
+	limiter := NewMemoryLimiter()
+
+	for i := 0; i < 100; i++ {
+		result, err := limiter.Allow(context.Background(), "client-a", Limit{})
+		assert.NoError(t, err)
+		assert.True(t, result.Allowed)
+	}
+}
+
+func TestMemoryLimiter_EvictsIdleBuckets(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	limiter := newTestLimiter(&now)
+	limit := Limit{Rate: 1, Burst: 1}
+
+	limiter.Allow(context.Background(), "client-a", limit)
+	assert.Len(t, limiter.buckets, 1)
+
+	now = now.Add(limiter.idleTTL + time.Minute)
+	limiter.Allow(context.Background(), "client-b", limit)
+	assert.Len(t, limiter.buckets, 1)
+	assert.Contains(t, limiter.buckets, "client-b")
+}
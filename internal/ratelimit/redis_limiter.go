@@ -0,0 +1,100 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// tokenBucketScript refills and takes a token from the bucket in KEYS[1] atomically
+// ARGV: rate (tokens per second), burst, now (unix seconds)
+// Returns {allowed (0 or 1), remaining tokens, retry after in seconds}; numbers are returned as
+// strings since Redis truncates Lua numbers to integers
+var tokenBucketScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+  tokens = burst
+  ts = now
+end
+
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate)
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+else
+  retry = (1 - tokens) / rate
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(now))
+-- A full bucket carries no state, keep the key only until it would be refilled
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst / rate * 1000) + 1000)
+return {allowed, tostring(tokens), tostring(retry)}
+`)
+
+// RedisLimiter implements Limiter with token buckets stored in Redis, shared by every replica
+type RedisLimiter struct {
+	client redis.Scripter
+	prefix string
+	now    func() time.Time
+}
+
+// NewRedisLimiter creates a token bucket limiter on client; keys are stored under "ratelimit:"
+func NewRedisLimiter(client redis.Scripter) *RedisLimiter {
+	return &RedisLimiter{client: client, prefix: "ratelimit:", now: time.Now}
+}
+
+// Allow takes one token from the bucket identified by key
+func (r *RedisLimiter) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	if limit.IsUnlimited() {
+		return Result{Allowed: true, Remaining: math.MaxInt32}, nil
+	}
+
+	now := float64(r.now().UnixMicro()) / 1e6
+	reply, err := tokenBucketScript.Run(ctx, r.client, []string{r.prefix + key}, limit.Rate, limit.Burst, now).Slice()
+	if err != nil {
+		return Result{}, fmt.Errorf("rate limiter: %w", err)
+	}
+	if len(reply) != 3 {
+		return Result{}, fmt.Errorf("rate limiter: unexpected reply %v", reply)
+	}
+
+	allowed, _ := reply[0].(int64)
+	tokens, err := parseReplyFloat(reply[1])
+	if err != nil {
+		return Result{}, err
+	}
+	retryAfter, err := parseReplyFloat(reply[2])
+	if err != nil {
+		return Result{}, err
+	}
+
+	if allowed != 1 {
+		return Result{Allowed: false, RetryAfter: time.Duration(retryAfter * float64(time.Second))}, nil
+	}
+	return Result{Allowed: true, Remaining: int(tokens)}, nil
+}
+
+// parseReplyFloat reads a number returned as a string by the script
+func parseReplyFloat(value interface{}) (float64, error) {
+	s, ok := value.(string)
+	if !ok {
+		return 0, fmt.Errorf("rate limiter: unexpected reply %v", value)
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("rate limiter: %w", err)
+	}
+	return f, nil
+}
@@ -0,0 +1,92 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRedisLimiter(t *testing.T, now *time.Time) (*RedisLimiter, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	limiter := NewRedisLimiter(client)
+	limiter.now = func() time.Time { return *now }
+	return limiter, server
+}
+
+func TestRedisLimiter_AllowsUpToBurst(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	limiter, _ := newTestRedisLimiter(t, &now)
+	limit := Limit{Rate: 1, Burst: 3}
+
+	for i := 0; i < 3; i++ {
+		result, err := limiter.Allow(context.Background(), "client-a", limit)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed, "request %d should be allowed", i)
+		assert.Equal(t, 2-i, result.Remaining)
+	}
+
+	result, err := limiter.Allow(context.Background(), "client-a", limit)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, time.Second, result.RetryAfter)
+
+	result, _ = limiter.Allow(context.Background(), "client-b", limit)
+	assert.True(t, result.Allowed, "keys have separate buckets")
+}
+
+func TestRedisLimiter_RefillsOverTime(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	limiter, _ := newTestRedisLimiter(t, &now)
+	limit := Limit{Rate: 2, Burst: 1}
+
+	result, _ := limiter.Allow(context.Background(), "client-a", limit)
+	assert.True(t, result.Allowed)
+
+	result, _ = limiter.Allow(context.Background(), "client-a", limit)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 500*time.Millisecond, result.RetryAfter)
+
+	now = now.Add(500 * time.Millisecond)
+	result, _ = limiter.Allow(context.Background(), "client-a", limit)
+	assert.True(t, result.Allowed)
+}
+
+func TestRedisLimiter_ExpiresIdleBuckets(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	limiter, server := newTestRedisLimiter(t, &now)
+
+	_, err := limiter.Allow(context.Background(), "client-a", Limit{Rate: 1, Burst: 10})
+	require.NoError(t, err)
+	assert.True(t, server.Exists("ratelimit:client-a"))
+
+	server.FastForward(11 * time.Second)
+	assert.False(t, server.Exists("ratelimit:client-a"), "removed once the bucket would be full again")
+}
+
+func TestRedisLimiter_Unlimited(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	limiter, server := newTestRedisLimiter(t, &now)
+
+	result, err := limiter.Allow(context.Background(), "client-a", Limit{})
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Empty(t, server.Keys())
+}
+
+func TestRedisLimiter_Unavailable(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	limiter, server := newTestRedisLimiter(t, &now)
+	server.Close()
+
+	_, err := limiter.Allow(context.Background(), "client-a", Limit{Rate: 1, Burst: 1})
+	assert.Error(t, err)
+}
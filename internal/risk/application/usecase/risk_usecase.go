@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"hub-user-service/internal/geoip"
+	loginHistoryUsecase "hub-user-service/internal/loginhistory/application/usecase"
+	loginHistoryRepository "hub-user-service/internal/loginhistory/domain/repository"
+	"hub-user-service/internal/risk/domain/model"
+)
+
+// minTravelTime is the shortest time a trip is assumed to take, so logins seconds apart do not
+// reach absurd speeds
+const minTravelTime = time.Minute
+
+type IRiskUsecase interface {
+	// Assess scores a login whose first factor was accepted; a required second factor is
+	// waived for multi-factor logins and replaced by the policy's fallback when the user has none
+	Assess(ctx context.Context, login model.Login) (*model.Assessment, error)
+}
+
+// PolicySource provides the current risk policy, which may change between calls
+type PolicySource interface {
+	Policy() *model.Policy
+}
+
+// IPBlocklist tells whether an IP address is blocked
+type IPBlocklist interface {
+	Contains(ip string) bool
+}
+
+type RiskUsecase struct {
+	history   loginHistoryRepository.ILoginHistoryRepository
+	locator   geoip.Locator
+	policies  PolicySource
+	blocklist IPBlocklist
+}
+
+// NewRiskUsecase creates the risk use case; blocklist may be nil
+func NewRiskUsecase(history loginHistoryRepository.ILoginHistoryRepository, locator geoip.Locator, policies PolicySource, blocklist IPBlocklist) IRiskUsecase {
+	return &RiskUsecase{history: history, locator: locator, policies: policies, blocklist: blocklist}
+}
+
+func (u *RiskUsecase) Assess(ctx context.Context, login model.Login) (*model.Assessment, error) {
+	policy := u.policies.Policy()
+	signals, err := u.signals(ctx, policy, login)
+	if err != nil {
+		return nil, err
+	}
+
+	assessment := policy.Evaluate(signals)
+	if assessment.Decision == model.DecisionMFA {
+		if login.MultiFactor {
+			assessment.Decision = model.DecisionAllow
+		} else if !login.CanStepUp {
+			assessment.Decision = policy.MFAFallback
+		}
+	}
+	if assessment.Score != 0 {
+		log.Printf("🛡️  Login risk of user %s: score %d (%s), %s", login.UserID, assessment.Score, strings.Join(assessment.Rules, ", "), assessment.Decision)
+	}
+	return assessment, nil
+}
+
+// signals measures the login against the user's history
+func (u *RiskUsecase) signals(ctx context.Context, policy *model.Policy, login model.Login) (model.Signals, error) {
+	signals := model.Signals{
+		model.SignalHour: float64(login.Time.In(policy.Location).Hour()),
+	}
+	if u.blocklist != nil && u.blocklist.Contains(login.IPAddress) {
+		signals[model.SignalBlockedIP] = 1
+	}
+
+	devices, err := u.history.GetDeviceHistory(ctx, login.UserID, loginHistoryUsecase.Fingerprint(login.UserAgent, login.Device))
+	if err != nil {
+		return nil, err
+	}
+	if devices.HasLogins && !devices.KnownDevice {
+		signals[model.SignalNewDevice] = 1
+	}
+
+	failures, err := u.history.CountFailures(ctx, login.UserID, login.Time.Add(-policy.FailureWindow))
+	if err != nil {
+		return nil, err
+	}
+	signals[model.SignalRecentFailures] = float64(failures)
+
+	last, err := u.history.GetLastSuccess(ctx, login.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if last != nil {
+		if distance, ok := geoip.Distance(u.locator.Locate(last.IPAddress), u.locator.Locate(login.IPAddress)); ok {
+			elapsed := math.Max(login.Time.Sub(last.CreatedAt).Hours(), minTravelTime.Hours())
+			signals[model.SignalTravelDistance] = distance
+			signals[model.SignalTravelSpeed] = distance / elapsed
+		}
+	}
+	return signals, nil
+}
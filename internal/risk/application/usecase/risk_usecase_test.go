@@ -0,0 +1,154 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"hub-user-service/internal/geoip"
+	loginHistoryUsecase "hub-user-service/internal/loginhistory/application/usecase"
+	loginHistoryModel "hub-user-service/internal/loginhistory/domain/model"
+	"hub-user-service/internal/loginhistory/infra/persistence"
+	"hub-user-service/internal/risk/domain/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// staticPolicy is a PolicySource that never changes
+type staticPolicy struct {
+	policy *model.Policy
+}
+
+func (s staticPolicy) Policy() *model.Policy {
+	return s.policy
+}
+
+// blockedIPs is an IPBlocklist of single addresses
+type blockedIPs map[string]bool
+
+func (b blockedIPs) Contains(ip string) bool {
+	return b[ip]
+}
+
+func bound(v float64) *float64 {
+	return &v
+}
+
+const laptopAgent = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 Chrome/126.0 Safari/537.36"
+
+var testNow = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+func newTestRiskUsecase(t *testing.T, policy *model.Policy) (IRiskUsecase, *persistence.MemoryLoginHistoryRepository) {
+	t.Helper()
+	locations, err := geoip.ParseDatabase(strings.NewReader(`network,country,region,city,latitude,longitude
+203.0.113.0/24,PT,Lisbon,Lisbon,38.7223,-9.1393
+198.51.100.0/24,BR,São Paulo,São Paulo,-23.5505,-46.6333
+`))
+	require.NoError(t, err)
+
+	history := persistence.NewMemoryLoginHistoryRepository()
+	require.NoError(t, history.RecordAttempt(context.Background(), &loginHistoryModel.LoginAttempt{
+		UserID: "42", Outcome: loginHistoryModel.OutcomeSuccess, IPAddress: "203.0.113.7",
+		DeviceFingerprint: loginHistoryUsecase.Fingerprint(laptopAgent, ""), CreatedAt: testNow.Add(-2 * time.Hour),
+	}))
+	return NewRiskUsecase(history, locations, staticPolicy{policy}, blockedIPs{"192.0.2.66": true}), history
+}
+
+func testPolicy() *model.Policy {
+	return &model.Policy{
+		MFAScore:      40,
+		DenyScore:     80,
+		MFAFallback:   model.DecisionDeny,
+		FailureWindow: 15 * time.Minute,
+		Location:      time.UTC,
+		Rules: []model.Rule{
+			{Name: "new device", When: map[model.Signal]model.Condition{model.SignalNewDevice: {Min: bound(1)}}, Score: 40},
+			{Name: "impossible travel", When: map[model.Signal]model.Condition{model.SignalTravelSpeed: {Min: bound(900)}}, Score: 80},
+			{Name: "failures", When: map[model.Signal]model.Condition{model.SignalRecentFailures: {Min: bound(3)}}, Score: 40},
+			{Name: "blocked ip", When: map[model.Signal]model.Condition{model.SignalBlockedIP: {Min: bound(1)}}, Score: 100},
+		},
+	}
+}
+
+func TestRiskUsecase_Signals(t *testing.T) {
+	risk, history := newTestRiskUsecase(t, &model.Policy{MFAScore: 1000, DenyScore: 1000, MFAFallback: model.DecisionDeny, FailureWindow: 15 * time.Minute, Location: time.UTC})
+	for _, at := range []time.Duration{-20 * time.Minute, -10 * time.Minute, -time.Minute} {
+		require.NoError(t, history.RecordAttempt(context.Background(), &loginHistoryModel.LoginAttempt{UserID: "42", Outcome: loginHistoryModel.OutcomeFailure, CreatedAt: testNow.Add(at)}))
+	}
+
+	assessment, err := risk.Assess(context.Background(), model.Login{UserID: "42", IPAddress: "198.51.100.7", UserAgent: "curl/8.0", Time: testNow.Add(3 * time.Hour)})
+	require.NoError(t, err)
+	signals := assessment.Signals
+	assert.Equal(t, 1.0, signals[model.SignalNewDevice])
+	assert.Equal(t, 0.0, signals[model.SignalBlockedIP])
+	assert.Equal(t, 15.0, signals[model.SignalHour])
+	assert.Equal(t, 0.0, signals[model.SignalRecentFailures], "failures outside the window are not counted")
+	assert.InDelta(t, 7930, signals[model.SignalTravelDistance], 20)
+	assert.InDelta(t, 7930/5.0, signals[model.SignalTravelSpeed], 5, "five hours since the last login")
+
+	assessment, err = risk.Assess(context.Background(), model.Login{UserID: "42", IPAddress: "192.0.2.66", UserAgent: laptopAgent, Time: testNow})
+	require.NoError(t, err)
+	signals = assessment.Signals
+	assert.Equal(t, 0.0, signals[model.SignalNewDevice])
+	assert.Equal(t, 1.0, signals[model.SignalBlockedIP])
+	assert.Equal(t, 2.0, signals[model.SignalRecentFailures])
+	assert.Equal(t, 0.0, signals[model.SignalTravelSpeed], "unknown location")
+}
+
+func TestRiskUsecase_Assess(t *testing.T) {
+	tests := []struct {
+		name     string
+		login    model.Login
+		decision model.Decision
+		rules    []string
+	}{
+		{
+			name:     "known device",
+			login:    model.Login{UserID: "42", IPAddress: "203.0.113.9", UserAgent: laptopAgent},
+			decision: model.DecisionAllow,
+		},
+		{
+			name:     "new device with TOTP",
+			login:    model.Login{UserID: "42", IPAddress: "203.0.113.9", UserAgent: "curl/8.0", CanStepUp: true},
+			decision: model.DecisionMFA,
+			rules:    []string{"new device"},
+		},
+		{
+			name:     "new device without a second factor",
+			login:    model.Login{UserID: "42", IPAddress: "203.0.113.9", UserAgent: "curl/8.0"},
+			decision: model.DecisionDeny,
+			rules:    []string{"new device"},
+		},
+		{
+			name:     "new device with a verified passkey",
+			login:    model.Login{UserID: "42", IPAddress: "203.0.113.9", UserAgent: "curl/8.0", MultiFactor: true},
+			decision: model.DecisionAllow,
+			rules:    []string{"new device"},
+		},
+		{
+			name:     "impossible travel",
+			login:    model.Login{UserID: "42", IPAddress: "198.51.100.7", UserAgent: laptopAgent, MultiFactor: true, CanStepUp: true},
+			decision: model.DecisionDeny,
+			rules:    []string{"impossible travel"},
+		},
+		{
+			name:     "first login",
+			login:    model.Login{UserID: "7", IPAddress: "198.51.100.7", UserAgent: "curl/8.0"},
+			decision: model.DecisionAllow,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			risk, _ := newTestRiskUsecase(t, testPolicy())
+			tt.login.Time = testNow
+
+			assessment, err := risk.Assess(context.Background(), tt.login)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.decision, assessment.Decision)
+			assert.Equal(t, tt.rules, assessment.Rules)
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Decision is the outcome of a risk assessment
+type Decision string
+
+const (
+	// DecisionAllow lets the login complete with the factors already given
+	DecisionAllow Decision = "allow"
+	// DecisionMFA asks for a second factor before tokens are issued
+	DecisionMFA Decision = "mfa"
+	// DecisionDeny refuses the login
+	DecisionDeny Decision = "deny"
+)
+
+// DefaultFailureWindow is the period SignalRecentFailures counts failed attempts over
+const DefaultFailureWindow = 15 * time.Minute
+
+// Condition is a range a signal must fall in; a nil bound is open. When Min is greater than Max
+// the range wraps around, e.g. hours 22 to 5
+type Condition struct {
+	Min *float64 `json:"min,omitempty"`
+	Max *float64 `json:"max,omitempty"`
+}
+
+// Matches tells whether value is within the range
+func (c Condition) Matches(value float64) bool {
+	if c.Min != nil && c.Max != nil && *c.Min > *c.Max {
+		return value >= *c.Min || value <= *c.Max
+	}
+	return (c.Min == nil || value >= *c.Min) && (c.Max == nil || value <= *c.Max)
+}
+
+// Rule adds Score to the risk of a login when every condition of When matches
+type Rule struct {
+	Name  string               `json:"name"`
+	When  map[Signal]Condition `json:"when"`
+	Score int                  `json:"score"`
+}
+
+// Policy maps the signals of a login to a decision
+type Policy struct {
+	// MFAScore and DenyScore are the risk scores from which a second factor is required, and
+	// from which the login is refused
+	MFAScore  int
+	DenyScore int
+	// MFAFallback is the decision (allow or deny) when a second factor is required but the user
+	// has none to give
+	MFAFallback   Decision
+	FailureWindow time.Duration
+	// Location is the time zone of SignalHour
+	Location *time.Location
+	Rules    []Rule
+}
+
+// Assessment is the scored risk of a login
+type Assessment struct {
+	Score    int
+	Decision Decision
+	// Rules are the names of the rules that matched
+	Rules   []string
+	Signals Signals
+}
+
+// Validate checks the thresholds and rules of the policy
+func (p *Policy) Validate() error {
+	if p.MFAScore <= 0 || p.DenyScore < p.MFAScore {
+		return errors.New("mfa_score must be positive and deny_score at least mfa_score")
+	}
+	if p.MFAFallback != DecisionAllow && p.MFAFallback != DecisionDeny {
+		return fmt.Errorf("mfa_fallback must be %q or %q", DecisionAllow, DecisionDeny)
+	}
+	if p.FailureWindow <= 0 {
+		return errors.New("failure_window must be positive")
+	}
+	if p.Location == nil {
+		return errors.New("time zone is required")
+	}
+
+	names := make(map[string]bool, len(p.Rules))
+	for _, rule := range p.Rules {
+		if rule.Name == "" || names[rule.Name] {
+			return fmt.Errorf("rule names must be set and unique, got %q", rule.Name)
+		}
+		names[rule.Name] = true
+		if len(rule.When) == 0 {
+			return fmt.Errorf("rule %q has no conditions", rule.Name)
+		}
+		if rule.Score == 0 {
+			return fmt.Errorf("rule %q has no score", rule.Name)
+		}
+		for signal, condition := range rule.When {
+			if !slices.Contains(KnownSignals, signal) {
+				return fmt.Errorf("rule %q: unknown signal %q", rule.Name, signal)
+			}
+			if condition.Min == nil && condition.Max == nil {
+				return fmt.Errorf("rule %q: signal %q needs a min or a max", rule.Name, signal)
+			}
+		}
+	}
+	return nil
+}
+
+// Evaluate scores the signals of a login; rules may lower the score with a negative Score
+func (p *Policy) Evaluate(signals Signals) *Assessment {
+	assessment := &Assessment{Decision: DecisionAllow, Signals: signals}
+	for _, rule := range p.Rules {
+		if rule.matches(signals) {
+			assessment.Score += rule.Score
+			assessment.Rules = append(assessment.Rules, rule.Name)
+		}
+	}
+
+	switch {
+	case assessment.Score >= p.DenyScore:
+		assessment.Decision = DecisionDeny
+	case assessment.Score >= p.MFAScore:
+		assessment.Decision = DecisionMFA
+	}
+	return assessment
+}
+
+// matches tells whether every condition of the rule holds
+func (r Rule) matches(signals Signals) bool {
+	for signal, condition := range r.When {
+		if !condition.Matches(signals[signal]) {
+			return false
+		}
+	}
+	return true
+}
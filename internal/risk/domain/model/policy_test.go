@@ -0,0 +1,87 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func bound(v float64) *float64 {
+	return &v
+}
+
+func TestCondition_Matches(t *testing.T) {
+	atLeastOne := Condition{Min: bound(1)}
+	assert.True(t, atLeastOne.Matches(1))
+	assert.False(t, atLeastOne.Matches(0))
+
+	between := Condition{Min: bound(3), Max: bound(5)}
+	assert.True(t, between.Matches(4))
+	assert.False(t, between.Matches(6))
+
+	night := Condition{Min: bound(22), Max: bound(5)}
+	for _, hour := range []float64{22, 23, 0, 5} {
+		assert.True(t, night.Matches(hour), hour)
+	}
+	for _, hour := range []float64{6, 12, 21} {
+		assert.False(t, night.Matches(hour), hour)
+	}
+}
+
+func testPolicy() *Policy {
+	return &Policy{
+		MFAScore:      40,
+		DenyScore:     80,
+		MFAFallback:   DecisionDeny,
+		FailureWindow: DefaultFailureWindow,
+		Location:      time.UTC,
+		Rules: []Rule{
+			{Name: "new device", When: map[Signal]Condition{SignalNewDevice: {Min: bound(1)}}, Score: 40},
+			{Name: "impossible travel", When: map[Signal]Condition{SignalTravelSpeed: {Min: bound(900)}, SignalTravelDistance: {Min: bound(500)}}, Score: 60},
+			{Name: "blocked ip", When: map[Signal]Condition{SignalBlockedIP: {Min: bound(1)}}, Score: 100},
+		},
+	}
+}
+
+func TestPolicy_Evaluate(t *testing.T) {
+	policy := testPolicy()
+
+	allowed := policy.Evaluate(Signals{SignalTravelSpeed: 2000, SignalTravelDistance: 50})
+	assert.Equal(t, DecisionAllow, allowed.Decision, "every condition of a rule must match")
+	assert.Zero(t, allowed.Score)
+	assert.Empty(t, allowed.Rules)
+
+	mfa := policy.Evaluate(Signals{SignalNewDevice: 1})
+	assert.Equal(t, DecisionMFA, mfa.Decision)
+	assert.Equal(t, 40, mfa.Score)
+	assert.Equal(t, []string{"new device"}, mfa.Rules)
+
+	denied := policy.Evaluate(Signals{SignalNewDevice: 1, SignalTravelSpeed: 2000, SignalTravelDistance: 8000})
+	assert.Equal(t, DecisionDeny, denied.Decision)
+	assert.Equal(t, 100, denied.Score)
+	assert.Equal(t, []string{"new device", "impossible travel"}, denied.Rules)
+}
+
+func TestPolicy_Validate(t *testing.T) {
+	assert.NoError(t, testPolicy().Validate())
+
+	tests := map[string]func(p *Policy){
+		"no mfa score":           func(p *Policy) { p.MFAScore = 0 },
+		"deny below mfa":         func(p *Policy) { p.DenyScore = 20 },
+		"invalid fallback":       func(p *Policy) { p.MFAFallback = DecisionMFA },
+		"no failure window":      func(p *Policy) { p.FailureWindow = 0 },
+		"no time zone":           func(p *Policy) { p.Location = nil },
+		"unnamed rule":           func(p *Policy) { p.Rules[0].Name = "" },
+		"duplicate rule":         func(p *Policy) { p.Rules[1].Name = p.Rules[0].Name },
+		"rule without condition": func(p *Policy) { p.Rules[0].When = nil },
+		"rule without score":     func(p *Policy) { p.Rules[0].Score = 0 },
+		"unknown signal":         func(p *Policy) { p.Rules[0].When = map[Signal]Condition{"country": {Min: bound(1)}} },
+		"unbounded condition":    func(p *Policy) { p.Rules[0].When = map[Signal]Condition{SignalNewDevice: {}} },
+	}
+	for name, change := range tests {
+		policy := testPolicy()
+		change(policy)
+		assert.Error(t, policy.Validate(), name)
+	}
+}
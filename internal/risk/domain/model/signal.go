@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+// Signal names a measurement of a login attempt that the risk rules score
+type Signal string
+
+const (
+	// SignalNewDevice is 1 when the account logged in before, but never from this device
+	SignalNewDevice Signal = "new_device"
+	// SignalTravelDistance is the distance in km from the location of the last successful login
+	SignalTravelDistance Signal = "travel_distance_km"
+	// SignalTravelSpeed is the speed in km/h needed to travel from the last successful login
+	SignalTravelSpeed Signal = "travel_speed_kmh"
+	// SignalRecentFailures is the number of failed attempts within the policy's failure window
+	SignalRecentFailures Signal = "recent_failures"
+	// SignalBlockedIP is 1 when the IP address is on the blocklist (e.g. TOR exit nodes)
+	SignalBlockedIP Signal = "blocked_ip"
+	// SignalHour is the hour of the day (0-23) in the policy's time zone
+	SignalHour Signal = "hour"
+)
+
+// KnownSignals are the signals rules can refer to
+var KnownSignals = []Signal{SignalNewDevice, SignalTravelDistance, SignalTravelSpeed, SignalRecentFailures, SignalBlockedIP, SignalHour}
+
+// Signals are the measured values of a login attempt; boolean signals are 0 or 1 and signals
+// that could not be measured (no previous login, no location) are 0
+type Signals map[Signal]float64
+
+// Login is a login whose first factor was accepted, to be assessed before tokens are issued
+type Login struct {
+	UserID    string
+	IPAddress string
+	UserAgent string
+	Device    string
+	// MultiFactor is set when the login already proves two factors (a passkey with user verification)
+	MultiFactor bool
+	// CanStepUp is set when the user has a second factor to ask for (TOTP enabled)
+	CanStepUp bool
+	Time      time.Time
+}
@@ -0,0 +1,99 @@
+package filesource
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net/netip"
+	"strings"
+	"sync/atomic"
+)
+
+// blocklist is a set of IP addresses and networks
+type blocklist struct {
+	addrs    map[netip.Addr]struct{}
+	prefixes []netip.Prefix
+}
+
+// parseBlocklist reads one IP address or CIDR network per line; blank lines and lines starting
+// with # are skipped, as is anything after the first field (e.g. the Tor bulk exit list format)
+func parseBlocklist(data []byte) (*blocklist, error) {
+	list := &blocklist{addrs: make(map[netip.Addr]struct{})}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		if strings.Contains(fields[0], "/") {
+			prefix, err := netip.ParsePrefix(fields[0])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			list.prefixes = append(list.prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		list.addrs[addr.Unmap()] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// contains tells whether addr is listed, alone or within a network
+func (l *blocklist) contains(addr netip.Addr) bool {
+	if _, ok := l.addrs[addr]; ok {
+		return true
+	}
+	for _, prefix := range l.prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// BlocklistFile is a list of blocked IP addresses and networks (e.g. Tor exit nodes) loaded
+// from a file, reloaded by Watch when the file changes
+type BlocklistFile struct {
+	*watchedFile
+	list atomic.Pointer[blocklist]
+}
+
+// LoadBlocklistFile reads the blocklist at path
+func LoadBlocklistFile(path string) (*BlocklistFile, error) {
+	f := &BlocklistFile{}
+	f.watchedFile = newWatchedFile(path, "IP blocklist", func(data []byte) error {
+		list, err := parseBlocklist(data)
+		if err != nil {
+			return err
+		}
+		f.list.Store(list)
+		return nil
+	})
+	if _, err := f.Reload(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Contains tells whether ip is blocked; invalid addresses are not
+func (f *BlocklistFile) Contains(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	return f.list.Load().contains(addr.Unmap())
+}
+
+// Len returns the number of listed addresses and networks
+func (f *BlocklistFile) Len() int {
+	list := f.list.Load()
+	return len(list.addrs) + len(list.prefixes)
+}
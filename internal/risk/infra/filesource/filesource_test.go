@@ -0,0 +1,136 @@
+package filesource
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"hub-user-service/internal/risk/domain/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPolicy = `{
+  "mfa_score": 40,
+  "deny_score": 80,
+  "mfa_fallback": "allow",
+  "failure_window": "30m",
+  "time_zone": "America/Sao_Paulo",
+  "rules": [
+    {"name": "new device", "when": {"new_device": {"min": 1}}, "score": 40}
+  ]
+}`
+
+func TestParsePolicy(t *testing.T) {
+	policy, err := ParsePolicy([]byte(testPolicy))
+	require.NoError(t, err)
+	assert.Equal(t, 40, policy.MFAScore)
+	assert.Equal(t, 80, policy.DenyScore)
+	assert.Equal(t, model.DecisionAllow, policy.MFAFallback)
+	assert.Equal(t, 30*time.Minute, policy.FailureWindow)
+	assert.Equal(t, "America/Sao_Paulo", policy.Location.String())
+	require.Len(t, policy.Rules, 1)
+	assert.Equal(t, 1.0, *policy.Rules[0].When[model.SignalNewDevice].Min)
+
+	defaults, err := ParsePolicy([]byte(`{"mfa_score": 40, "deny_score": 80}`))
+	require.NoError(t, err)
+	assert.Equal(t, model.DecisionDeny, defaults.MFAFallback)
+	assert.Equal(t, model.DefaultFailureWindow, defaults.FailureWindow)
+	assert.Equal(t, time.UTC, defaults.Location)
+
+	for name, input := range map[string]string{
+		"invalid json":   `{`,
+		"unknown field":  `{"mfa_score": 40, "deny_score": 80, "deny": 90}`,
+		"invalid window": `{"mfa_score": 40, "deny_score": 80, "failure_window": "soon"}`,
+		"invalid zone":   `{"mfa_score": 40, "deny_score": 80, "time_zone": "Mars/Olympus"}`,
+		"invalid policy": `{"mfa_score": 0, "deny_score": 80}`,
+		"unknown signal": `{"mfa_score": 40, "deny_score": 80, "rules": [{"name": "x", "when": {"country": {"min": 1}}, "score": 1}]}`,
+		"invalid bounds": `{"mfa_score": 40, "deny_score": 80, "rules": [{"name": "x", "when": {"hour": {"min": "night"}}, "score": 1}]}`,
+	} {
+		_, err := ParsePolicy([]byte(input))
+		assert.Error(t, err, name)
+	}
+}
+
+func TestPolicyFile_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "risk_rules.json")
+	require.NoError(t, os.WriteFile(path, []byte(testPolicy), 0o600))
+
+	file, err := LoadPolicyFile(path)
+	require.NoError(t, err)
+	defer file.Close()
+	assert.Equal(t, 40, file.Policy().MFAScore)
+
+	reloaded, err := file.Reload()
+	require.NoError(t, err)
+	assert.False(t, reloaded, "unchanged file")
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"mfa_score": 30, "deny_score": 60}`), 0o600))
+	reloaded, err = file.Reload()
+	require.NoError(t, err)
+	assert.True(t, reloaded)
+	assert.Equal(t, 30, file.Policy().MFAScore)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"mfa_score": 30, "deny_score": 10}`), 0o600))
+	_, err = file.Reload()
+	assert.Error(t, err)
+	assert.Equal(t, 30, file.Policy().MFAScore, "an invalid file keeps the previous policy")
+
+	_, err = LoadPolicyFile(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestPolicyFile_Watch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "risk_rules.json")
+	require.NoError(t, os.WriteFile(path, []byte(testPolicy), 0o600))
+	file, err := LoadPolicyFile(path)
+	require.NoError(t, err)
+
+	file.Watch(10 * time.Millisecond)
+	defer file.Close()
+	require.NoError(t, os.WriteFile(path, []byte(`{"mfa_score": 50, "deny_score": 90}`), 0o600))
+
+	assert.Eventually(t, func() bool { return file.Policy().MFAScore == 50 }, time.Second, 10*time.Millisecond)
+}
+
+func TestBlocklistFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blocklist.txt")
+	require.NoError(t, os.WriteFile(path, []byte(`# Tor exit nodes
+203.0.113.7
+198.51.100.0/24 added 2026-10-01
+
+2001:db8::1
+`), 0o600))
+
+	blocklist, err := LoadBlocklistFile(path)
+	require.NoError(t, err)
+	defer blocklist.Close()
+	assert.Equal(t, 3, blocklist.Len())
+
+	for ip, blocked := range map[string]bool{
+		"203.0.113.7":        true,
+		"::ffff:203.0.113.7": true,
+		"198.51.100.200":     true,
+		"2001:db8::1":        true,
+		"203.0.113.8":        false,
+		"2001:db8::2":        false,
+		"not-an-ip":          false,
+		"":                   false,
+	} {
+		assert.Equal(t, blocked, blocklist.Contains(ip), ip)
+	}
+
+	require.NoError(t, os.WriteFile(path, []byte("203.0.113.999\n"), 0o600))
+	_, err = blocklist.Reload()
+	assert.Error(t, err)
+	assert.True(t, blocklist.Contains("203.0.113.7"), "an invalid file keeps the previous list")
+}
+
+func TestLoadPolicyFile_Example(t *testing.T) {
+	file, err := LoadPolicyFile("../../../../risk_rules.example.json")
+	require.NoError(t, err)
+	defer file.Close()
+	assert.Len(t, file.Policy().Rules, 6)
+}
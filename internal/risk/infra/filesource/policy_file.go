@@ -0,0 +1,91 @@
+package filesource
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"hub-user-service/internal/risk/domain/model"
+)
+
+// policyDocument is the JSON layout of a risk rules file
+type policyDocument struct {
+	MFAScore      int          `json:"mfa_score"`
+	DenyScore     int          `json:"deny_score"`
+	MFAFallback   string       `json:"mfa_fallback"`
+	FailureWindow string       `json:"failure_window"`
+	TimeZone      string       `json:"time_zone"`
+	Rules         []model.Rule `json:"rules"`
+}
+
+// ParsePolicy reads a risk policy from JSON; see risk_rules.example.json
+// mfa_fallback defaults to deny, failure_window to 15m and time_zone to UTC
+func ParsePolicy(data []byte) (*model.Policy, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var document policyDocument
+	if err := decoder.Decode(&document); err != nil {
+		return nil, err
+	}
+
+	policy := &model.Policy{
+		MFAScore:      document.MFAScore,
+		DenyScore:     document.DenyScore,
+		MFAFallback:   model.DecisionDeny,
+		FailureWindow: model.DefaultFailureWindow,
+		Location:      time.UTC,
+		Rules:         document.Rules,
+	}
+	if document.MFAFallback != "" {
+		policy.MFAFallback = model.Decision(document.MFAFallback)
+	}
+	if document.FailureWindow != "" {
+		window, err := time.ParseDuration(document.FailureWindow)
+		if err != nil {
+			return nil, fmt.Errorf("invalid failure_window: %w", err)
+		}
+		policy.FailureWindow = window
+	}
+	if document.TimeZone != "" {
+		location, err := time.LoadLocation(document.TimeZone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time_zone: %w", err)
+		}
+		policy.Location = location
+	}
+
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// PolicyFile is a risk policy loaded from a JSON file, reloaded by Watch when the file changes
+type PolicyFile struct {
+	*watchedFile
+	policy atomic.Pointer[model.Policy]
+}
+
+// LoadPolicyFile reads the risk policy at path
+func LoadPolicyFile(path string) (*PolicyFile, error) {
+	f := &PolicyFile{}
+	f.watchedFile = newWatchedFile(path, "risk rules", func(data []byte) error {
+		policy, err := ParsePolicy(data)
+		if err != nil {
+			return err
+		}
+		f.policy.Store(policy)
+		return nil
+	})
+	if _, err := f.Reload(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Policy returns the policy last loaded successfully
+func (f *PolicyFile) Policy() *model.Policy {
+	return f.policy.Load()
+}
@@ -0,0 +1,83 @@
+package filesource
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// watchedFile is a file parsed again whenever its modification time or size changes
+type watchedFile struct {
+	path  string
+	name  string
+	parse func(data []byte) error
+
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+func newWatchedFile(path string, name string, parse func(data []byte) error) *watchedFile {
+	return &watchedFile{path: path, name: name, parse: parse, stop: make(chan struct{})}
+}
+
+// Reload parses the file if it changed since the last load and reports whether it did
+// On error the previously loaded content stays in use
+func (w *watchedFile) Reload() (bool, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	info, err := os.Stat(w.path)
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s: %w", w.name, err)
+	}
+	if info.ModTime().Equal(w.modTime) && info.Size() == w.size {
+		return false, nil
+	}
+
+	data, err := os.ReadFile(w.path)
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s: %w", w.name, err)
+	}
+	if err := w.parse(data); err != nil {
+		return false, fmt.Errorf("failed to parse %s %s: %w", w.name, w.path, err)
+	}
+	w.modTime, w.size = info.ModTime(), info.Size()
+	return true, nil
+}
+
+// Watch checks the file for changes every interval until Close
+func (w *watchedFile) Watch(interval time.Duration) {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				reloaded, err := w.Reload()
+				if err != nil {
+					log.Printf("⚠️  %v, keeping the previous version", err)
+				} else if reloaded {
+					log.Printf("🔄 Reloaded %s from %s", w.name, w.path)
+				}
+			case <-w.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Close stops watching the file
+func (w *watchedFile) Close() {
+	w.stopOnce.Do(func() { close(w.stop) })
+	w.wg.Wait()
+}
@@ -0,0 +1,178 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"hub-user-service/internal/session/domain/model"
+	"hub-user-service/internal/session/domain/repository"
+)
+
+var (
+	// ErrSessionRevoked is returned for tokens of a revoked, unknown or foreign session
+	ErrSessionRevoked = errors.New("session has been revoked")
+	// ErrSessionExpired is returned for tokens of a session past its idle or absolute timeout
+	ErrSessionExpired = errors.New("session has expired")
+	// ErrSessionNotFound is returned when revoking a session the user does not have
+	ErrSessionNotFound = errors.New("session not found")
+)
+
+type ISessionUsecase interface {
+	// Start records a new login session
+	Start(ctx context.Context, userID string, client model.ClientInfo) (*model.Session, error)
+	// Check verifies the session of a token is still active and records it as seen
+	Check(ctx context.Context, userID string, sessionID string) error
+	// Refresh verifies a session can be extended with a new token, given when the presented
+	// token expires, and records it as seen
+	Refresh(ctx context.Context, userID string, sessionID string, tokenExpiresAt time.Time) error
+	// List returns the user's active sessions, most recently seen first
+	List(ctx context.Context, userID string) ([]*model.Session, error)
+	// Revoke ends one of the user's sessions
+	Revoke(ctx context.Context, userID string, sessionID string) error
+	// RevokeOthers ends every session of the user except currentID and returns the revoked IDs
+	RevokeOthers(ctx context.Context, userID string, currentID string) ([]string, error)
+}
+
+// SessionConfig configures SessionUsecase
+type SessionConfig struct {
+	// LastSeenInterval bounds how often a session's last seen time is written
+	LastSeenInterval time.Duration
+	// Timeouts are the idle and absolute timeouts per client type; client types without an
+	// entry have none
+	Timeouts map[model.ClientType]model.Timeouts
+}
+
+type SessionUsecase struct {
+	repo   repository.ISessionRepository
+	config SessionConfig
+	now    func() time.Time
+}
+
+// NewSessionUsecase creates the session management use case
+func NewSessionUsecase(repo repository.ISessionRepository, config SessionConfig) ISessionUsecase {
+	return &SessionUsecase{repo: repo, config: config, now: time.Now}
+}
+
+func (u *SessionUsecase) Start(ctx context.Context, userID string, client model.ClientInfo) (*model.Session, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("failed to generate session ID: %w", err)
+	}
+
+	device := client.Device
+	if device == "" {
+		device = model.DescribeDevice(client.UserAgent)
+	}
+	now := u.now()
+	clientType := client.Type
+	if clientType == "" {
+		clientType = model.ClientWeb
+	}
+	session := &model.Session{
+		ID:         base64.RawURLEncoding.EncodeToString(id),
+		UserID:     userID,
+		ClientType: clientType,
+		Device:     model.Truncate(device, model.MaxDeviceLength),
+		IPAddress:  model.Truncate(client.IPAddress, model.MaxIPAddressLength),
+		UserAgent:  model.Truncate(client.UserAgent, model.MaxUserAgentLength),
+		CreatedAt:  now,
+		LastSeenAt: now,
+	}
+	if err := u.repo.CreateSession(ctx, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+func (u *SessionUsecase) Check(ctx context.Context, userID string, sessionID string) error {
+	now := u.now()
+	session, err := u.activeSession(ctx, userID, sessionID, now)
+	if err != nil {
+		return err
+	}
+
+	if now.Sub(session.LastSeenAt) >= u.config.LastSeenInterval {
+		u.touch(ctx, sessionID, now)
+	}
+	return nil
+}
+
+func (u *SessionUsecase) Refresh(ctx context.Context, userID string, sessionID string, tokenExpiresAt time.Time) error {
+	now := u.now()
+	session, err := u.activeSession(ctx, userID, sessionID, now)
+	if err != nil {
+		return err
+	}
+
+	// A token left unused for longer than the idle timeout can not revive its session, even
+	// when other tokens of the session kept it active
+	idle := u.config.Timeouts[session.ClientType].Idle
+	if idle > 0 && now.Sub(tokenExpiresAt) >= idle {
+		return ErrSessionExpired
+	}
+
+	u.touch(ctx, sessionID, now)
+	return nil
+}
+
+// activeSession returns the session of a token, failing when it was revoked or timed out
+func (u *SessionUsecase) activeSession(ctx context.Context, userID string, sessionID string, now time.Time) (*model.Session, error) {
+	session, err := u.repo.GetSession(ctx, sessionID)
+	if errors.Is(err, repository.ErrSessionNotFound) {
+		return nil, ErrSessionRevoked
+	}
+	if err != nil {
+		return nil, err
+	}
+	if session.UserID != userID || session.IsRevoked() {
+		return nil, ErrSessionRevoked
+	}
+	if session.IsExpired(u.config.Timeouts[session.ClientType], now) {
+		return nil, ErrSessionExpired
+	}
+	return session, nil
+}
+
+// touch records activity on a session; last seen is informational, a failed write must not
+// reject the token
+func (u *SessionUsecase) touch(ctx context.Context, sessionID string, now time.Time) {
+	if err := u.repo.TouchSession(ctx, sessionID, now); err != nil {
+		log.Printf("Failed to record activity of session %s: %v", sessionID, err)
+	}
+}
+
+func (u *SessionUsecase) List(ctx context.Context, userID string) ([]*model.Session, error) {
+	sessions, err := u.repo.ListActiveSessions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := u.now()
+	active := sessions[:0]
+	for _, session := range sessions {
+		if !session.IsExpired(u.config.Timeouts[session.ClientType], now) {
+			active = append(active, session)
+		}
+	}
+	return active, nil
+}
+
+func (u *SessionUsecase) Revoke(ctx context.Context, userID string, sessionID string) error {
+	revoked, err := u.repo.RevokeSession(ctx, userID, sessionID, u.now())
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+func (u *SessionUsecase) RevokeOthers(ctx context.Context, userID string, currentID string) ([]string, error) {
+	return u.repo.RevokeOtherSessions(ctx, userID, currentID, u.now())
+}
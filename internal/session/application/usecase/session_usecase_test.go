@@ -0,0 +1,167 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"hub-user-service/internal/session/domain/model"
+	"hub-user-service/internal/session/infra/persistence"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testNow = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+const chromeOnMac = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/129.0.0.0 Safari/537.36"
+
+type sessionFixture struct {
+	usecase *SessionUsecase
+	repo    *persistence.MemorySessionRepository
+}
+
+func newFixture() *sessionFixture {
+	repo := persistence.NewMemorySessionRepository()
+	uc := NewSessionUsecase(repo, SessionConfig{LastSeenInterval: time.Minute}).(*SessionUsecase)
+	uc.now = func() time.Time { return testNow }
+	return &sessionFixture{usecase: uc, repo: repo}
+}
+
+// at moves the fixture clock
+func (f *sessionFixture) at(now time.Time) {
+	f.usecase.now = func() time.Time { return now }
+}
+
+func TestSessionUsecase_Start(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+
+	session, err := f.usecase.Start(ctx, "42", model.ClientInfo{IPAddress: "203.0.113.7", UserAgent: chromeOnMac})
+	require.NoError(t, err)
+	assert.Len(t, session.ID, 22)
+	assert.Equal(t, "Chrome on macOS", session.Device)
+	assert.Equal(t, testNow, session.CreatedAt)
+
+	named, err := f.usecase.Start(ctx, "42", model.ClientInfo{Device: "Ada's iPhone", UserAgent: chromeOnMac})
+	require.NoError(t, err)
+	assert.Equal(t, "Ada's iPhone", named.Device, "a device name from the client wins")
+	assert.NotEqual(t, session.ID, named.ID)
+
+	long, err := f.usecase.Start(ctx, "42", model.ClientInfo{UserAgent: strings.Repeat("é", 300)})
+	require.NoError(t, err)
+	assert.Len(t, long.UserAgent, model.MaxUserAgentLength)
+}
+
+func TestSessionUsecase_CheckAndRevoke(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	laptop, err := f.usecase.Start(ctx, "42", model.ClientInfo{})
+	require.NoError(t, err)
+
+	require.NoError(t, f.usecase.Check(ctx, "42", laptop.ID))
+	assert.ErrorIs(t, f.usecase.Check(ctx, "7", laptop.ID), ErrSessionRevoked, "tokens of another user's session")
+	assert.ErrorIs(t, f.usecase.Check(ctx, "42", "unknown"), ErrSessionRevoked)
+
+	assert.ErrorIs(t, f.usecase.Revoke(ctx, "7", laptop.ID), ErrSessionNotFound)
+	require.NoError(t, f.usecase.Revoke(ctx, "42", laptop.ID))
+	assert.ErrorIs(t, f.usecase.Check(ctx, "42", laptop.ID), ErrSessionRevoked)
+	assert.ErrorIs(t, f.usecase.Revoke(ctx, "42", laptop.ID), ErrSessionNotFound)
+}
+
+func TestSessionUsecase_CheckRecordsLastSeen(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	session, err := f.usecase.Start(ctx, "42", model.ClientInfo{})
+	require.NoError(t, err)
+
+	f.at(testNow.Add(30 * time.Second))
+	require.NoError(t, f.usecase.Check(ctx, "42", session.ID))
+	stored, _ := f.repo.GetSession(ctx, session.ID)
+	assert.Equal(t, testNow, stored.LastSeenAt, "not written more than once per interval")
+
+	f.at(testNow.Add(2 * time.Minute))
+	require.NoError(t, f.usecase.Check(ctx, "42", session.ID))
+	stored, _ = f.repo.GetSession(ctx, session.ID)
+	assert.Equal(t, testNow.Add(2*time.Minute), stored.LastSeenAt)
+}
+
+func TestSessionUsecase_RevokeOthers(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	current, _ := f.usecase.Start(ctx, "42", model.ClientInfo{})
+	phone, _ := f.usecase.Start(ctx, "42", model.ClientInfo{})
+	tablet, _ := f.usecase.Start(ctx, "42", model.ClientInfo{})
+
+	revoked, err := f.usecase.RevokeOthers(ctx, "42", current.ID)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{phone.ID, tablet.ID}, revoked)
+
+	sessions, err := f.usecase.List(ctx, "42")
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, current.ID, sessions[0].ID)
+	assert.NoError(t, f.usecase.Check(ctx, "42", current.ID))
+	assert.ErrorIs(t, f.usecase.Check(ctx, "42", phone.ID), ErrSessionRevoked)
+}
+
+func TestSessionUsecase_Timeouts(t *testing.T) {
+	f := newFixture()
+	f.usecase.config.Timeouts = map[model.ClientType]model.Timeouts{
+		model.ClientWeb:    {Idle: 15 * time.Minute, Absolute: 12 * time.Hour},
+		model.ClientMobile: {Idle: 7 * 24 * time.Hour, Absolute: 30 * 24 * time.Hour},
+	}
+	ctx := context.Background()
+	web, err := f.usecase.Start(ctx, "42", model.ClientInfo{})
+	require.NoError(t, err)
+	assert.Equal(t, model.ClientWeb, web.ClientType)
+	phone, err := f.usecase.Start(ctx, "42", model.ClientInfo{Type: model.ClientMobile})
+	require.NoError(t, err)
+
+	// Activity keeps the web session alive
+	f.at(testNow.Add(10 * time.Minute))
+	require.NoError(t, f.usecase.Check(ctx, "42", web.ID))
+	f.at(testNow.Add(24 * time.Minute))
+	require.NoError(t, f.usecase.Check(ctx, "42", web.ID))
+
+	f.at(testNow.Add(40 * time.Minute))
+	assert.ErrorIs(t, f.usecase.Check(ctx, "42", web.ID), ErrSessionExpired)
+	assert.NoError(t, f.usecase.Check(ctx, "42", phone.ID))
+
+	sessions, err := f.usecase.List(ctx, "42")
+	require.NoError(t, err)
+	require.Len(t, sessions, 1, "expired sessions are not listed")
+	assert.Equal(t, phone.ID, sessions[0].ID)
+
+	f.at(testNow.Add(30 * 24 * time.Hour))
+	assert.ErrorIs(t, f.usecase.Check(ctx, "42", phone.ID), ErrSessionExpired)
+}
+
+func TestSessionUsecase_Refresh(t *testing.T) {
+	f := newFixture()
+	f.usecase.config.Timeouts = map[model.ClientType]model.Timeouts{
+		model.ClientWeb: {Idle: 15 * time.Minute, Absolute: 12 * time.Hour},
+	}
+	ctx := context.Background()
+	session, err := f.usecase.Start(ctx, "42", model.ClientInfo{})
+	require.NoError(t, err)
+	tokenExpiresAt := testNow.Add(10 * time.Minute)
+
+	// Refreshing an expired token within the idle timeout extends the session
+	f.at(testNow.Add(12 * time.Minute))
+	require.NoError(t, f.usecase.Refresh(ctx, "42", session.ID, tokenExpiresAt))
+	stored, _ := f.repo.GetSession(ctx, session.ID)
+	assert.Equal(t, testNow.Add(12*time.Minute), stored.LastSeenAt)
+
+	// The old token can not be refreshed once it has been unused for the idle timeout
+	f.at(tokenExpiresAt.Add(15 * time.Minute))
+	assert.ErrorIs(t, f.usecase.Refresh(ctx, "42", session.ID, tokenExpiresAt), ErrSessionExpired)
+	require.NoError(t, f.usecase.Refresh(ctx, "42", session.ID, testNow.Add(22*time.Minute)))
+
+	assert.ErrorIs(t, f.usecase.Refresh(ctx, "7", session.ID, testNow.Add(22*time.Minute)), ErrSessionRevoked)
+
+	// Refreshing never extends the session past its absolute timeout
+	f.at(testNow.Add(12 * time.Hour))
+	assert.ErrorIs(t, f.usecase.Refresh(ctx, "42", session.ID, testNow.Add(12*time.Hour)), ErrSessionExpired)
+}
@@ -0,0 +1,72 @@
+package model
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Sizes of the device, IP address and user agent columns of user_sessions and login_history
+const (
+	MaxDeviceLength    = 100
+	MaxIPAddressLength = 45
+	MaxUserAgentLength = 512
+)
+
+// userAgentTokens are matched in order, so more specific names come first
+// (Edge and Opera user agents also mention Chrome and Safari, Chrome mentions Safari)
+var (
+	browsers = []struct{ token, name string }{
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"Firefox/", "Firefox"},
+		{"CriOS/", "Chrome"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+	}
+	platforms = []struct{ token, name string }{
+		{"iPhone", "iOS"},
+		{"iPad", "iPadOS"},
+		{"Android", "Android"},
+		{"Windows", "Windows"},
+		{"Mac OS X", "macOS"},
+		{"CrOS", "ChromeOS"},
+		{"Linux", "Linux"},
+	}
+)
+
+// DescribeDevice derives a device label such as "Chrome on macOS" from a browser user agent;
+// it returns "Unknown device" for user agents it does not recognize
+func DescribeDevice(userAgent string) string {
+	browser := match(userAgent, browsers)
+	platform := match(userAgent, platforms)
+	switch {
+	case browser != "" && platform != "":
+		return browser + " on " + platform
+	case browser != "":
+		return browser
+	case platform != "":
+		return platform
+	default:
+		return "Unknown device"
+	}
+}
+
+func match(userAgent string, tokens []struct{ token, name string }) string {
+	for _, t := range tokens {
+		if strings.Contains(userAgent, t.token) {
+			return t.name
+		}
+	}
+	return ""
+}
+
+// Truncate shortens s to at most max bytes without splitting a UTF-8 sequence
+func Truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	for max > 0 && !utf8.RuneStart(s[max]) {
+		max--
+	}
+	return s[:max]
+}
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDescribeDevice(t *testing.T) {
+	tests := map[string]string{
+		"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/129.0.0.0 Safari/537.36":                   "Chrome on macOS",
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/129.0.0.0 Safari/537.36 Edg/129.0.2792.79":       "Edge on Windows",
+		"Mozilla/5.0 (iPhone; CPU iPhone OS 18_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/18.0 Mobile/15E148 Safari/604.1": "Safari on iOS",
+		"Mozilla/5.0 (X11; Linux x86_64; rv:131.0) Gecko/20100101 Firefox/131.0":                                                                  "Firefox on Linux",
+		"Mozilla/5.0 (Linux; Android 14) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/129.0.6668.81 Mobile Safari/537.36":                        "Chrome on Android",
+		"HubInvestments/3.2 (Android)": "Android",
+		"grpc-go/1.75.0":               "Unknown device",
+		"":                             "Unknown device",
+	}
+	for userAgent, device := range tests {
+		assert.Equal(t, device, DescribeDevice(userAgent), userAgent)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "Chrome", Truncate("Chrome", 10))
+	assert.Equal(t, "Chr", Truncate("Chrome", 3))
+	assert.Equal(t, "Caf", Truncate("Café", 4), "a multi-byte rune is not split")
+	assert.Equal(t, "", Truncate("é", 1))
+}
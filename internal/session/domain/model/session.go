@@ -0,0 +1,67 @@
+package model
+
+import "time"
+
+// ClientType is the kind of client a session was started from; each has its own timeouts
+type ClientType string
+
+const (
+	ClientWeb    ClientType = "web"
+	ClientMobile ClientType = "mobile"
+	ClientAPI    ClientType = "api"
+)
+
+// ParseClientType returns the client type named by s, or ClientWeb when s is empty or unknown
+func ParseClientType(s string) ClientType {
+	switch ClientType(s) {
+	case ClientMobile, ClientAPI:
+		return ClientType(s)
+	default:
+		return ClientWeb
+	}
+}
+
+// Timeouts bound the lifetime of a session; a zero duration disables the bound
+type Timeouts struct {
+	// Idle ends a session not used for this long
+	Idle time.Duration
+	// Absolute ends a session this long after the login, however active it is
+	Absolute time.Duration
+}
+
+// Session is a login of a user on a device; every token issued for the login carries its ID (sid claim)
+type Session struct {
+	ID         string
+	UserID     string
+	ClientType ClientType
+	// Device is a label for the device, e.g. "Chrome on macOS"
+	Device    string
+	IPAddress string
+	UserAgent string
+	CreatedAt time.Time
+	// LastSeenAt is updated when a token of the session is validated, at most once per configured interval
+	LastSeenAt time.Time
+	RevokedAt  *time.Time
+}
+
+// IsRevoked reports whether the session was ended
+func (s *Session) IsRevoked() bool {
+	return s.RevokedAt != nil
+}
+
+// IsExpired reports whether the session ran past one of its timeouts at now
+func (s *Session) IsExpired(timeouts Timeouts, now time.Time) bool {
+	if timeouts.Idle > 0 && now.Sub(s.LastSeenAt) >= timeouts.Idle {
+		return true
+	}
+	return timeouts.Absolute > 0 && now.Sub(s.CreatedAt) >= timeouts.Absolute
+}
+
+// ClientInfo describes the client a login came from
+type ClientInfo struct {
+	Type ClientType
+	// Device is a label chosen by the client; when empty it is derived from the user agent
+	Device    string
+	IPAddress string
+	UserAgent string
+}
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseClientType(t *testing.T) {
+	assert.Equal(t, ClientMobile, ParseClientType("mobile"))
+	assert.Equal(t, ClientAPI, ParseClientType("api"))
+	assert.Equal(t, ClientWeb, ParseClientType("web"))
+	assert.Equal(t, ClientWeb, ParseClientType(""))
+	assert.Equal(t, ClientWeb, ParseClientType("Mobile"), "unknown types get the web timeouts")
+}
+
+func TestSession_IsExpired(t *testing.T) {
+	login := time.Date(2026, 10, 18, 8, 0, 0, 0, time.UTC)
+	session := &Session{CreatedAt: login, LastSeenAt: login.Add(11 * time.Hour)}
+	timeouts := Timeouts{Idle: 15 * time.Minute, Absolute: 12 * time.Hour}
+
+	assert.False(t, session.IsExpired(timeouts, login.Add(11*time.Hour+14*time.Minute)))
+	assert.True(t, session.IsExpired(timeouts, login.Add(11*time.Hour+15*time.Minute)), "idle")
+
+	session.LastSeenAt = login.Add(12*time.Hour - time.Minute)
+	assert.True(t, session.IsExpired(timeouts, login.Add(12*time.Hour)), "absolute, however active")
+
+	assert.False(t, session.IsExpired(Timeouts{}, login.Add(1000*time.Hour)), "zero timeouts never expire")
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"hub-user-service/internal/session/domain/model"
+)
+
+// ErrSessionNotFound is returned for unknown session IDs
+var ErrSessionNotFound = errors.New("session not found")
+
+type ISessionRepository interface {
+	CreateSession(ctx context.Context, session *model.Session) error
+	GetSession(ctx context.Context, id string) (*model.Session, error)
+	// ListActiveSessions returns the user's sessions that are not revoked, most recently seen first
+	ListActiveSessions(ctx context.Context, userID string) ([]*model.Session, error)
+	// TouchSession records that the session was seen at the given time
+	TouchSession(ctx context.Context, id string, at time.Time) error
+	// RevokeSession revokes one of the user's active sessions; it returns false if the user has
+	// no active session with this ID
+	RevokeSession(ctx context.Context, userID string, id string, at time.Time) (bool, error)
+	// RevokeOtherSessions revokes every active session of the user except keepID and returns
+	// the revoked IDs
+	RevokeOtherSessions(ctx context.Context, userID string, keepID string, at time.Time) ([]string, error)
+}
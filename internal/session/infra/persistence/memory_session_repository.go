@@ -0,0 +1,112 @@
+package persistence
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"hub-user-service/internal/session/domain/model"
+	"hub-user-service/internal/session/domain/repository"
+)
+
+// MemorySessionRepository keeps sessions in process memory (DB_DRIVER=memory)
+type MemorySessionRepository struct {
+	mu       sync.Mutex
+	sessions map[string]model.Session
+}
+
+// NewMemorySessionRepository creates an empty in-memory session repository
+func NewMemorySessionRepository() *MemorySessionRepository {
+	return &MemorySessionRepository{sessions: make(map[string]model.Session)}
+}
+
+func (r *MemorySessionRepository) CreateSession(ctx context.Context, session *model.Session) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.sessions[session.ID] = copySession(session)
+	return nil
+}
+
+func (r *MemorySessionRepository) GetSession(ctx context.Context, id string) (*model.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil, repository.ErrSessionNotFound
+	}
+	found := copySession(&session)
+	return &found, nil
+}
+
+func (r *MemorySessionRepository) ListActiveSessions(ctx context.Context, userID string) ([]*model.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var sessions []*model.Session
+	for _, session := range r.sessions {
+		if session.UserID == userID && !session.IsRevoked() {
+			found := copySession(&session)
+			sessions = append(sessions, &found)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		if !sessions[i].LastSeenAt.Equal(sessions[j].LastSeenAt) {
+			return sessions[i].LastSeenAt.After(sessions[j].LastSeenAt)
+		}
+		return sessions[i].ID < sessions[j].ID
+	})
+	return sessions, nil
+}
+
+func (r *MemorySessionRepository) TouchSession(ctx context.Context, id string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if session, ok := r.sessions[id]; ok && session.LastSeenAt.Before(at) {
+		session.LastSeenAt = at
+		r.sessions[id] = session
+	}
+	return nil
+}
+
+func (r *MemorySessionRepository) RevokeSession(ctx context.Context, userID string, id string, at time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, ok := r.sessions[id]
+	if !ok || session.UserID != userID || session.IsRevoked() {
+		return false, nil
+	}
+	session.RevokedAt = &at
+	r.sessions[id] = session
+	return true, nil
+}
+
+func (r *MemorySessionRepository) RevokeOtherSessions(ctx context.Context, userID string, keepID string, at time.Time) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var ids []string
+	for id, session := range r.sessions {
+		if session.UserID == userID && id != keepID && !session.IsRevoked() {
+			session.RevokedAt = &at
+			r.sessions[id] = session
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// copySession copies session so callers can not modify the stored one
+func copySession(session *model.Session) model.Session {
+	found := *session
+	if session.RevokedAt != nil {
+		revokedAt := *session.RevokedAt
+		found.RevokedAt = &revokedAt
+	}
+	return found
+}
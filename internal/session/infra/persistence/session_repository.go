@@ -0,0 +1,131 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"hub-user-service/internal/database"
+	"hub-user-service/internal/session/domain/model"
+	"hub-user-service/internal/session/domain/repository"
+)
+
+type SessionRepository struct {
+	db database.Querier
+}
+
+// sessionDTO represents the user_sessions table
+type sessionDTO struct {
+	ID         string       `db:"id"`
+	UserID     string       `db:"user_id"`
+	ClientType string       `db:"client_type"`
+	Device     string       `db:"device"`
+	IPAddress  string       `db:"ip_address"`
+	UserAgent  string       `db:"user_agent"`
+	CreatedAt  time.Time    `db:"created_at"`
+	LastSeenAt time.Time    `db:"last_seen_at"`
+	RevokedAt  sql.NullTime `db:"revoked_at"`
+}
+
+// NewSessionRepository creates a session repository on a database or a transaction
+func NewSessionRepository(db database.Querier) repository.ISessionRepository {
+	return &SessionRepository{db: db}
+}
+
+const sessionColumns = "id, user_id, client_type, device, ip_address, user_agent, created_at, last_seen_at, revoked_at"
+
+func (r *SessionRepository) CreateSession(ctx context.Context, session *model.Session) error {
+	query := `INSERT INTO user_sessions (id, user_id, client_type, device, ip_address, user_agent, created_at, last_seen_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	_, err := r.db.ExecContext(ctx, query, session.ID, session.UserID, string(session.ClientType), session.Device, session.IPAddress,
+		session.UserAgent, session.CreatedAt, session.LastSeenAt)
+	if err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+	return nil
+}
+
+func (r *SessionRepository) GetSession(ctx context.Context, id string) (*model.Session, error) {
+	query := "SELECT " + sessionColumns + " FROM user_sessions WHERE id = $1"
+
+	// Revocation is checked here, so a lagging replica must not accept a revoked session
+	var dto sessionDTO
+	if err := r.db.GetContext(database.WithPrimary(ctx), &dto, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	return dto.toModel(), nil
+}
+
+func (r *SessionRepository) ListActiveSessions(ctx context.Context, userID string) ([]*model.Session, error) {
+	query := "SELECT " + sessionColumns + " FROM user_sessions WHERE user_id = $1 AND revoked_at IS NULL ORDER BY last_seen_at DESC, id"
+
+	var dtos []sessionDTO
+	if err := r.db.SelectContext(ctx, &dtos, query, userID); err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	sessions := make([]*model.Session, len(dtos))
+	for i, dto := range dtos {
+		sessions[i] = dto.toModel()
+	}
+	return sessions, nil
+}
+
+func (r *SessionRepository) TouchSession(ctx context.Context, id string, at time.Time) error {
+	query := "UPDATE user_sessions SET last_seen_at = $2 WHERE id = $1 AND last_seen_at < $2"
+
+	if _, err := r.db.ExecContext(ctx, query, id, at); err != nil {
+		return fmt.Errorf("failed to touch session: %w", err)
+	}
+	return nil
+}
+
+func (r *SessionRepository) RevokeSession(ctx context.Context, userID string, id string, at time.Time) (bool, error) {
+	query := "UPDATE user_sessions SET revoked_at = $3 WHERE user_id = $1 AND id = $2 AND revoked_at IS NULL"
+
+	result, err := r.db.ExecContext(ctx, query, userID, id, at)
+	if err != nil {
+		return false, fmt.Errorf("failed to revoke session: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to read affected rows: %w", err)
+	}
+	return affected == 1, nil
+}
+
+func (r *SessionRepository) RevokeOtherSessions(ctx context.Context, userID string, keepID string, at time.Time) ([]string, error) {
+	query := "UPDATE user_sessions SET revoked_at = $3 WHERE user_id = $1 AND id <> $2 AND revoked_at IS NULL RETURNING id"
+
+	// A write returning rows must not be routed to a read-only replica or retried as a read
+	var ids []string
+	if err := r.db.SelectContext(database.WithWrite(ctx), &ids, query, userID, keepID, at); err != nil {
+		return nil, fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	return ids, nil
+}
+
+// toModel converts the DTO to the domain model
+func (d sessionDTO) toModel() *model.Session {
+	session := &model.Session{
+		ID:         d.ID,
+		UserID:     d.UserID,
+		ClientType: model.ClientType(d.ClientType),
+		Device:     d.Device,
+		IPAddress:  d.IPAddress,
+		UserAgent:  d.UserAgent,
+		CreatedAt:  d.CreatedAt,
+		LastSeenAt: d.LastSeenAt,
+	}
+	if d.RevokedAt.Valid {
+		revokedAt := d.RevokedAt.Time
+		session.RevokedAt = &revokedAt
+	}
+	return session
+}
@@ -0,0 +1,197 @@
+package persistence
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"hub-user-service/internal/database"
+	"hub-user-service/internal/database/databasetest"
+	"hub-user-service/internal/session/domain/model"
+	"hub-user-service/internal/session/domain/repository"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sessionSchema is the SQLite version of the user_sessions table
+const sessionSchema = `
+INSERT INTO users (id, email, name, password) VALUES (42, 'dev@example.com', 'Dev', 'DevPass123!'), (7, 'ops@example.com', 'Ops', 'OpsPass123!');
+CREATE TABLE user_sessions (
+    id TEXT PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    device TEXT NOT NULL DEFAULT '',
+    ip_address TEXT NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_seen_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at TIMESTAMP,
+    client_type TEXT NOT NULL DEFAULT 'web'
+);`
+
+var testNow = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+// newSessionDatabase opens a database with sessions s1, s2 and s3 of user 42, last seen a minute
+// apart, and session other of user 7
+func newSessionDatabase(t *testing.T) database.Database {
+	db := databasetest.OpenSQLite(t, sessionSchema)
+	repo := NewSessionRepository(db)
+	for i, id := range []string{"s1", "s2", "s3"} {
+		at := testNow.Add(time.Duration(i) * time.Minute)
+		require.NoError(t, repo.CreateSession(context.Background(), &model.Session{ID: id, UserID: "42", ClientType: model.ClientWeb, CreatedAt: at, LastSeenAt: at}))
+	}
+	require.NoError(t, repo.CreateSession(context.Background(), &model.Session{ID: "other", UserID: "7", ClientType: model.ClientWeb, CreatedAt: testNow, LastSeenAt: testNow}))
+	return db
+}
+
+func TestSessionRepository_CreateAndGet(t *testing.T) {
+	repo := NewSessionRepository(databasetest.OpenSQLite(t, sessionSchema))
+	ctx := context.Background()
+
+	_, err := repo.GetSession(ctx, "s1")
+	assert.ErrorIs(t, err, repository.ErrSessionNotFound)
+
+	require.NoError(t, repo.CreateSession(ctx, &model.Session{
+		ID: "s1", UserID: "42", ClientType: model.ClientMobile, Device: "Chrome on macOS", IPAddress: "203.0.113.7", CreatedAt: testNow, LastSeenAt: testNow,
+	}))
+	session, err := repo.GetSession(ctx, "s1")
+	require.NoError(t, err)
+	assert.Equal(t, "42", session.UserID)
+	assert.Equal(t, model.ClientMobile, session.ClientType)
+	assert.Equal(t, "Chrome on macOS", session.Device)
+	assert.Equal(t, "203.0.113.7", session.IPAddress)
+	assert.True(t, testNow.Equal(session.LastSeenAt))
+	assert.False(t, session.IsRevoked())
+}
+
+func TestSessionRepository_TouchNeverMovesBack(t *testing.T) {
+	repo := NewSessionRepository(newSessionDatabase(t))
+	ctx := context.Background()
+
+	require.NoError(t, repo.TouchSession(ctx, "s1", testNow.Add(time.Hour)))
+	require.NoError(t, repo.TouchSession(ctx, "s1", testNow.Add(30*time.Minute)), "a late request")
+
+	sessions, err := repo.ListActiveSessions(ctx, "42")
+	require.NoError(t, err)
+	require.Len(t, sessions, 3)
+	assert.Equal(t, []string{"s1", "s3", "s2"}, []string{sessions[0].ID, sessions[1].ID, sessions[2].ID}, "most recently seen first")
+	assert.True(t, testNow.Add(time.Hour).Equal(sessions[0].LastSeenAt))
+}
+
+func TestSessionRepository_RevokeIsConditional(t *testing.T) {
+	repo := NewSessionRepository(newSessionDatabase(t))
+	ctx := context.Background()
+
+	revoked, err := repo.RevokeSession(ctx, "7", "s2", testNow)
+	require.NoError(t, err)
+	assert.False(t, revoked, "sessions belong to their user")
+
+	// Concurrent revocations of one session: exactly one revokes it
+	var accepted atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if revoked, err := repo.RevokeSession(ctx, "42", "s2", testNow); err == nil && revoked {
+				accepted.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), accepted.Load())
+
+	session, err := repo.GetSession(ctx, "s2")
+	require.NoError(t, err)
+	require.NotNil(t, session.RevokedAt)
+	assert.True(t, testNow.Equal(*session.RevokedAt))
+}
+
+func TestSessionRepository_RevokeOthersReturnsIDs(t *testing.T) {
+	repo := NewSessionRepository(newSessionDatabase(t))
+	ctx := context.Background()
+	_, err := repo.RevokeSession(ctx, "42", "s2", testNow)
+	require.NoError(t, err)
+
+	ids, err := repo.RevokeOtherSessions(ctx, "42", "s1", testNow)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"s3"}, ids, "already revoked sessions are not returned")
+
+	sessions, err := repo.ListActiveSessions(ctx, "42")
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, "s1", sessions[0].ID)
+	other, err := repo.ListActiveSessions(ctx, "7")
+	require.NoError(t, err)
+	assert.Len(t, other, 1, "other users' sessions are kept")
+}
+
+// replicaDatabase is a hot standby replica rejecting every statement it should not see
+type replicaDatabase struct {
+	database.Database
+}
+
+func (replicaDatabase) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return &pq.Error{Code: "25006", Message: "cannot execute UPDATE in a read-only transaction"}
+}
+
+func (d replicaDatabase) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return d.GetContext(ctx, dest, query, args...)
+}
+
+func TestSessionRepository_RevocationRunsOnPrimary(t *testing.T) {
+	repo := NewSessionRepository(database.NewRoutingDatabase(newSessionDatabase(t), []database.Database{replicaDatabase{}}, 0))
+	ctx := database.WithReadYourWrites(context.Background())
+
+	session, err := repo.GetSession(ctx, "s1")
+	require.NoError(t, err)
+	assert.Equal(t, "s1", session.ID)
+
+	ids, err := repo.RevokeOtherSessions(ctx, "42", "s1", testNow)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"s2", "s3"}, ids)
+}
+
+func TestMemorySessionRepository_Lifecycle(t *testing.T) {
+	var repo repository.ISessionRepository = NewMemorySessionRepository()
+	ctx := context.Background()
+	for i, id := range []string{"s1", "s2", "s3"} {
+		at := testNow.Add(time.Duration(i) * time.Minute)
+		require.NoError(t, repo.CreateSession(ctx, &model.Session{ID: id, UserID: "42", CreatedAt: at, LastSeenAt: at}))
+	}
+	require.NoError(t, repo.CreateSession(ctx, &model.Session{ID: "other", UserID: "7", CreatedAt: testNow, LastSeenAt: testNow}))
+
+	require.NoError(t, repo.TouchSession(ctx, "s1", testNow.Add(time.Hour)))
+	sessions, err := repo.ListActiveSessions(ctx, "42")
+	require.NoError(t, err)
+	require.Len(t, sessions, 3)
+	assert.Equal(t, []string{"s1", "s3", "s2"}, []string{sessions[0].ID, sessions[1].ID, sessions[2].ID}, "most recently seen first")
+
+	revoked, _ := repo.RevokeSession(ctx, "7", "s2", testNow)
+	assert.False(t, revoked, "sessions belong to their user")
+	revoked, err = repo.RevokeSession(ctx, "42", "s2", testNow)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+	revoked, _ = repo.RevokeSession(ctx, "42", "s2", testNow)
+	assert.False(t, revoked, "a session is revoked once")
+
+	session, err := repo.GetSession(ctx, "s2")
+	require.NoError(t, err)
+	assert.True(t, session.IsRevoked())
+
+	ids, err := repo.RevokeOtherSessions(ctx, "42", "s1", testNow)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"s3"}, ids)
+	sessions, _ = repo.ListActiveSessions(ctx, "42")
+	require.Len(t, sessions, 1)
+	assert.Equal(t, "s1", sessions[0].ID)
+
+	other, _ := repo.ListActiveSessions(ctx, "7")
+	assert.Len(t, other, 1, "other users' sessions are kept")
+
+	_, err = repo.GetSession(ctx, "missing")
+	assert.ErrorIs(t, err, repository.ErrSessionNotFound)
+}
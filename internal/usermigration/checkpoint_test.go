@@ -0,0 +1,49 @@
+package usermigration
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: true}
+}
+
+func TestCheckpoint_SaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users.checkpoint")
+	checkpoint := &Checkpoint{LastID: 1000, Copy: CopyStats{Read: 1000, Inserted: 990, Failed: 10}}
+
+	require.NoError(t, checkpoint.Save(path))
+	loaded, err := LoadCheckpoint(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1000), loaded.LastID)
+	assert.Equal(t, checkpoint.Copy, loaded.Copy)
+	assert.False(t, loaded.UpdatedAt.IsZero())
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "temporary files are cleaned up")
+}
+
+func TestLoadCheckpoint_MissingFileIsEmpty(t *testing.T) {
+	checkpoint, err := LoadCheckpoint(filepath.Join(t.TempDir(), "missing"))
+
+	require.NoError(t, err)
+	assert.Equal(t, &Checkpoint{}, checkpoint)
+}
+
+func TestLoadCheckpoint_RejectsCorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users.checkpoint")
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o600))
+
+	_, err := LoadCheckpoint(path)
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,43 @@
+package usermigration
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"strings"
+	"time"
+
+	"hub-user-service/internal/login/domain/model"
+)
+
+// Checksum fingerprints the columns copied from the monolith. Status and login timestamps are
+// owned by this service and excluded, so they never cause a mismatch.
+// Timestamps are compared in UTC at PostgreSQL's microsecond precision.
+func Checksum(user *model.User) string {
+	fields := []string{
+		user.ID,
+		user.GetEmailString(),
+		user.Name,
+		user.GetPasswordString(),
+		user.CreatedAt.UTC().Truncate(time.Microsecond).Format(time.RFC3339Nano),
+	}
+	sum := sha256.Sum256([]byte(strings.Join(fields, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// digest combines row checksums, in ID order, into a checksum of the whole table
+type digest struct {
+	h hash.Hash
+}
+
+func newDigest() *digest {
+	return &digest{h: sha256.New()}
+}
+
+func (d *digest) add(checksum string) {
+	d.h.Write([]byte(checksum))
+}
+
+func (d *digest) String() string {
+	return hex.EncodeToString(d.h.Sum(nil))
+}
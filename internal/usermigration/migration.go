@@ -0,0 +1,318 @@
+package usermigration
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"hub-user-service/internal/database"
+	"hub-user-service/internal/events"
+	"hub-user-service/internal/login/domain/model"
+	"hub-user-service/internal/login/domain/repository"
+)
+
+// DefaultBatchSize is the number of users read from the source per query
+const DefaultBatchSize = 500
+
+// maxReportedIDs bounds the ids listed per category in a report
+const maxReportedIDs = 20
+
+// sourceQuery pages through the monolith users table by id (keyset pagination)
+const sourceQuery = "SELECT id, email, name, password, created_at FROM users WHERE id > $1 ORDER BY id LIMIT $2"
+
+// Options configures a UserMigration
+type Options struct {
+	// BatchSize is the number of users read and compared per round trip
+	BatchSize int
+	// DryRun compares source and target without writing users or the checkpoint
+	DryRun bool
+	// CheckpointPath, when set, stores progress after every batch so an interrupted run resumes
+	CheckpointPath string
+	// Events receives password_changed and email_changed for the users whose copy changed them;
+	// nil discards them
+	Events events.Publisher
+}
+
+// CopyStats counts what happened to the source users during the copy
+type CopyStats struct {
+	Read      int      `json:"read"`
+	Inserted  int      `json:"inserted"`
+	Updated   int      `json:"updated"`
+	Unchanged int      `json:"unchanged"`
+	Failed    int      `json:"failed"`
+	FailedIDs []string `json:"failed_ids,omitempty"`
+}
+
+// Reconciliation compares every source user with the target after the copy
+type Reconciliation struct {
+	SourceRows     int      `json:"source_rows"`
+	TargetRows     int      `json:"target_rows"`
+	Matched        int      `json:"matched"`
+	Missing        int      `json:"missing"`
+	Mismatched     int      `json:"mismatched"`
+	MissingIDs     []string `json:"missing_ids,omitempty"`
+	MismatchedIDs  []string `json:"mismatched_ids,omitempty"`
+	SourceChecksum string   `json:"source_checksum"`
+	TargetChecksum string   `json:"target_checksum"`
+}
+
+// Report is the outcome of a run
+type Report struct {
+	DryRun         bool           `json:"dry_run"`
+	ResumedFromID  int64          `json:"resumed_from_id"`
+	Copy           CopyStats      `json:"copy"`
+	Reconciliation Reconciliation `json:"reconciliation"`
+	Duration       time.Duration  `json:"duration"`
+}
+
+// Consistent reports whether every source user is present and identical in the target.
+// A dry run is consistent only if there was nothing left to copy.
+func (r *Report) Consistent() bool {
+	return r.Copy.Failed == 0 && r.Reconciliation.Missing == 0 && r.Reconciliation.Mismatched == 0
+}
+
+// sourceUser is a row of the monolith users table
+type sourceUser struct {
+	ID        int64          `db:"id"`
+	Email     string         `db:"email"`
+	Name      sql.NullString `db:"name"`
+	Password  string         `db:"password"`
+	CreatedAt sql.NullTime   `db:"created_at"`
+}
+
+func (s sourceUser) toModel() *model.User {
+	user := model.NewUserFromRepository(strconv.FormatInt(s.ID, 10), s.Email, s.Password)
+	user.Name = s.Name.String
+	if s.CreatedAt.Valid {
+		user.CreatedAt = s.CreatedAt.Time
+	}
+	return user
+}
+
+// UserMigration copies users from the monolith database into this service's database.
+// Runs are idempotent: users whose checksum already matches are skipped, the others are upserted
+// by id, so it can be repeated throughout the strangler-fig cutover.
+type UserMigration struct {
+	source database.Querier
+	target repository.IUserRepository
+	opts   Options
+}
+
+// NewUserMigration creates a migration reading from source and writing through target
+func NewUserMigration(source database.Querier, target repository.IUserRepository, opts Options) *UserMigration {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+	if opts.Events == nil {
+		opts.Events = events.NopPublisher{}
+	}
+	return &UserMigration{source: source, target: target, opts: opts}
+}
+
+// Run copies the users, then reconciles the whole table
+func (m *UserMigration) Run(ctx context.Context) (*Report, error) {
+	started := time.Now()
+
+	checkpoint, err := m.loadCheckpoint()
+	if err != nil {
+		return nil, err
+	}
+	report := &Report{DryRun: m.opts.DryRun, ResumedFromID: checkpoint.LastID, Copy: checkpoint.Copy}
+
+	if err := m.copyUsers(ctx, checkpoint, report); err != nil {
+		return report, err
+	}
+	if !m.opts.DryRun {
+		if err := m.target.SyncIDSequence(ctx); err != nil {
+			return report, err
+		}
+	}
+
+	if err := m.reconcile(ctx, report); err != nil {
+		return report, err
+	}
+	report.Duration = time.Since(started)
+	return report, nil
+}
+
+// loadCheckpoint returns the checkpoint to resume from; a completed run starts over
+func (m *UserMigration) loadCheckpoint() (*Checkpoint, error) {
+	if m.opts.CheckpointPath == "" {
+		return &Checkpoint{}, nil
+	}
+
+	checkpoint, err := LoadCheckpoint(m.opts.CheckpointPath)
+	if err != nil {
+		return nil, err
+	}
+	if checkpoint.Completed {
+		return &Checkpoint{}, nil
+	}
+	if checkpoint.LastID > 0 {
+		log.Printf("⏩ Resuming after user id %d (%d users already read)", checkpoint.LastID, checkpoint.Copy.Read)
+	}
+	return checkpoint, nil
+}
+
+// copyUsers upserts every source user whose checksum differs from the target, batch by batch
+func (m *UserMigration) copyUsers(ctx context.Context, checkpoint *Checkpoint, report *Report) error {
+	return m.eachBatch(ctx, checkpoint.LastID, func(batch []*model.User, existing map[string]*model.User) error {
+		for _, user := range batch {
+			report.Copy.Read++
+
+			current, found := existing[user.ID]
+			if found && Checksum(current) == Checksum(user) {
+				report.Copy.Unchanged++
+				continue
+			}
+
+			if !m.opts.DryRun {
+				if err := m.target.UpsertUser(ctx, user); err != nil {
+					if ctx.Err() != nil {
+						return ctx.Err()
+					}
+					log.Printf("⚠️  %v", err)
+					report.Copy.Failed++
+					report.Copy.FailedIDs = appendID(report.Copy.FailedIDs, user.ID)
+					continue
+				}
+			}
+
+			if found {
+				report.Copy.Updated++
+				if !m.opts.DryRun {
+					m.publishChanges(ctx, current, user)
+				}
+			} else {
+				report.Copy.Inserted++
+			}
+		}
+
+		checkpoint.LastID, _ = strconv.ParseInt(batch[len(batch)-1].ID, 10, 64)
+		checkpoint.Copy = report.Copy
+		return m.saveCheckpoint(checkpoint)
+	}, func() error {
+		checkpoint.Completed = true
+		return m.saveCheckpoint(checkpoint)
+	})
+}
+
+// publishChanges publishes the credential changes between the previous and the copied user,
+// so the running service's consumers drop cached sessions and profiles
+func (m *UserMigration) publishChanges(ctx context.Context, previous, copied *model.User) {
+	var changes []events.EventType
+	if previous.GetPasswordString() != copied.GetPasswordString() {
+		changes = append(changes, events.EventPasswordChanged)
+	}
+	if previous.GetEmailString() != copied.GetEmailString() {
+		changes = append(changes, events.EventEmailChanged)
+	}
+
+	for _, eventType := range changes {
+		event := events.Event{Type: eventType, UserID: copied.ID, Attributes: map[string]string{"source": "migrate-users"}}
+		if err := m.opts.Events.Publish(ctx, event); err != nil {
+			log.Printf("⚠️  Failed to publish %s event for user %s: %v", eventType, copied.ID, err)
+		}
+	}
+}
+
+// reconcile compares checksums of every source user with the target
+func (m *UserMigration) reconcile(ctx context.Context, report *Report) error {
+	result := &report.Reconciliation
+	sourceDigest, targetDigest := newDigest(), newDigest()
+
+	err := m.eachBatch(ctx, 0, func(batch []*model.User, existing map[string]*model.User) error {
+		for _, user := range batch {
+			result.SourceRows++
+			sourceSum := Checksum(user)
+			sourceDigest.add(sourceSum)
+
+			current, found := existing[user.ID]
+			if !found {
+				result.Missing++
+				result.MissingIDs = appendID(result.MissingIDs, user.ID)
+				continue
+			}
+
+			targetSum := Checksum(current)
+			targetDigest.add(targetSum)
+			if targetSum == sourceSum {
+				result.Matched++
+			} else {
+				result.Mismatched++
+				result.MismatchedIDs = appendID(result.MismatchedIDs, user.ID)
+			}
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	result.TargetRows, err = m.target.CountUsers(ctx)
+	if err != nil {
+		return err
+	}
+	result.SourceChecksum = sourceDigest.String()
+	result.TargetChecksum = targetDigest.String()
+	return nil
+}
+
+// eachBatch pages through the source users after afterID and calls fn with each batch and the
+// target users sharing their ids; done, when set, is called once the source is exhausted
+func (m *UserMigration) eachBatch(ctx context.Context, afterID int64, fn func(batch []*model.User, existing map[string]*model.User) error, done func() error) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		var rows []sourceUser
+		if err := m.source.SelectContext(ctx, &rows, sourceQuery, afterID, m.opts.BatchSize); err != nil {
+			return fmt.Errorf("failed to read source users after id %d: %w", afterID, err)
+		}
+		if len(rows) == 0 {
+			if done != nil {
+				return done()
+			}
+			return nil
+		}
+
+		batch := make([]*model.User, len(rows))
+		ids := make([]string, len(rows))
+		for i, row := range rows {
+			batch[i] = row.toModel()
+			ids[i] = batch[i].ID
+		}
+
+		targetUsers, err := m.target.GetUsersByIDs(ctx, ids)
+		if err != nil {
+			return err
+		}
+		existing := make(map[string]*model.User, len(targetUsers))
+		for _, user := range targetUsers {
+			existing[user.ID] = user
+		}
+
+		if err := fn(batch, existing); err != nil {
+			return err
+		}
+		afterID = rows[len(rows)-1].ID
+	}
+}
+
+func (m *UserMigration) saveCheckpoint(checkpoint *Checkpoint) error {
+	if m.opts.DryRun || m.opts.CheckpointPath == "" {
+		return nil
+	}
+	return checkpoint.Save(m.opts.CheckpointPath)
+}
+
+func appendID(ids []string, id string) []string {
+	if len(ids) >= maxReportedIDs {
+		return ids
+	}
+	return append(ids, id)
+}
@@ -0,0 +1,271 @@
+package usermigration
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"hub-user-service/internal/database"
+	"hub-user-service/internal/events"
+	"hub-user-service/internal/login/domain/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSource serves sourceQuery from an in-memory users table
+type fakeSource struct {
+	database.Querier
+	users   []sourceUser
+	queries int
+}
+
+func (s *fakeSource) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	s.queries++
+	afterID, limit := args[0].(int64), args[1].(int)
+	rows := dest.(*[]sourceUser)
+	for _, user := range s.users {
+		if user.ID > afterID && len(*rows) < limit {
+			*rows = append(*rows, user)
+		}
+	}
+	return nil
+}
+
+// fakeTarget is an in-memory IUserRepository
+type fakeTarget struct {
+	users     map[string]*model.User
+	upserts   int
+	failIDs   map[string]bool
+	sequenced bool
+}
+
+func newFakeTarget() *fakeTarget {
+	return &fakeTarget{users: map[string]*model.User{}, failIDs: map[string]bool{}}
+}
+
+func (t *fakeTarget) UpsertUser(ctx context.Context, user *model.User) error {
+	if t.failIDs[user.ID] {
+		return errors.New("duplicate key value violates unique constraint \"users_email_key\"")
+	}
+	t.upserts++
+	copied := *user
+	if existing, ok := t.users[user.ID]; ok {
+		copied.Status = existing.Status
+	}
+	t.users[user.ID] = &copied
+	return nil
+}
+
+func (t *fakeTarget) GetUsersByIDs(ctx context.Context, ids []string) ([]*model.User, error) {
+	var users []*model.User
+	for _, id := range ids {
+		if user, ok := t.users[id]; ok {
+			copied := *user
+			users = append(users, &copied)
+		}
+	}
+	return users, nil
+}
+
+func (t *fakeTarget) CountUsers(ctx context.Context) (int, error) {
+	return len(t.users), nil
+}
+
+func (t *fakeTarget) SyncIDSequence(ctx context.Context) error {
+	t.sequenced = true
+	return nil
+}
+
+func sourceUsers(n int) []sourceUser {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	users := make([]sourceUser, n)
+	for i := range users {
+		id := int64(i + 1)
+		users[i] = sourceUser{ID: id, Email: "user" + strconv.FormatInt(id, 10) + "@example.com", Password: "hash", CreatedAt: nullTime(created)}
+		users[i].Name.String, users[i].Name.Valid = "User", true
+	}
+	return users
+}
+
+func targetIDs(target *fakeTarget) []string {
+	ids := make([]string, 0, len(target.users))
+	for id := range target.users {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func TestUserMigration_CopiesAllUsersInBatches(t *testing.T) {
+	source := &fakeSource{users: sourceUsers(5)}
+	target := newFakeTarget()
+
+	report, err := NewUserMigration(source, target, Options{BatchSize: 2}).Run(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, CopyStats{Read: 5, Inserted: 5}, report.Copy)
+	assert.Equal(t, 5, report.Reconciliation.Matched)
+	assert.Equal(t, 5, report.Reconciliation.TargetRows)
+	assert.Equal(t, report.Reconciliation.SourceChecksum, report.Reconciliation.TargetChecksum)
+	assert.True(t, report.Consistent())
+	assert.True(t, target.sequenced)
+	assert.Equal(t, "user3@example.com", target.users["3"].GetEmailString())
+	// 3 batches plus the empty page, for the copy and for the reconciliation
+	assert.Equal(t, 8, source.queries)
+}
+
+func TestUserMigration_RepeatedRunsAreIdempotent(t *testing.T) {
+	source := &fakeSource{users: sourceUsers(3)}
+	target := newFakeTarget()
+	migration := NewUserMigration(source, target, Options{})
+	_, err := migration.Run(context.Background())
+	require.NoError(t, err)
+	target.users["2"].Status = model.UserStatusLocked
+
+	source.users[0].Password = "rotated"
+	report, err := migration.Run(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, CopyStats{Read: 3, Updated: 1, Unchanged: 2}, report.Copy)
+	assert.Equal(t, 4, target.upserts)
+	assert.Equal(t, "rotated", target.users["1"].GetPasswordString())
+	assert.Equal(t, model.UserStatusLocked, target.users["2"].Status)
+	assert.True(t, report.Consistent())
+}
+
+func TestUserMigration_PublishesCredentialChanges(t *testing.T) {
+	source := &fakeSource{users: sourceUsers(3)}
+	target := newFakeTarget()
+	broker := events.NewBroker(10, 10)
+	_, err := NewUserMigration(source, target, Options{Events: broker}).Run(context.Background())
+	require.NoError(t, err)
+	sub, err := broker.Subscribe(context.Background(), "", events.Filter{})
+	require.NoError(t, err)
+	defer sub.Close()
+
+	source.users[0].Password = "rotated"
+	source.users[1].Email = "renamed@example.com"
+	source.users[2].Name.String = "Renamed"
+	_, err = NewUserMigration(source, target, Options{Events: broker, DryRun: true}).Run(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, sub.Events(), "dry runs publish nothing")
+	_, err = NewUserMigration(source, target, Options{Events: broker}).Run(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, sub.Events(), 2, "new users and name changes publish nothing")
+	password, email := <-sub.Events(), <-sub.Events()
+	assert.Equal(t, events.EventPasswordChanged, password.Type)
+	assert.Equal(t, "1", password.UserID)
+	assert.Equal(t, events.EventEmailChanged, email.Type)
+	assert.Equal(t, "2", email.UserID)
+	assert.Equal(t, "migrate-users", email.Attributes["source"])
+}
+
+func TestUserMigration_DryRunWritesNothing(t *testing.T) {
+	source := &fakeSource{users: sourceUsers(3)}
+	target := newFakeTarget()
+	checkpoint := filepath.Join(t.TempDir(), "users.checkpoint")
+
+	report, err := NewUserMigration(source, target, Options{DryRun: true, CheckpointPath: checkpoint}).Run(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, CopyStats{Read: 3, Inserted: 3}, report.Copy)
+	assert.Empty(t, target.users)
+	assert.False(t, target.sequenced)
+	assert.Equal(t, 3, report.Reconciliation.Missing)
+	assert.Equal(t, []string{"1", "2", "3"}, report.Reconciliation.MissingIDs)
+	assert.False(t, report.Consistent())
+	assert.NoFileExists(t, checkpoint)
+}
+
+func TestUserMigration_ResumesFromCheckpoint(t *testing.T) {
+	source := &fakeSource{users: sourceUsers(4)}
+	target := newFakeTarget()
+	path := filepath.Join(t.TempDir(), "users.checkpoint")
+	require.NoError(t, (&Checkpoint{LastID: 2, Copy: CopyStats{Read: 2, Inserted: 2}}).Save(path))
+
+	report, err := NewUserMigration(source, target, Options{CheckpointPath: path}).Run(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), report.ResumedFromID)
+	assert.Equal(t, CopyStats{Read: 4, Inserted: 4}, report.Copy)
+	assert.Equal(t, []string{"3", "4"}, targetIDs(target))
+	// users 1 and 2 were never copied into this target, so the reconciliation flags them
+	assert.Equal(t, []string{"1", "2"}, report.Reconciliation.MissingIDs)
+
+	saved, err := LoadCheckpoint(path)
+	require.NoError(t, err)
+	assert.True(t, saved.Completed)
+	assert.Equal(t, int64(4), saved.LastID)
+}
+
+func TestUserMigration_CompletedCheckpointStartsOver(t *testing.T) {
+	source := &fakeSource{users: sourceUsers(2)}
+	target := newFakeTarget()
+	path := filepath.Join(t.TempDir(), "users.checkpoint")
+	require.NoError(t, (&Checkpoint{LastID: 2, Completed: true}).Save(path))
+
+	report, err := NewUserMigration(source, target, Options{CheckpointPath: path}).Run(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), report.ResumedFromID)
+	assert.Equal(t, []string{"1", "2"}, targetIDs(target))
+}
+
+func TestUserMigration_ReportsFailedUsersAndContinues(t *testing.T) {
+	source := &fakeSource{users: sourceUsers(3)}
+	target := newFakeTarget()
+	target.failIDs["2"] = true
+
+	report, err := NewUserMigration(source, target, Options{}).Run(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Copy.Failed)
+	assert.Equal(t, []string{"2"}, report.Copy.FailedIDs)
+	assert.Equal(t, 2, report.Copy.Inserted)
+	assert.Equal(t, []string{"2"}, report.Reconciliation.MissingIDs)
+	assert.False(t, report.Consistent())
+}
+
+func TestUserMigration_ReconciliationDetectsMismatches(t *testing.T) {
+	source := &fakeSource{users: sourceUsers(2)}
+	target := newFakeTarget()
+	migration := NewUserMigration(source, target, Options{})
+	_, err := migration.Run(context.Background())
+	require.NoError(t, err)
+
+	target.failIDs["1"] = true
+	target.users["1"].Name = "Changed in target"
+	report, err := migration.Run(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1"}, report.Reconciliation.MismatchedIDs)
+	assert.NotEqual(t, report.Reconciliation.SourceChecksum, report.Reconciliation.TargetChecksum)
+}
+
+func TestUserMigration_StopsWhenContextIsCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := NewUserMigration(&fakeSource{users: sourceUsers(1)}, newFakeTarget(), Options{}).Run(ctx)
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestChecksum_IgnoresServiceOwnedColumns(t *testing.T) {
+	user := sourceUsers(1)[0].toModel()
+	other := *user
+	other.Status = model.UserStatusDisabled
+	now := time.Now()
+	other.LastLoginAt = &now
+
+	assert.Equal(t, Checksum(user), Checksum(&other))
+
+	other.CreatedAt = other.CreatedAt.Add(time.Second)
+	assert.NotEqual(t, Checksum(user), Checksum(&other))
+}
@@ -0,0 +1,19 @@
+-- Migration: Reconcile users table with the domain model (ROLLBACK)
+-- Module: User Management
+-- Created: 2026-10-18
+-- Description: Remove the public UUID identifier, account status and login timestamps
+
+DROP INDEX IF EXISTS idx_users_status;
+
+-- Restore the original password constraint (NOT VALID keeps existing rows loadable)
+ALTER TABLE users DROP CONSTRAINT IF EXISTS non_empty_password;
+ALTER TABLE users ADD CONSTRAINT non_empty_password CHECK (LENGTH(password) >= 6) NOT VALID;
+
+ALTER TABLE users DROP COLUMN IF EXISTS last_failed_login_at;
+ALTER TABLE users DROP COLUMN IF EXISTS last_login_at;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS valid_status;
+ALTER TABLE users DROP COLUMN IF EXISTS status;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_uuid_key;
+ALTER TABLE users DROP COLUMN IF EXISTS uuid;
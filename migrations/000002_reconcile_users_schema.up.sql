@@ -0,0 +1,24 @@
+-- Migration: Reconcile users table with the domain model
+-- Module: User Management
+-- Created: 2026-10-18
+-- Description: Add a public UUID identifier, account status and login timestamps.
+--              The SERIAL id is kept as the legacy identifier shared with the monolith.
+--              gen_random_uuid() is built in since PostgreSQL 13.
+
+-- Stable public identifier, backfilled for existing rows by the column default
+ALTER TABLE users ADD COLUMN IF NOT EXISTS uuid UUID NOT NULL DEFAULT gen_random_uuid();
+ALTER TABLE users ADD CONSTRAINT users_uuid_key UNIQUE (uuid);
+
+-- Account status
+ALTER TABLE users ADD COLUMN IF NOT EXISTS status VARCHAR(32) NOT NULL DEFAULT 'active';
+ALTER TABLE users ADD CONSTRAINT valid_status CHECK (status IN ('active', 'locked', 'disabled', 'pending_verification'));
+
+-- Login timestamps
+ALTER TABLE users ADD COLUMN IF NOT EXISTS last_login_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS last_failed_login_at TIMESTAMP WITH TIME ZONE;
+
+-- Password policy is enforced by the domain (valueobject.NewPassword), the column only has to be set
+ALTER TABLE users DROP CONSTRAINT IF EXISTS non_empty_password;
+ALTER TABLE users ADD CONSTRAINT non_empty_password CHECK (LENGTH(password) > 0);
+
+CREATE INDEX IF NOT EXISTS idx_users_status ON users(status);
@@ -0,0 +1,6 @@
+-- Migration: Create TOTP factors (ROLLBACK)
+-- Module: Multi-factor Authentication
+-- Created: 2026-10-18
+-- Description: Remove TOTP factors; users fall back to password-only login
+
+DROP TABLE IF EXISTS user_totp;
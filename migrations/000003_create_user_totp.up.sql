@@ -0,0 +1,21 @@
+-- Migration: Create TOTP factors
+-- Module: Multi-factor Authentication
+-- Created: 2026-10-18
+-- Description: One authenticator app enrollment per user. The shared secret is encrypted by the
+--              service (AES-256-GCM, MFA_ENCRYPTION_KEY) before it is stored.
+
+CREATE TABLE IF NOT EXISTS user_totp (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret_ciphertext BYTEA NOT NULL,
+    -- NULL while enrollment is pending; MFA is enforced at login once set
+    confirmed_at TIMESTAMP WITH TIME ZONE,
+    -- Most recent accepted time step, codes for this step or earlier are rejected as replays
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER update_user_totp_updated_at
+    BEFORE UPDATE ON user_totp
+    FOR EACH ROW
+    EXECUTE FUNCTION update_users_updated_at_column();
@@ -0,0 +1,6 @@
+-- Migration: Create MFA recovery codes (ROLLBACK)
+-- Module: Multi-factor Authentication
+-- Created: 2026-10-18
+-- Description: Remove recovery codes; users with MFA enabled need their authenticator app again
+
+DROP TABLE IF EXISTS user_recovery_codes;
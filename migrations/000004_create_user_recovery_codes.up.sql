@@ -0,0 +1,16 @@
+-- Migration: Create MFA recovery codes
+-- Module: Multi-factor Authentication
+-- Created: 2026-10-18
+-- Description: One-time codes replacing a TOTP code when the authenticator device is lost.
+--              Only SHA-256 hashes bound to the user are stored.
+
+CREATE TABLE IF NOT EXISTS user_recovery_codes (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash BYTEA NOT NULL,
+    -- Set when the code is used, a code is accepted once
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_recovery_codes_user_hash ON user_recovery_codes(user_id, code_hash);
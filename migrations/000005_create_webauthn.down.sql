@@ -0,0 +1,7 @@
+-- Migration: Create WebAuthn credentials and ceremonies (ROLLBACK)
+-- Module: Passkeys
+-- Created: 2026-10-18
+-- Description: Remove passkeys; users fall back to password login
+
+DROP TABLE IF EXISTS webauthn_ceremonies;
+DROP TABLE IF EXISTS webauthn_credentials;
@@ -0,0 +1,36 @@
+-- Migration: Create WebAuthn credentials and ceremonies
+-- Module: Passkeys
+-- Created: 2026-10-18
+-- Description: Public key credentials registered by users (passkeys, security keys) and the
+--              short-lived state kept between the begin and finish calls of a ceremony.
+
+CREATE TABLE IF NOT EXISTS webauthn_credentials (
+    -- Credential ID chosen by the authenticator, unique per relying party
+    id BYTEA PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL DEFAULT '',
+    public_key BYTEA NOT NULL,
+    attestation_type VARCHAR(32) NOT NULL,
+    aaguid BYTEA,
+    -- Last signature counter seen, used to detect cloned authenticators
+    sign_count BIGINT NOT NULL DEFAULT 0,
+    transports TEXT[] NOT NULL DEFAULT '{}',
+    user_verified BOOLEAN NOT NULL DEFAULT FALSE,
+    backup_eligible BOOLEAN NOT NULL DEFAULT FALSE,
+    backup_state BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_webauthn_credentials_user_id ON webauthn_credentials(user_id);
+
+CREATE TABLE IF NOT EXISTS webauthn_ceremonies (
+    id VARCHAR(64) PRIMARY KEY,
+    kind VARCHAR(16) NOT NULL,
+    -- NULL for passkey logins, where the authenticator picks the account
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    session JSONB NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_webauthn_ceremonies_expires_at ON webauthn_ceremonies(expires_at);
@@ -0,0 +1,6 @@
+-- Migration: Create login codes (ROLLBACK)
+-- Module: Passwordless Login
+-- Created: 2026-10-18
+-- Description: Remove login codes; codes already sent stop working
+
+DROP TABLE IF EXISTS login_codes;
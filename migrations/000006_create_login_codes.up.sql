@@ -0,0 +1,12 @@
+-- Migration: Create login codes
+-- Module: Passwordless Login
+-- Created: 2026-10-18
+-- Description: One-time codes and magic link tokens emailed for passwordless login. Only the
+--              latest code per user is kept, as a SHA-256 hash bound to the user.
+
+CREATE TABLE IF NOT EXISTS login_codes (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    code_hash BYTEA NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,6 @@
+-- Migration: Create user sessions (ROLLBACK)
+-- Module: Session Management
+-- Created: 2026-10-18
+-- Description: Remove sessions; tokens carrying a session ID are accepted until they expire
+
+DROP TABLE IF EXISTS user_sessions;
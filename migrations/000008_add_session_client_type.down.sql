@@ -0,0 +1,6 @@
+-- Migration: Add the client type of user sessions (ROLLBACK)
+-- Module: Session Management
+-- Created: 2026-10-18
+-- Description: Remove the client type; sessions keep only the revocation check
+
+ALTER TABLE user_sessions DROP COLUMN IF EXISTS client_type;
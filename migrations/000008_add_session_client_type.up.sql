@@ -0,0 +1,7 @@
+-- Migration: Add the client type of user sessions
+-- Module: Session Management
+-- Created: 2026-10-18
+-- Description: Idle and absolute session timeouts are configured per client type (web, mobile,
+--              api). Existing sessions get the web timeouts.
+
+ALTER TABLE user_sessions ADD COLUMN IF NOT EXISTS client_type VARCHAR(16) NOT NULL DEFAULT 'web';
@@ -0,0 +1,6 @@
+-- Migration: Create login history (ROLLBACK)
+-- Module: Login History
+-- Created: 2026-10-18
+-- Description: Remove the login history; every device counts as new afterwards
+
+DROP TABLE IF EXISTS login_history;
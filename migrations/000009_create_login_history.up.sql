@@ -0,0 +1,24 @@
+-- Migration: Create login history
+-- Module: Login History
+-- Created: 2026-10-18
+-- Description: One row per login attempt, successful or not. Attempts for unknown emails or
+--              unidentified passkeys have no user_id.
+
+CREATE TABLE IF NOT EXISTS login_history (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    methods VARCHAR(64) NOT NULL DEFAULT '',
+    outcome VARCHAR(16) NOT NULL,
+    device VARCHAR(100) NOT NULL DEFAULT '',
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    user_agent VARCHAR(512) NOT NULL DEFAULT '',
+    country VARCHAR(8) NOT NULL DEFAULT '',
+    region VARCHAR(128) NOT NULL DEFAULT '',
+    city VARCHAR(128) NOT NULL DEFAULT '',
+    device_fingerprint VARCHAR(64) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_history_user_id ON login_history(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_login_history_devices ON login_history(user_id, device_fingerprint) WHERE outcome = 'success';
@@ -0,0 +1,6 @@
+-- Migration: Add failed attempts of login codes (ROLLBACK)
+-- Module: Passwordless Login
+-- Created: 2026-10-18
+-- Description: Remove the failed attempts; codes are only protected by the rate limiter
+
+ALTER TABLE login_codes DROP COLUMN IF EXISTS failed_attempts;
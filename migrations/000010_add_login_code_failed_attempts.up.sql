@@ -0,0 +1,7 @@
+-- Migration: Add failed attempts of login codes
+-- Module: Passwordless Login
+-- Created: 2026-10-18
+-- Description: Wrong codes checked against the stored code; the code is deleted once
+--              LOGIN_CODE_MAX_ATTEMPTS is reached, whatever the rate limiter allows.
+
+ALTER TABLE login_codes ADD COLUMN IF NOT EXISTS failed_attempts INTEGER NOT NULL DEFAULT 0;
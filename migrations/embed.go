@@ -0,0 +1,9 @@
+// Package migrations embeds the SQL schema migrations into the service binary
+package migrations
+
+import "embed"
+
+// FS holds the NNNNNN_name.up.sql / NNNNNN_name.down.sql migration files
+//
+//go:embed *.sql
+var FS embed.FS
@@ -86,6 +86,6 @@ echo "   DB_PASSWORD=$NEW_DB_PASSWORD"
 echo ""
 echo "⏭️  Next steps:"
 echo "   1. Run migrations: make migrate-up"
-echo "   2. Migrate data: ./scripts/migrate_users_data.sh"
+echo "   2. Migrate data: make migrate-data"
 echo ""
 
@@ -1,6 +1,7 @@
 package integration
 
 import (
+	"context"
 	"os"
 	"testing"
 	"time"
@@ -36,14 +37,27 @@ type MockLoginRepository struct {
 	mock.Mock
 }
 
-func (m *MockLoginRepository) GetUserByEmail(email string) (*model.User, error) {
-	args := m.Called(email)
+func (m *MockLoginRepository) GetUserByEmail(ctx context.Context, email string) (*model.User, error) {
+	args := m.Called(ctx, email)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*model.User), args.Error(1)
 }
 
+func (m *MockLoginRepository) GetUserByID(ctx context.Context, id string) (*model.User, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.User), args.Error(1)
+}
+
+// RecordLogin accepts every login, the login timestamps are not under test
+func (m *MockLoginRepository) RecordLogin(ctx context.Context, id string, succeeded bool, at time.Time) error {
+	return nil
+}
+
 func TestCrossServiceAuth_MicroserviceToMonolith_HappyPath(t *testing.T) {
 	// This is the CRITICAL integration test that validates the main requirement:
 	// Token created by microservice MUST be validated by monolith
@@ -65,13 +79,14 @@ func TestCrossServiceAuth_MicroserviceToMonolith_HappyPath(t *testing.T) {
 		ID:       "integration-test-user-123",
 		Email:    valueobject.NewEmailFromRepository("integration@test.com"),
 		Password: valueobject.NewPasswordFromRepository("password123"),
+		Status:   model.UserStatusActive,
 	}
-	mockRepo.On("GetUserByEmail", "integration@test.com").Return(testUser, nil)
+	mockRepo.On("GetUserByEmail", mock.Anything, "integration@test.com").Return(testUser, nil)
 
 	loginUsecase := usecase.NewDoLoginUsecase(mockRepo)
 
 	// Execute login
-	user, err := loginUsecase.Execute("integration@test.com", "password123")
+	user, err := loginUsecase.Execute(context.Background(), "integration@test.com", "password123")
 	assert.NoError(t, err)
 	assert.NotNil(t, user)
 
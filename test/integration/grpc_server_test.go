@@ -0,0 +1,692 @@
+package integration
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"hub-user-service/internal/auth"
+	"hub-user-service/internal/auth/token"
+	"hub-user-service/internal/config"
+	"hub-user-service/internal/events"
+	"hub-user-service/internal/geoip"
+	grpcServer "hub-user-service/internal/grpc"
+	"hub-user-service/internal/grpc/clientinfo"
+	"hub-user-service/internal/grpc/interceptor"
+	"hub-user-service/internal/grpc/proto"
+	"hub-user-service/internal/login/application/usecase"
+	"hub-user-service/internal/login/domain/model"
+	"hub-user-service/internal/login/infra/persistence"
+	loginCodeUsecase "hub-user-service/internal/logincode/application/usecase"
+	loginCodePersistence "hub-user-service/internal/logincode/infra/persistence"
+	loginHistoryUsecase "hub-user-service/internal/loginhistory/application/usecase"
+	loginHistoryPersistence "hub-user-service/internal/loginhistory/infra/persistence"
+	mfaUsecase "hub-user-service/internal/mfa/application/usecase"
+	"hub-user-service/internal/mfa/domain/totp"
+	"hub-user-service/internal/mfa/infra/crypto"
+	mfaPersistence "hub-user-service/internal/mfa/infra/persistence"
+	"hub-user-service/internal/notification"
+	passkeyUsecase "hub-user-service/internal/passkey/application/usecase"
+	passkeyPersistence "hub-user-service/internal/passkey/infra/persistence"
+	"hub-user-service/internal/passkey/passkeytest"
+	riskUsecase "hub-user-service/internal/risk/application/usecase"
+	"hub-user-service/internal/risk/infra/filesource"
+	sessionUsecase "hub-user-service/internal/session/application/usecase"
+	sessionPersistence "hub-user-service/internal/session/infra/persistence"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// ============================================================================
+// INTEGRATION TEST: gRPC server on the in-memory database (DB_DRIVER=memory)
+// ============================================================================
+//
+// Wires the real use case, auth service, event broker and gRPC services the way
+// cmd/server does, with the in-memory repository instead of PostgreSQL, and talks
+// to them through a real gRPC client over an in-process listener.
+// ============================================================================
+
+// passkeyOrigin is the web app origin allowed to run passkey ceremonies against the test server
+const passkeyOrigin = "http://localhost:3000"
+
+// riskRules is the risk policy of the test server: logins from blockedIP are refused, and
+// repeated failures require a second factor
+const riskRules = `{
+  "mfa_score": 40,
+  "deny_score": 100,
+  "rules": [
+    {"name": "blocked ip", "when": {"blocked_ip": {"min": 1}}, "score": 100},
+    {"name": "repeated failures", "when": {"recent_failures": {"min": 3}}, "score": 40}
+  ]
+}`
+
+// blockedIP is on the IP blocklist of the test server
+const blockedIP = "192.0.2.66"
+
+// testServer is a running gRPC server backed by the in-memory repository
+type testServer struct {
+	auth   proto.AuthServiceClient
+	events proto.UserEventServiceClient
+	users  *persistence.MemoryLoginRepository
+	broker *events.Broker
+	mail   *outbox
+}
+
+// outbox captures the emails sent by the server
+type outbox struct {
+	mu       sync.Mutex
+	messages []notification.Message
+}
+
+func (o *outbox) Send(ctx context.Context, message notification.Message) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.messages = append(o.messages, message)
+	return nil
+}
+
+// loginCode returns the code in the last email sent to email
+func (o *outbox) loginCode(t *testing.T, email string) string {
+	t.Helper()
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for i := len(o.messages) - 1; i >= 0; i-- {
+		if o.messages[i].To == email {
+			return regexp.MustCompile(`code is (\d{6})`).FindStringSubmatch(o.messages[i].Body)[1]
+		}
+	}
+	t.Fatalf("no email sent to %s", email)
+	return ""
+}
+
+// withSubject returns the emails sent to email with the given subject
+func (o *outbox) withSubject(email string, subject string) []notification.Message {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	var found []notification.Message
+	for _, message := range o.messages {
+		if message.To == email && message.Subject == subject {
+			found = append(found, message)
+		}
+	}
+	return found
+}
+
+func startTestServer(t *testing.T) *testServer {
+	t.Helper()
+
+	cfg := config.Get()
+	users := persistence.NewMemoryLoginRepository()
+	broker := events.NewBroker(100, 10)
+	authService := auth.NewAuthService(token.NewTokenService())
+	box, err := crypto.NewSecretBox(make([]byte, crypto.KeySize))
+	require.NoError(t, err)
+	totpUsecase := mfaUsecase.NewTOTPUsecase(mfaPersistence.NewMemoryTOTPRepository(), mfaPersistence.NewMemoryRecoveryCodeRepository(), box, mfaUsecase.TOTPConfig{
+		Issuer: "Hub Investments",
+		Skew:   1,
+	})
+	passkeys, err := passkeyUsecase.NewPasskeyUsecase(users, passkeyPersistence.NewMemoryCredentialRepository(), passkeyPersistence.NewMemoryCeremonyRepository(), passkeyUsecase.PasskeyConfig{
+		RPID:                    "localhost",
+		RPDisplayName:           "Hub Investments",
+		RPOrigins:               []string{passkeyOrigin},
+		Attestation:             "none",
+		RequireUserVerification: true,
+		CeremonyTimeout:         time.Minute,
+	})
+	require.NoError(t, err)
+	mail := &outbox{}
+	loginCodes := loginCodeUsecase.NewLoginCodeUsecase(users, loginCodePersistence.NewMemoryLoginCodeRepository(), mail, loginCodeUsecase.LoginCodeConfig{
+		Delivery: loginCodeUsecase.DeliveryCode,
+		TTL:      time.Minute,
+	})
+	sessions := sessionUsecase.NewSessionUsecase(sessionPersistence.NewMemorySessionRepository(), sessionUsecase.SessionConfig{
+		LastSeenInterval: time.Minute,
+	})
+	locations, err := geoip.ParseDatabase(strings.NewReader("network,country,region,city\n203.0.113.0/24,PT,Lisbon,Lisbon\n"))
+	require.NoError(t, err)
+	history := loginHistoryPersistence.NewMemoryLoginHistoryRepository()
+	loginHistory := loginHistoryUsecase.NewLoginHistoryUsecase(users, history, locations, mail, loginHistoryUsecase.LoginHistoryConfig{
+		NotifyNewDevices: true,
+	})
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "risk_rules.json"), []byte(riskRules), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "blocklist.txt"), []byte(blockedIP+"\n"), 0o600))
+	policies, err := filesource.LoadPolicyFile(filepath.Join(dir, "risk_rules.json"))
+	require.NoError(t, err)
+	blocklist, err := filesource.LoadBlocklistFile(filepath.Join(dir, "blocklist.txt"))
+	require.NoError(t, err)
+	risk := riskUsecase.NewRiskUsecase(history, locations, policies, blocklist)
+	loginGate := usecase.NewLoginGateUsecase(totpUsecase, risk)
+
+	serverOptions := grpcServer.NewServerOptions(cfg)
+	serverOptions = append(serverOptions, grpc.ChainUnaryInterceptor(interceptor.DefaultTimeout(cfg.GRPCRequestTimeout)))
+	server := grpc.NewServer(serverOptions...)
+	proto.RegisterAuthServiceServer(server, grpcServer.NewAuthServer(
+		usecase.NewDoLoginUsecase(users, usecase.WithLoginGate(loginGate)), authService, grpcServer.WithEventPublisher(broker), grpcServer.WithTOTP(totpUsecase), grpcServer.WithPasskeys(passkeys), grpcServer.WithLoginCodes(loginCodes), grpcServer.WithSessions(sessions), grpcServer.WithLoginHistory(loginHistory), grpcServer.WithLoginGate(loginGate),
+		// The test client plays the gateway; bufconn peers have no IP address to match trusted proxies
+		grpcServer.WithClientTrust(func(context.Context) bool { return true })))
+	proto.RegisterUserEventServiceServer(server, grpcServer.NewUserEventServer(broker))
+
+	listener := bufconn.Listen(1024 * 1024)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return &testServer{
+		auth:   proto.NewAuthServiceClient(conn),
+		events: proto.NewUserEventServiceClient(conn),
+		users:  users,
+		broker: broker,
+		mail:   mail,
+	}
+}
+
+func TestGRPCServer_LoginAndValidateToken(t *testing.T) {
+	server := startTestServer(t)
+	server.users.Save(model.NewUserFromRepository("42", "dev@example.com", "DevPass123!"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	login, err := server.auth.Login(ctx, &proto.LoginRequest{Email: "dev@example.com", Password: "DevPass123!"})
+	require.NoError(t, err)
+	require.True(t, login.ApiResponse.Success, login.ApiResponse.Message)
+	assert.Equal(t, "42", login.UserInfo.UserId)
+	require.NotEmpty(t, login.Token)
+
+	validation, err := server.auth.ValidateToken(ctx, &proto.ValidateTokenRequest{Token: "Bearer " + login.Token})
+	require.NoError(t, err)
+	assert.True(t, validation.IsValid)
+	assert.Equal(t, "42", validation.UserInfo.UserId)
+}
+
+func TestGRPCServer_LoginFailures(t *testing.T) {
+	server := startTestServer(t)
+	locked := model.NewUserFromRepository("7", "locked@example.com", "DevPass123!")
+	locked.Status = model.UserStatusLocked
+	server.users.Save(model.NewUserFromRepository("42", "dev@example.com", "DevPass123!"))
+	server.users.Save(locked)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tests := []struct {
+		name     string
+		email    string
+		password string
+	}{
+		{"wrong password", "dev@example.com", "WrongPass123!"},
+		{"unknown user", "nobody@example.com", "DevPass123!"},
+		{"locked account", "locked@example.com", "DevPass123!"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := server.auth.Login(ctx, &proto.LoginRequest{Email: tt.email, Password: tt.password})
+			require.NoError(t, err)
+			assert.False(t, resp.ApiResponse.Success)
+			assert.Equal(t, int32(401), resp.ApiResponse.Code)
+			assert.Empty(t, resp.Token)
+		})
+	}
+}
+
+func TestGRPCServer_LoginPublishesUserEvent(t *testing.T) {
+	server := startTestServer(t)
+	server.users.Save(model.NewUserFromRepository("42", "dev@example.com", "DevPass123!"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Capture a resume token before logging in so the stream replays the login event
+	marker, err := server.broker.Subscribe(context.Background(), "", events.Filter{})
+	require.NoError(t, err)
+	require.NoError(t, server.broker.Publish(ctx, events.Event{Type: events.EventLogout, UserID: "marker"}))
+	resumeToken := server.broker.ResumeToken(<-marker.Events())
+	marker.Close()
+
+	login, err := server.auth.Login(ctx, &proto.LoginRequest{Email: "dev@example.com", Password: "DevPass123!"})
+	require.NoError(t, err)
+	require.True(t, login.ApiResponse.Success)
+
+	stream, err := server.events.WatchUserEvents(ctx, &proto.WatchUserEventsRequest{
+		ResumeToken: resumeToken,
+		UserIds:     []string{"42"},
+	})
+	require.NoError(t, err)
+
+	event, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, proto.UserEventType_USER_EVENT_TYPE_LOGIN, event.Type)
+	assert.Equal(t, "42", event.UserId)
+}
+
+func TestGRPCServer_TOTPEnrollmentAndLogin(t *testing.T) {
+	server := startTestServer(t)
+	server.users.Save(model.NewUserFromRepository("42", "dev@example.com", "DevPass123!"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	login, err := server.auth.Login(ctx, &proto.LoginRequest{Email: "dev@example.com", Password: "DevPass123!"})
+	require.NoError(t, err)
+	require.True(t, login.ApiResponse.Success)
+	require.False(t, login.MfaRequired)
+
+	// Enroll the authenticator app
+	enrollment, err := server.auth.BeginTOTPEnrollment(ctx, &proto.BeginTOTPEnrollmentRequest{AccessToken: "Bearer " + login.Token})
+	require.NoError(t, err)
+	require.True(t, enrollment.ApiResponse.Success, enrollment.ApiResponse.Message)
+	assert.Contains(t, enrollment.OtpauthUri, "otpauth://totp/")
+	secret, err := totp.DecodeSecret(enrollment.Secret)
+	require.NoError(t, err)
+
+	// Confirm with the previous step's code so the current one stays usable for the login below
+	step := totp.Step(time.Now())
+	confirm, err := server.auth.ConfirmTOTPEnrollment(ctx, &proto.ConfirmTOTPEnrollmentRequest{
+		AccessToken: "Bearer " + login.Token,
+		Code:        totp.Code(secret, step-1),
+	})
+	require.NoError(t, err)
+	require.True(t, confirm.ApiResponse.Success, confirm.ApiResponse.Message)
+	require.Len(t, confirm.RecoveryCodes, 10)
+
+	// The password alone now only yields a challenge
+	login, err = server.auth.Login(ctx, &proto.LoginRequest{Email: "dev@example.com", Password: "DevPass123!"})
+	require.NoError(t, err)
+	require.True(t, login.MfaRequired)
+	assert.Empty(t, login.Token)
+	require.NotEmpty(t, login.MfaChallengeToken)
+
+	// The challenge is not an access token
+	validation, err := server.auth.ValidateToken(ctx, &proto.ValidateTokenRequest{Token: "Bearer " + login.MfaChallengeToken})
+	require.NoError(t, err)
+	assert.False(t, validation.IsValid)
+
+	code := totp.Code(secret, totp.Step(time.Now()))
+	verified, err := server.auth.VerifyMFA(ctx, &proto.VerifyMFARequest{MfaChallengeToken: login.MfaChallengeToken, Code: code})
+	require.NoError(t, err)
+	require.True(t, verified.ApiResponse.Success, verified.ApiResponse.Message)
+	require.NotEmpty(t, verified.Token)
+	assert.Equal(t, "42", verified.UserInfo.UserId)
+
+	validation, err = server.auth.ValidateToken(ctx, &proto.ValidateTokenRequest{Token: "Bearer " + verified.Token})
+	require.NoError(t, err)
+	assert.True(t, validation.IsValid)
+
+	// A code is accepted once
+	replay, err := server.auth.VerifyMFA(ctx, &proto.VerifyMFARequest{MfaChallengeToken: login.MfaChallengeToken, Code: code})
+	require.NoError(t, err)
+	assert.False(t, replay.ApiResponse.Success)
+	assert.Equal(t, int32(401), replay.ApiResponse.Code)
+	assert.Empty(t, replay.Token)
+}
+
+func TestGRPCServer_LoginWithRecoveryCode(t *testing.T) {
+	server := startTestServer(t)
+	server.users.Save(model.NewUserFromRepository("42", "dev@example.com", "DevPass123!"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	login, err := server.auth.Login(ctx, &proto.LoginRequest{Email: "dev@example.com", Password: "DevPass123!"})
+	require.NoError(t, err)
+	accessToken := "Bearer " + login.Token
+
+	enrollment, err := server.auth.BeginTOTPEnrollment(ctx, &proto.BeginTOTPEnrollmentRequest{AccessToken: accessToken})
+	require.NoError(t, err)
+	secret, err := totp.DecodeSecret(enrollment.Secret)
+	require.NoError(t, err)
+	confirm, err := server.auth.ConfirmTOTPEnrollment(ctx, &proto.ConfirmTOTPEnrollmentRequest{
+		AccessToken: accessToken,
+		Code:        totp.Code(secret, totp.Step(time.Now())-1),
+	})
+	require.NoError(t, err)
+	codes := confirm.RecoveryCodes
+
+	// The authenticator device is lost: log in with a recovery code
+	login, err = server.auth.Login(ctx, &proto.LoginRequest{Email: "dev@example.com", Password: "DevPass123!"})
+	require.NoError(t, err)
+	require.True(t, login.MfaRequired)
+
+	verified, err := server.auth.VerifyMFA(ctx, &proto.VerifyMFARequest{MfaChallengeToken: login.MfaChallengeToken, Code: codes[0]})
+	require.NoError(t, err)
+	require.True(t, verified.ApiResponse.Success, verified.ApiResponse.Message)
+	assert.NotEmpty(t, verified.Token)
+	assert.True(t, verified.RecoveryCodeUsed)
+	assert.Equal(t, int32(len(codes)-1), verified.RecoveryCodesRemaining)
+
+	replay, err := server.auth.VerifyMFA(ctx, &proto.VerifyMFARequest{MfaChallengeToken: login.MfaChallengeToken, Code: codes[0]})
+	require.NoError(t, err)
+	assert.Equal(t, int32(401), replay.ApiResponse.Code)
+
+	// Regenerating with another recovery code invalidates the old ones
+	regenerated, err := server.auth.RegenerateRecoveryCodes(ctx, &proto.RegenerateRecoveryCodesRequest{
+		AccessToken: "Bearer " + verified.Token,
+		Code:        codes[1],
+	})
+	require.NoError(t, err)
+	require.True(t, regenerated.ApiResponse.Success, regenerated.ApiResponse.Message)
+	assert.Len(t, regenerated.RecoveryCodes, len(codes))
+
+	stale, err := server.auth.VerifyMFA(ctx, &proto.VerifyMFARequest{MfaChallengeToken: login.MfaChallengeToken, Code: codes[2]})
+	require.NoError(t, err)
+	assert.Equal(t, int32(401), stale.ApiResponse.Code)
+}
+
+func TestGRPCServer_PasskeyRegistrationAndLogin(t *testing.T) {
+	server := startTestServer(t)
+	server.users.Save(model.NewUserFromRepository("42", "dev@example.com", "DevPass123!"))
+	authenticator := passkeytest.NewAuthenticator(passkeyOrigin)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	login, err := server.auth.Login(ctx, &proto.LoginRequest{Email: "dev@example.com", Password: "DevPass123!"})
+	require.NoError(t, err)
+	accessToken := "Bearer " + login.Token
+
+	// Register a passkey while logged in with the password
+	registration, err := server.auth.BeginPasskeyRegistration(ctx, &proto.BeginPasskeyRegistrationRequest{AccessToken: accessToken})
+	require.NoError(t, err)
+	require.True(t, registration.ApiResponse.Success, registration.ApiResponse.Message)
+	credential, err := authenticator.Register([]byte(registration.OptionsJson))
+	require.NoError(t, err)
+
+	registered, err := server.auth.FinishPasskeyRegistration(ctx, &proto.FinishPasskeyRegistrationRequest{
+		AccessToken:    accessToken,
+		CeremonyId:     registration.CeremonyId,
+		CredentialJson: string(credential),
+		Name:           "Laptop",
+	})
+	require.NoError(t, err)
+	require.True(t, registered.ApiResponse.Success, registered.ApiResponse.Message)
+	assert.NotEmpty(t, registered.CredentialId)
+
+	// Log in without a password
+	begin, err := server.auth.BeginPasskeyLogin(ctx, &proto.BeginPasskeyLoginRequest{})
+	require.NoError(t, err)
+	require.True(t, begin.ApiResponse.Success, begin.ApiResponse.Message)
+	assertion, err := authenticator.Login([]byte(begin.OptionsJson))
+	require.NoError(t, err)
+
+	passkeyLogin, err := server.auth.FinishPasskeyLogin(ctx, &proto.FinishPasskeyLoginRequest{CeremonyId: begin.CeremonyId, CredentialJson: string(assertion)})
+	require.NoError(t, err)
+	require.True(t, passkeyLogin.ApiResponse.Success, passkeyLogin.ApiResponse.Message)
+	assert.Equal(t, "42", passkeyLogin.UserInfo.UserId)
+	assert.Equal(t, "dev@example.com", passkeyLogin.UserInfo.Email)
+
+	validation, err := server.auth.ValidateToken(ctx, &proto.ValidateTokenRequest{Token: "Bearer " + passkeyLogin.Token})
+	require.NoError(t, err)
+	assert.True(t, validation.IsValid)
+
+	// The ceremony can not be replayed
+	replay, err := server.auth.FinishPasskeyLogin(ctx, &proto.FinishPasskeyLoginRequest{CeremonyId: begin.CeremonyId, CredentialJson: string(assertion)})
+	require.NoError(t, err)
+	assert.False(t, replay.ApiResponse.Success)
+	assert.Empty(t, replay.Token)
+}
+
+func TestGRPCServer_StepUpForSensitiveOperations(t *testing.T) {
+	server := startTestServer(t)
+	server.users.Save(model.NewUserFromRepository("42", "dev@example.com", "DevPass123!"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	login, err := server.auth.Login(ctx, &proto.LoginRequest{Email: "dev@example.com", Password: "DevPass123!"})
+	require.NoError(t, err)
+	accessToken := "Bearer " + login.Token
+
+	validation, err := server.auth.ValidateToken(ctx, &proto.ValidateTokenRequest{Token: accessToken})
+	require.NoError(t, err)
+	assert.True(t, validation.IsValid)
+	assert.Equal(t, "aal1", validation.Acr)
+	assert.Equal(t, []string{"pwd"}, validation.Amr)
+	assert.InDelta(t, time.Now().Unix(), validation.AuthTime, 5)
+
+	// A large order needs two factors
+	validation, err = server.auth.ValidateToken(ctx, &proto.ValidateTokenRequest{Token: accessToken, RequiredAcr: "aal2", MaxAgeSeconds: 60})
+	require.NoError(t, err)
+	assert.False(t, validation.IsValid)
+	assert.True(t, validation.StepUpRequired)
+
+	// Without MFA the password is re-verified, which does not reach aal2
+	stepUp, err := server.auth.StepUp(ctx, &proto.StepUpRequest{AccessToken: accessToken, Password: "DevPass123!"})
+	require.NoError(t, err)
+	require.True(t, stepUp.ApiResponse.Success, stepUp.ApiResponse.Message)
+	assert.Equal(t, "aal1", stepUp.Acr)
+
+	// Once TOTP is enabled the second factor steps up to aal2
+	enrollment, err := server.auth.BeginTOTPEnrollment(ctx, &proto.BeginTOTPEnrollmentRequest{AccessToken: accessToken})
+	require.NoError(t, err)
+	secret, err := totp.DecodeSecret(enrollment.Secret)
+	require.NoError(t, err)
+	step := totp.Step(time.Now())
+	confirm, err := server.auth.ConfirmTOTPEnrollment(ctx, &proto.ConfirmTOTPEnrollmentRequest{AccessToken: accessToken, Code: totp.Code(secret, step-1)})
+	require.NoError(t, err)
+	require.True(t, confirm.ApiResponse.Success, confirm.ApiResponse.Message)
+
+	stepUp, err = server.auth.StepUp(ctx, &proto.StepUpRequest{AccessToken: accessToken, Code: totp.Code(secret, step)})
+	require.NoError(t, err)
+	require.True(t, stepUp.ApiResponse.Success, stepUp.ApiResponse.Message)
+	assert.Equal(t, "aal2", stepUp.Acr)
+
+	validation, err = server.auth.ValidateToken(ctx, &proto.ValidateTokenRequest{Token: "Bearer " + stepUp.Token, RequiredAcr: "aal2", MaxAgeSeconds: 60})
+	require.NoError(t, err)
+	assert.True(t, validation.IsValid, validation.ApiResponse.Message)
+	assert.Equal(t, "42", validation.UserInfo.UserId)
+	assert.InDelta(t, time.Now().Add(5*time.Minute).Unix(), validation.ExpiresAt, 5, "elevated tokens are short-lived")
+}
+
+func TestGRPCServer_LoginWithEmailCode(t *testing.T) {
+	server := startTestServer(t)
+	server.users.Save(model.NewUserFromRepository("42", "dev@example.com", "DevPass123!"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Unknown emails get the same answer and no email
+	unknown, err := server.auth.RequestLoginCode(ctx, &proto.RequestLoginCodeRequest{Email: "nobody@example.com"})
+	require.NoError(t, err)
+	requested, err := server.auth.RequestLoginCode(ctx, &proto.RequestLoginCodeRequest{Email: "dev@example.com"})
+	require.NoError(t, err)
+	require.True(t, requested.ApiResponse.Success, requested.ApiResponse.Message)
+	assert.Equal(t, requested.ApiResponse.Message, unknown.ApiResponse.Message)
+	require.Len(t, server.mail.messages, 1)
+
+	code := server.mail.loginCode(t, "dev@example.com")
+	login, err := server.auth.VerifyLoginCode(ctx, &proto.VerifyLoginCodeRequest{Email: "dev@example.com", Code: code})
+	require.NoError(t, err)
+	require.True(t, login.ApiResponse.Success, login.ApiResponse.Message)
+	assert.Equal(t, "42", login.UserInfo.UserId)
+
+	validation, err := server.auth.ValidateToken(ctx, &proto.ValidateTokenRequest{Token: "Bearer " + login.Token})
+	require.NoError(t, err)
+	assert.True(t, validation.IsValid)
+	assert.Equal(t, "aal1", validation.Acr)
+	assert.Equal(t, []string{"email"}, validation.Amr)
+
+	// Codes are single-use
+	replay, err := server.auth.VerifyLoginCode(ctx, &proto.VerifyLoginCodeRequest{Email: "dev@example.com", Code: code})
+	require.NoError(t, err)
+	assert.Equal(t, int32(401), replay.ApiResponse.Code)
+	assert.Empty(t, replay.Token)
+}
+
+func TestGRPCServer_SessionsListAndRevoke(t *testing.T) {
+	server := startTestServer(t)
+	server.users.Save(model.NewUserFromRepository("42", "dev@example.com", "DevPass123!"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	phone, err := server.auth.Login(metadata.AppendToOutgoingContext(ctx, clientinfo.UserAgentMetadataKey,
+		"Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 Version/17.0 Mobile/15E148 Safari/604.1"),
+		&proto.LoginRequest{Email: "dev@example.com", Password: "DevPass123!"})
+	require.NoError(t, err)
+	require.True(t, phone.ApiResponse.Success, phone.ApiResponse.Message)
+	laptop, err := server.auth.Login(metadata.AppendToOutgoingContext(ctx, clientinfo.DeviceNameMetadataKey, "Work laptop"),
+		&proto.LoginRequest{Email: "dev@example.com", Password: "DevPass123!"})
+	require.NoError(t, err)
+	require.True(t, laptop.ApiResponse.Success, laptop.ApiResponse.Message)
+
+	list, err := server.auth.ListSessions(ctx, &proto.ListSessionsRequest{AccessToken: "Bearer " + laptop.Token})
+	require.NoError(t, err)
+	require.True(t, list.ApiResponse.Success, list.ApiResponse.Message)
+	require.Len(t, list.Sessions, 2)
+	devices := map[string]bool{}
+	for _, session := range list.Sessions {
+		devices[session.Device] = session.Current
+	}
+	assert.Equal(t, map[string]bool{"Work laptop": true, "Safari on iOS": false}, devices)
+
+	revoked, err := server.auth.RevokeAllOtherSessions(ctx, &proto.RevokeAllOtherSessionsRequest{AccessToken: "Bearer " + laptop.Token})
+	require.NoError(t, err)
+	require.True(t, revoked.ApiResponse.Success, revoked.ApiResponse.Message)
+	assert.Equal(t, int32(1), revoked.RevokedCount)
+
+	phoneValidation, err := server.auth.ValidateToken(ctx, &proto.ValidateTokenRequest{Token: "Bearer " + phone.Token})
+	require.NoError(t, err)
+	assert.False(t, phoneValidation.IsValid)
+	assert.Equal(t, int32(401), phoneValidation.ApiResponse.Code)
+
+	laptopValidation, err := server.auth.ValidateToken(ctx, &proto.ValidateTokenRequest{Token: "Bearer " + laptop.Token})
+	require.NoError(t, err)
+	assert.True(t, laptopValidation.IsValid)
+}
+
+func TestGRPCServer_RefreshToken(t *testing.T) {
+	server := startTestServer(t)
+	server.users.Save(model.NewUserFromRepository("42", "dev@example.com", "DevPass123!"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	login, err := server.auth.Login(metadata.AppendToOutgoingContext(ctx, clientinfo.ClientTypeMetadataKey, "mobile"),
+		&proto.LoginRequest{Email: "dev@example.com", Password: "DevPass123!"})
+	require.NoError(t, err)
+	require.True(t, login.ApiResponse.Success, login.ApiResponse.Message)
+
+	refreshed, err := server.auth.RefreshToken(ctx, &proto.RefreshTokenRequest{AccessToken: "Bearer " + login.Token})
+	require.NoError(t, err)
+	require.True(t, refreshed.ApiResponse.Success, refreshed.ApiResponse.Message)
+
+	validation, err := server.auth.ValidateToken(ctx, &proto.ValidateTokenRequest{Token: "Bearer " + refreshed.Token})
+	require.NoError(t, err)
+	assert.True(t, validation.IsValid)
+	assert.Equal(t, []string{"pwd"}, validation.Amr)
+
+	list, err := server.auth.ListSessions(ctx, &proto.ListSessionsRequest{AccessToken: "Bearer " + refreshed.Token})
+	require.NoError(t, err)
+	require.Len(t, list.Sessions, 1, "the refreshed token belongs to the same session")
+	assert.True(t, list.Sessions[0].Current)
+	assert.Equal(t, "mobile", list.Sessions[0].ClientType)
+
+	// Once the session is revoked none of its tokens can be refreshed
+	revoked, err := server.auth.RevokeSession(ctx, &proto.RevokeSessionRequest{AccessToken: "Bearer " + refreshed.Token, SessionId: list.Sessions[0].SessionId})
+	require.NoError(t, err)
+	require.True(t, revoked.ApiResponse.Success, revoked.ApiResponse.Message)
+	again, err := server.auth.RefreshToken(ctx, &proto.RefreshTokenRequest{AccessToken: "Bearer " + login.Token})
+	require.NoError(t, err)
+	assert.Equal(t, int32(401), again.ApiResponse.Code)
+	assert.Empty(t, again.Token)
+}
+
+func TestGRPCServer_LoginHistoryAndNewDeviceNotification(t *testing.T) {
+	server := startTestServer(t)
+	server.users.Save(model.NewUserFromRepository("42", "dev@example.com", "DevPass123!"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	const subject = "New sign-in to your Hub Investments account"
+	laptopCtx := metadata.AppendToOutgoingContext(ctx, clientinfo.UserAgentMetadataKey, "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 Chrome/126.0 Safari/537.36", clientinfo.ForwardedForMetadataKey, "203.0.113.7")
+
+	failed, err := server.auth.Login(laptopCtx, &proto.LoginRequest{Email: "dev@example.com", Password: "wrong"})
+	require.NoError(t, err)
+	require.False(t, failed.ApiResponse.Success)
+	laptop, err := server.auth.Login(laptopCtx, &proto.LoginRequest{Email: "dev@example.com", Password: "DevPass123!"})
+	require.NoError(t, err)
+	require.True(t, laptop.ApiResponse.Success, laptop.ApiResponse.Message)
+	assert.Empty(t, server.mail.withSubject("dev@example.com", subject), "the first login of an account is not notified")
+
+	// Logging in again from the same device is not notified either
+	_, err = server.auth.Login(laptopCtx, &proto.LoginRequest{Email: "dev@example.com", Password: "DevPass123!"})
+	require.NoError(t, err)
+	assert.Empty(t, server.mail.withSubject("dev@example.com", subject))
+
+	phone, err := server.auth.Login(metadata.AppendToOutgoingContext(ctx, clientinfo.DeviceNameMetadataKey, "Ada's phone"),
+		&proto.LoginRequest{Email: "dev@example.com", Password: "DevPass123!"})
+	require.NoError(t, err)
+	require.True(t, phone.ApiResponse.Success, phone.ApiResponse.Message)
+	notifications := server.mail.withSubject("dev@example.com", subject)
+	require.Len(t, notifications, 1)
+	assert.Contains(t, notifications[0].Body, "Device: Ada's phone")
+
+	history, err := server.auth.GetLoginHistory(ctx, &proto.GetLoginHistoryRequest{AccessToken: "Bearer " + phone.Token})
+	require.NoError(t, err)
+	require.True(t, history.ApiResponse.Success, history.ApiResponse.Message)
+	require.Len(t, history.Attempts, 4)
+	assert.Equal(t, "Ada's phone", history.Attempts[0].Device)
+	assert.Equal(t, "failure", history.Attempts[3].Outcome)
+	assert.Equal(t, "Chrome on macOS", history.Attempts[3].Device)
+	assert.Equal(t, "203.0.113.7", history.Attempts[3].IpAddress)
+	assert.Equal(t, "PT", history.Attempts[3].Country)
+
+	older, err := server.auth.GetLoginHistory(ctx, &proto.GetLoginHistoryRequest{AccessToken: "Bearer " + phone.Token, Limit: 2, BeforeId: history.Attempts[1].Id})
+	require.NoError(t, err)
+	require.Len(t, older.Attempts, 2)
+	assert.Equal(t, history.Attempts[2].Id, older.Attempts[0].Id)
+	assert.Equal(t, history.Attempts[3].Id, older.Attempts[1].Id)
+}
+
+func TestGRPCServer_RiskAssessment(t *testing.T) {
+	server := startTestServer(t)
+	server.users.Save(model.NewUserFromRepository("42", "dev@example.com", "DevPass123!"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	blocked, err := server.auth.Login(metadata.AppendToOutgoingContext(ctx, clientinfo.ForwardedForMetadataKey, blockedIP),
+		&proto.LoginRequest{Email: "dev@example.com", Password: "DevPass123!"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(403), blocked.ApiResponse.Code)
+	assert.Empty(t, blocked.Token)
+
+	allowed, err := server.auth.Login(ctx, &proto.LoginRequest{Email: "dev@example.com", Password: "DevPass123!"})
+	require.NoError(t, err)
+	require.True(t, allowed.ApiResponse.Success, allowed.ApiResponse.Message)
+
+	history, err := server.auth.GetLoginHistory(ctx, &proto.GetLoginHistoryRequest{AccessToken: "Bearer " + allowed.Token})
+	require.NoError(t, err)
+	require.Len(t, history.Attempts, 2)
+	assert.Equal(t, "denied", history.Attempts[1].Outcome)
+	assert.Equal(t, blockedIP, history.Attempts[1].IpAddress)
+
+	// Without TOTP the second factor required after repeated failures can not be given
+	for i := 0; i < 3; i++ {
+		_, err := server.auth.Login(ctx, &proto.LoginRequest{Email: "dev@example.com", Password: "WrongPass123!"})
+		require.NoError(t, err)
+	}
+	afterFailures, err := server.auth.Login(ctx, &proto.LoginRequest{Email: "dev@example.com", Password: "DevPass123!"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(403), afterFailures.ApiResponse.Code)
+	assert.Empty(t, afterFailures.Token)
+}